@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/apache/arrow/go/v14/arrow/ipc"
+	"github.com/apache/arrow/go/v14/arrow/memory"
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/oklog/ulid"
@@ -70,6 +71,10 @@ type ColumnStore struct {
 	sources []DataSource
 	sinks   []DataSink
 
+	// allocator allocates the records the column store computes itself,
+	// such as the deltas of materialized views.
+	allocator memory.Allocator
+
 	compactAfterRecovery           bool
 	compactAfterRecoveryTableNames []string
 
@@ -101,6 +106,7 @@ func New(
 		splitSize:           2,
 		granuleSizeBytes:    1 * MiB,
 		activeMemorySize:    512 * MiB,
+		allocator:           memory.DefaultAllocator,
 	}
 
 	for _, option := range options {
@@ -273,6 +279,15 @@ func WithRecoveryConcurrency(concurrency int) Option {
 	}
 }
 
+// WithAllocator sets the allocator of the records the column store computes
+// itself, such as the deltas of materialized views.
+func WithAllocator(allocator memory.Allocator) Option {
+	return func(s *ColumnStore) error {
+		s.allocator = allocator
+		return nil
+	}
+}
+
 // Close persists all data from the columnstore to storage.
 // It is no longer valid to use the coumnstore for reads or writes, and the object should not longer be reused.
 func (s *ColumnStore) Close() error {
@@ -1129,9 +1144,15 @@ func (db *DB) Table(name string, config *tablepb.TableConfig) (*Table, error) {
 	}
 	db.mtx.RLock()
 	table, ok := db.tables[name]
-	db.mtx.RUnlock()
 	if ok {
+		if err := db.validateMaterializedViewLocked(name, config); err != nil {
+			db.mtx.RUnlock()
+			return nil, err
+		}
 		table.config.Store(config)
+	}
+	db.mtx.RUnlock()
+	if ok {
 		return table, nil
 	}
 
@@ -1145,6 +1166,10 @@ func (db *DB) Table(name string, config *tablepb.TableConfig) (*Table, error) {
 		return table, nil
 	}
 
+	if err := db.validateMaterializedViewLocked(name, config); err != nil {
+		return nil, err
+	}
+
 	// Check if this table exists as a read only table
 	if _, ok := db.roTables[name]; ok {
 		var err error
@@ -8,6 +8,8 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -17,6 +19,7 @@ import (
 	"github.com/apache/arrow/go/v14/arrow/memory"
 	"github.com/go-kit/log/level"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 	"github.com/thanos-io/objstore"
 	"golang.org/x/sync/errgroup"
@@ -24,6 +27,7 @@ import (
 
 	"github.com/polarsignals/frostdb/dynparquet"
 	schemapb "github.com/polarsignals/frostdb/gen/proto/go/frostdb/schema/v1alpha1"
+	tablepb "github.com/polarsignals/frostdb/gen/proto/go/frostdb/table/v1alpha1"
 	walpb "github.com/polarsignals/frostdb/gen/proto/go/frostdb/wal/v1alpha1"
 	"github.com/polarsignals/frostdb/query"
 	"github.com/polarsignals/frostdb/query/logicalplan"
@@ -1842,10 +1846,93 @@ func Test_DB_Limiter(t *testing.T) {
 				Execute(context.Background(), func(ctx context.Context, r arrow.Record) error {
 					return nil
 				})
+			if err != nil {
+				require.ErrorIs(t, err, query.ErrMemoryLimitExceeded)
+			}
 		})
 	}
 }
 
+func Test_DB_QueryMemoryLimit(t *testing.T) {
+	config := NewTableConfig(
+		dynparquet.SampleDefinition(),
+	)
+
+	c, err := New(
+		WithLogger(newTestLogger(t)),
+	)
+	require.NoError(t, err)
+	defer c.Close()
+	db, err := c.DB(context.Background(), "test")
+	require.NoError(t, err)
+	table, err := db.Table("test", config)
+	require.NoError(t, err)
+
+	samples := dynparquet.GenerateTestSamples(1000)
+	r, err := samples.ToRecord()
+	require.NoError(t, err)
+	_, err = table.InsertRecord(context.Background(), r)
+	require.NoError(t, err)
+	r.Release()
+
+	reg := prometheus.NewRegistry()
+	debug := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer debug.AssertSize(t, 0)
+	pool := query.NewLimitAllocator(1024*1024*1024, debug, query.WithRegistry(reg))
+
+	aggregate := func(engine *query.LocalEngine) error {
+		return engine.ScanTable("test").
+			Aggregate(
+				[]logicalplan.Expr{logicalplan.Sum(logicalplan.Col("value"))},
+				[]logicalplan.Expr{logicalplan.Col("timestamp")},
+			).
+			Execute(context.Background(), func(ctx context.Context, r arrow.Record) error {
+				return nil
+			})
+	}
+
+	// Only the query exceeding its own limit fails, queries sharing the same
+	// parent allocator are unaffected.
+	limited := query.NewEngine(pool, db.TableProvider(), query.WithQueryMemoryLimit(1024))
+	unlimited := query.NewEngine(pool, db.TableProvider())
+	errg := errgroup.Group{}
+	errg.Go(func() error {
+		err := aggregate(limited)
+		if !errors.Is(err, query.ErrMemoryLimitExceeded) {
+			return fmt.Errorf("expected memory limit to be exceeded, got: %w", err)
+		}
+		return nil
+	})
+	errg.Go(func() error {
+		return aggregate(unlimited)
+	})
+	require.NoError(t, errg.Wait())
+	require.Equal(t, 0, pool.Allocated())
+	require.Greater(t, pool.Peak(), 1024)
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	exceeded := 0.0
+	for _, f := range families {
+		if f.GetName() == "memory_limit_exceeded_total" {
+			for _, m := range f.GetMetric() {
+				exceeded += m.GetCounter().GetValue()
+			}
+		}
+	}
+	require.Equal(t, 1.0, exceeded)
+}
+
+func Test_DB_PanicMemoryLimit(t *testing.T) {
+	// Allocations that can't be attributed to a query still panic with the
+	// value callers recovering from the panic compare to.
+	pool := query.NewLimitAllocator(8, memory.DefaultAllocator)
+	require.PanicsWithValue(t, query.PanicMemoryLimit, func() {
+		pool.Allocate(16)
+	})
+	require.Equal(t, 0, pool.Allocated())
+}
+
 // DropStorage ensures that a database can continue on after drop storage is called.
 func Test_DB_DropStorage(t *testing.T) {
 	logger := newTestLogger(t)
@@ -2250,3 +2337,531 @@ func TestDBConcurrentOpen(t *testing.T) {
 	}
 	require.NoError(t, errg.Wait())
 }
+
+func Test_DB_ResultCache(t *testing.T) {
+	config := NewTableConfig(
+		dynparquet.SampleDefinition(),
+	)
+
+	bucket := objstore.NewInMemBucket()
+	c, err := New(
+		WithLogger(newTestLogger(t)),
+		WithReadWriteStorage(NewDefaultObjstoreBucket(bucket)),
+		WithManualBlockRotation(),
+	)
+	require.NoError(t, err)
+	defer c.Close()
+	db, err := c.DB(context.Background(), "test")
+	require.NoError(t, err)
+	table, err := db.Table("test", config)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	insert := func(n int) {
+		r, err := dynparquet.GenerateTestSamples(n).ToRecord()
+		require.NoError(t, err)
+		defer r.Release()
+		_, err = table.InsertRecord(ctx, r)
+		require.NoError(t, err)
+	}
+	rotate := func() {
+		require.NoError(t, table.RotateBlock(ctx, table.ActiveBlock(), false))
+		require.Eventually(t, func() bool {
+			ts, ok := table.LastBlockTimestamp()
+			return ok && ts == table.ActiveBlock().ulid.Time()
+		}, 10*time.Second, 10*time.Millisecond)
+	}
+
+	pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer pool.AssertSize(t, 0)
+	cache := query.NewResultCache(1024 * 1024)
+	cached := query.NewEngine(pool, db.TableProvider(), query.WithResultCache(cache))
+	uncached := query.NewEngine(pool, db.TableProvider())
+
+	queries := map[string]func(engine *query.LocalEngine) query.Builder{
+		"aggregate": func(engine *query.LocalEngine) query.Builder {
+			return engine.ScanTable("test").Aggregate(
+				[]logicalplan.Expr{
+					logicalplan.Sum(logicalplan.Col("value")),
+					logicalplan.Count(logicalplan.Col("value")),
+					logicalplan.Max(logicalplan.Col("value")),
+				},
+				[]logicalplan.Expr{logicalplan.Col("timestamp")},
+			)
+		},
+		"avg": func(engine *query.LocalEngine) query.Builder {
+			return engine.ScanTable("test").Aggregate(
+				[]logicalplan.Expr{logicalplan.Avg(logicalplan.Col("value"))},
+				[]logicalplan.Expr{logicalplan.Col("timestamp")},
+			)
+		},
+		"first and last": func(engine *query.LocalEngine) query.Builder {
+			return engine.ScanTable("test").Aggregate(
+				[]logicalplan.Expr{
+					logicalplan.First(logicalplan.Col("value"), logicalplan.Col("timestamp")),
+					logicalplan.Last(logicalplan.Col("value"), logicalplan.Col("timestamp")),
+				},
+				[]logicalplan.Expr{logicalplan.Duration(4 * time.Millisecond)},
+			)
+		},
+		"distinct": func(engine *query.LocalEngine) query.Builder {
+			return engine.ScanTable("test").Distinct(logicalplan.Col("timestamp"))
+		},
+		"filter": func(engine *query.LocalEngine) query.Builder {
+			return engine.ScanTable("test").
+				Filter(logicalplan.Col("timestamp").Gt(logicalplan.Literal(int64(5)))).
+				Project(logicalplan.Col("timestamp"), logicalplan.Col("value"))
+		},
+	}
+
+	rows := func(b query.Builder) []string {
+		var res []string
+		require.NoError(t, b.Execute(ctx, func(_ context.Context, r arrow.Record) error {
+			for i := 0; i < int(r.NumRows()); i++ {
+				row := make([]string, 0, r.NumCols())
+				for j, f := range r.Schema().Fields() {
+					row = append(row, f.Name+"="+r.Column(j).ValueStr(i))
+				}
+				sort.Strings(row)
+				res = append(res, strings.Join(row, ","))
+			}
+			return nil
+		}))
+		sort.Strings(res)
+		return res
+	}
+	compare := func() {
+		for name, q := range queries {
+			expected := rows(q(uncached))
+			require.NotEmpty(t, expected, name)
+			require.Equal(t, expected, rows(q(cached)), name)
+			// The second execution is answered from the cache.
+			require.Equal(t, expected, rows(q(cached)), name)
+		}
+	}
+
+	insert(10)
+	rotate()
+	insert(20)
+	compare()
+	require.Greater(t, cache.Size(), int64(0))
+
+	// New in-memory data is merged with the cached results.
+	insert(15)
+	compare()
+
+	// Rotating a block invalidates the cached results.
+	rotate()
+	insert(5)
+	compare()
+
+	// Only the results of aggregations are cached, and only if they fit into
+	// the budget of the cache.
+	for _, tc := range []struct {
+		name   string
+		budget int64
+		query  string
+	}{
+		{name: "scan", budget: 1024 * 1024, query: "filter"},
+		{name: "budget exceeded", budget: 64, query: "aggregate"},
+	} {
+		cache := query.NewResultCache(tc.budget)
+		engine := query.NewEngine(pool, db.TableProvider(), query.WithResultCache(cache))
+		require.Equal(t, rows(queries[tc.query](uncached)), rows(queries[tc.query](engine)), tc.name)
+		require.Equal(t, int64(0), cache.Size(), tc.name)
+	}
+
+	// Unseeded samples differ between queries, so their results aren't
+	// cached either.
+	sampleCache := query.NewResultCache(1024 * 1024)
+	require.NoError(t, query.NewEngine(pool, db.TableProvider(), query.WithResultCache(sampleCache)).
+		ScanTable("test").(query.ExtendedBuilder).
+		Sample(logicalplan.Sample{Method: logicalplan.SampleBernoulli, Fraction: 0.5}).
+		Aggregate([]logicalplan.Expr{logicalplan.Sum(logicalplan.Col("value"))}, nil).
+		Execute(ctx, func(context.Context, arrow.Record) error { return nil }))
+	require.Equal(t, int64(0), sampleCache.Size())
+
+	// The tables of other databases sharing the cache have results of their
+	// own, even if they have the same name.
+	otherDB, err := c.DB(context.Background(), "other")
+	require.NoError(t, err)
+	table, err = otherDB.Table("test", config)
+	require.NoError(t, err)
+	insert(3)
+	rotate()
+	insert(2)
+	otherCached := query.NewEngine(pool, otherDB.TableProvider(), query.WithResultCache(cache))
+	otherUncached := query.NewEngine(pool, otherDB.TableProvider())
+	for name, q := range queries {
+		expected := rows(q(otherUncached))
+		require.Equal(t, expected, rows(q(otherCached)), name)
+		require.NotEqual(t, rows(q(uncached)), expected, name)
+	}
+}
+
+func Test_DB_MaterializedView(t *testing.T) {
+	config := NewTableConfig(
+		dynparquet.SampleDefinition(),
+	)
+	viewConfig := NewTableConfig(
+		&schemapb.Schema{
+			Name: "rollup",
+			Columns: []*schemapb.Column{{
+				Name:          "timestamp",
+				StorageLayout: &schemapb.StorageLayout{Type: schemapb.StorageLayout_TYPE_INT64},
+			}, {
+				Name:          "value_sum",
+				StorageLayout: &schemapb.StorageLayout{Type: schemapb.StorageLayout_TYPE_INT64},
+			}, {
+				Name:          "value_count",
+				StorageLayout: &schemapb.StorageLayout{Type: schemapb.StorageLayout_TYPE_INT64},
+			}},
+			SortingColumns: []*schemapb.SortingColumn{{
+				Name:      "timestamp",
+				Direction: schemapb.SortingColumn_DIRECTION_ASCENDING,
+			}},
+		},
+		WithMaterializedView(&tablepb.MaterializedView{
+			Source: "test",
+			Aggregations: []*tablepb.Aggregation{{
+				Function: tablepb.Aggregation_FUNCTION_SUM,
+				Column:   "value",
+				Alias:    "value_sum",
+			}, {
+				Function: tablepb.Aggregation_FUNCTION_COUNT,
+				Column:   "value",
+				Alias:    "value_count",
+			}},
+			GroupBy: []*tablepb.GroupBy{{
+				Expr: &tablepb.GroupBy_DurationMillis{DurationMillis: 10},
+			}},
+		}),
+	)
+
+	logger := newTestLogger(t)
+	dir := t.TempDir()
+	open := func() (*ColumnStore, *DB) {
+		c, err := New(
+			WithLogger(logger),
+			WithWAL(),
+			WithStoragePath(dir),
+		)
+		require.NoError(t, err)
+		db, err := c.DB(context.Background(), "test")
+		require.NoError(t, err)
+		return c, db
+	}
+
+	ctx := context.Background()
+	insert := func(table *Table) {
+		r, err := dynparquet.GenerateTestSamples(100).ToRecord()
+		require.NoError(t, err)
+		defer r.Release()
+		_, err = table.InsertRecord(ctx, r)
+		require.NoError(t, err)
+	}
+
+	type result struct {
+		sum, count int64
+	}
+	aggregate := func(db *DB, table string, aggExprs ...logicalplan.Expr) map[int64]result {
+		res := map[int64]result{}
+		engine := query.NewEngine(memory.DefaultAllocator, db.TableProvider())
+		require.NoError(t, engine.ScanTable(table).
+			Aggregate(aggExprs, []logicalplan.Expr{logicalplan.Duration(10 * time.Millisecond)}).
+			Execute(ctx, func(_ context.Context, r arrow.Record) error {
+				for i := 0; i < int(r.NumRows()); i++ {
+					res[r.Column(0).(*array.Int64).Value(i)] = result{
+						sum:   r.Column(1).(*array.Int64).Value(i),
+						count: r.Column(2).(*array.Int64).Value(i),
+					}
+				}
+				return nil
+			}))
+		return res
+	}
+	compare := func(db *DB) {
+		expected := aggregate(db, "test",
+			logicalplan.Sum(logicalplan.Col("value")),
+			logicalplan.Count(logicalplan.Col("value")),
+		)
+		require.NotEmpty(t, expected)
+		require.Equal(t, expected, aggregate(db, "rollup",
+			logicalplan.Sum(logicalplan.Col("value_sum")),
+			logicalplan.Sum(logicalplan.Col("value_count")),
+		))
+	}
+
+	c, db := open()
+	table, err := db.Table("test", config)
+	require.NoError(t, err)
+	_, err = db.Table("rollup", viewConfig)
+	require.NoError(t, err)
+
+	insert(table)
+	insert(table)
+	compare(db)
+	require.NoError(t, c.Close())
+
+	// The view definition is replayed from the WAL and the view continues to
+	// be maintained.
+	c, db = open()
+	defer c.Close()
+	view, err := db.GetTable("rollup")
+	require.NoError(t, err)
+	require.True(t, proto.Equal(viewConfig.MaterializedView, view.config.Load().MaterializedView))
+	compare(db)
+
+	table, err = db.Table("test", config)
+	require.NoError(t, err)
+	insert(table)
+	compare(db)
+
+	// Empty records don't insert empty deltas into the view, so consecutive
+	// inserts only use the transactions of the source.
+	r, err := dynparquet.GenerateTestSamples(1).ToRecord()
+	require.NoError(t, err)
+	defer r.Release()
+	empty := r.NewSlice(0, 0)
+	defer empty.Release()
+	tx, err := table.InsertRecord(ctx, empty)
+	require.NoError(t, err)
+	next, err := table.InsertRecord(ctx, empty)
+	require.NoError(t, err)
+	require.Equal(t, tx+1, next)
+
+	// A delta the view fails to insert doesn't fail the insert into the
+	// source, and is inserted with its next delta.
+	setClosing := func(closing bool) {
+		view.mtx.Lock()
+		view.closing = closing
+		view.mtx.Unlock()
+	}
+	pending := func() int {
+		view.pendingViewMtx.Lock()
+		defer view.pendingViewMtx.Unlock()
+		return len(view.pendingViewRecords)
+	}
+	setClosing(true)
+	_, err = table.InsertRecord(ctx, r)
+	require.NoError(t, err)
+	require.Equal(t, 1, pending())
+	setClosing(false)
+	insert(table)
+	require.Zero(t, pending())
+	compare(db)
+
+	// Once the view keeps the most deltas it may, inserts into the source
+	// fail until it catches up.
+	setClosing(true)
+	view.pendingViewMtx.Lock()
+	for i := 0; i < maxPendingViewRecords; i++ {
+		empty.Retain()
+		view.pendingViewRecords = append(view.pendingViewRecords, empty)
+	}
+	view.pendingViewMtx.Unlock()
+	before := aggregate(db, "test", logicalplan.Sum(logicalplan.Col("value")), logicalplan.Count(logicalplan.Col("value")))
+	_, err = table.InsertRecord(ctx, r)
+	require.ErrorIs(t, err, ErrTableClosing)
+	require.Equal(t, before, aggregate(db, "test", logicalplan.Sum(logicalplan.Col("value")), logicalplan.Count(logicalplan.Col("value"))))
+	setClosing(false)
+	insert(table)
+	require.Zero(t, pending())
+	compare(db)
+
+	// Views also catch up when the block of their source is rotated. The
+	// rotated block isn't persisted without storage, so the view is ahead
+	// of the source afterwards.
+	setClosing(true)
+	insert(table)
+	require.Equal(t, 1, pending())
+	setClosing(false)
+	require.NoError(t, table.RotateBlock(ctx, table.ActiveBlock(), false))
+	require.Zero(t, pending())
+	require.Eventually(t, func() bool {
+		table.mtx.RLock()
+		defer table.mtx.RUnlock()
+		return len(table.pendingBlocks) == 0
+	}, time.Second, 10*time.Millisecond)
+
+	// Records the deltas of a view can't be computed for aren't inserted.
+	_, err = db.Table("broken", NewTableConfig(
+		viewConfig.GetDeprecatedSchema(),
+		WithMaterializedView(&tablepb.MaterializedView{
+			Source: "test",
+			Aggregations: []*tablepb.Aggregation{{
+				Function: tablepb.Aggregation_FUNCTION_SUM,
+				Column:   "missing",
+			}},
+		}),
+	))
+	require.NoError(t, err)
+	before = aggregate(db, "test", logicalplan.Sum(logicalplan.Col("value")), logicalplan.Count(logicalplan.Col("value")))
+	_, err = table.InsertRecord(ctx, r)
+	require.ErrorContains(t, err, "materialized view broken")
+	require.Equal(t, before, aggregate(db, "test", logicalplan.Sum(logicalplan.Col("value")), logicalplan.Count(logicalplan.Col("value"))))
+
+	// Views can't be views of themselves, directly or through other views.
+	viewOf := func(source string) *tablepb.TableConfig {
+		return NewTableConfig(
+			viewConfig.GetDeprecatedSchema(),
+			WithMaterializedView(&tablepb.MaterializedView{
+				Source:       source,
+				Aggregations: viewConfig.MaterializedView.Aggregations,
+				GroupBy:      viewConfig.MaterializedView.GroupBy,
+			}),
+		)
+	}
+	_, err = db.Table("self", viewOf("self"))
+	require.ErrorContains(t, err, "can't be its own source")
+	_, err = db.Table("first", viewOf("second"))
+	require.NoError(t, err)
+	_, err = db.Table("second", viewOf("rollup"))
+	require.NoError(t, err)
+	_, err = db.Table("rollup", viewOf("first"))
+	require.ErrorContains(t, err, "form a cycle")
+}
+
+func Test_DB_MergeAggregate(t *testing.T) {
+	config := NewTableConfig(
+		dynparquet.SampleDefinition(),
+	)
+
+	c, err := New(WithLogger(newTestLogger(t)))
+	require.NoError(t, err)
+	defer c.Close()
+	db, err := c.DB(context.Background(), "test")
+	require.NoError(t, err)
+
+	// Every source holds a part of the data, "all" holds all of it.
+	const numSources = 3
+	ctx := context.Background()
+	all, err := db.Table("all", config)
+	require.NoError(t, err)
+	for i := 0; i < numSources; i++ {
+		table, err := db.Table(fmt.Sprintf("source%d", i), config)
+		require.NoError(t, err)
+
+		r, err := dynparquet.GenerateTestSamples(10 * (i + 1)).ToRecord()
+		require.NoError(t, err)
+		_, err = table.InsertRecord(ctx, r)
+		require.NoError(t, err)
+		_, err = all.InsertRecord(ctx, r)
+		require.NoError(t, err)
+		r.Release()
+	}
+
+	pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer pool.AssertSize(t, 0)
+	engine := query.NewEngine(pool, db.TableProvider())
+	var rows []string
+	collect := func(_ context.Context, r arrow.Record) error {
+		for i := 0; i < int(r.NumRows()); i++ {
+			row := make([]string, 0, r.NumCols())
+			for j, f := range r.Schema().Fields() {
+				switch col := r.Column(j).(type) {
+				case *array.Float64:
+					if col.IsNull(i) {
+						row = append(row, f.Name+"=(null)")
+						continue
+					}
+					// Merged statistics may differ in their last digits.
+					row = append(row, f.Name+"="+strconv.FormatFloat(col.Value(i), 'g', 10, 64))
+				case *array.List:
+					// Histogram buckets are bounded by +Inf, which can't be
+					// formatted as JSON by ValueStr.
+					value := array.NewSlice(col, int64(i), int64(i+1))
+					row = append(row, f.Name+"="+value.String())
+					value.Release()
+				default:
+					row = append(row, f.Name+"="+col.ValueStr(i))
+				}
+			}
+			rows = append(rows, strings.Join(row, ","))
+		}
+		return nil
+	}
+
+	for _, tc := range []struct {
+		aggExprs   []logicalplan.Expr
+		groupExprs []logicalplan.Expr
+	}{{
+		aggExprs: []logicalplan.Expr{
+			logicalplan.Sum(logicalplan.Col("value")),
+			logicalplan.Min(logicalplan.Col("value")),
+			logicalplan.Max(logicalplan.Col("value")),
+			logicalplan.Count(logicalplan.Col("value")),
+		},
+		groupExprs: []logicalplan.Expr{logicalplan.Col("timestamp")},
+	}, {
+		aggExprs:   []logicalplan.Expr{logicalplan.Avg(logicalplan.Col("value"))},
+		groupExprs: []logicalplan.Expr{logicalplan.Col("timestamp")},
+	}, {
+		aggExprs: []logicalplan.Expr{
+			logicalplan.Sum(logicalplan.Col("value")),
+			logicalplan.Count(logicalplan.Col("value")),
+		},
+		groupExprs: []logicalplan.Expr{logicalplan.Duration(4 * time.Millisecond)},
+	}, {
+		aggExprs: []logicalplan.Expr{
+			logicalplan.First(logicalplan.Col("value"), logicalplan.Col("timestamp")),
+			logicalplan.Last(logicalplan.Col("value"), logicalplan.Col("timestamp")).Alias("last"),
+		},
+		groupExprs: []logicalplan.Expr{logicalplan.Duration(4 * time.Millisecond)},
+	}, {
+		aggExprs: []logicalplan.Expr{
+			logicalplan.VarPop(logicalplan.Col("value")),
+			logicalplan.StddevSamp(logicalplan.Col("value")),
+			logicalplan.CovarSamp(logicalplan.Col("value"), logicalplan.Col("timestamp")),
+			logicalplan.Corr(logicalplan.Col("value"), logicalplan.Col("timestamp")).Alias("corr"),
+			logicalplan.Histogram(logicalplan.Col("value"), logicalplan.ExponentialBuckets(1, 2, 4)),
+		},
+		groupExprs: []logicalplan.Expr{logicalplan.Duration(4 * time.Millisecond)},
+	}, {
+		aggExprs: []logicalplan.Expr{
+			logicalplan.Rate(logicalplan.Col("value"), logicalplan.Col("timestamp")),
+			logicalplan.Increase(logicalplan.Col("value"), logicalplan.Col("timestamp")).Alias("increase"),
+		},
+		groupExprs: []logicalplan.Expr{logicalplan.Duration(4 * time.Millisecond)},
+	}} {
+		aggregate := func(table string) query.ExtendedBuilder {
+			return engine.ScanTable(table).Aggregate(tc.aggExprs, tc.groupExprs).(query.ExtendedBuilder)
+		}
+
+		rows = nil
+		require.NoError(t, aggregate("all").Execute(ctx, collect))
+		expected := rows
+		require.NotEmpty(t, expected)
+		sort.Strings(expected)
+
+		partials := make([]array.RecordReader, 0, numSources)
+		for i := 0; i < numSources; i++ {
+			b := aggregate(fmt.Sprintf("source%d", i))
+			schema, err := b.PartialSchema()
+			require.NoError(t, err)
+
+			var records []arrow.Record
+			require.NoError(t, b.ExecutePartial(ctx, func(_ context.Context, r arrow.Record) error {
+				require.True(t, schema.Equal(r.Schema()), "%s != %s", schema, r.Schema())
+				r.Retain()
+				records = append(records, r)
+				return nil
+			}))
+			reader, err := array.NewRecordReader(schema, records)
+			require.NoError(t, err)
+			defer reader.Release()
+			for _, r := range records {
+				r.Release()
+			}
+			partials = append(partials, reader)
+		}
+
+		rows = nil
+		require.NoError(t, aggregate("all").MergeAggregate(ctx, partials, collect))
+		sort.Strings(rows)
+		require.Equal(t, expected, rows)
+	}
+
+	_, err = engine.ScanTable("all").Distinct(logicalplan.Col("timestamp")).(query.ExtendedBuilder).PartialSchema()
+	require.ErrorIs(t, err, query.ErrNotMergeable)
+}
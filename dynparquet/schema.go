@@ -35,6 +35,8 @@ type ColumnDefinition struct {
 	StorageLayout parquet.Node
 	Dynamic       bool
 	PreHash       bool
+	// BloomFilter indicates that a bloom filter is written for the column.
+	BloomFilter bool
 }
 
 // SortingColumn describes a column to sort by in a dynamic parquet schema.
@@ -160,6 +162,7 @@ func findLeavesFromNode(node *schemav2pb.Node) []ColumnDefinition {
 				Name:          n.Leaf.Name,
 				StorageLayout: ret,
 				Dynamic:       false, // TODO(can we get rid of dynamic cols): do we need dynamic columns to be separate?
+				BloomFilter:   n.Leaf.StorageLayout.GetBloomFilter(),
 			},
 		}
 	case *schemav2pb.Node_Group:
@@ -431,6 +434,7 @@ type StorageLayout interface {
 	GetNullable() bool
 	GetEncodingInt32() int32
 	GetCompressionInt32() int32
+	GetBloomFilter() bool
 }
 
 type v1storageLayoutWrapper struct {
@@ -453,6 +457,12 @@ func (s *v1storageLayoutWrapper) GetCompressionInt32() int32 {
 	return int32(s.StorageLayout.GetCompression())
 }
 
+// GetBloomFilter returns false as bloom filters can only be configured
+// per-column in v1alpha2 schemas.
+func (s *v1storageLayoutWrapper) GetBloomFilter() bool {
+	return false
+}
+
 type v2storageLayoutWrapper struct {
 	*schemav2pb.StorageLayout
 }
@@ -1236,27 +1246,10 @@ func (s *Schema) NewWriter(w io.Writer, dynamicColumns map[string][]string, sort
 	defer s.PutPooledParquetSchema(ps)
 
 	cols := s.ParquetSortingColumns(dynamicColumns)
-	bloomFilterColumns := make([]parquet.BloomFilterColumn, 0, len(cols))
-	for _, col := range cols {
-		// Don't add bloom filters to boolean columns
-		colName := strings.Split(col.Path()[0], ".")[0]
-		def, ok := s.ColumnByName(colName)
-		if !ok {
-			continue
-		}
-		if def.StorageLayout.Type().Kind() == parquet.Boolean {
-			continue
-		}
-
-		bloomFilterColumns = append(
-			bloomFilterColumns, parquet.SplitBlockFilter(bloomFilterBitsPerValue, col.Path()...),
-		)
-	}
-
 	writerOptions := []parquet.WriterOption{
 		ps.Schema,
 		parquet.ColumnIndexSizeLimit(ColumnIndexSize),
-		parquet.BloomFilters(bloomFilterColumns...),
+		parquet.BloomFilters(s.bloomFilterColumns(ps.Schema, cols)...),
 		parquet.KeyValueMetadata(
 			DynamicColumnsKey,
 			serializeDynamicColumns(dynamicColumns),
@@ -1271,6 +1264,89 @@ func (s *Schema) NewWriter(w io.Writer, dynamicColumns map[string][]string, sort
 	return parquet.NewGenericWriter[any](w, writerOptions...), nil
 }
 
+// bloomFilterColumns returns the bloom filters to write for the given concrete
+// parquet schema. Bloom filters are written for all sorting columns as well as
+// any column that explicitly enables them in its storage layout. Boolean
+// columns never get a bloom filter as their min/max statistics are already
+// exact.
+func (s *Schema) bloomFilterColumns(ps *parquet.Schema, sortingCols []parquet.SortingColumn) []parquet.BloomFilterColumn {
+	paths := make([][]string, 0, len(sortingCols))
+	seen := make(map[string]struct{}, len(sortingCols))
+	addPath := func(path []string) {
+		key := strings.Join(path, ".")
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+		paths = append(paths, path)
+	}
+
+	for _, col := range sortingCols {
+		addPath(col.Path())
+	}
+	for _, path := range ps.Columns() {
+		def, ok := s.columnDefinitionForPath(path)
+		if ok && def.BloomFilter {
+			addPath(path)
+		}
+	}
+
+	bloomFilterColumns := make([]parquet.BloomFilterColumn, 0, len(paths))
+	for _, path := range paths {
+		def, ok := s.columnDefinitionForPath(path)
+		if !ok {
+			continue
+		}
+		// Don't add bloom filters to boolean columns
+		if def.StorageLayout.Type().Kind() == parquet.Boolean {
+			continue
+		}
+
+		bloomFilterColumns = append(
+			bloomFilterColumns, parquet.SplitBlockFilter(bloomFilterBitsPerValue, path...),
+		)
+	}
+	return bloomFilterColumns
+}
+
+// columnDefinitionForPath returns the column definition of the leaf column
+// found at the given path of a concrete parquet schema. Top-level columns may
+// be concrete dynamic columns (e.g. "labels.label1") while nested columns are
+// resolved by following their full path through the groups of the schema, as
+// leaves of different groups may share a name.
+func (s *Schema) columnDefinitionForPath(path []string) (ColumnDefinition, bool) {
+	if len(path) == 1 {
+		return s.ColumnByName(strings.Split(path[0], ".")[0])
+	}
+	def, ok := s.def.(*schemav2pb.Schema)
+	if !ok {
+		return ColumnDefinition{}, false
+	}
+	nodes := def.Root.Nodes
+	for i, name := range path {
+		var next *schemav2pb.Node
+		for _, node := range nodes {
+			if nameFromNodeDef(node) == name {
+				next = node
+				break
+			}
+		}
+		if next == nil {
+			return ColumnDefinition{}, false
+		}
+		switch n := next.Type.(type) {
+		case *schemav2pb.Node_Group:
+			nodes = n.Group.Nodes
+		case *schemav2pb.Node_Leaf:
+			if i != len(path)-1 {
+				return ColumnDefinition{}, false
+			}
+			return findLeavesFromNode(next)[0], true
+		}
+	}
+	return ColumnDefinition{}, false
+}
+
 type ParquetWriter interface {
 	Schema() *parquet.Schema
 	Write(rows []any) (int, error)
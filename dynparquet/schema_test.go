@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	schemapb "github.com/polarsignals/frostdb/gen/proto/go/frostdb/schema/v1alpha1"
+	schemav2pb "github.com/polarsignals/frostdb/gen/proto/go/frostdb/schema/v1alpha2"
 )
 
 func TestMergeRowBatches(t *testing.T) {
@@ -285,6 +286,106 @@ func Test_SchemaFromParquetFile(t *testing.T) {
 	require.Equal(t, SampleDefinition(), def)
 }
 
+func Test_Schema_BloomFilterColumns(t *testing.T) {
+	leaf := func(name string, typ schemav2pb.StorageLayout_Type, bloomFilter bool) *schemav2pb.Node {
+		return &schemav2pb.Node{
+			Type: &schemav2pb.Node_Leaf{
+				Leaf: &schemav2pb.Leaf{
+					Name: name,
+					StorageLayout: &schemav2pb.StorageLayout{
+						Type:        typ,
+						BloomFilter: bloomFilter,
+					},
+				},
+			},
+		}
+	}
+	schema, err := SchemaFromDefinition(&schemav2pb.Schema{
+		Root: &schemav2pb.Group{
+			Name: "bloom",
+			Nodes: []*schemav2pb.Node{
+				leaf("id", schemav2pb.StorageLayout_TYPE_STRING, true),
+				leaf("timestamp", schemav2pb.StorageLayout_TYPE_INT64, false),
+				leaf("value", schemav2pb.StorageLayout_TYPE_INT64, false),
+			},
+		},
+		SortingColumns: []*schemav2pb.SortingColumn{
+			{
+				Path:      "timestamp",
+				Direction: schemav2pb.SortingColumn_DIRECTION_ASCENDING,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	def, ok := schema.ColumnByName("id")
+	require.True(t, ok)
+	require.True(t, def.BloomFilter)
+
+	b := bytes.NewBuffer(nil)
+	w, err := schema.NewWriter(b, nil, false)
+	require.NoError(t, err)
+	_, err = w.WriteRows([]parquet.Row{
+		{
+			parquet.ValueOf("a").Level(0, 0, 0),
+			parquet.ValueOf(int64(1)).Level(0, 0, 1),
+			parquet.ValueOf(int64(1)).Level(0, 0, 2),
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	file, err := parquet.OpenFile(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	require.NoError(t, err)
+
+	chunks := file.RowGroups()[0].ColumnChunks()
+	require.NotNil(t, chunks[0].BloomFilter(), "id opted into a bloom filter")
+	require.NotNil(t, chunks[1].BloomFilter(), "timestamp is a sorting column")
+	require.Nil(t, chunks[2].BloomFilter(), "value has no bloom filter")
+
+	ok, err = chunks[0].BloomFilter().Check(parquet.ValueOf("a"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = chunks[0].BloomFilter().Check(parquet.ValueOf("b"))
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func Test_Schema_BloomFilterColumnsNested(t *testing.T) {
+	group := func(name string, bloomFilter bool) *schemav2pb.Node {
+		return &schemav2pb.Node{
+			Type: &schemav2pb.Node_Group{
+				Group: &schemav2pb.Group{
+					Name: name,
+					Nodes: []*schemav2pb.Node{{
+						Type: &schemav2pb.Node_Leaf{
+							Leaf: &schemav2pb.Leaf{
+								Name: "id",
+								StorageLayout: &schemav2pb.StorageLayout{
+									Type:        schemav2pb.StorageLayout_TYPE_STRING,
+									BloomFilter: bloomFilter,
+								},
+							},
+						},
+					}},
+				},
+			},
+		}
+	}
+	schema, err := SchemaFromDefinition(&schemav2pb.Schema{
+		Root: &schemav2pb.Group{
+			Name:  "nested",
+			Nodes: []*schemav2pb.Node{group("a", false), group("b", true)},
+		},
+	})
+	require.NoError(t, err)
+
+	// Both groups have an id leaf, but only b.id opted into a bloom filter.
+	columns := schema.bloomFilterColumns(schema.ParquetSchema(), nil)
+	require.Len(t, columns, 1)
+	require.Equal(t, []string{"b", "id"}, columns[0].Path())
+}
+
 func TestIsDynamicColumn(t *testing.T) {
 	for _, tc := range []struct {
 		input      string
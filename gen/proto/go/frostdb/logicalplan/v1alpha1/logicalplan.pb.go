@@ -0,0 +1,3559 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        (unknown)
+// source: frostdb/logicalplan/v1alpha1/logicalplan.proto
+
+package logicalplanv1alpha1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Method is the method used to sample the rows.
+type Sample_Method int32
+
+const (
+	// METHOD_UNKNOWN_UNSPECIFIED is the default value and is invalid.
+	Sample_METHOD_UNKNOWN_UNSPECIFIED Sample_Method = 0
+	// METHOD_BERNOULLI samples every row independently.
+	Sample_METHOD_BERNOULLI Sample_Method = 1
+	// METHOD_SYSTEM samples whole row groups.
+	Sample_METHOD_SYSTEM Sample_Method = 2
+)
+
+// Enum value maps for Sample_Method.
+var (
+	Sample_Method_name = map[int32]string{
+		0: "METHOD_UNKNOWN_UNSPECIFIED",
+		1: "METHOD_BERNOULLI",
+		2: "METHOD_SYSTEM",
+	}
+	Sample_Method_value = map[string]int32{
+		"METHOD_UNKNOWN_UNSPECIFIED": 0,
+		"METHOD_BERNOULLI":           1,
+		"METHOD_SYSTEM":              2,
+	}
+)
+
+func (x Sample_Method) Enum() *Sample_Method {
+	p := new(Sample_Method)
+	*p = x
+	return p
+}
+
+func (x Sample_Method) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Sample_Method) Descriptor() protoreflect.EnumDescriptor {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_enumTypes[0].Descriptor()
+}
+
+func (Sample_Method) Type() protoreflect.EnumType {
+	return &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_enumTypes[0]
+}
+
+func (x Sample_Method) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Sample_Method.Descriptor instead.
+func (Sample_Method) EnumDescriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{8, 0}
+}
+
+// Fill is how the values of missing buckets are filled.
+type GapFill_Fill int32
+
+const (
+	// FILL_UNKNOWN_UNSPECIFIED is the default value and is invalid.
+	GapFill_FILL_UNKNOWN_UNSPECIFIED GapFill_Fill = 0
+	// FILL_NULL fills the values with nulls.
+	GapFill_FILL_NULL GapFill_Fill = 1
+	// FILL_PREVIOUS carries the values of the previous bucket forward.
+	GapFill_FILL_PREVIOUS GapFill_Fill = 2
+	// FILL_LINEAR interpolates linearly between the surrounding buckets.
+	GapFill_FILL_LINEAR GapFill_Fill = 3
+)
+
+// Enum value maps for GapFill_Fill.
+var (
+	GapFill_Fill_name = map[int32]string{
+		0: "FILL_UNKNOWN_UNSPECIFIED",
+		1: "FILL_NULL",
+		2: "FILL_PREVIOUS",
+		3: "FILL_LINEAR",
+	}
+	GapFill_Fill_value = map[string]int32{
+		"FILL_UNKNOWN_UNSPECIFIED": 0,
+		"FILL_NULL":                1,
+		"FILL_PREVIOUS":            2,
+		"FILL_LINEAR":              3,
+	}
+)
+
+func (x GapFill_Fill) Enum() *GapFill_Fill {
+	p := new(GapFill_Fill)
+	*p = x
+	return p
+}
+
+func (x GapFill_Fill) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GapFill_Fill) Descriptor() protoreflect.EnumDescriptor {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_enumTypes[1].Descriptor()
+}
+
+func (GapFill_Fill) Type() protoreflect.EnumType {
+	return &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_enumTypes[1]
+}
+
+func (x GapFill_Fill) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GapFill_Fill.Descriptor instead.
+func (GapFill_Fill) EnumDescriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{9, 0}
+}
+
+// Op is an operator.
+type BinaryExpr_Op int32
+
+const (
+	// OP_UNKNOWN_UNSPECIFIED is the default value and is invalid.
+	BinaryExpr_OP_UNKNOWN_UNSPECIFIED BinaryExpr_Op = 0
+	// OP_EQ is the equality operator.
+	BinaryExpr_OP_EQ BinaryExpr_Op = 1
+	// OP_NOT_EQ is the inequality operator.
+	BinaryExpr_OP_NOT_EQ BinaryExpr_Op = 2
+	// OP_LT is the less than operator.
+	BinaryExpr_OP_LT BinaryExpr_Op = 3
+	// OP_LT_EQ is the less than or equal operator.
+	BinaryExpr_OP_LT_EQ BinaryExpr_Op = 4
+	// OP_GT is the greater than operator.
+	BinaryExpr_OP_GT BinaryExpr_Op = 5
+	// OP_GT_EQ is the greater than or equal operator.
+	BinaryExpr_OP_GT_EQ BinaryExpr_Op = 6
+	// OP_REGEX_MATCH is the regular expression match operator.
+	BinaryExpr_OP_REGEX_MATCH BinaryExpr_Op = 7
+	// OP_REGEX_NOT_MATCH is the negated regular expression match operator.
+	BinaryExpr_OP_REGEX_NOT_MATCH BinaryExpr_Op = 8
+	// OP_AND is the logical and operator.
+	BinaryExpr_OP_AND BinaryExpr_Op = 9
+	// OP_OR is the logical or operator.
+	BinaryExpr_OP_OR BinaryExpr_Op = 10
+)
+
+// Enum value maps for BinaryExpr_Op.
+var (
+	BinaryExpr_Op_name = map[int32]string{
+		0:  "OP_UNKNOWN_UNSPECIFIED",
+		1:  "OP_EQ",
+		2:  "OP_NOT_EQ",
+		3:  "OP_LT",
+		4:  "OP_LT_EQ",
+		5:  "OP_GT",
+		6:  "OP_GT_EQ",
+		7:  "OP_REGEX_MATCH",
+		8:  "OP_REGEX_NOT_MATCH",
+		9:  "OP_AND",
+		10: "OP_OR",
+	}
+	BinaryExpr_Op_value = map[string]int32{
+		"OP_UNKNOWN_UNSPECIFIED": 0,
+		"OP_EQ":                  1,
+		"OP_NOT_EQ":              2,
+		"OP_LT":                  3,
+		"OP_LT_EQ":               4,
+		"OP_GT":                  5,
+		"OP_GT_EQ":               6,
+		"OP_REGEX_MATCH":         7,
+		"OP_REGEX_NOT_MATCH":     8,
+		"OP_AND":                 9,
+		"OP_OR":                  10,
+	}
+)
+
+func (x BinaryExpr_Op) Enum() *BinaryExpr_Op {
+	p := new(BinaryExpr_Op)
+	*p = x
+	return p
+}
+
+func (x BinaryExpr_Op) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BinaryExpr_Op) Descriptor() protoreflect.EnumDescriptor {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_enumTypes[2].Descriptor()
+}
+
+func (BinaryExpr_Op) Type() protoreflect.EnumType {
+	return &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_enumTypes[2]
+}
+
+func (x BinaryExpr_Op) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BinaryExpr_Op.Descriptor instead.
+func (BinaryExpr_Op) EnumDescriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{14, 0}
+}
+
+// Type is the type of aggregation function.
+type AggregationFunction_Type int32
+
+const (
+	// TYPE_UNKNOWN_UNSPECIFIED is the default value and is invalid.
+	AggregationFunction_TYPE_UNKNOWN_UNSPECIFIED AggregationFunction_Type = 0
+	// TYPE_SUM sums the values.
+	AggregationFunction_TYPE_SUM AggregationFunction_Type = 1
+	// TYPE_MIN is the minimum of the values.
+	AggregationFunction_TYPE_MIN AggregationFunction_Type = 2
+	// TYPE_MAX is the maximum of the values.
+	AggregationFunction_TYPE_MAX AggregationFunction_Type = 3
+	// TYPE_COUNT counts the values.
+	AggregationFunction_TYPE_COUNT AggregationFunction_Type = 4
+	// TYPE_AVG is the average of the values.
+	AggregationFunction_TYPE_AVG AggregationFunction_Type = 5
+	// TYPE_FIRST is the value with the lowest order by value.
+	AggregationFunction_TYPE_FIRST AggregationFunction_Type = 6
+	// TYPE_LAST is the value with the highest order by value.
+	AggregationFunction_TYPE_LAST AggregationFunction_Type = 7
+	// TYPE_STDDEV_POP is the population standard deviation of the values.
+	AggregationFunction_TYPE_STDDEV_POP AggregationFunction_Type = 8
+	// TYPE_STDDEV_SAMP is the sample standard deviation of the values.
+	AggregationFunction_TYPE_STDDEV_SAMP AggregationFunction_Type = 9
+	// TYPE_VAR_POP is the population variance of the values.
+	AggregationFunction_TYPE_VAR_POP AggregationFunction_Type = 10
+	// TYPE_VAR_SAMP is the sample variance of the values.
+	AggregationFunction_TYPE_VAR_SAMP AggregationFunction_Type = 11
+	// TYPE_COVAR_POP is the population covariance of the pairs of values.
+	AggregationFunction_TYPE_COVAR_POP AggregationFunction_Type = 12
+	// TYPE_COVAR_SAMP is the sample covariance of the pairs of values.
+	AggregationFunction_TYPE_COVAR_SAMP AggregationFunction_Type = 13
+	// TYPE_CORR is the correlation coefficient of the pairs of values.
+	AggregationFunction_TYPE_CORR AggregationFunction_Type = 14
+	// TYPE_HISTOGRAM counts the values falling into each bucket.
+	AggregationFunction_TYPE_HISTOGRAM AggregationFunction_Type = 15
+	// TYPE_RATE is the per-second rate a counter increased at.
+	AggregationFunction_TYPE_RATE AggregationFunction_Type = 16
+	// TYPE_INCREASE is how much a counter increased.
+	AggregationFunction_TYPE_INCREASE AggregationFunction_Type = 17
+)
+
+// Enum value maps for AggregationFunction_Type.
+var (
+	AggregationFunction_Type_name = map[int32]string{
+		0:  "TYPE_UNKNOWN_UNSPECIFIED",
+		1:  "TYPE_SUM",
+		2:  "TYPE_MIN",
+		3:  "TYPE_MAX",
+		4:  "TYPE_COUNT",
+		5:  "TYPE_AVG",
+		6:  "TYPE_FIRST",
+		7:  "TYPE_LAST",
+		8:  "TYPE_STDDEV_POP",
+		9:  "TYPE_STDDEV_SAMP",
+		10: "TYPE_VAR_POP",
+		11: "TYPE_VAR_SAMP",
+		12: "TYPE_COVAR_POP",
+		13: "TYPE_COVAR_SAMP",
+		14: "TYPE_CORR",
+		15: "TYPE_HISTOGRAM",
+		16: "TYPE_RATE",
+		17: "TYPE_INCREASE",
+	}
+	AggregationFunction_Type_value = map[string]int32{
+		"TYPE_UNKNOWN_UNSPECIFIED": 0,
+		"TYPE_SUM":                 1,
+		"TYPE_MIN":                 2,
+		"TYPE_MAX":                 3,
+		"TYPE_COUNT":               4,
+		"TYPE_AVG":                 5,
+		"TYPE_FIRST":               6,
+		"TYPE_LAST":                7,
+		"TYPE_STDDEV_POP":          8,
+		"TYPE_STDDEV_SAMP":         9,
+		"TYPE_VAR_POP":             10,
+		"TYPE_VAR_SAMP":            11,
+		"TYPE_COVAR_POP":           12,
+		"TYPE_COVAR_SAMP":          13,
+		"TYPE_CORR":                14,
+		"TYPE_HISTOGRAM":           15,
+		"TYPE_RATE":                16,
+		"TYPE_INCREASE":            17,
+	}
+)
+
+func (x AggregationFunction_Type) Enum() *AggregationFunction_Type {
+	p := new(AggregationFunction_Type)
+	*p = x
+	return p
+}
+
+func (x AggregationFunction_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AggregationFunction_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_enumTypes[3].Descriptor()
+}
+
+func (AggregationFunction_Type) Type() protoreflect.EnumType {
+	return &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_enumTypes[3]
+}
+
+func (x AggregationFunction_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AggregationFunction_Type.Descriptor instead.
+func (AggregationFunction_Type) EnumDescriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{19, 0}
+}
+
+// PlanNode is a node of a logical plan.
+type PlanNode struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Input is the node this node reads from.
+	Input *PlanNode `protobuf:"bytes,1,opt,name=input,proto3" json:"input,omitempty"`
+	// Spec is the operation of the node.
+	//
+	// Types that are assignable to Spec:
+	//
+	//	*PlanNode_TableScan
+	//	*PlanNode_SchemaScan
+	//	*PlanNode_Filter
+	//	*PlanNode_Distinct
+	//	*PlanNode_Projection
+	//	*PlanNode_Aggregation
+	//	*PlanNode_Unnest
+	//	*PlanNode_Sample
+	//	*PlanNode_GapFill
+	//	*PlanNode_Pivot
+	//	*PlanNode_Unpivot
+	//	*PlanNode_SemiJoin
+	Spec isPlanNode_Spec `protobuf_oneof:"spec"`
+}
+
+func (x *PlanNode) Reset() {
+	*x = PlanNode{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlanNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanNode) ProtoMessage() {}
+
+func (x *PlanNode) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanNode.ProtoReflect.Descriptor instead.
+func (*PlanNode) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PlanNode) GetInput() *PlanNode {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (m *PlanNode) GetSpec() isPlanNode_Spec {
+	if m != nil {
+		return m.Spec
+	}
+	return nil
+}
+
+func (x *PlanNode) GetTableScan() *TableScan {
+	if x, ok := x.GetSpec().(*PlanNode_TableScan); ok {
+		return x.TableScan
+	}
+	return nil
+}
+
+func (x *PlanNode) GetSchemaScan() *SchemaScan {
+	if x, ok := x.GetSpec().(*PlanNode_SchemaScan); ok {
+		return x.SchemaScan
+	}
+	return nil
+}
+
+func (x *PlanNode) GetFilter() *Filter {
+	if x, ok := x.GetSpec().(*PlanNode_Filter); ok {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *PlanNode) GetDistinct() *Distinct {
+	if x, ok := x.GetSpec().(*PlanNode_Distinct); ok {
+		return x.Distinct
+	}
+	return nil
+}
+
+func (x *PlanNode) GetProjection() *Projection {
+	if x, ok := x.GetSpec().(*PlanNode_Projection); ok {
+		return x.Projection
+	}
+	return nil
+}
+
+func (x *PlanNode) GetAggregation() *Aggregation {
+	if x, ok := x.GetSpec().(*PlanNode_Aggregation); ok {
+		return x.Aggregation
+	}
+	return nil
+}
+
+func (x *PlanNode) GetUnnest() *Unnest {
+	if x, ok := x.GetSpec().(*PlanNode_Unnest); ok {
+		return x.Unnest
+	}
+	return nil
+}
+
+func (x *PlanNode) GetSample() *Sample {
+	if x, ok := x.GetSpec().(*PlanNode_Sample); ok {
+		return x.Sample
+	}
+	return nil
+}
+
+func (x *PlanNode) GetGapFill() *GapFill {
+	if x, ok := x.GetSpec().(*PlanNode_GapFill); ok {
+		return x.GapFill
+	}
+	return nil
+}
+
+func (x *PlanNode) GetPivot() *Pivot {
+	if x, ok := x.GetSpec().(*PlanNode_Pivot); ok {
+		return x.Pivot
+	}
+	return nil
+}
+
+func (x *PlanNode) GetUnpivot() *Unpivot {
+	if x, ok := x.GetSpec().(*PlanNode_Unpivot); ok {
+		return x.Unpivot
+	}
+	return nil
+}
+
+func (x *PlanNode) GetSemiJoin() *SemiJoin {
+	if x, ok := x.GetSpec().(*PlanNode_SemiJoin); ok {
+		return x.SemiJoin
+	}
+	return nil
+}
+
+type isPlanNode_Spec interface {
+	isPlanNode_Spec()
+}
+
+type PlanNode_TableScan struct {
+	// TableScan scans the data of a table.
+	TableScan *TableScan `protobuf:"bytes,2,opt,name=table_scan,json=tableScan,proto3,oneof"`
+}
+
+type PlanNode_SchemaScan struct {
+	// SchemaScan scans the schema of a table.
+	SchemaScan *SchemaScan `protobuf:"bytes,3,opt,name=schema_scan,json=schemaScan,proto3,oneof"`
+}
+
+type PlanNode_Filter struct {
+	// Filter filters the rows of the input.
+	Filter *Filter `protobuf:"bytes,4,opt,name=filter,proto3,oneof"`
+}
+
+type PlanNode_Distinct struct {
+	// Distinct deduplicates the rows of the input.
+	Distinct *Distinct `protobuf:"bytes,5,opt,name=distinct,proto3,oneof"`
+}
+
+type PlanNode_Projection struct {
+	// Projection projects the columns of the input.
+	Projection *Projection `protobuf:"bytes,6,opt,name=projection,proto3,oneof"`
+}
+
+type PlanNode_Aggregation struct {
+	// Aggregation aggregates the rows of the input.
+	Aggregation *Aggregation `protobuf:"bytes,7,opt,name=aggregation,proto3,oneof"`
+}
+
+type PlanNode_Unnest struct {
+	// Unnest flattens a list column of the input.
+	Unnest *Unnest `protobuf:"bytes,8,opt,name=unnest,proto3,oneof"`
+}
+
+type PlanNode_Sample struct {
+	// Sample samples the rows of the input.
+	Sample *Sample `protobuf:"bytes,9,opt,name=sample,proto3,oneof"`
+}
+
+type PlanNode_GapFill struct {
+	// GapFill fills in the buckets missing from the aggregation of the input.
+	GapFill *GapFill `protobuf:"bytes,10,opt,name=gap_fill,json=gapFill,proto3,oneof"`
+}
+
+type PlanNode_Pivot struct {
+	// Pivot turns the values of a column of the input into columns.
+	Pivot *Pivot `protobuf:"bytes,11,opt,name=pivot,proto3,oneof"`
+}
+
+type PlanNode_Unpivot struct {
+	// Unpivot turns columns of the input into rows of keys and values.
+	Unpivot *Unpivot `protobuf:"bytes,12,opt,name=unpivot,proto3,oneof"`
+}
+
+type PlanNode_SemiJoin struct {
+	// SemiJoin filters the rows of the input by the results of an inner plan.
+	SemiJoin *SemiJoin `protobuf:"bytes,13,opt,name=semi_join,json=semiJoin,proto3,oneof"`
+}
+
+func (*PlanNode_TableScan) isPlanNode_Spec() {}
+
+func (*PlanNode_SchemaScan) isPlanNode_Spec() {}
+
+func (*PlanNode_Filter) isPlanNode_Spec() {}
+
+func (*PlanNode_Distinct) isPlanNode_Spec() {}
+
+func (*PlanNode_Projection) isPlanNode_Spec() {}
+
+func (*PlanNode_Aggregation) isPlanNode_Spec() {}
+
+func (*PlanNode_Unnest) isPlanNode_Spec() {}
+
+func (*PlanNode_Sample) isPlanNode_Spec() {}
+
+func (*PlanNode_GapFill) isPlanNode_Spec() {}
+
+func (*PlanNode_Pivot) isPlanNode_Spec() {}
+
+func (*PlanNode_Unpivot) isPlanNode_Spec() {}
+
+func (*PlanNode_SemiJoin) isPlanNode_Spec() {}
+
+// TableScan scans the data of a table.
+type TableScan struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// TableName is the name of the table. It is resolved by the receiver of the plan.
+	TableName string `protobuf:"bytes,1,opt,name=table_name,json=tableName,proto3" json:"table_name,omitempty"`
+	// PhysicalProjection describes the columns that are physically read.
+	PhysicalProjection []*Expr `protobuf:"bytes,2,rep,name=physical_projection,json=physicalProjection,proto3" json:"physical_projection,omitempty"`
+	// Filter is the predicate used to rule out data that is scanned.
+	Filter *Expr `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Distinct describes the columns that are distinct.
+	Distinct []*Expr `protobuf:"bytes,4,rep,name=distinct,proto3" json:"distinct,omitempty"`
+	// Projection is the list of columns that are projected.
+	Projection []*Expr `protobuf:"bytes,5,rep,name=projection,proto3" json:"projection,omitempty"`
+	// SkipSources indicates to skip scanning the table's sources.
+	SkipSources bool `protobuf:"varint,6,opt,name=skip_sources,json=skipSources,proto3" json:"skip_sources,omitempty"`
+	// PersistedBefore indicates to only scan the blocks of the table's sources created before the timestamp.
+	PersistedBefore uint64 `protobuf:"varint,7,opt,name=persisted_before,json=persistedBefore,proto3" json:"persisted_before,omitempty"`
+	// MetadataAggregations are the ungrouped aggregations computed from the metadata of the table's data.
+	MetadataAggregations []*Expr `protobuf:"bytes,8,rep,name=metadata_aggregations,json=metadataAggregations,proto3" json:"metadata_aggregations,omitempty"`
+	// Sample restricts the scan to a random fraction of the row groups.
+	Sample *Sample `protobuf:"bytes,9,opt,name=sample,proto3" json:"sample,omitempty"`
+}
+
+func (x *TableScan) Reset() {
+	*x = TableScan{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TableScan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TableScan) ProtoMessage() {}
+
+func (x *TableScan) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TableScan.ProtoReflect.Descriptor instead.
+func (*TableScan) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TableScan) GetTableName() string {
+	if x != nil {
+		return x.TableName
+	}
+	return ""
+}
+
+func (x *TableScan) GetPhysicalProjection() []*Expr {
+	if x != nil {
+		return x.PhysicalProjection
+	}
+	return nil
+}
+
+func (x *TableScan) GetFilter() *Expr {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *TableScan) GetDistinct() []*Expr {
+	if x != nil {
+		return x.Distinct
+	}
+	return nil
+}
+
+func (x *TableScan) GetProjection() []*Expr {
+	if x != nil {
+		return x.Projection
+	}
+	return nil
+}
+
+func (x *TableScan) GetSkipSources() bool {
+	if x != nil {
+		return x.SkipSources
+	}
+	return false
+}
+
+func (x *TableScan) GetPersistedBefore() uint64 {
+	if x != nil {
+		return x.PersistedBefore
+	}
+	return 0
+}
+
+func (x *TableScan) GetMetadataAggregations() []*Expr {
+	if x != nil {
+		return x.MetadataAggregations
+	}
+	return nil
+}
+
+func (x *TableScan) GetSample() *Sample {
+	if x != nil {
+		return x.Sample
+	}
+	return nil
+}
+
+// SchemaScan scans the schema of a table.
+type SchemaScan struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// TableName is the name of the table. It is resolved by the receiver of the plan.
+	TableName string `protobuf:"bytes,1,opt,name=table_name,json=tableName,proto3" json:"table_name,omitempty"`
+	// PhysicalProjection describes the columns that are physically read.
+	PhysicalProjection []*Expr `protobuf:"bytes,2,rep,name=physical_projection,json=physicalProjection,proto3" json:"physical_projection,omitempty"`
+	// Filter is the predicate used to rule out data that is scanned.
+	Filter *Expr `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Distinct describes the columns that are distinct.
+	Distinct []*Expr `protobuf:"bytes,4,rep,name=distinct,proto3" json:"distinct,omitempty"`
+	// Projection is the list of columns that are projected.
+	Projection []*Expr `protobuf:"bytes,5,rep,name=projection,proto3" json:"projection,omitempty"`
+	// SkipSources indicates to skip scanning the table's sources.
+	SkipSources bool `protobuf:"varint,6,opt,name=skip_sources,json=skipSources,proto3" json:"skip_sources,omitempty"`
+}
+
+func (x *SchemaScan) Reset() {
+	*x = SchemaScan{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SchemaScan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchemaScan) ProtoMessage() {}
+
+func (x *SchemaScan) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchemaScan.ProtoReflect.Descriptor instead.
+func (*SchemaScan) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SchemaScan) GetTableName() string {
+	if x != nil {
+		return x.TableName
+	}
+	return ""
+}
+
+func (x *SchemaScan) GetPhysicalProjection() []*Expr {
+	if x != nil {
+		return x.PhysicalProjection
+	}
+	return nil
+}
+
+func (x *SchemaScan) GetFilter() *Expr {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *SchemaScan) GetDistinct() []*Expr {
+	if x != nil {
+		return x.Distinct
+	}
+	return nil
+}
+
+func (x *SchemaScan) GetProjection() []*Expr {
+	if x != nil {
+		return x.Projection
+	}
+	return nil
+}
+
+func (x *SchemaScan) GetSkipSources() bool {
+	if x != nil {
+		return x.SkipSources
+	}
+	return false
+}
+
+// Filter filters the rows of the input.
+type Filter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Expr is the predicate rows have to match.
+	Expr *Expr `protobuf:"bytes,1,opt,name=expr,proto3" json:"expr,omitempty"`
+}
+
+func (x *Filter) Reset() {
+	*x = Filter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Filter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Filter) ProtoMessage() {}
+
+func (x *Filter) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Filter.ProtoReflect.Descriptor instead.
+func (*Filter) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Filter) GetExpr() *Expr {
+	if x != nil {
+		return x.Expr
+	}
+	return nil
+}
+
+// Distinct deduplicates the rows of the input.
+type Distinct struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Exprs are the expressions whose values are deduplicated.
+	Exprs []*Expr `protobuf:"bytes,1,rep,name=exprs,proto3" json:"exprs,omitempty"`
+	// Limit, if set, is the number of distinct values after which the input stops being read.
+	Limit uint64 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *Distinct) Reset() {
+	*x = Distinct{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Distinct) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Distinct) ProtoMessage() {}
+
+func (x *Distinct) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Distinct.ProtoReflect.Descriptor instead.
+func (*Distinct) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Distinct) GetExprs() []*Expr {
+	if x != nil {
+		return x.Exprs
+	}
+	return nil
+}
+
+func (x *Distinct) GetLimit() uint64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// Projection projects the columns of the input.
+type Projection struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Exprs are the projected expressions.
+	Exprs []*Expr `protobuf:"bytes,1,rep,name=exprs,proto3" json:"exprs,omitempty"`
+}
+
+func (x *Projection) Reset() {
+	*x = Projection{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Projection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Projection) ProtoMessage() {}
+
+func (x *Projection) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Projection.ProtoReflect.Descriptor instead.
+func (*Projection) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Projection) GetExprs() []*Expr {
+	if x != nil {
+		return x.Exprs
+	}
+	return nil
+}
+
+// Aggregation aggregates the rows of the input.
+type Aggregation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// AggExprs are the aggregations.
+	AggExprs []*Expr `protobuf:"bytes,1,rep,name=agg_exprs,json=aggExprs,proto3" json:"agg_exprs,omitempty"`
+	// GroupExprs are the expressions the rows are grouped by.
+	GroupExprs []*Expr `protobuf:"bytes,2,rep,name=group_exprs,json=groupExprs,proto3" json:"group_exprs,omitempty"`
+}
+
+func (x *Aggregation) Reset() {
+	*x = Aggregation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Aggregation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Aggregation) ProtoMessage() {}
+
+func (x *Aggregation) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Aggregation.ProtoReflect.Descriptor instead.
+func (*Aggregation) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Aggregation) GetAggExprs() []*Expr {
+	if x != nil {
+		return x.AggExprs
+	}
+	return nil
+}
+
+func (x *Aggregation) GetGroupExprs() []*Expr {
+	if x != nil {
+		return x.GroupExprs
+	}
+	return nil
+}
+
+// Unnest flattens a list column into a row per list element.
+type Unnest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Expr is the list column.
+	Expr *Expr `protobuf:"bytes,1,opt,name=expr,proto3" json:"expr,omitempty"`
+}
+
+func (x *Unnest) Reset() {
+	*x = Unnest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Unnest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Unnest) ProtoMessage() {}
+
+func (x *Unnest) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Unnest.ProtoReflect.Descriptor instead.
+func (*Unnest) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Unnest) GetExpr() *Expr {
+	if x != nil {
+		return x.Expr
+	}
+	return nil
+}
+
+// Sample samples a random fraction of the rows of a table.
+type Sample struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Method is the method used to sample the rows.
+	Method Sample_Method `protobuf:"varint,1,opt,name=method,proto3,enum=frostdb.logicalplan.v1alpha1.Sample_Method" json:"method,omitempty"`
+	// Fraction is the fraction of the rows that are sampled, in (0, 1].
+	Fraction float64 `protobuf:"fixed64,2,opt,name=fraction,proto3" json:"fraction,omitempty"`
+	// Seed makes the sample repeatable if non-zero.
+	Seed int64 `protobuf:"varint,3,opt,name=seed,proto3" json:"seed,omitempty"`
+	// ScaleAggregations scales the results of sum and count aggregations of the sample up to the full data.
+	ScaleAggregations bool `protobuf:"varint,4,opt,name=scale_aggregations,json=scaleAggregations,proto3" json:"scale_aggregations,omitempty"`
+}
+
+func (x *Sample) Reset() {
+	*x = Sample{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Sample) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sample) ProtoMessage() {}
+
+func (x *Sample) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sample.ProtoReflect.Descriptor instead.
+func (*Sample) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Sample) GetMethod() Sample_Method {
+	if x != nil {
+		return x.Method
+	}
+	return Sample_METHOD_UNKNOWN_UNSPECIFIED
+}
+
+func (x *Sample) GetFraction() float64 {
+	if x != nil {
+		return x.Fraction
+	}
+	return 0
+}
+
+func (x *Sample) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *Sample) GetScaleAggregations() bool {
+	if x != nil {
+		return x.ScaleAggregations
+	}
+	return false
+}
+
+// GapFill fills in the buckets missing from an aggregation grouped by a duration.
+type GapFill struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Start is the start of the filled time range in milliseconds, inclusive.
+	Start int64 `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	// End is the end of the filled time range in milliseconds, exclusive.
+	End int64 `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	// Step is the duration of the buckets.
+	Step *Duration `protobuf:"bytes,3,opt,name=step,proto3" json:"step,omitempty"`
+	// Fill is how the values of missing buckets are filled.
+	Fill GapFill_Fill `protobuf:"varint,4,opt,name=fill,proto3,enum=frostdb.logicalplan.v1alpha1.GapFill_Fill" json:"fill,omitempty"`
+}
+
+func (x *GapFill) Reset() {
+	*x = GapFill{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GapFill) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GapFill) ProtoMessage() {}
+
+func (x *GapFill) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GapFill.ProtoReflect.Descriptor instead.
+func (*GapFill) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GapFill) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *GapFill) GetEnd() int64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *GapFill) GetStep() *Duration {
+	if x != nil {
+		return x.Step
+	}
+	return nil
+}
+
+func (x *GapFill) GetFill() GapFill_Fill {
+	if x != nil {
+		return x.Fill
+	}
+	return GapFill_FILL_UNKNOWN_UNSPECIFIED
+}
+
+// Pivot turns the values of the key column into columns holding the values of the value column.
+type Pivot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Key is the column whose values become columns.
+	Key *Expr `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Value is the column whose values fill the pivoted columns.
+	Value *Expr `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// Prefix is prepended to the names of the pivoted columns.
+	Prefix string `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+}
+
+func (x *Pivot) Reset() {
+	*x = Pivot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Pivot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pivot) ProtoMessage() {}
+
+func (x *Pivot) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pivot.ProtoReflect.Descriptor instead.
+func (*Pivot) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Pivot) GetKey() *Expr {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *Pivot) GetValue() *Expr {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *Pivot) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+// Unpivot turns columns into rows of key and value columns.
+type Unpivot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Expr matches the unpivoted columns.
+	Expr *Expr `protobuf:"bytes,1,opt,name=expr,proto3" json:"expr,omitempty"`
+	// Key is the name of the key column.
+	Key string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// Value is the name of the value column.
+	Value string `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Unpivot) Reset() {
+	*x = Unpivot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Unpivot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Unpivot) ProtoMessage() {}
+
+func (x *Unpivot) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Unpivot.ProtoReflect.Descriptor instead.
+func (*Unpivot) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Unpivot) GetExpr() *Expr {
+	if x != nil {
+		return x.Expr
+	}
+	return nil
+}
+
+func (x *Unpivot) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Unpivot) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+// SemiJoin keeps the rows of the input whose key is one of the keys of the
+// results of an inner plan, or with anti, the rows whose key isn't.
+type SemiJoin struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Key is the key of the rows of the input.
+	Key *Expr `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Inner is the plan whose results the rows are filtered by.
+	Inner *PlanNode `protobuf:"bytes,2,opt,name=inner,proto3" json:"inner,omitempty"`
+	// InnerKey is the key of the results of the inner plan.
+	InnerKey *Expr `protobuf:"bytes,3,opt,name=inner_key,json=innerKey,proto3" json:"inner_key,omitempty"`
+	// Anti keeps the rows whose key isn't one of the inner plan's keys.
+	Anti bool `protobuf:"varint,4,opt,name=anti,proto3" json:"anti,omitempty"`
+}
+
+func (x *SemiJoin) Reset() {
+	*x = SemiJoin{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SemiJoin) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SemiJoin) ProtoMessage() {}
+
+func (x *SemiJoin) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SemiJoin.ProtoReflect.Descriptor instead.
+func (*SemiJoin) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SemiJoin) GetKey() *Expr {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *SemiJoin) GetInner() *PlanNode {
+	if x != nil {
+		return x.Inner
+	}
+	return nil
+}
+
+func (x *SemiJoin) GetInnerKey() *Expr {
+	if x != nil {
+		return x.InnerKey
+	}
+	return nil
+}
+
+func (x *SemiJoin) GetAnti() bool {
+	if x != nil {
+		return x.Anti
+	}
+	return false
+}
+
+// Expr is an expression.
+type Expr struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Def is the definition of the expression.
+	//
+	// Types that are assignable to Def:
+	//
+	//	*Expr_Binary
+	//	*Expr_Column
+	//	*Expr_DynamicColumn
+	//	*Expr_Literal
+	//	*Expr_AggregationFunction
+	//	*Expr_Alias
+	//	*Expr_Duration
+	//	*Expr_Average
+	//	*Expr_RegexpColumnMatch
+	//	*Expr_All
+	//	*Expr_Not
+	//	*Expr_WidthBucket
+	//	*Expr_In
+	Def isExpr_Def `protobuf_oneof:"def"`
+}
+
+func (x *Expr) Reset() {
+	*x = Expr{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Expr) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Expr) ProtoMessage() {}
+
+func (x *Expr) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Expr.ProtoReflect.Descriptor instead.
+func (*Expr) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{13}
+}
+
+func (m *Expr) GetDef() isExpr_Def {
+	if m != nil {
+		return m.Def
+	}
+	return nil
+}
+
+func (x *Expr) GetBinary() *BinaryExpr {
+	if x, ok := x.GetDef().(*Expr_Binary); ok {
+		return x.Binary
+	}
+	return nil
+}
+
+func (x *Expr) GetColumn() *Column {
+	if x, ok := x.GetDef().(*Expr_Column); ok {
+		return x.Column
+	}
+	return nil
+}
+
+func (x *Expr) GetDynamicColumn() *DynamicColumn {
+	if x, ok := x.GetDef().(*Expr_DynamicColumn); ok {
+		return x.DynamicColumn
+	}
+	return nil
+}
+
+func (x *Expr) GetLiteral() *Literal {
+	if x, ok := x.GetDef().(*Expr_Literal); ok {
+		return x.Literal
+	}
+	return nil
+}
+
+func (x *Expr) GetAggregationFunction() *AggregationFunction {
+	if x, ok := x.GetDef().(*Expr_AggregationFunction); ok {
+		return x.AggregationFunction
+	}
+	return nil
+}
+
+func (x *Expr) GetAlias() *Alias {
+	if x, ok := x.GetDef().(*Expr_Alias); ok {
+		return x.Alias
+	}
+	return nil
+}
+
+func (x *Expr) GetDuration() *Duration {
+	if x, ok := x.GetDef().(*Expr_Duration); ok {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *Expr) GetAverage() *Average {
+	if x, ok := x.GetDef().(*Expr_Average); ok {
+		return x.Average
+	}
+	return nil
+}
+
+func (x *Expr) GetRegexpColumnMatch() *RegexpColumnMatch {
+	if x, ok := x.GetDef().(*Expr_RegexpColumnMatch); ok {
+		return x.RegexpColumnMatch
+	}
+	return nil
+}
+
+func (x *Expr) GetAll() *All {
+	if x, ok := x.GetDef().(*Expr_All); ok {
+		return x.All
+	}
+	return nil
+}
+
+func (x *Expr) GetNot() *Not {
+	if x, ok := x.GetDef().(*Expr_Not); ok {
+		return x.Not
+	}
+	return nil
+}
+
+func (x *Expr) GetWidthBucket() *WidthBucket {
+	if x, ok := x.GetDef().(*Expr_WidthBucket); ok {
+		return x.WidthBucket
+	}
+	return nil
+}
+
+func (x *Expr) GetIn() *In {
+	if x, ok := x.GetDef().(*Expr_In); ok {
+		return x.In
+	}
+	return nil
+}
+
+type isExpr_Def interface {
+	isExpr_Def()
+}
+
+type Expr_Binary struct {
+	// Binary is a binary expression.
+	Binary *BinaryExpr `protobuf:"bytes,1,opt,name=binary,proto3,oneof"`
+}
+
+type Expr_Column struct {
+	// Column is a column.
+	Column *Column `protobuf:"bytes,2,opt,name=column,proto3,oneof"`
+}
+
+type Expr_DynamicColumn struct {
+	// DynamicColumn is a dynamic column.
+	DynamicColumn *DynamicColumn `protobuf:"bytes,3,opt,name=dynamic_column,json=dynamicColumn,proto3,oneof"`
+}
+
+type Expr_Literal struct {
+	// Literal is a literal value.
+	Literal *Literal `protobuf:"bytes,4,opt,name=literal,proto3,oneof"`
+}
+
+type Expr_AggregationFunction struct {
+	// AggregationFunction is an aggregation function.
+	AggregationFunction *AggregationFunction `protobuf:"bytes,5,opt,name=aggregation_function,json=aggregationFunction,proto3,oneof"`
+}
+
+type Expr_Alias struct {
+	// Alias is an aliased expression.
+	Alias *Alias `protobuf:"bytes,6,opt,name=alias,proto3,oneof"`
+}
+
+type Expr_Duration struct {
+	// Duration is a duration timestamps are grouped into.
+	Duration *Duration `protobuf:"bytes,7,opt,name=duration,proto3,oneof"`
+}
+
+type Expr_Average struct {
+	// Average is the average computed from a sum and a count.
+	Average *Average `protobuf:"bytes,8,opt,name=average,proto3,oneof"`
+}
+
+type Expr_RegexpColumnMatch struct {
+	// RegexpColumnMatch matches columns by a regular expression.
+	RegexpColumnMatch *RegexpColumnMatch `protobuf:"bytes,9,opt,name=regexp_column_match,json=regexpColumnMatch,proto3,oneof"`
+}
+
+type Expr_All struct {
+	// All matches all columns.
+	All *All `protobuf:"bytes,10,opt,name=all,proto3,oneof"`
+}
+
+type Expr_Not struct {
+	// Not negates a column match.
+	Not *Not `protobuf:"bytes,11,opt,name=not,proto3,oneof"`
+}
+
+type Expr_WidthBucket struct {
+	// WidthBucket is the number of the bucket a value falls into.
+	WidthBucket *WidthBucket `protobuf:"bytes,12,opt,name=width_bucket,json=widthBucket,proto3,oneof"`
+}
+
+type Expr_In struct {
+	// In is the membership of a value in a set of values.
+	In *In `protobuf:"bytes,13,opt,name=in,proto3,oneof"`
+}
+
+func (*Expr_Binary) isExpr_Def() {}
+
+func (*Expr_Column) isExpr_Def() {}
+
+func (*Expr_DynamicColumn) isExpr_Def() {}
+
+func (*Expr_Literal) isExpr_Def() {}
+
+func (*Expr_AggregationFunction) isExpr_Def() {}
+
+func (*Expr_Alias) isExpr_Def() {}
+
+func (*Expr_Duration) isExpr_Def() {}
+
+func (*Expr_Average) isExpr_Def() {}
+
+func (*Expr_RegexpColumnMatch) isExpr_Def() {}
+
+func (*Expr_All) isExpr_Def() {}
+
+func (*Expr_Not) isExpr_Def() {}
+
+func (*Expr_WidthBucket) isExpr_Def() {}
+
+func (*Expr_In) isExpr_Def() {}
+
+// BinaryExpr is a binary expression.
+type BinaryExpr struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Left is the left operand.
+	Left *Expr `protobuf:"bytes,1,opt,name=left,proto3" json:"left,omitempty"`
+	// Op is the operator.
+	Op BinaryExpr_Op `protobuf:"varint,2,opt,name=op,proto3,enum=frostdb.logicalplan.v1alpha1.BinaryExpr_Op" json:"op,omitempty"`
+	// Right is the right operand.
+	Right *Expr `protobuf:"bytes,3,opt,name=right,proto3" json:"right,omitempty"`
+}
+
+func (x *BinaryExpr) Reset() {
+	*x = BinaryExpr{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BinaryExpr) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BinaryExpr) ProtoMessage() {}
+
+func (x *BinaryExpr) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BinaryExpr.ProtoReflect.Descriptor instead.
+func (*BinaryExpr) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BinaryExpr) GetLeft() *Expr {
+	if x != nil {
+		return x.Left
+	}
+	return nil
+}
+
+func (x *BinaryExpr) GetOp() BinaryExpr_Op {
+	if x != nil {
+		return x.Op
+	}
+	return BinaryExpr_OP_UNKNOWN_UNSPECIFIED
+}
+
+func (x *BinaryExpr) GetRight() *Expr {
+	if x != nil {
+		return x.Right
+	}
+	return nil
+}
+
+// Column is a column.
+type Column struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name is the name of the column.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *Column) Reset() {
+	*x = Column{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Column) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Column) ProtoMessage() {}
+
+func (x *Column) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Column.ProtoReflect.Descriptor instead.
+func (*Column) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Column) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// DynamicColumn is a dynamic column.
+type DynamicColumn struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name is the name of the dynamic column.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *DynamicColumn) Reset() {
+	*x = DynamicColumn{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DynamicColumn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DynamicColumn) ProtoMessage() {}
+
+func (x *DynamicColumn) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DynamicColumn.ProtoReflect.Descriptor instead.
+func (*DynamicColumn) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DynamicColumn) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Literal is a literal value.
+type Literal struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Value is the value.
+	//
+	// Types that are assignable to Value:
+	//
+	//	*Literal_Null
+	//	*Literal_BoolValue
+	//	*Literal_Int32Value
+	//	*Literal_Uint32Value
+	//	*Literal_Int64Value
+	//	*Literal_Uint64Value
+	//	*Literal_FloatValue
+	//	*Literal_DoubleValue
+	//	*Literal_StringValue
+	//	*Literal_BinaryValue
+	Value isLiteral_Value `protobuf_oneof:"value"`
+}
+
+func (x *Literal) Reset() {
+	*x = Literal{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Literal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Literal) ProtoMessage() {}
+
+func (x *Literal) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Literal.ProtoReflect.Descriptor instead.
+func (*Literal) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{17}
+}
+
+func (m *Literal) GetValue() isLiteral_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (x *Literal) GetNull() *Null {
+	if x, ok := x.GetValue().(*Literal_Null); ok {
+		return x.Null
+	}
+	return nil
+}
+
+func (x *Literal) GetBoolValue() bool {
+	if x, ok := x.GetValue().(*Literal_BoolValue); ok {
+		return x.BoolValue
+	}
+	return false
+}
+
+func (x *Literal) GetInt32Value() int32 {
+	if x, ok := x.GetValue().(*Literal_Int32Value); ok {
+		return x.Int32Value
+	}
+	return 0
+}
+
+func (x *Literal) GetUint32Value() uint32 {
+	if x, ok := x.GetValue().(*Literal_Uint32Value); ok {
+		return x.Uint32Value
+	}
+	return 0
+}
+
+func (x *Literal) GetInt64Value() int64 {
+	if x, ok := x.GetValue().(*Literal_Int64Value); ok {
+		return x.Int64Value
+	}
+	return 0
+}
+
+func (x *Literal) GetUint64Value() uint64 {
+	if x, ok := x.GetValue().(*Literal_Uint64Value); ok {
+		return x.Uint64Value
+	}
+	return 0
+}
+
+func (x *Literal) GetFloatValue() float32 {
+	if x, ok := x.GetValue().(*Literal_FloatValue); ok {
+		return x.FloatValue
+	}
+	return 0
+}
+
+func (x *Literal) GetDoubleValue() float64 {
+	if x, ok := x.GetValue().(*Literal_DoubleValue); ok {
+		return x.DoubleValue
+	}
+	return 0
+}
+
+func (x *Literal) GetStringValue() string {
+	if x, ok := x.GetValue().(*Literal_StringValue); ok {
+		return x.StringValue
+	}
+	return ""
+}
+
+func (x *Literal) GetBinaryValue() []byte {
+	if x, ok := x.GetValue().(*Literal_BinaryValue); ok {
+		return x.BinaryValue
+	}
+	return nil
+}
+
+type isLiteral_Value interface {
+	isLiteral_Value()
+}
+
+type Literal_Null struct {
+	// Null is the null value.
+	Null *Null `protobuf:"bytes,1,opt,name=null,proto3,oneof"`
+}
+
+type Literal_BoolValue struct {
+	// BoolValue is a boolean value.
+	BoolValue bool `protobuf:"varint,2,opt,name=bool_value,json=boolValue,proto3,oneof"`
+}
+
+type Literal_Int32Value struct {
+	// Int32Value is a 32 bit integer value.
+	Int32Value int32 `protobuf:"varint,3,opt,name=int32_value,json=int32Value,proto3,oneof"`
+}
+
+type Literal_Uint32Value struct {
+	// Uint32Value is a 32 bit unsigned integer value.
+	Uint32Value uint32 `protobuf:"varint,4,opt,name=uint32_value,json=uint32Value,proto3,oneof"`
+}
+
+type Literal_Int64Value struct {
+	// Int64Value is a 64 bit integer value.
+	Int64Value int64 `protobuf:"varint,5,opt,name=int64_value,json=int64Value,proto3,oneof"`
+}
+
+type Literal_Uint64Value struct {
+	// Uint64Value is a 64 bit unsigned integer value.
+	Uint64Value uint64 `protobuf:"varint,6,opt,name=uint64_value,json=uint64Value,proto3,oneof"`
+}
+
+type Literal_FloatValue struct {
+	// FloatValue is a 32 bit floating point value.
+	FloatValue float32 `protobuf:"fixed32,7,opt,name=float_value,json=floatValue,proto3,oneof"`
+}
+
+type Literal_DoubleValue struct {
+	// DoubleValue is a 64 bit floating point value.
+	DoubleValue float64 `protobuf:"fixed64,8,opt,name=double_value,json=doubleValue,proto3,oneof"`
+}
+
+type Literal_StringValue struct {
+	// StringValue is a string value.
+	StringValue string `protobuf:"bytes,9,opt,name=string_value,json=stringValue,proto3,oneof"`
+}
+
+type Literal_BinaryValue struct {
+	// BinaryValue is a binary value.
+	BinaryValue []byte `protobuf:"bytes,10,opt,name=binary_value,json=binaryValue,proto3,oneof"`
+}
+
+func (*Literal_Null) isLiteral_Value() {}
+
+func (*Literal_BoolValue) isLiteral_Value() {}
+
+func (*Literal_Int32Value) isLiteral_Value() {}
+
+func (*Literal_Uint32Value) isLiteral_Value() {}
+
+func (*Literal_Int64Value) isLiteral_Value() {}
+
+func (*Literal_Uint64Value) isLiteral_Value() {}
+
+func (*Literal_FloatValue) isLiteral_Value() {}
+
+func (*Literal_DoubleValue) isLiteral_Value() {}
+
+func (*Literal_StringValue) isLiteral_Value() {}
+
+func (*Literal_BinaryValue) isLiteral_Value() {}
+
+// Null is the null value.
+type Null struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *Null) Reset() {
+	*x = Null{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Null) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Null) ProtoMessage() {}
+
+func (x *Null) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Null.ProtoReflect.Descriptor instead.
+func (*Null) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{18}
+}
+
+// AggregationFunction is an aggregation function.
+type AggregationFunction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Type is the type of aggregation function.
+	Type AggregationFunction_Type `protobuf:"varint,1,opt,name=type,proto3,enum=frostdb.logicalplan.v1alpha1.AggregationFunction_Type" json:"type,omitempty"`
+	// Expr is the aggregated expression.
+	Expr *Expr `protobuf:"bytes,2,opt,name=expr,proto3" json:"expr,omitempty"`
+	// OrderBy is the expression the values of first, last, rate and increase
+	// aggregations are ordered by.
+	OrderBy *Expr `protobuf:"bytes,3,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	// Paired is the second expression of aggregations of pairs of values.
+	Paired *Expr `protobuf:"bytes,4,opt,name=paired,proto3" json:"paired,omitempty"`
+	// Buckets are the increasing upper bounds of the buckets of histogram
+	// aggregations.
+	Buckets []float64 `protobuf:"fixed64,5,rep,packed,name=buckets,proto3" json:"buckets,omitempty"`
+}
+
+func (x *AggregationFunction) Reset() {
+	*x = AggregationFunction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AggregationFunction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AggregationFunction) ProtoMessage() {}
+
+func (x *AggregationFunction) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AggregationFunction.ProtoReflect.Descriptor instead.
+func (*AggregationFunction) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AggregationFunction) GetType() AggregationFunction_Type {
+	if x != nil {
+		return x.Type
+	}
+	return AggregationFunction_TYPE_UNKNOWN_UNSPECIFIED
+}
+
+func (x *AggregationFunction) GetExpr() *Expr {
+	if x != nil {
+		return x.Expr
+	}
+	return nil
+}
+
+func (x *AggregationFunction) GetOrderBy() *Expr {
+	if x != nil {
+		return x.OrderBy
+	}
+	return nil
+}
+
+func (x *AggregationFunction) GetPaired() *Expr {
+	if x != nil {
+		return x.Paired
+	}
+	return nil
+}
+
+func (x *AggregationFunction) GetBuckets() []float64 {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+// Alias is an aliased expression.
+type Alias struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Expr is the aliased expression.
+	Expr *Expr `protobuf:"bytes,1,opt,name=expr,proto3" json:"expr,omitempty"`
+	// Name is the alias.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *Alias) Reset() {
+	*x = Alias{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Alias) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alias) ProtoMessage() {}
+
+func (x *Alias) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alias.ProtoReflect.Descriptor instead.
+func (*Alias) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Alias) GetExpr() *Expr {
+	if x != nil {
+		return x.Expr
+	}
+	return nil
+}
+
+func (x *Alias) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Duration is a duration timestamps are grouped into.
+type Duration struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Nanoseconds is the duration in nanoseconds.
+	Nanoseconds int64 `protobuf:"varint,1,opt,name=nanoseconds,proto3" json:"nanoseconds,omitempty"`
+}
+
+func (x *Duration) Reset() {
+	*x = Duration{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Duration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Duration) ProtoMessage() {}
+
+func (x *Duration) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Duration.ProtoReflect.Descriptor instead.
+func (*Duration) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Duration) GetNanoseconds() int64 {
+	if x != nil {
+		return x.Nanoseconds
+	}
+	return 0
+}
+
+// Average is the average computed from a sum and a count.
+type Average struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Expr is the averaged expression.
+	Expr *Expr `protobuf:"bytes,1,opt,name=expr,proto3" json:"expr,omitempty"`
+}
+
+func (x *Average) Reset() {
+	*x = Average{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Average) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Average) ProtoMessage() {}
+
+func (x *Average) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Average.ProtoReflect.Descriptor instead.
+func (*Average) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Average) GetExpr() *Expr {
+	if x != nil {
+		return x.Expr
+	}
+	return nil
+}
+
+// RegexpColumnMatch matches columns by a regular expression.
+type RegexpColumnMatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Match is the regular expression.
+	Match string `protobuf:"bytes,1,opt,name=match,proto3" json:"match,omitempty"`
+	// Inverse matches the columns that don't match the regular expression.
+	Inverse bool `protobuf:"varint,2,opt,name=inverse,proto3" json:"inverse,omitempty"`
+}
+
+func (x *RegexpColumnMatch) Reset() {
+	*x = RegexpColumnMatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegexpColumnMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegexpColumnMatch) ProtoMessage() {}
+
+func (x *RegexpColumnMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegexpColumnMatch.ProtoReflect.Descriptor instead.
+func (*RegexpColumnMatch) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RegexpColumnMatch) GetMatch() string {
+	if x != nil {
+		return x.Match
+	}
+	return ""
+}
+
+func (x *RegexpColumnMatch) GetInverse() bool {
+	if x != nil {
+		return x.Inverse
+	}
+	return false
+}
+
+// All matches all columns.
+type All struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *All) Reset() {
+	*x = All{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *All) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*All) ProtoMessage() {}
+
+func (x *All) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use All.ProtoReflect.Descriptor instead.
+func (*All) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{24}
+}
+
+// WidthBucket is the number of the bucket a value falls into, out of a number
+// of buckets of equal width spanning the range from min to max.
+type WidthBucket struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Expr is the expression whose values are bucketed.
+	Expr *Expr `protobuf:"bytes,1,opt,name=expr,proto3" json:"expr,omitempty"`
+	// Min is the inclusive lower bound of the range.
+	Min float64 `protobuf:"fixed64,2,opt,name=min,proto3" json:"min,omitempty"`
+	// Max is the exclusive upper bound of the range.
+	Max float64 `protobuf:"fixed64,3,opt,name=max,proto3" json:"max,omitempty"`
+	// Buckets is the number of buckets.
+	Buckets int64 `protobuf:"varint,4,opt,name=buckets,proto3" json:"buckets,omitempty"`
+}
+
+func (x *WidthBucket) Reset() {
+	*x = WidthBucket{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WidthBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WidthBucket) ProtoMessage() {}
+
+func (x *WidthBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WidthBucket.ProtoReflect.Descriptor instead.
+func (*WidthBucket) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *WidthBucket) GetExpr() *Expr {
+	if x != nil {
+		return x.Expr
+	}
+	return nil
+}
+
+func (x *WidthBucket) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *WidthBucket) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *WidthBucket) GetBuckets() int64 {
+	if x != nil {
+		return x.Buckets
+	}
+	return 0
+}
+
+// In is true for the rows whose value is one of a set of values, or with not,
+// for the rows whose value isn't.
+type In struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Expr is the expression whose values are looked up.
+	Expr *Expr `protobuf:"bytes,1,opt,name=expr,proto3" json:"expr,omitempty"`
+	// Values are the set of values.
+	Values []*Literal `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+	// Not negates the membership.
+	Not bool `protobuf:"varint,3,opt,name=not,proto3" json:"not,omitempty"`
+}
+
+func (x *In) Reset() {
+	*x = In{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *In) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*In) ProtoMessage() {}
+
+func (x *In) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use In.ProtoReflect.Descriptor instead.
+func (*In) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *In) GetExpr() *Expr {
+	if x != nil {
+		return x.Expr
+	}
+	return nil
+}
+
+func (x *In) GetValues() []*Literal {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *In) GetNot() bool {
+	if x != nil {
+		return x.Not
+	}
+	return false
+}
+
+// Not negates a column match.
+type Not struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Expr is the negated expression.
+	Expr *Expr `protobuf:"bytes,1,opt,name=expr,proto3" json:"expr,omitempty"`
+}
+
+func (x *Not) Reset() {
+	*x = Not{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Not) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Not) ProtoMessage() {}
+
+func (x *Not) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Not.ProtoReflect.Descriptor instead.
+func (*Not) Descriptor() ([]byte, []int) {
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *Not) GetExpr() *Expr {
+	if x != nil {
+		return x.Expr
+	}
+	return nil
+}
+
+var File_frostdb_logicalplan_v1alpha1_logicalplan_proto protoreflect.FileDescriptor
+
+var file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDesc = []byte{
+	0x0a, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61,
+	0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2f, 0x6c,
+	0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x1c, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61,
+	0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x22, 0x93,
+	0x07, 0x0a, 0x08, 0x50, 0x6c, 0x61, 0x6e, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x3c, 0x0a, 0x05, 0x69,
+	0x6e, 0x70, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x66, 0x72, 0x6f,
+	0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x4e, 0x6f,
+	0x64, 0x65, 0x52, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x48, 0x0a, 0x0a, 0x74, 0x61, 0x62,
+	0x6c, 0x65, 0x5f, 0x73, 0x63, 0x61, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e,
+	0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70,
+	0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x54, 0x61, 0x62,
+	0x6c, 0x65, 0x53, 0x63, 0x61, 0x6e, 0x48, 0x00, 0x52, 0x09, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x53,
+	0x63, 0x61, 0x6e, 0x12, 0x4b, 0x0a, 0x0b, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x5f, 0x73, 0x63,
+	0x61, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74,
+	0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76,
+	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x53, 0x63,
+	0x61, 0x6e, 0x48, 0x00, 0x52, 0x0a, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x53, 0x63, 0x61, 0x6e,
+	0x12, 0x3e, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x24, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63,
+	0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e,
+	0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x48, 0x00, 0x52, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72,
+	0x12, 0x44, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x63, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x26, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67,
+	0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
+	0x31, 0x2e, 0x44, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x63, 0x74, 0x48, 0x00, 0x52, 0x08, 0x64, 0x69,
+	0x73, 0x74, 0x69, 0x6e, 0x63, 0x74, 0x12, 0x4a, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x66, 0x72, 0x6f,
+	0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x4d, 0x0a, 0x0b, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64,
+	0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31,
+	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0b, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x3e, 0x0a, 0x06, 0x75, 0x6e, 0x6e, 0x65, 0x73, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x24, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69,
+	0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
+	0x2e, 0x55, 0x6e, 0x6e, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x06, 0x75, 0x6e, 0x6e, 0x65, 0x73,
+	0x74, 0x12, 0x3e, 0x0a, 0x06, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x24, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69,
+	0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
+	0x2e, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x48, 0x00, 0x52, 0x06, 0x73, 0x61, 0x6d, 0x70, 0x6c,
+	0x65, 0x12, 0x42, 0x0a, 0x08, 0x67, 0x61, 0x70, 0x5f, 0x66, 0x69, 0x6c, 0x6c, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f,
+	0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x47, 0x61, 0x70, 0x46, 0x69, 0x6c, 0x6c, 0x48, 0x00, 0x52, 0x07, 0x67, 0x61,
+	0x70, 0x46, 0x69, 0x6c, 0x6c, 0x12, 0x3b, 0x0a, 0x05, 0x70, 0x69, 0x76, 0x6f, 0x74, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c,
+	0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x2e, 0x50, 0x69, 0x76, 0x6f, 0x74, 0x48, 0x00, 0x52, 0x05, 0x70, 0x69, 0x76,
+	0x6f, 0x74, 0x12, 0x41, 0x0a, 0x07, 0x75, 0x6e, 0x70, 0x69, 0x76, 0x6f, 0x74, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f,
+	0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x55, 0x6e, 0x70, 0x69, 0x76, 0x6f, 0x74, 0x48, 0x00, 0x52, 0x07, 0x75, 0x6e,
+	0x70, 0x69, 0x76, 0x6f, 0x74, 0x12, 0x45, 0x0a, 0x09, 0x73, 0x65, 0x6d, 0x69, 0x5f, 0x6a, 0x6f,
+	0x69, 0x6e, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74,
+	0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76,
+	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x53, 0x65, 0x6d, 0x69, 0x4a, 0x6f, 0x69, 0x6e,
+	0x48, 0x00, 0x52, 0x08, 0x73, 0x65, 0x6d, 0x69, 0x4a, 0x6f, 0x69, 0x6e, 0x42, 0x06, 0x0a, 0x04,
+	0x73, 0x70, 0x65, 0x63, 0x22, 0xa4, 0x04, 0x0a, 0x09, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x63,
+	0x61, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x53, 0x0a, 0x13, 0x70, 0x68, 0x79, 0x73, 0x69, 0x63, 0x61, 0x6c, 0x5f, 0x70, 0x72,
+	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22,
+	0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c,
+	0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78,
+	0x70, 0x72, 0x52, 0x12, 0x70, 0x68, 0x79, 0x73, 0x69, 0x63, 0x61, 0x6c, 0x50, 0x72, 0x6f, 0x6a,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3a, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62,
+	0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61,
+	0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x06, 0x66, 0x69, 0x6c, 0x74,
+	0x65, 0x72, 0x12, 0x3e, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x63, 0x74, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c,
+	0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x08, 0x64, 0x69, 0x73, 0x74, 0x69, 0x6e,
+	0x63, 0x74, 0x12, 0x42, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62,
+	0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61,
+	0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x6a,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x6b, 0x69, 0x70, 0x5f, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x73, 0x6b,
+	0x69, 0x70, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x65, 0x72,
+	0x73, 0x69, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0f, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x64, 0x42, 0x65,
+	0x66, 0x6f, 0x72, 0x65, 0x12, 0x57, 0x0a, 0x15, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x5f, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x08, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f,
+	0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x14, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x3c, 0x0a,
+	0x06, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e,
+	0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70,
+	0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x53, 0x61, 0x6d,
+	0x70, 0x6c, 0x65, 0x52, 0x06, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x22, 0xe3, 0x02, 0x0a, 0x0a,
+	0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x53, 0x63, 0x61, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61,
+	0x62, 0x6c, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x74, 0x61, 0x62, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x53, 0x0a, 0x13, 0x70, 0x68, 0x79,
+	0x73, 0x69, 0x63, 0x61, 0x6c, 0x5f, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62,
+	0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61,
+	0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x12, 0x70, 0x68, 0x79, 0x73,
+	0x69, 0x63, 0x61, 0x6c, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3a,
+	0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22,
+	0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c,
+	0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78,
+	0x70, 0x72, 0x52, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x3e, 0x0a, 0x08, 0x64, 0x69,
+	0x73, 0x74, 0x69, 0x6e, 0x63, 0x74, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66,
+	0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c,
+	0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72,
+	0x52, 0x08, 0x64, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x63, 0x74, 0x12, 0x42, 0x0a, 0x0a, 0x70, 0x72,
+	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22,
+	0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c,
+	0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78,
+	0x70, 0x72, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21,
+	0x0a, 0x0c, 0x73, 0x6b, 0x69, 0x70, 0x5f, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x73, 0x6b, 0x69, 0x70, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x73, 0x22, 0x40, 0x0a, 0x06, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x36, 0x0a, 0x04, 0x65,
+	0x78, 0x70, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73,
+	0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e,
+	0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x04, 0x65,
+	0x78, 0x70, 0x72, 0x22, 0x5a, 0x0a, 0x08, 0x44, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x63, 0x74, 0x12,
+	0x38, 0x0a, 0x05, 0x65, 0x78, 0x70, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22,
+	0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c,
+	0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78,
+	0x70, 0x72, 0x52, 0x05, 0x65, 0x78, 0x70, 0x72, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22,
+	0x46, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x38, 0x0a,
+	0x05, 0x65, 0x78, 0x70, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66,
+	0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c,
+	0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72,
+	0x52, 0x05, 0x65, 0x78, 0x70, 0x72, 0x73, 0x22, 0x93, 0x01, 0x0a, 0x0b, 0x41, 0x67, 0x67, 0x72,
+	0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3f, 0x0a, 0x09, 0x61, 0x67, 0x67, 0x5f, 0x65,
+	0x78, 0x70, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f,
+	0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x08,
+	0x61, 0x67, 0x67, 0x45, 0x78, 0x70, 0x72, 0x73, 0x12, 0x43, 0x0a, 0x0b, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x5f, 0x65, 0x78, 0x70, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e,
+	0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70,
+	0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70,
+	0x72, 0x52, 0x0a, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x45, 0x78, 0x70, 0x72, 0x73, 0x22, 0x40, 0x0a,
+	0x06, 0x55, 0x6e, 0x6e, 0x65, 0x73, 0x74, 0x12, 0x36, 0x0a, 0x04, 0x65, 0x78, 0x70, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e,
+	0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x04, 0x65, 0x78, 0x70, 0x72, 0x22,
+	0xff, 0x01, 0x0a, 0x06, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x12, 0x43, 0x0a, 0x06, 0x6d, 0x65,
+	0x74, 0x68, 0x6f, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x2b, 0x2e, 0x66, 0x72, 0x6f,
+	0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65,
+	0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12,
+	0x1a, 0x0a, 0x08, 0x66, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x08, 0x66, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x73,
+	0x65, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x65, 0x65, 0x64, 0x12,
+	0x2d, 0x0a, 0x12, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x5f, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x73, 0x63, 0x61,
+	0x6c, 0x65, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x51,
+	0x0a, 0x06, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x1e, 0x0a, 0x1a, 0x4d, 0x45, 0x54, 0x48,
+	0x4f, 0x44, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x4d, 0x45, 0x54, 0x48,
+	0x4f, 0x44, 0x5f, 0x42, 0x45, 0x52, 0x4e, 0x4f, 0x55, 0x4c, 0x4c, 0x49, 0x10, 0x01, 0x12, 0x11,
+	0x0a, 0x0d, 0x4d, 0x45, 0x54, 0x48, 0x4f, 0x44, 0x5f, 0x53, 0x59, 0x53, 0x54, 0x45, 0x4d, 0x10,
+	0x02, 0x22, 0x86, 0x02, 0x0a, 0x07, 0x47, 0x61, 0x70, 0x46, 0x69, 0x6c, 0x6c, 0x12, 0x14, 0x0a,
+	0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x3a, 0x0a, 0x04, 0x73, 0x74, 0x65, 0x70, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f,
+	0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x04, 0x73, 0x74, 0x65,
+	0x70, 0x12, 0x3e, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x2a, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61,
+	0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x47,
+	0x61, 0x70, 0x46, 0x69, 0x6c, 0x6c, 0x2e, 0x46, 0x69, 0x6c, 0x6c, 0x52, 0x04, 0x66, 0x69, 0x6c,
+	0x6c, 0x22, 0x57, 0x0a, 0x04, 0x46, 0x69, 0x6c, 0x6c, 0x12, 0x1c, 0x0a, 0x18, 0x46, 0x49, 0x4c,
+	0x4c, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x46, 0x49, 0x4c, 0x4c, 0x5f,
+	0x4e, 0x55, 0x4c, 0x4c, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x46, 0x49, 0x4c, 0x4c, 0x5f, 0x50,
+	0x52, 0x45, 0x56, 0x49, 0x4f, 0x55, 0x53, 0x10, 0x02, 0x12, 0x0f, 0x0a, 0x0b, 0x46, 0x49, 0x4c,
+	0x4c, 0x5f, 0x4c, 0x49, 0x4e, 0x45, 0x41, 0x52, 0x10, 0x03, 0x22, 0x8f, 0x01, 0x0a, 0x05, 0x50,
+	0x69, 0x76, 0x6f, 0x74, 0x12, 0x34, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69,
+	0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
+	0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x38, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73,
+	0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e,
+	0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x22, 0x69, 0x0a, 0x07,
+	0x55, 0x6e, 0x70, 0x69, 0x76, 0x6f, 0x74, 0x12, 0x36, 0x0a, 0x04, 0x65, 0x78, 0x70, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e,
+	0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x04, 0x65, 0x78, 0x70, 0x72, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xd3, 0x01, 0x0a, 0x08, 0x53, 0x65, 0x6d, 0x69,
+	0x4a, 0x6f, 0x69, 0x6e, 0x12, 0x34, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69,
+	0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
+	0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x3c, 0x0a, 0x05, 0x69, 0x6e,
+	0x6e, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x66, 0x72, 0x6f, 0x73,
+	0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e,
+	0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x4e, 0x6f, 0x64,
+	0x65, 0x52, 0x05, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x12, 0x3f, 0x0a, 0x09, 0x69, 0x6e, 0x6e, 0x65,
+	0x72, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72,
+	0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61,
+	0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52,
+	0x08, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x6e, 0x74,
+	0x69, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x61, 0x6e, 0x74, 0x69, 0x22, 0xad, 0x07,
+	0x0a, 0x04, 0x45, 0x78, 0x70, 0x72, 0x12, 0x42, 0x0a, 0x06, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62,
+	0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61,
+	0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x42, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x45, 0x78, 0x70, 0x72,
+	0x48, 0x00, 0x52, 0x06, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x12, 0x3e, 0x0a, 0x06, 0x63, 0x6f,
+	0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x66, 0x72, 0x6f,
+	0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e,
+	0x48, 0x00, 0x52, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x54, 0x0a, 0x0e, 0x64, 0x79,
+	0x6e, 0x61, 0x6d, 0x69, 0x63, 0x5f, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67,
+	0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
+	0x31, 0x2e, 0x44, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x48,
+	0x00, 0x52, 0x0d, 0x64, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e,
+	0x12, 0x41, 0x0a, 0x07, 0x6c, 0x69, 0x74, 0x65, 0x72, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x25, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69,
+	0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
+	0x2e, 0x4c, 0x69, 0x74, 0x65, 0x72, 0x61, 0x6c, 0x48, 0x00, 0x52, 0x07, 0x6c, 0x69, 0x74, 0x65,
+	0x72, 0x61, 0x6c, 0x12, 0x66, 0x0a, 0x14, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x31, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69,
+	0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
+	0x2e, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x75, 0x6e, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x13, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3b, 0x0a, 0x05, 0x61,
+	0x6c, 0x69, 0x61, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x66, 0x72, 0x6f,
+	0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x48,
+	0x00, 0x52, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x44, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x66, 0x72, 0x6f,
+	0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x48, 0x00, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x41,
+	0x0a, 0x07, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x25, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61,
+	0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x41,
+	0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x07, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67,
+	0x65, 0x12, 0x61, 0x0a, 0x13, 0x72, 0x65, 0x67, 0x65, 0x78, 0x70, 0x5f, 0x63, 0x6f, 0x6c, 0x75,
+	0x6d, 0x6e, 0x5f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f,
+	0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c,
+	0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x52, 0x65,
+	0x67, 0x65, 0x78, 0x70, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x48,
+	0x00, 0x52, 0x11, 0x72, 0x65, 0x67, 0x65, 0x78, 0x70, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x4d,
+	0x61, 0x74, 0x63, 0x68, 0x12, 0x35, 0x0a, 0x03, 0x61, 0x6c, 0x6c, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x21, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69,
+	0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
+	0x2e, 0x41, 0x6c, 0x6c, 0x48, 0x00, 0x52, 0x03, 0x61, 0x6c, 0x6c, 0x12, 0x35, 0x0a, 0x03, 0x6e,
+	0x6f, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74,
+	0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76,
+	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4e, 0x6f, 0x74, 0x48, 0x00, 0x52, 0x03, 0x6e,
+	0x6f, 0x74, 0x12, 0x4e, 0x0a, 0x0c, 0x77, 0x69, 0x64, 0x74, 0x68, 0x5f, 0x62, 0x75, 0x63, 0x6b,
+	0x65, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74,
+	0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76,
+	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x57, 0x69, 0x64, 0x74, 0x68, 0x42, 0x75, 0x63,
+	0x6b, 0x65, 0x74, 0x48, 0x00, 0x52, 0x0b, 0x77, 0x69, 0x64, 0x74, 0x68, 0x42, 0x75, 0x63, 0x6b,
+	0x65, 0x74, 0x12, 0x32, 0x0a, 0x02, 0x69, 0x6e, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20,
+	0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c,
+	0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x49, 0x6e,
+	0x48, 0x00, 0x52, 0x02, 0x69, 0x6e, 0x42, 0x05, 0x0a, 0x03, 0x64, 0x65, 0x66, 0x22, 0xed, 0x02,
+	0x0a, 0x0a, 0x42, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x45, 0x78, 0x70, 0x72, 0x12, 0x36, 0x0a, 0x04,
+	0x6c, 0x65, 0x66, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f,
+	0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x04,
+	0x6c, 0x65, 0x66, 0x74, 0x12, 0x3b, 0x0a, 0x02, 0x6f, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x2b, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63,
+	0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e,
+	0x42, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x45, 0x78, 0x70, 0x72, 0x2e, 0x4f, 0x70, 0x52, 0x02, 0x6f,
+	0x70, 0x12, 0x38, 0x0a, 0x05, 0x72, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63,
+	0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e,
+	0x45, 0x78, 0x70, 0x72, 0x52, 0x05, 0x72, 0x69, 0x67, 0x68, 0x74, 0x22, 0xaf, 0x01, 0x0a, 0x02,
+	0x4f, 0x70, 0x12, 0x1a, 0x0a, 0x16, 0x4f, 0x50, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e,
+	0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x09,
+	0x0a, 0x05, 0x4f, 0x50, 0x5f, 0x45, 0x51, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x4f, 0x50, 0x5f,
+	0x4e, 0x4f, 0x54, 0x5f, 0x45, 0x51, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x4f, 0x50, 0x5f, 0x4c,
+	0x54, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x4f, 0x50, 0x5f, 0x4c, 0x54, 0x5f, 0x45, 0x51, 0x10,
+	0x04, 0x12, 0x09, 0x0a, 0x05, 0x4f, 0x50, 0x5f, 0x47, 0x54, 0x10, 0x05, 0x12, 0x0c, 0x0a, 0x08,
+	0x4f, 0x50, 0x5f, 0x47, 0x54, 0x5f, 0x45, 0x51, 0x10, 0x06, 0x12, 0x12, 0x0a, 0x0e, 0x4f, 0x50,
+	0x5f, 0x52, 0x45, 0x47, 0x45, 0x58, 0x5f, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x07, 0x12, 0x16,
+	0x0a, 0x12, 0x4f, 0x50, 0x5f, 0x52, 0x45, 0x47, 0x45, 0x58, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x4d,
+	0x41, 0x54, 0x43, 0x48, 0x10, 0x08, 0x12, 0x0a, 0x0a, 0x06, 0x4f, 0x50, 0x5f, 0x41, 0x4e, 0x44,
+	0x10, 0x09, 0x12, 0x09, 0x0a, 0x05, 0x4f, 0x50, 0x5f, 0x4f, 0x52, 0x10, 0x0a, 0x22, 0x1c, 0x0a,
+	0x06, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x23, 0x0a, 0x0d, 0x44,
+	0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x22, 0x8f, 0x03, 0x0a, 0x07, 0x4c, 0x69, 0x74, 0x65, 0x72, 0x61, 0x6c, 0x12, 0x38, 0x0a, 0x04,
+	0x6e, 0x75, 0x6c, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f,
+	0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4e, 0x75, 0x6c, 0x6c, 0x48, 0x00,
+	0x52, 0x04, 0x6e, 0x75, 0x6c, 0x6c, 0x12, 0x1f, 0x0a, 0x0a, 0x62, 0x6f, 0x6f, 0x6c, 0x5f, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x09, 0x62, 0x6f,
+	0x6f, 0x6c, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x21, 0x0a, 0x0b, 0x69, 0x6e, 0x74, 0x33, 0x32,
+	0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x0a,
+	0x69, 0x6e, 0x74, 0x33, 0x32, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x23, 0x0a, 0x0c, 0x75, 0x69,
+	0x6e, 0x74, 0x33, 0x32, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d,
+	0x48, 0x00, 0x52, 0x0b, 0x75, 0x69, 0x6e, 0x74, 0x33, 0x32, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12,
+	0x21, 0x0a, 0x0b, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0a, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x12, 0x23, 0x0a, 0x0c, 0x75, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x5f, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x0b, 0x75, 0x69, 0x6e, 0x74,
+	0x36, 0x34, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x21, 0x0a, 0x0b, 0x66, 0x6c, 0x6f, 0x61, 0x74,
+	0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x02, 0x48, 0x00, 0x52, 0x0a,
+	0x66, 0x6c, 0x6f, 0x61, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x23, 0x0a, 0x0c, 0x64, 0x6f,
+	0x75, 0x62, 0x6c, 0x65, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01,
+	0x48, 0x00, 0x52, 0x0b, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12,
+	0x23, 0x0a, 0x0c, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0b, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x12, 0x23, 0x0a, 0x0c, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x5f, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x0b, 0x62, 0x69,
+	0x6e, 0x61, 0x72, 0x79, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x22, 0x06, 0x0a, 0x04, 0x4e, 0x75, 0x6c, 0x6c, 0x22, 0xfa, 0x04, 0x0a, 0x13, 0x41,
+	0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x4a, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x36, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63,
+	0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e,
+	0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x75, 0x6e, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x36,
+	0x0a, 0x04, 0x65, 0x78, 0x70, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66,
+	0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c,
+	0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72,
+	0x52, 0x04, 0x65, 0x78, 0x70, 0x72, 0x12, 0x3d, 0x0a, 0x08, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f,
+	0x62, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74,
+	0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76,
+	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x07, 0x6f, 0x72,
+	0x64, 0x65, 0x72, 0x42, 0x79, 0x12, 0x3a, 0x0a, 0x06, 0x70, 0x61, 0x69, 0x72, 0x65, 0x64, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e,
+	0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x06, 0x70, 0x61, 0x69, 0x72, 0x65,
+	0x64, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03,
+	0x28, 0x01, 0x52, 0x07, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x22, 0xc9, 0x02, 0x0a, 0x04,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x18, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x4b,
+	0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44,
+	0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x53, 0x55, 0x4d, 0x10, 0x01,
+	0x12, 0x0c, 0x0a, 0x08, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4d, 0x49, 0x4e, 0x10, 0x02, 0x12, 0x0c,
+	0x0a, 0x08, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4d, 0x41, 0x58, 0x10, 0x03, 0x12, 0x0e, 0x0a, 0x0a,
+	0x54, 0x59, 0x50, 0x45, 0x5f, 0x43, 0x4f, 0x55, 0x4e, 0x54, 0x10, 0x04, 0x12, 0x0c, 0x0a, 0x08,
+	0x54, 0x59, 0x50, 0x45, 0x5f, 0x41, 0x56, 0x47, 0x10, 0x05, 0x12, 0x0e, 0x0a, 0x0a, 0x54, 0x59,
+	0x50, 0x45, 0x5f, 0x46, 0x49, 0x52, 0x53, 0x54, 0x10, 0x06, 0x12, 0x0d, 0x0a, 0x09, 0x54, 0x59,
+	0x50, 0x45, 0x5f, 0x4c, 0x41, 0x53, 0x54, 0x10, 0x07, 0x12, 0x13, 0x0a, 0x0f, 0x54, 0x59, 0x50,
+	0x45, 0x5f, 0x53, 0x54, 0x44, 0x44, 0x45, 0x56, 0x5f, 0x50, 0x4f, 0x50, 0x10, 0x08, 0x12, 0x14,
+	0x0a, 0x10, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x53, 0x54, 0x44, 0x44, 0x45, 0x56, 0x5f, 0x53, 0x41,
+	0x4d, 0x50, 0x10, 0x09, 0x12, 0x10, 0x0a, 0x0c, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x56, 0x41, 0x52,
+	0x5f, 0x50, 0x4f, 0x50, 0x10, 0x0a, 0x12, 0x11, 0x0a, 0x0d, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x56,
+	0x41, 0x52, 0x5f, 0x53, 0x41, 0x4d, 0x50, 0x10, 0x0b, 0x12, 0x12, 0x0a, 0x0e, 0x54, 0x59, 0x50,
+	0x45, 0x5f, 0x43, 0x4f, 0x56, 0x41, 0x52, 0x5f, 0x50, 0x4f, 0x50, 0x10, 0x0c, 0x12, 0x13, 0x0a,
+	0x0f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x43, 0x4f, 0x56, 0x41, 0x52, 0x5f, 0x53, 0x41, 0x4d, 0x50,
+	0x10, 0x0d, 0x12, 0x0d, 0x0a, 0x09, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x43, 0x4f, 0x52, 0x52, 0x10,
+	0x0e, 0x12, 0x12, 0x0a, 0x0e, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x48, 0x49, 0x53, 0x54, 0x4f, 0x47,
+	0x52, 0x41, 0x4d, 0x10, 0x0f, 0x12, 0x0d, 0x0a, 0x09, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52, 0x41,
+	0x54, 0x45, 0x10, 0x10, 0x12, 0x11, 0x0a, 0x0d, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x49, 0x4e, 0x43,
+	0x52, 0x45, 0x41, 0x53, 0x45, 0x10, 0x11, 0x22, 0x53, 0x0a, 0x05, 0x41, 0x6c, 0x69, 0x61, 0x73,
+	0x12, 0x36, 0x0a, 0x04, 0x65, 0x78, 0x70, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22,
+	0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c,
+	0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78,
+	0x70, 0x72, 0x52, 0x04, 0x65, 0x78, 0x70, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x2c, 0x0a, 0x08,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x6e, 0x61, 0x6e, 0x6f,
+	0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6e,
+	0x61, 0x6e, 0x6f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x22, 0x41, 0x0a, 0x07, 0x41, 0x76,
+	0x65, 0x72, 0x61, 0x67, 0x65, 0x12, 0x36, 0x0a, 0x04, 0x65, 0x78, 0x70, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f,
+	0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x04, 0x65, 0x78, 0x70, 0x72, 0x22, 0x43, 0x0a,
+	0x11, 0x52, 0x65, 0x67, 0x65, 0x78, 0x70, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x4d, 0x61, 0x74,
+	0x63, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x18, 0x0a, 0x07, 0x69, 0x6e, 0x76, 0x65,
+	0x72, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x69, 0x6e, 0x76, 0x65, 0x72,
+	0x73, 0x65, 0x22, 0x05, 0x0a, 0x03, 0x41, 0x6c, 0x6c, 0x22, 0x83, 0x01, 0x0a, 0x0b, 0x57, 0x69,
+	0x64, 0x74, 0x68, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x36, 0x0a, 0x04, 0x65, 0x78, 0x70,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64,
+	0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31,
+	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x04, 0x65, 0x78, 0x70,
+	0x72, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03,
+	0x6d, 0x69, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x03, 0x6d, 0x61, 0x78, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x22,
+	0x8d, 0x01, 0x0a, 0x02, 0x49, 0x6e, 0x12, 0x36, 0x0a, 0x04, 0x65, 0x78, 0x70, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c,
+	0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x04, 0x65, 0x78, 0x70, 0x72, 0x12, 0x3d,
+	0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25,
+	0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c,
+	0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4c, 0x69,
+	0x74, 0x65, 0x72, 0x61, 0x6c, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x12, 0x10, 0x0a,
+	0x03, 0x6e, 0x6f, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x6e, 0x6f, 0x74, 0x22,
+	0x3d, 0x0a, 0x03, 0x4e, 0x6f, 0x74, 0x12, 0x36, 0x0a, 0x04, 0x65, 0x78, 0x70, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c,
+	0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x04, 0x65, 0x78, 0x70, 0x72, 0x42, 0xa5,
+	0x02, 0x0a, 0x20, 0x63, 0x6f, 0x6d, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x6c,
+	0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x42, 0x10, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x5d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x70, 0x6f, 0x6c, 0x61, 0x72, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x73,
+	0x2f, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2f, 0x67, 0x6f, 0x2f, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f, 0x6c, 0x6f,
+	0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x3b, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x76, 0x31,
+	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xa2, 0x02, 0x03, 0x46, 0x4c, 0x58, 0xaa, 0x02, 0x1c, 0x46,
+	0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c,
+	0x61, 0x6e, 0x2e, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xca, 0x02, 0x1c, 0x46, 0x72,
+	0x6f, 0x73, 0x74, 0x64, 0x62, 0x5c, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61,
+	0x6e, 0x5c, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xe2, 0x02, 0x28, 0x46, 0x72, 0x6f,
+	0x73, 0x74, 0x64, 0x62, 0x5c, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e,
+	0x5c, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x1e, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x3a,
+	0x3a, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x70, 0x6c, 0x61, 0x6e, 0x3a, 0x3a, 0x56, 0x31,
+	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescOnce sync.Once
+	file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescData = file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDesc
+)
+
+func file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescGZIP() []byte {
+	file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescOnce.Do(func() {
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescData = protoimpl.X.CompressGZIP(file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescData)
+	})
+	return file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDescData
+}
+
+var file_frostdb_logicalplan_v1alpha1_logicalplan_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
+var file_frostdb_logicalplan_v1alpha1_logicalplan_proto_goTypes = []interface{}{
+	(Sample_Method)(0),            // 0: frostdb.logicalplan.v1alpha1.Sample.Method
+	(GapFill_Fill)(0),             // 1: frostdb.logicalplan.v1alpha1.GapFill.Fill
+	(BinaryExpr_Op)(0),            // 2: frostdb.logicalplan.v1alpha1.BinaryExpr.Op
+	(AggregationFunction_Type)(0), // 3: frostdb.logicalplan.v1alpha1.AggregationFunction.Type
+	(*PlanNode)(nil),              // 4: frostdb.logicalplan.v1alpha1.PlanNode
+	(*TableScan)(nil),             // 5: frostdb.logicalplan.v1alpha1.TableScan
+	(*SchemaScan)(nil),            // 6: frostdb.logicalplan.v1alpha1.SchemaScan
+	(*Filter)(nil),                // 7: frostdb.logicalplan.v1alpha1.Filter
+	(*Distinct)(nil),              // 8: frostdb.logicalplan.v1alpha1.Distinct
+	(*Projection)(nil),            // 9: frostdb.logicalplan.v1alpha1.Projection
+	(*Aggregation)(nil),           // 10: frostdb.logicalplan.v1alpha1.Aggregation
+	(*Unnest)(nil),                // 11: frostdb.logicalplan.v1alpha1.Unnest
+	(*Sample)(nil),                // 12: frostdb.logicalplan.v1alpha1.Sample
+	(*GapFill)(nil),               // 13: frostdb.logicalplan.v1alpha1.GapFill
+	(*Pivot)(nil),                 // 14: frostdb.logicalplan.v1alpha1.Pivot
+	(*Unpivot)(nil),               // 15: frostdb.logicalplan.v1alpha1.Unpivot
+	(*SemiJoin)(nil),              // 16: frostdb.logicalplan.v1alpha1.SemiJoin
+	(*Expr)(nil),                  // 17: frostdb.logicalplan.v1alpha1.Expr
+	(*BinaryExpr)(nil),            // 18: frostdb.logicalplan.v1alpha1.BinaryExpr
+	(*Column)(nil),                // 19: frostdb.logicalplan.v1alpha1.Column
+	(*DynamicColumn)(nil),         // 20: frostdb.logicalplan.v1alpha1.DynamicColumn
+	(*Literal)(nil),               // 21: frostdb.logicalplan.v1alpha1.Literal
+	(*Null)(nil),                  // 22: frostdb.logicalplan.v1alpha1.Null
+	(*AggregationFunction)(nil),   // 23: frostdb.logicalplan.v1alpha1.AggregationFunction
+	(*Alias)(nil),                 // 24: frostdb.logicalplan.v1alpha1.Alias
+	(*Duration)(nil),              // 25: frostdb.logicalplan.v1alpha1.Duration
+	(*Average)(nil),               // 26: frostdb.logicalplan.v1alpha1.Average
+	(*RegexpColumnMatch)(nil),     // 27: frostdb.logicalplan.v1alpha1.RegexpColumnMatch
+	(*All)(nil),                   // 28: frostdb.logicalplan.v1alpha1.All
+	(*WidthBucket)(nil),           // 29: frostdb.logicalplan.v1alpha1.WidthBucket
+	(*In)(nil),                    // 30: frostdb.logicalplan.v1alpha1.In
+	(*Not)(nil),                   // 31: frostdb.logicalplan.v1alpha1.Not
+}
+var file_frostdb_logicalplan_v1alpha1_logicalplan_proto_depIdxs = []int32{
+	4,  // 0: frostdb.logicalplan.v1alpha1.PlanNode.input:type_name -> frostdb.logicalplan.v1alpha1.PlanNode
+	5,  // 1: frostdb.logicalplan.v1alpha1.PlanNode.table_scan:type_name -> frostdb.logicalplan.v1alpha1.TableScan
+	6,  // 2: frostdb.logicalplan.v1alpha1.PlanNode.schema_scan:type_name -> frostdb.logicalplan.v1alpha1.SchemaScan
+	7,  // 3: frostdb.logicalplan.v1alpha1.PlanNode.filter:type_name -> frostdb.logicalplan.v1alpha1.Filter
+	8,  // 4: frostdb.logicalplan.v1alpha1.PlanNode.distinct:type_name -> frostdb.logicalplan.v1alpha1.Distinct
+	9,  // 5: frostdb.logicalplan.v1alpha1.PlanNode.projection:type_name -> frostdb.logicalplan.v1alpha1.Projection
+	10, // 6: frostdb.logicalplan.v1alpha1.PlanNode.aggregation:type_name -> frostdb.logicalplan.v1alpha1.Aggregation
+	11, // 7: frostdb.logicalplan.v1alpha1.PlanNode.unnest:type_name -> frostdb.logicalplan.v1alpha1.Unnest
+	12, // 8: frostdb.logicalplan.v1alpha1.PlanNode.sample:type_name -> frostdb.logicalplan.v1alpha1.Sample
+	13, // 9: frostdb.logicalplan.v1alpha1.PlanNode.gap_fill:type_name -> frostdb.logicalplan.v1alpha1.GapFill
+	14, // 10: frostdb.logicalplan.v1alpha1.PlanNode.pivot:type_name -> frostdb.logicalplan.v1alpha1.Pivot
+	15, // 11: frostdb.logicalplan.v1alpha1.PlanNode.unpivot:type_name -> frostdb.logicalplan.v1alpha1.Unpivot
+	16, // 12: frostdb.logicalplan.v1alpha1.PlanNode.semi_join:type_name -> frostdb.logicalplan.v1alpha1.SemiJoin
+	17, // 13: frostdb.logicalplan.v1alpha1.TableScan.physical_projection:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 14: frostdb.logicalplan.v1alpha1.TableScan.filter:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 15: frostdb.logicalplan.v1alpha1.TableScan.distinct:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 16: frostdb.logicalplan.v1alpha1.TableScan.projection:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 17: frostdb.logicalplan.v1alpha1.TableScan.metadata_aggregations:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	12, // 18: frostdb.logicalplan.v1alpha1.TableScan.sample:type_name -> frostdb.logicalplan.v1alpha1.Sample
+	17, // 19: frostdb.logicalplan.v1alpha1.SchemaScan.physical_projection:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 20: frostdb.logicalplan.v1alpha1.SchemaScan.filter:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 21: frostdb.logicalplan.v1alpha1.SchemaScan.distinct:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 22: frostdb.logicalplan.v1alpha1.SchemaScan.projection:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 23: frostdb.logicalplan.v1alpha1.Filter.expr:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 24: frostdb.logicalplan.v1alpha1.Distinct.exprs:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 25: frostdb.logicalplan.v1alpha1.Projection.exprs:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 26: frostdb.logicalplan.v1alpha1.Aggregation.agg_exprs:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 27: frostdb.logicalplan.v1alpha1.Aggregation.group_exprs:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 28: frostdb.logicalplan.v1alpha1.Unnest.expr:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	0,  // 29: frostdb.logicalplan.v1alpha1.Sample.method:type_name -> frostdb.logicalplan.v1alpha1.Sample.Method
+	25, // 30: frostdb.logicalplan.v1alpha1.GapFill.step:type_name -> frostdb.logicalplan.v1alpha1.Duration
+	1,  // 31: frostdb.logicalplan.v1alpha1.GapFill.fill:type_name -> frostdb.logicalplan.v1alpha1.GapFill.Fill
+	17, // 32: frostdb.logicalplan.v1alpha1.Pivot.key:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 33: frostdb.logicalplan.v1alpha1.Pivot.value:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 34: frostdb.logicalplan.v1alpha1.Unpivot.expr:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 35: frostdb.logicalplan.v1alpha1.SemiJoin.key:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	4,  // 36: frostdb.logicalplan.v1alpha1.SemiJoin.inner:type_name -> frostdb.logicalplan.v1alpha1.PlanNode
+	17, // 37: frostdb.logicalplan.v1alpha1.SemiJoin.inner_key:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	18, // 38: frostdb.logicalplan.v1alpha1.Expr.binary:type_name -> frostdb.logicalplan.v1alpha1.BinaryExpr
+	19, // 39: frostdb.logicalplan.v1alpha1.Expr.column:type_name -> frostdb.logicalplan.v1alpha1.Column
+	20, // 40: frostdb.logicalplan.v1alpha1.Expr.dynamic_column:type_name -> frostdb.logicalplan.v1alpha1.DynamicColumn
+	21, // 41: frostdb.logicalplan.v1alpha1.Expr.literal:type_name -> frostdb.logicalplan.v1alpha1.Literal
+	23, // 42: frostdb.logicalplan.v1alpha1.Expr.aggregation_function:type_name -> frostdb.logicalplan.v1alpha1.AggregationFunction
+	24, // 43: frostdb.logicalplan.v1alpha1.Expr.alias:type_name -> frostdb.logicalplan.v1alpha1.Alias
+	25, // 44: frostdb.logicalplan.v1alpha1.Expr.duration:type_name -> frostdb.logicalplan.v1alpha1.Duration
+	26, // 45: frostdb.logicalplan.v1alpha1.Expr.average:type_name -> frostdb.logicalplan.v1alpha1.Average
+	27, // 46: frostdb.logicalplan.v1alpha1.Expr.regexp_column_match:type_name -> frostdb.logicalplan.v1alpha1.RegexpColumnMatch
+	28, // 47: frostdb.logicalplan.v1alpha1.Expr.all:type_name -> frostdb.logicalplan.v1alpha1.All
+	31, // 48: frostdb.logicalplan.v1alpha1.Expr.not:type_name -> frostdb.logicalplan.v1alpha1.Not
+	29, // 49: frostdb.logicalplan.v1alpha1.Expr.width_bucket:type_name -> frostdb.logicalplan.v1alpha1.WidthBucket
+	30, // 50: frostdb.logicalplan.v1alpha1.Expr.in:type_name -> frostdb.logicalplan.v1alpha1.In
+	17, // 51: frostdb.logicalplan.v1alpha1.BinaryExpr.left:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	2,  // 52: frostdb.logicalplan.v1alpha1.BinaryExpr.op:type_name -> frostdb.logicalplan.v1alpha1.BinaryExpr.Op
+	17, // 53: frostdb.logicalplan.v1alpha1.BinaryExpr.right:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	22, // 54: frostdb.logicalplan.v1alpha1.Literal.null:type_name -> frostdb.logicalplan.v1alpha1.Null
+	3,  // 55: frostdb.logicalplan.v1alpha1.AggregationFunction.type:type_name -> frostdb.logicalplan.v1alpha1.AggregationFunction.Type
+	17, // 56: frostdb.logicalplan.v1alpha1.AggregationFunction.expr:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 57: frostdb.logicalplan.v1alpha1.AggregationFunction.order_by:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 58: frostdb.logicalplan.v1alpha1.AggregationFunction.paired:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 59: frostdb.logicalplan.v1alpha1.Alias.expr:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 60: frostdb.logicalplan.v1alpha1.Average.expr:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 61: frostdb.logicalplan.v1alpha1.WidthBucket.expr:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	17, // 62: frostdb.logicalplan.v1alpha1.In.expr:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	21, // 63: frostdb.logicalplan.v1alpha1.In.values:type_name -> frostdb.logicalplan.v1alpha1.Literal
+	17, // 64: frostdb.logicalplan.v1alpha1.Not.expr:type_name -> frostdb.logicalplan.v1alpha1.Expr
+	65, // [65:65] is the sub-list for method output_type
+	65, // [65:65] is the sub-list for method input_type
+	65, // [65:65] is the sub-list for extension type_name
+	65, // [65:65] is the sub-list for extension extendee
+	0,  // [0:65] is the sub-list for field type_name
+}
+
+func init() { file_frostdb_logicalplan_v1alpha1_logicalplan_proto_init() }
+func file_frostdb_logicalplan_v1alpha1_logicalplan_proto_init() {
+	if File_frostdb_logicalplan_v1alpha1_logicalplan_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlanNode); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TableScan); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SchemaScan); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Filter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Distinct); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Projection); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Aggregation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Unnest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Sample); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GapFill); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Pivot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Unpivot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SemiJoin); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Expr); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BinaryExpr); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Column); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DynamicColumn); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Literal); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Null); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AggregationFunction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Alias); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Duration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Average); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RegexpColumnMatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*All); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WidthBucket); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*In); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Not); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*PlanNode_TableScan)(nil),
+		(*PlanNode_SchemaScan)(nil),
+		(*PlanNode_Filter)(nil),
+		(*PlanNode_Distinct)(nil),
+		(*PlanNode_Projection)(nil),
+		(*PlanNode_Aggregation)(nil),
+		(*PlanNode_Unnest)(nil),
+		(*PlanNode_Sample)(nil),
+		(*PlanNode_GapFill)(nil),
+		(*PlanNode_Pivot)(nil),
+		(*PlanNode_Unpivot)(nil),
+		(*PlanNode_SemiJoin)(nil),
+	}
+	file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[13].OneofWrappers = []interface{}{
+		(*Expr_Binary)(nil),
+		(*Expr_Column)(nil),
+		(*Expr_DynamicColumn)(nil),
+		(*Expr_Literal)(nil),
+		(*Expr_AggregationFunction)(nil),
+		(*Expr_Alias)(nil),
+		(*Expr_Duration)(nil),
+		(*Expr_Average)(nil),
+		(*Expr_RegexpColumnMatch)(nil),
+		(*Expr_All)(nil),
+		(*Expr_Not)(nil),
+		(*Expr_WidthBucket)(nil),
+		(*Expr_In)(nil),
+	}
+	file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes[17].OneofWrappers = []interface{}{
+		(*Literal_Null)(nil),
+		(*Literal_BoolValue)(nil),
+		(*Literal_Int32Value)(nil),
+		(*Literal_Uint32Value)(nil),
+		(*Literal_Int64Value)(nil),
+		(*Literal_Uint64Value)(nil),
+		(*Literal_FloatValue)(nil),
+		(*Literal_DoubleValue)(nil),
+		(*Literal_StringValue)(nil),
+		(*Literal_BinaryValue)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDesc,
+			NumEnums:      4,
+			NumMessages:   28,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_frostdb_logicalplan_v1alpha1_logicalplan_proto_goTypes,
+		DependencyIndexes: file_frostdb_logicalplan_v1alpha1_logicalplan_proto_depIdxs,
+		EnumInfos:         file_frostdb_logicalplan_v1alpha1_logicalplan_proto_enumTypes,
+		MessageInfos:      file_frostdb_logicalplan_v1alpha1_logicalplan_proto_msgTypes,
+	}.Build()
+	File_frostdb_logicalplan_v1alpha1_logicalplan_proto = out.File
+	file_frostdb_logicalplan_v1alpha1_logicalplan_proto_rawDesc = nil
+	file_frostdb_logicalplan_v1alpha1_logicalplan_proto_goTypes = nil
+	file_frostdb_logicalplan_v1alpha1_logicalplan_proto_depIdxs = nil
+}
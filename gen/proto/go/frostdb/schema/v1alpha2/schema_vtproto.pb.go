@@ -315,6 +315,16 @@ func (m *StorageLayout) MarshalToSizedBufferVT(dAtA []byte) (int, error) {
 		i -= len(m.unknownFields)
 		copy(dAtA[i:], m.unknownFields)
 	}
+	if m.BloomFilter {
+		i--
+		if m.BloomFilter {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
 	if m.Repeated {
 		i--
 		if m.Repeated {
@@ -544,6 +554,9 @@ func (m *StorageLayout) SizeVT() (n int) {
 	if m.Repeated {
 		n += 2
 	}
+	if m.BloomFilter {
+		n += 2
+	}
 	n += len(m.unknownFields)
 	return n
 }
@@ -1250,6 +1263,26 @@ func (m *StorageLayout) UnmarshalVT(dAtA []byte) error {
 				}
 			}
 			m.Repeated = bool(v != 0)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BloomFilter", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflow
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.BloomFilter = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skip(dAtA[iNdEx:])
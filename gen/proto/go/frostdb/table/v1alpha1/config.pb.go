@@ -22,6 +22,67 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Function is an aggregation function.
+type Aggregation_Function int32
+
+const (
+	// FUNCTION_UNSPECIFIED is the default value and is invalid.
+	Aggregation_FUNCTION_UNSPECIFIED Aggregation_Function = 0
+	// FUNCTION_SUM sums the values.
+	Aggregation_FUNCTION_SUM Aggregation_Function = 1
+	// FUNCTION_MIN is the minimum of the values.
+	Aggregation_FUNCTION_MIN Aggregation_Function = 2
+	// FUNCTION_MAX is the maximum of the values.
+	Aggregation_FUNCTION_MAX Aggregation_Function = 3
+	// FUNCTION_COUNT counts the values.
+	Aggregation_FUNCTION_COUNT Aggregation_Function = 4
+)
+
+// Enum value maps for Aggregation_Function.
+var (
+	Aggregation_Function_name = map[int32]string{
+		0: "FUNCTION_UNSPECIFIED",
+		1: "FUNCTION_SUM",
+		2: "FUNCTION_MIN",
+		3: "FUNCTION_MAX",
+		4: "FUNCTION_COUNT",
+	}
+	Aggregation_Function_value = map[string]int32{
+		"FUNCTION_UNSPECIFIED": 0,
+		"FUNCTION_SUM":         1,
+		"FUNCTION_MIN":         2,
+		"FUNCTION_MAX":         3,
+		"FUNCTION_COUNT":       4,
+	}
+)
+
+func (x Aggregation_Function) Enum() *Aggregation_Function {
+	p := new(Aggregation_Function)
+	*p = x
+	return p
+}
+
+func (x Aggregation_Function) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Aggregation_Function) Descriptor() protoreflect.EnumDescriptor {
+	return file_frostdb_table_v1alpha1_config_proto_enumTypes[0].Descriptor()
+}
+
+func (Aggregation_Function) Type() protoreflect.EnumType {
+	return &file_frostdb_table_v1alpha1_config_proto_enumTypes[0]
+}
+
+func (x Aggregation_Function) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Aggregation_Function.Descriptor instead.
+func (Aggregation_Function) EnumDescriptor() ([]byte, []int) {
+	return file_frostdb_table_v1alpha1_config_proto_rawDescGZIP(), []int{2, 0}
+}
+
 // TableConfig is the configuration information for a table.
 type TableConfig struct {
 	state         protoimpl.MessageState
@@ -41,6 +102,9 @@ type TableConfig struct {
 	BlockReaderLimit uint64 `protobuf:"varint,4,opt,name=block_reader_limit,json=blockReaderLimit,proto3" json:"block_reader_limit,omitempty"`
 	// DisableWal disables the write ahead log for this table.
 	DisableWal bool `protobuf:"varint,5,opt,name=disable_wal,json=disableWal,proto3" json:"disable_wal,omitempty"`
+	// MaterializedView declares the table as an aggregation of another table
+	// that is maintained as records are inserted into the other table.
+	MaterializedView *MaterializedView `protobuf:"bytes,6,opt,name=materialized_view,json=materializedView,proto3" json:"materialized_view,omitempty"`
 }
 
 func (x *TableConfig) Reset() {
@@ -117,6 +181,13 @@ func (x *TableConfig) GetDisableWal() bool {
 	return false
 }
 
+func (x *TableConfig) GetMaterializedView() *MaterializedView {
+	if x != nil {
+		return x.MaterializedView
+	}
+	return nil
+}
+
 type isTableConfig_Schema interface {
 	isTableConfig_Schema()
 }
@@ -135,6 +206,242 @@ func (*TableConfig_DeprecatedSchema) isTableConfig_Schema() {}
 
 func (*TableConfig_SchemaV2) isTableConfig_Schema() {}
 
+// MaterializedView is the definition of a table that is maintained as an
+// aggregation of the records inserted into a source table.
+type MaterializedView struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Source is the name of the table whose inserted records are aggregated.
+	Source string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	// Aggregations are the aggregations computed for each group.
+	Aggregations []*Aggregation `protobuf:"bytes,2,rep,name=aggregations,proto3" json:"aggregations,omitempty"`
+	// GroupBy are the expressions the records are grouped by.
+	GroupBy []*GroupBy `protobuf:"bytes,3,rep,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"`
+}
+
+func (x *MaterializedView) Reset() {
+	*x = MaterializedView{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_table_v1alpha1_config_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MaterializedView) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaterializedView) ProtoMessage() {}
+
+func (x *MaterializedView) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_table_v1alpha1_config_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaterializedView.ProtoReflect.Descriptor instead.
+func (*MaterializedView) Descriptor() ([]byte, []int) {
+	return file_frostdb_table_v1alpha1_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MaterializedView) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *MaterializedView) GetAggregations() []*Aggregation {
+	if x != nil {
+		return x.Aggregations
+	}
+	return nil
+}
+
+func (x *MaterializedView) GetGroupBy() []*GroupBy {
+	if x != nil {
+		return x.GroupBy
+	}
+	return nil
+}
+
+// Aggregation is an aggregation of a column of the source table.
+type Aggregation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Function is the aggregation function.
+	Function Aggregation_Function `protobuf:"varint,1,opt,name=function,proto3,enum=frostdb.table.v1alpha1.Aggregation_Function" json:"function,omitempty"`
+	// Column is the name of the aggregated column.
+	Column string `protobuf:"bytes,2,opt,name=column,proto3" json:"column,omitempty"`
+	// Alias is the name of the column the result is stored in.
+	Alias string `protobuf:"bytes,3,opt,name=alias,proto3" json:"alias,omitempty"`
+}
+
+func (x *Aggregation) Reset() {
+	*x = Aggregation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_table_v1alpha1_config_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Aggregation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Aggregation) ProtoMessage() {}
+
+func (x *Aggregation) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_table_v1alpha1_config_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Aggregation.ProtoReflect.Descriptor instead.
+func (*Aggregation) Descriptor() ([]byte, []int) {
+	return file_frostdb_table_v1alpha1_config_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Aggregation) GetFunction() Aggregation_Function {
+	if x != nil {
+		return x.Function
+	}
+	return Aggregation_FUNCTION_UNSPECIFIED
+}
+
+func (x *Aggregation) GetColumn() string {
+	if x != nil {
+		return x.Column
+	}
+	return ""
+}
+
+func (x *Aggregation) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+// GroupBy is an expression records are grouped by.
+type GroupBy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Expr is the expression.
+	//
+	// Types that are assignable to Expr:
+	//
+	//	*GroupBy_Column
+	//	*GroupBy_DynamicColumn
+	//	*GroupBy_DurationMillis
+	Expr isGroupBy_Expr `protobuf_oneof:"expr"`
+}
+
+func (x *GroupBy) Reset() {
+	*x = GroupBy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_table_v1alpha1_config_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GroupBy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupBy) ProtoMessage() {}
+
+func (x *GroupBy) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_table_v1alpha1_config_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupBy.ProtoReflect.Descriptor instead.
+func (*GroupBy) Descriptor() ([]byte, []int) {
+	return file_frostdb_table_v1alpha1_config_proto_rawDescGZIP(), []int{3}
+}
+
+func (m *GroupBy) GetExpr() isGroupBy_Expr {
+	if m != nil {
+		return m.Expr
+	}
+	return nil
+}
+
+func (x *GroupBy) GetColumn() string {
+	if x, ok := x.GetExpr().(*GroupBy_Column); ok {
+		return x.Column
+	}
+	return ""
+}
+
+func (x *GroupBy) GetDynamicColumn() string {
+	if x, ok := x.GetExpr().(*GroupBy_DynamicColumn); ok {
+		return x.DynamicColumn
+	}
+	return ""
+}
+
+func (x *GroupBy) GetDurationMillis() int64 {
+	if x, ok := x.GetExpr().(*GroupBy_DurationMillis); ok {
+		return x.DurationMillis
+	}
+	return 0
+}
+
+type isGroupBy_Expr interface {
+	isGroupBy_Expr()
+}
+
+type GroupBy_Column struct {
+	// Column groups by the column with the given name.
+	Column string `protobuf:"bytes,1,opt,name=column,proto3,oneof"`
+}
+
+type GroupBy_DynamicColumn struct {
+	// DynamicColumn groups by all columns of the dynamic column with the given name.
+	DynamicColumn string `protobuf:"bytes,2,opt,name=dynamic_column,json=dynamicColumn,proto3,oneof"`
+}
+
+type GroupBy_DurationMillis struct {
+	// DurationMillis groups the timestamp column into windows of the given number of milliseconds.
+	DurationMillis int64 `protobuf:"varint,3,opt,name=duration_millis,json=durationMillis,proto3,oneof"`
+}
+
+func (*GroupBy_Column) isGroupBy_Expr() {}
+
+func (*GroupBy_DynamicColumn) isGroupBy_Expr() {}
+
+func (*GroupBy_DurationMillis) isGroupBy_Expr() {}
+
 var File_frostdb_table_v1alpha1_config_proto protoreflect.FileDescriptor
 
 var file_frostdb_table_v1alpha1_config_proto_rawDesc = []byte{
@@ -146,7 +453,7 @@ var file_frostdb_table_v1alpha1_config_proto_rawDesc = []byte{
 	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2f, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2e, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x1a, 0x24, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f, 0x73, 0x63, 0x68,
 	0x65, 0x6d, 0x61, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x32, 0x2f, 0x73, 0x63, 0x68,
-	0x65, 0x6d, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x9c, 0x02, 0x0a, 0x0b, 0x54, 0x61,
+	0x65, 0x6d, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf3, 0x02, 0x0a, 0x0b, 0x54, 0x61,
 	0x62, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x4e, 0x0a, 0x11, 0x64, 0x65, 0x70,
 	0x72, 0x65, 0x63, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73,
@@ -163,24 +470,64 @@ var file_frostdb_table_v1alpha1_config_proto_rawDesc = []byte{
 	0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x62, 0x6c, 0x6f,
 	0x63, 0x6b, 0x52, 0x65, 0x61, 0x64, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1f, 0x0a,
 	0x0b, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x77, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x0a, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x57, 0x61, 0x6c, 0x42, 0x08,
-	0x0a, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x42, 0xf6, 0x01, 0x0a, 0x1a, 0x63, 0x6f, 0x6d,
-	0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x2e, 0x76,
-	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x42, 0x0b, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x50,
-	0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x51, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
-	0x6f, 0x6d, 0x2f, 0x70, 0x6f, 0x6c, 0x61, 0x72, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x73, 0x2f,
-	0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2f, 0x67, 0x6f, 0x2f, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f, 0x74, 0x61, 0x62,
-	0x6c, 0x65, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x3b, 0x74, 0x61, 0x62, 0x6c,
-	0x65, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xa2, 0x02, 0x03, 0x46, 0x54, 0x58, 0xaa,
-	0x02, 0x16, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x2e,
-	0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xca, 0x02, 0x16, 0x46, 0x72, 0x6f, 0x73, 0x74,
-	0x64, 0x62, 0x5c, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x5c, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
-	0x31, 0xe2, 0x02, 0x22, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x5c, 0x54, 0x61, 0x62, 0x6c,
-	0x65, 0x5c, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65,
-	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x18, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62,
-	0x3a, 0x3a, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x3a, 0x3a, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
-	0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x28, 0x08, 0x52, 0x0a, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x57, 0x61, 0x6c, 0x12, 0x55,
+	0x0a, 0x11, 0x6d, 0x61, 0x74, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x5f, 0x76,
+	0x69, 0x65, 0x77, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x66, 0x72, 0x6f, 0x73,
+	0x74, 0x64, 0x62, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x4d, 0x61, 0x74, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x56,
+	0x69, 0x65, 0x77, 0x52, 0x10, 0x6d, 0x61, 0x74, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65,
+	0x64, 0x56, 0x69, 0x65, 0x77, 0x42, 0x08, 0x0a, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x22,
+	0xaf, 0x01, 0x0a, 0x10, 0x4d, 0x61, 0x74, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64,
+	0x56, 0x69, 0x65, 0x77, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x47, 0x0a, 0x0c,
+	0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x23, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x74, 0x61, 0x62,
+	0x6c, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x41, 0x67, 0x67, 0x72,
+	0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x3a, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x62,
+	0x79, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64,
+	0x62, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
+	0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x42, 0x79, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x42,
+	0x79, 0x22, 0xf5, 0x01, 0x0a, 0x0b, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x48, 0x0a, 0x08, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x2c, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x74, 0x61,
+	0x62, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x41, 0x67, 0x67,
+	0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x08, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x63,
+	0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x6f, 0x6c,
+	0x75, 0x6d, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x22, 0x6e, 0x0a, 0x08, 0x46, 0x75, 0x6e,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x14, 0x46, 0x55, 0x4e, 0x43, 0x54, 0x49, 0x4f,
+	0x4e, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12,
+	0x10, 0x0a, 0x0c, 0x46, 0x55, 0x4e, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x55, 0x4d, 0x10,
+	0x01, 0x12, 0x10, 0x0a, 0x0c, 0x46, 0x55, 0x4e, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4d, 0x49,
+	0x4e, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x46, 0x55, 0x4e, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f,
+	0x4d, 0x41, 0x58, 0x10, 0x03, 0x12, 0x12, 0x0a, 0x0e, 0x46, 0x55, 0x4e, 0x43, 0x54, 0x49, 0x4f,
+	0x4e, 0x5f, 0x43, 0x4f, 0x55, 0x4e, 0x54, 0x10, 0x04, 0x22, 0x7f, 0x0a, 0x07, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x42, 0x79, 0x12, 0x18, 0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x27,
+	0x0a, 0x0e, 0x64, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x5f, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0d, 0x64, 0x79, 0x6e, 0x61, 0x6d, 0x69,
+	0x63, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x29, 0x0a, 0x0f, 0x64, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x48, 0x00, 0x52, 0x0e, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x69, 0x6c, 0x6c,
+	0x69, 0x73, 0x42, 0x06, 0x0a, 0x04, 0x65, 0x78, 0x70, 0x72, 0x42, 0xf6, 0x01, 0x0a, 0x1a, 0x63,
+	0x6f, 0x6d, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x42, 0x0b, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x51, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x70, 0x6f, 0x6c, 0x61, 0x72, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c,
+	0x73, 0x2f, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x6f, 0x2f, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f, 0x74,
+	0x61, 0x62, 0x6c, 0x65, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x3b, 0x74, 0x61,
+	0x62, 0x6c, 0x65, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xa2, 0x02, 0x03, 0x46, 0x54,
+	0x58, 0xaa, 0x02, 0x16, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x54, 0x61, 0x62, 0x6c,
+	0x65, 0x2e, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xca, 0x02, 0x16, 0x46, 0x72, 0x6f,
+	0x73, 0x74, 0x64, 0x62, 0x5c, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x5c, 0x56, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0xe2, 0x02, 0x22, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x5c, 0x54, 0x61,
+	0x62, 0x6c, 0x65, 0x5c, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x5c, 0x47, 0x50, 0x42,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x18, 0x46, 0x72, 0x6f, 0x73, 0x74,
+	0x64, 0x62, 0x3a, 0x3a, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x3a, 0x3a, 0x56, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -195,20 +542,29 @@ func file_frostdb_table_v1alpha1_config_proto_rawDescGZIP() []byte {
 	return file_frostdb_table_v1alpha1_config_proto_rawDescData
 }
 
-var file_frostdb_table_v1alpha1_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_frostdb_table_v1alpha1_config_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_frostdb_table_v1alpha1_config_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_frostdb_table_v1alpha1_config_proto_goTypes = []interface{}{
-	(*TableConfig)(nil),     // 0: frostdb.table.v1alpha1.TableConfig
-	(*v1alpha1.Schema)(nil), // 1: frostdb.schema.v1alpha1.Schema
-	(*v1alpha2.Schema)(nil), // 2: frostdb.schema.v1alpha2.Schema
+	(Aggregation_Function)(0), // 0: frostdb.table.v1alpha1.Aggregation.Function
+	(*TableConfig)(nil),       // 1: frostdb.table.v1alpha1.TableConfig
+	(*MaterializedView)(nil),  // 2: frostdb.table.v1alpha1.MaterializedView
+	(*Aggregation)(nil),       // 3: frostdb.table.v1alpha1.Aggregation
+	(*GroupBy)(nil),           // 4: frostdb.table.v1alpha1.GroupBy
+	(*v1alpha1.Schema)(nil),   // 5: frostdb.schema.v1alpha1.Schema
+	(*v1alpha2.Schema)(nil),   // 6: frostdb.schema.v1alpha2.Schema
 }
 var file_frostdb_table_v1alpha1_config_proto_depIdxs = []int32{
-	1, // 0: frostdb.table.v1alpha1.TableConfig.deprecated_schema:type_name -> frostdb.schema.v1alpha1.Schema
-	2, // 1: frostdb.table.v1alpha1.TableConfig.schema_v2:type_name -> frostdb.schema.v1alpha2.Schema
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	5, // 0: frostdb.table.v1alpha1.TableConfig.deprecated_schema:type_name -> frostdb.schema.v1alpha1.Schema
+	6, // 1: frostdb.table.v1alpha1.TableConfig.schema_v2:type_name -> frostdb.schema.v1alpha2.Schema
+	2, // 2: frostdb.table.v1alpha1.TableConfig.materialized_view:type_name -> frostdb.table.v1alpha1.MaterializedView
+	3, // 3: frostdb.table.v1alpha1.MaterializedView.aggregations:type_name -> frostdb.table.v1alpha1.Aggregation
+	4, // 4: frostdb.table.v1alpha1.MaterializedView.group_by:type_name -> frostdb.table.v1alpha1.GroupBy
+	0, // 5: frostdb.table.v1alpha1.Aggregation.function:type_name -> frostdb.table.v1alpha1.Aggregation.Function
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_frostdb_table_v1alpha1_config_proto_init() }
@@ -229,23 +585,65 @@ func file_frostdb_table_v1alpha1_config_proto_init() {
 				return nil
 			}
 		}
+		file_frostdb_table_v1alpha1_config_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MaterializedView); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_table_v1alpha1_config_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Aggregation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_table_v1alpha1_config_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GroupBy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_frostdb_table_v1alpha1_config_proto_msgTypes[0].OneofWrappers = []interface{}{
 		(*TableConfig_DeprecatedSchema)(nil),
 		(*TableConfig_SchemaV2)(nil),
 	}
+	file_frostdb_table_v1alpha1_config_proto_msgTypes[3].OneofWrappers = []interface{}{
+		(*GroupBy_Column)(nil),
+		(*GroupBy_DynamicColumn)(nil),
+		(*GroupBy_DurationMillis)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_frostdb_table_v1alpha1_config_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   1,
+			NumEnums:      1,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_frostdb_table_v1alpha1_config_proto_goTypes,
 		DependencyIndexes: file_frostdb_table_v1alpha1_config_proto_depIdxs,
+		EnumInfos:         file_frostdb_table_v1alpha1_config_proto_enumTypes,
 		MessageInfos:      file_frostdb_table_v1alpha1_config_proto_msgTypes,
 	}.Build()
 	File_frostdb_table_v1alpha1_config_proto = out.File
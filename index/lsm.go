@@ -213,7 +213,11 @@ func (l *LSM) Iterate(iter func(node *Node) bool) {
 	l.levels.Iterate(iter)
 }
 
-func (l *LSM) Scan(ctx context.Context, _ string, _ *dynparquet.Schema, filter logicalplan.Expr, tx uint64, callback func(context.Context, any) error) error {
+// Scan passes the records and row groups of the parts visible to the
+// transaction that may match the filter on to the callback. The row groups of
+// a sample carried by the context are identified by the prefix, their part's
+// position counted from the oldest part and their index within the part.
+func (l *LSM) Scan(ctx context.Context, prefix string, _ *dynparquet.Schema, filter logicalplan.Expr, tx uint64, callback func(context.Context, any) error) error {
 	l.RLock()
 	defer l.RUnlock()
 
@@ -221,19 +225,41 @@ func (l *LSM) Scan(ctx context.Context, _ string, _ *dynparquet.Schema, filter l
 	if err != nil {
 		return fmt.Errorf("boolean expr: %w", err)
 	}
+	stats := expr.ScanStatsFromContext(ctx)
+	sample := expr.SampleFromContext(ctx)
+	// New parts are prepended, so that the positions of the older parts don't
+	// change with writes.
+	position := 0
+	if sample != nil {
+		l.levels.Iterate(func(node *Node) bool {
+			if node.part != nil {
+				position++
+			}
+			return true
+		})
+	}
 	var iterError error
 	l.levels.Iterate(func(node *Node) bool {
 		if node.part == nil { // encountered a sentinel node; continue on
 			return true
 		}
+		position--
 
 		if node.part.TX() > tx { // skip parts that are newer than this transaction
 			return true
 		}
 
 		if r := node.part.Record(); r != nil {
+			var key string
+			if sample != nil {
+				key = fmt.Sprintf("%s/%d", prefix, position)
+			}
+			if !expr.SampleRowGroup(sample, key) {
+				return true
+			}
+			stats.ObservePart(false)
 			r.Retain()
-			if err := callback(ctx, r); err != nil {
+			if err := callback(ctx, expr.RowGroupOfSample(sample, key, r)); err != nil {
 				iterError = err
 				return false
 			}
@@ -246,21 +272,32 @@ func (l *LSM) Scan(ctx context.Context, _ string, _ *dynparquet.Schema, filter l
 			return false
 		}
 
+		pruned := true
 		for i := 0; i < buf.NumRowGroups(); i++ {
+			var key string
+			if sample != nil {
+				key = fmt.Sprintf("%s/%d/%d", prefix, position, i)
+			}
+			if !expr.SampleRowGroup(sample, key) {
+				continue
+			}
 			rg := buf.DynamicRowGroup(i)
 			mayContainUsefulData, err := booleanFilter.Eval(rg)
 			if err != nil {
 				iterError = err
 				return false
 			}
+			stats.ObserveRowGroup(!mayContainUsefulData)
 
 			if mayContainUsefulData {
-				if err := callback(ctx, rg); err != nil {
+				pruned = false
+				if err := callback(ctx, expr.RowGroupOfSample(sample, key, rg)); err != nil {
 					iterError = err
 					return false
 				}
 			}
 		}
+		stats.ObservePart(pruned)
 		return true
 	})
 	return iterError
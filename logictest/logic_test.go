@@ -59,6 +59,32 @@ var schemas = map[string]*schemapb.Schema{
 			Direction: schemapb.SortingColumn_DIRECTION_ASCENDING,
 		}},
 	},
+	"repeated": {
+		Name: "repeated",
+		Columns: []*schemapb.Column{{
+			Name: "locations",
+			StorageLayout: &schemapb.StorageLayout{
+				Type:     schemapb.StorageLayout_TYPE_STRING,
+				Encoding: schemapb.StorageLayout_ENCODING_RLE_DICTIONARY,
+				Repeated: true,
+			},
+		}, {
+			Name: "name",
+			StorageLayout: &schemapb.StorageLayout{
+				Type:     schemapb.StorageLayout_TYPE_STRING,
+				Encoding: schemapb.StorageLayout_ENCODING_RLE_DICTIONARY,
+			},
+		}, {
+			Name: "value",
+			StorageLayout: &schemapb.StorageLayout{
+				Type: schemapb.StorageLayout_TYPE_INT64,
+			},
+		}},
+		SortingColumns: []*schemapb.SortingColumn{{
+			Name:      "name",
+			Direction: schemapb.SortingColumn_DIRECTION_ASCENDING,
+		}},
+	},
 	"prehashed": {
 		Name: "test",
 		Columns: []*schemapb.Column{{
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -75,6 +76,10 @@ type Runner struct {
 	sqlParser                 *sqlparse.Parser
 }
 
+// analyzeStatsRe matches the runtime statistics of an EXPLAIN ANALYZE that are
+// not deterministic.
+var analyzeStatsRe = regexp.MustCompile(` (records_in|records_out|alloc_bytes|wall|cpu)=[^ \]]+`)
+
 func NewRunner(db DB, schemas map[string]*schemapb.Schema) *Runner {
 	return &Runner{
 		db:        db,
@@ -215,6 +220,17 @@ func (r *Runner) handleInsert(ctx context.Context, c *datadriven.TestData) (stri
 				continue
 			}
 
+			if col.StorageLayout.Repeated() {
+				// Lists are specified as [a,b,c].
+				values, err := stringToList(col.StorageLayout.Type(), valueForCol[col.Name])
+				if err != nil {
+					return "", fmt.Errorf("insert: %w", err)
+				}
+				rows[i] = append(rows[i], listToValues(values, colIdx)...)
+				colIdx++
+				continue
+			}
+
 			if !col.Dynamic {
 				// Column is not dynamic.
 				v, err := stringToValue(col.StorageLayout.Type(), valueForCol[col.Name])
@@ -300,6 +316,45 @@ func stringToValue(t parquet.Type, stringValue string) (any, error) {
 	}
 }
 
+func stringToList(t parquet.Type, stringValue string) ([]any, error) {
+	if stringValue == nullString {
+		return nil, nil
+	}
+	if !strings.HasPrefix(stringValue, "[") || !strings.HasSuffix(stringValue, "]") {
+		return nil, fmt.Errorf("invalid list value: %s", stringValue)
+	}
+	stringValue = strings.TrimSuffix(strings.TrimPrefix(stringValue, "["), "]")
+	if stringValue == "" {
+		return nil, nil
+	}
+
+	var values []any
+	for _, element := range strings.Split(stringValue, ",") {
+		v, err := stringToValue(t, element)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// listToValues returns the parquet values of a list of a repeated column.
+func listToValues(values []any, colIdx int) []parquet.Value {
+	if len(values) == 0 {
+		return []parquet.Value{parquet.ValueOf(nil).Level(0, 0, colIdx)}
+	}
+	res := make([]parquet.Value, 0, len(values))
+	for i, v := range values {
+		repetitionLevel := 1
+		if i == 0 {
+			repetitionLevel = 0
+		}
+		res = append(res, parquet.ValueOf(v).Level(repetitionLevel, 1, colIdx))
+	}
+	return res
+}
+
 func (r *Runner) handleExec(ctx context.Context, c *datadriven.TestData) (string, error) {
 	unordered := false
 	for _, arg := range c.CmdArgs {
@@ -313,6 +368,20 @@ func (r *Runner) handleExec(ctx context.Context, c *datadriven.TestData) (string
 		return "", fmt.Errorf("exec: parse err: %w", err)
 	}
 
+	if res.Explain && res.Analyze {
+		// Record counts, allocations and timings depend on scheduling, so
+		// only the deterministic statistics are compared.
+		plan, ok := res.Plan.(query.ExtendedBuilder)
+		if !ok {
+			return "", fmt.Errorf("exec: explain analyze is not supported by the query builder")
+		}
+		analyzed, err := plan.ExplainAnalyze(ctx)
+		if err != nil {
+			return "", err
+		}
+		return analyzeStatsRe.ReplaceAllString(analyzed, ""), nil
+	}
+
 	if res.Explain {
 		// This plan should be explained. Note that we need to execute an
 		// explain this way because we have no notion of building an explain
@@ -401,6 +470,16 @@ func arrayToStringVals(a arrow.Array) ([]string, error) {
 			}
 			result[i] = strconv.Itoa(int(col.Value(i)))
 		}
+	case *array.Float64:
+		for i := range result {
+			if col.IsNull(i) {
+				result[i] = nullString
+				continue
+			}
+			// Floats are rounded, since their last digits depend on the
+			// order partial aggregations are merged in.
+			result[i] = strconv.FormatFloat(col.Value(i), 'g', 10, 64)
+		}
 	case *array.Boolean:
 		for i := range result {
 			if col.IsNull(i) {
@@ -409,6 +488,41 @@ func arrayToStringVals(a arrow.Array) ([]string, error) {
 			}
 			result[i] = fmt.Sprintf("%t", col.Value(i))
 		}
+	case *array.List:
+		for i := range result {
+			if col.IsNull(i) {
+				result[i] = nullString
+				continue
+			}
+			start, end := col.ValueOffsets(i)
+			values := array.NewSlice(col.ListValues(), start, end)
+			stringVals, err := arrayToStringVals(values)
+			values.Release()
+			if err != nil {
+				return nil, err
+			}
+			result[i] = "[" + strings.Join(stringVals, ",") + "]"
+		}
+	case *array.Struct:
+		fieldVals := make([][]string, col.NumField())
+		for j := range fieldVals {
+			var err error
+			fieldVals[j], err = arrayToStringVals(col.Field(j))
+			if err != nil {
+				return nil, err
+			}
+		}
+		for i := range result {
+			if col.IsNull(i) {
+				result[i] = nullString
+				continue
+			}
+			vals := make([]string, len(fieldVals))
+			for j := range fieldVals {
+				vals[j] = fieldVals[j][i]
+			}
+			result[i] = "{" + strings.Join(vals, ",") + "}"
+		}
 	case *array.Dictionary:
 		switch dict := col.Dictionary().(type) {
 		case *array.Binary:
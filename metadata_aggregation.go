@@ -0,0 +1,278 @@
+package frostdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/polarsignals/frostdb/dynparquet"
+	"github.com/polarsignals/frostdb/pqarrow"
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+// SupportsMetadataAggregations implements logicalplan.MetadataAggregationReader.
+func (t *Table) SupportsMetadataAggregations() bool {
+	return true
+}
+
+// metadataAggregation is the partial result of an ungrouped min, max or count
+// aggregation.
+type metadataAggregation struct {
+	name   string
+	fn     logicalplan.AggFunc
+	column string
+
+	value int64
+	valid bool
+}
+
+func (a *metadataAggregation) add(v int64) {
+	switch {
+	case !a.valid:
+		a.value = v
+	case a.fn == logicalplan.AggFuncMin:
+		a.value = min(a.value, v)
+	case a.fn == logicalplan.AggFuncMax:
+		a.value = max(a.value, v)
+	default:
+		a.value += v
+	}
+	a.valid = true
+}
+
+// metadataAggregator computes ungrouped min, max and count aggregations. Row
+// groups are aggregated from their column indexes and row counts without
+// decoding any rows, unless their column indexes are unavailable.
+type metadataAggregator struct {
+	pool         memory.Allocator
+	aggregations []metadataAggregation
+	converter    *pqarrow.ParquetConverter
+	aggregated   bool
+}
+
+func newMetadataAggregator(pool memory.Allocator, exprs []logicalplan.Expr) (*metadataAggregator, error) {
+	a := &metadataAggregator{pool: pool}
+	columns := make([]logicalplan.Expr, 0, len(exprs))
+	for _, expr := range exprs {
+		name := expr.Name()
+		if alias, ok := expr.(*logicalplan.AliasExpr); ok {
+			expr = alias.Expr
+		}
+		f, ok := expr.(*logicalplan.AggregationFunction)
+		if !ok {
+			return nil, fmt.Errorf("unsupported metadata aggregation: %s", expr)
+		}
+		col, ok := f.Expr.(*logicalplan.Column)
+		if !ok {
+			return nil, fmt.Errorf("unsupported metadata aggregation: %s", expr)
+		}
+		switch f.Func {
+		case logicalplan.AggFuncMin, logicalplan.AggFuncMax, logicalplan.AggFuncCount:
+		default:
+			return nil, fmt.Errorf("unsupported metadata aggregation: %s", expr)
+		}
+
+		a.aggregations = append(a.aggregations, metadataAggregation{
+			name:   name,
+			fn:     f.Func,
+			column: col.ColumnName,
+		})
+		columns = append(columns, col)
+	}
+	a.converter = pqarrow.NewParquetConverter(pool, logicalplan.IterOptions{PhysicalProjection: columns})
+	return a, nil
+}
+
+// addRecord aggregates the rows of the record.
+func (a *metadataAggregator) addRecord(r arrow.Record) {
+	if r.NumRows() == 0 {
+		return
+	}
+	a.aggregated = true
+
+	for i := range a.aggregations {
+		agg := &a.aggregations[i]
+		if agg.fn == logicalplan.AggFuncCount {
+			agg.add(r.NumRows())
+			continue
+		}
+
+		indices := r.Schema().FieldIndices(agg.column)
+		if len(indices) == 0 {
+			continue
+		}
+		arr, ok := r.Column(indices[0]).(*array.Int64)
+		if !ok {
+			continue
+		}
+		for j := 0; j < arr.Len(); j++ {
+			if arr.IsValid(j) {
+				agg.add(arr.Value(j))
+			}
+		}
+	}
+}
+
+// addRowGroup aggregates the row group from its metadata. False is returned
+// if the metadata doesn't provide the result of an aggregation.
+func (a *metadataAggregator) addRowGroup(rg parquet.RowGroup) (bool, error) {
+	numRows := rg.NumRows()
+	if numRows == 0 {
+		return true, nil
+	}
+
+	values := make([]int64, len(a.aggregations))
+	valid := make([]bool, len(a.aggregations))
+	fields := rg.Schema().Fields()
+	chunks := rg.ColumnChunks()
+	for i, agg := range a.aggregations {
+		if agg.fn == logicalplan.AggFuncCount {
+			values[i], valid[i] = numRows, true
+			continue
+		}
+
+		idx := -1
+		for j, field := range fields {
+			if field.Name() == agg.column {
+				idx = j
+				break
+			}
+		}
+		if idx == -1 {
+			return false, nil
+		}
+
+		index, err := chunks[idx].ColumnIndex()
+		if err != nil {
+			return false, nil
+		}
+		for page := 0; page < index.NumPages(); page++ {
+			if index.NullPage(page) {
+				continue
+			}
+			if index.NullCount(page) > 0 {
+				// Pages with nulls are aggregated by decoding the values
+				// to match the results of decoding the row group.
+				return false, nil
+			}
+
+			var v int64
+			if agg.fn == logicalplan.AggFuncMin {
+				v = index.MinValue(page).Int64()
+			} else {
+				v = index.MaxValue(page).Int64()
+			}
+			if !valid[i] || (agg.fn == logicalplan.AggFuncMin && v < values[i]) || (agg.fn == logicalplan.AggFuncMax && v > values[i]) {
+				values[i], valid[i] = v, true
+			}
+		}
+	}
+
+	a.aggregated = true
+	for i := range a.aggregations {
+		if valid[i] {
+			a.aggregations[i].add(values[i])
+		}
+	}
+	return true, nil
+}
+
+// addDecoded aggregates the row group by decoding its rows.
+func (a *metadataAggregator) addDecoded(ctx context.Context, rg dynparquet.DynamicRowGroup) error {
+	if err := a.converter.Convert(ctx, rg); err != nil {
+		return fmt.Errorf("failed to convert row group to arrow record: %v", err)
+	}
+	r := a.converter.NewRecord()
+	if r == nil {
+		return nil
+	}
+	defer r.Release()
+	a.addRecord(r)
+	return nil
+}
+
+// record returns a record with a single row holding the partial results of
+// the aggregations, or nil if nothing was aggregated.
+func (a *metadataAggregator) record() arrow.Record {
+	if !a.aggregated {
+		return nil
+	}
+
+	fields := make([]arrow.Field, 0, len(a.aggregations))
+	cols := make([]arrow.Array, 0, len(a.aggregations))
+	defer func() {
+		for _, col := range cols {
+			col.Release()
+		}
+	}()
+	for _, agg := range a.aggregations {
+		b := array.NewInt64Builder(a.pool)
+		if agg.valid {
+			b.Append(agg.value)
+		} else {
+			b.AppendNull()
+		}
+		fields = append(fields, arrow.Field{Name: agg.name, Type: arrow.PrimitiveTypes.Int64, Nullable: true})
+		cols = append(cols, b.NewArray())
+		b.Release()
+	}
+	return array.NewRecord(arrow.NewSchema(fields, nil), cols, 1)
+}
+
+func (a *metadataAggregator) Close() {
+	a.converter.Close()
+}
+
+// aggregateMetadata consumes the row groups and passes the partial results of
+// the iteration's metadata aggregations on to the callback.
+func aggregateMetadata(
+	ctx context.Context,
+	pool memory.Allocator,
+	iterOpts *logicalplan.IterOptions,
+	rowGroups <-chan any,
+	callback logicalplan.Callback,
+) error {
+	aggregator, err := newMetadataAggregator(pool, iterOpts.MetadataAggregations)
+	if err != nil {
+		return err
+	}
+	defer aggregator.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case rg, ok := <-rowGroups:
+			if !ok {
+				r := aggregator.record()
+				if r == nil {
+					return nil
+				}
+				defer r.Release()
+				return callback(ctx, r)
+			}
+
+			switch t := rg.(type) {
+			case arrow.Record:
+				aggregator.addRecord(t)
+				t.Release()
+			case dynparquet.DynamicRowGroup:
+				ok, err := aggregator.addRowGroup(t)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					if err := aggregator.addDecoded(ctx, t); err != nil {
+						return err
+					}
+				}
+			default:
+				return fmt.Errorf("unknown row group type: %T", t)
+			}
+		}
+	}
+}
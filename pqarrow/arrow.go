@@ -27,7 +27,7 @@ func ParquetRowGroupToArrowSchema(ctx context.Context, rg parquet.RowGroup, opti
 func ParquetSchemaToArrowSchema(ctx context.Context, schema *parquet.Schema, options logicalplan.IterOptions) (*arrow.Schema, error) {
 	parquetFields := schema.Fields()
 
-	if len(options.DistinctColumns) == 1 && options.Filter == nil {
+	if len(options.DistinctColumns) == 1 && filtersDistinctColumnsOnly(options) {
 		// We can use the faster path for a single distinct column by just
 		// returning its dictionary.
 		fields := make([]arrow.Field, 0, 1)
@@ -148,12 +148,12 @@ const (
 	// optimizations are applied.
 	normal parquetConverterMode = iota
 	// singleDistinctColumn is an execution mode when a single distinct column
-	// is specified with no filter.
+	// is specified with no filter of other columns.
 	singleDistinctColumn
 	// multiDistinctColumn is an execution mode where there are multiple
-	// distinct columns specified with no filter. Note that only "simple"
-	// distinct expressions are supported in this mode (i.e. multiple columns
-	// are not specified in the same distinct expression).
+	// distinct columns specified with no filter of other columns. Note that
+	// only "simple" distinct expressions are supported in this mode (i.e.
+	// multiple columns are not specified in the same distinct expression).
 	multiDistinctColumn
 )
 
@@ -214,7 +214,7 @@ func NewParquetConverter(
 		distinctColInfos: make([]*distinctColInfo, len(iterOpts.DistinctColumns)),
 	}
 
-	if iterOpts.Filter == nil && len(iterOpts.DistinctColumns) != 0 {
+	if len(iterOpts.DistinctColumns) != 0 && filtersDistinctColumnsOnly(iterOpts) {
 		simpleDistinctExprs := true
 		for _, distinctColumn := range iterOpts.DistinctColumns {
 			if _, ok := distinctColumn.(*logicalplan.DynamicColumn); ok ||
@@ -234,6 +234,30 @@ func NewParquetConverter(
 	return c
 }
 
+// filtersDistinctColumnsOnly returns whether the filter of the iteration, if
+// any, only uses the columns that are distinct. The distinct values of such
+// columns can be filtered instead of the rows they were read from, so they
+// don't need to be read row by row.
+func filtersDistinctColumnsOnly(iterOpts logicalplan.IterOptions) bool {
+	if iterOpts.Filter == nil {
+		return true
+	}
+	for _, col := range iterOpts.Filter.ColumnsUsedExprs() {
+		distinct := false
+		for _, expr := range iterOpts.DistinctColumns {
+			for _, used := range expr.ColumnsUsedExprs() {
+				if used.MatchColumn(col.Name()) {
+					distinct = true
+				}
+			}
+		}
+		if !distinct {
+			return false
+		}
+	}
+	return true
+}
+
 func (c *ParquetConverter) Convert(ctx context.Context, rg parquet.RowGroup) error {
 	schema, err := ParquetRowGroupToArrowSchema(ctx, rg, c.iterOpts)
 	if err != nil {
@@ -403,16 +427,27 @@ func (c *ParquetConverter) schemaChanged(parquetFields []parquet.Field) error {
 			continue
 		}
 
-		newWriter, err := convert.GetWriter(i, field)
-		if err != nil {
-			return err
+		var (
+			newWriter writer.NewWriterFunc
+			cols      []int
+		)
+		if structType, ok := c.outputSchema.Field(indices[0]).Type.(*arrow.StructType); ok && !field.Repeated() {
+			// Only the leaves of the group that are projected are read.
+			cols = structColumns(field, structType, colOffset, nil)
+			newWriter = writer.NewStructWriterFromColumns(cols)
+		} else {
+			var err error
+			newWriter, err = convert.GetWriter(i, field)
+			if err != nil {
+				return err
+			}
+			cols = make([]int, numLeaves(field))
+			for i := range cols {
+				cols[i] = colOffset + i
+			}
 		}
+		colOffset += numLeaves(field)
 		writer := newWriter(c.builder.Field(indices[0]), 0)
-		cols := make([]int, numLeaves(field))
-		for i := range cols {
-			cols[i] = colOffset
-			colOffset++
-		}
 		c.writers = append(c.writers, MultiColumnWriter{
 			writer:   writer,
 			fieldIdx: i,
@@ -449,6 +484,28 @@ func (c *ParquetConverter) schemaChanged(parquetFields []parquet.Field) error {
 	return nil
 }
 
+// structColumns appends the column indexes of the leaves of the group that
+// are part of the struct type to cols. offset is the column index of the
+// first leaf of the group.
+func structColumns(group parquet.Field, structType *arrow.StructType, offset int, cols []int) []int {
+	for _, f := range group.Fields() {
+		child, ok := structType.FieldByName(f.Name())
+		if !ok {
+			offset += numLeaves(f)
+			continue
+		}
+		if childType, ok := child.Type.(*arrow.StructType); ok && !f.Repeated() {
+			cols = structColumns(f, childType, offset, cols)
+		} else {
+			for i := 0; i < numLeaves(f); i++ {
+				cols = append(cols, offset+i)
+			}
+		}
+		offset += numLeaves(f)
+	}
+	return cols
+}
+
 func (c *ParquetConverter) writeDistinctAllColumns(
 	ctx context.Context,
 	parquetFields []parquet.Field,
@@ -682,60 +739,16 @@ func (c *ParquetConverter) writeColumnToArray(
 	w writer.ValueWriter,
 ) error {
 	repeated := n.Repeated()
+	var nulls, indexed bool
 	if !repeated && dictionaryOnly {
-		// Check all the page indexes of the column chunk. If they are
-		// trustworthy and there is only one value contained in the column
-		// chunk, we can avoid reading any pages and construct a dictionary from
-		// the index values.
-		// TODO(asubiotto): This optimization can be applied at a finer
-		// granularity at the page level as well.
-		columnIndex, err := columnChunk.ColumnIndex()
-		if err != nil {
-			return err
-		}
-		columnType := columnChunk.Type()
-
-		globalMinValue := columnIndex.MinValue(0)
-		readPages := false
-		for pageIdx := 0; pageIdx < columnIndex.NumPages(); pageIdx++ {
-			if columnIndex.NullCount(pageIdx) > 0 {
-				// NULLs are not represented in the column index, so fall back
-				// to the non-optimized path.
-				// TODO(asubiotto): This is unexpected, verify upstream.
-				readPages = true
-				break
-			}
-
-			minValue := globalMinValue
-			if pageIdx != 0 {
-				// MinValue/MaxValue are relatively expensive calls, so we avoid
-				// them as much as possible.
-				minValue = columnIndex.MinValue(pageIdx)
-			}
-			maxValue := columnIndex.MaxValue(pageIdx)
-			if columnType.Length() == 0 {
-				// Variable-length datatype. The index can only be trusted if
-				// the size of the values is less than the column index size,
-				// since we cannot otherwise know if the index values are
-				// truncated.
-				if len(minValue.Bytes()) >= dynparquet.ColumnIndexSize ||
-					len(maxValue.Bytes()) >= dynparquet.ColumnIndexSize {
-					readPages = true
-					break
-				}
-			}
-
-			if columnType.Compare(minValue, maxValue) != 0 ||
-				(pageIdx != 0 && columnType.Compare(globalMinValue, minValue) != 0) {
-				readPages = true
-				break
-			}
-		}
-
-		if !readPages {
-			w.Write([]parquet.Value{globalMinValue})
+		if value, ok := singleValue(columnChunk); ok {
+			w.Write([]parquet.Value{value})
 			return nil
 		}
+		// Nulls are not part of the dictionary, but are counted by the
+		// column index. Without a column index, the values have to be read
+		// to find them.
+		nulls, indexed = hasNulls(columnChunk)
 	}
 
 	pages := columnChunk.Pages()
@@ -751,12 +764,18 @@ func (c *ParquetConverter) writeColumnToArray(
 		dict := p.Dictionary()
 
 		switch {
-		case !repeated && dictionaryOnly && dict != nil && p.NumNulls() == 0:
+		case !repeated && dictionaryOnly && indexed && dict != nil:
 			// If we are only writing the dictionary, we don't need to read
-			// the values.
+			// the values. All data pages of the column chunk are encoded
+			// with the same dictionary, so the remaining pages aren't read
+			// at all.
 			if err := w.WritePage(dict.Page()); err != nil {
 				return fmt.Errorf("write dictionary page: %w", err)
 			}
+			if nulls {
+				w.Write([]parquet.Value{parquet.NullValue()})
+			}
+			return nil
 		case !repeated && p.NumNulls() == 0 && dict == nil:
 			// If the column has no nulls, we can read all values at once
 			// consecutively without worrying about null values.
@@ -783,6 +802,70 @@ func (c *ParquetConverter) writeColumnToArray(
 	return nil
 }
 
+// singleValue returns the only value of the column chunk if all of its
+// values are equal according to its column index, so that no pages need to be
+// read. It returns false if the column chunk has no column index, the index
+// can't be trusted, or the chunk holds different values or nulls.
+// TODO(asubiotto): This optimization can be applied at a finer granularity at
+// the page level as well.
+func singleValue(columnChunk parquet.ColumnChunk) (parquet.Value, bool) {
+	columnIndex, err := columnChunk.ColumnIndex()
+	if err != nil {
+		return parquet.Value{}, false
+	}
+	columnType := columnChunk.Type()
+
+	globalMinValue := columnIndex.MinValue(0)
+	for pageIdx := 0; pageIdx < columnIndex.NumPages(); pageIdx++ {
+		if columnIndex.NullCount(pageIdx) > 0 {
+			// NULLs are not represented in the column index, so fall back
+			// to the non-optimized path.
+			// TODO(asubiotto): This is unexpected, verify upstream.
+			return parquet.Value{}, false
+		}
+
+		minValue := globalMinValue
+		if pageIdx != 0 {
+			// MinValue/MaxValue are relatively expensive calls, so we avoid
+			// them as much as possible.
+			minValue = columnIndex.MinValue(pageIdx)
+		}
+		maxValue := columnIndex.MaxValue(pageIdx)
+		if columnType.Length() == 0 {
+			// Variable-length datatype. The index can only be trusted if
+			// the size of the values is less than the column index size,
+			// since we cannot otherwise know if the index values are
+			// truncated.
+			if len(minValue.Bytes()) >= dynparquet.ColumnIndexSize ||
+				len(maxValue.Bytes()) >= dynparquet.ColumnIndexSize {
+				return parquet.Value{}, false
+			}
+		}
+
+		if columnType.Compare(minValue, maxValue) != 0 ||
+			(pageIdx != 0 && columnType.Compare(globalMinValue, minValue) != 0) {
+			return parquet.Value{}, false
+		}
+	}
+	return globalMinValue, true
+}
+
+// hasNulls returns whether any page of the column chunk contains nulls,
+// according to its column index. ok is false if the column chunk has no
+// column index.
+func hasNulls(columnChunk parquet.ColumnChunk) (nulls, ok bool) {
+	columnIndex, err := columnChunk.ColumnIndex()
+	if err != nil {
+		return false, false
+	}
+	for pageIdx := 0; pageIdx < columnIndex.NumPages(); pageIdx++ {
+		if columnIndex.NullCount(pageIdx) > 0 {
+			return true, true
+		}
+	}
+	return false, true
+}
+
 // SingleMatchingColumn returns true if there is only a single matching column for the given column matchers.
 func SingleMatchingColumn(distinctColumns []logicalplan.Expr, fields []parquet.Field) bool {
 	count := 0
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/polarsignals/frostdb/dynparquet"
+	"github.com/polarsignals/frostdb/pqarrow/arrowutils"
 	"github.com/polarsignals/frostdb/query/logicalplan"
 )
 
@@ -574,6 +575,82 @@ func TestDistinctBinaryExprOptimizationMixed(t *testing.T) {
 	require.Len(t, ar.Schema().Fields(), 3)
 }
 
+func TestDistinctFilteredDictionary(t *testing.T) {
+	samples := dynparquet.Samples{}
+	for i, v := range []string{"api", "app", "api", "db", "app", ""} {
+		s := dynparquet.Sample{
+			Stacktrace: []uuid.UUID{{0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1}},
+			Timestamp:  int64(i),
+			Value:      1,
+		}
+		if v == "" {
+			s.Labels = []dynparquet.Label{{Name: "label2", Value: "value2"}}
+		} else {
+			s.Labels = []dynparquet.Label{{Name: "label1", Value: v}}
+		}
+		samples = append(samples, s)
+	}
+	buf, err := samples.ToBuffer(dynparquet.NewSampleSchema())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	convert := func(filter logicalplan.Expr) arrow.Record {
+		c := NewParquetConverter(memory.DefaultAllocator, logicalplan.IterOptions{
+			PhysicalProjection: []logicalplan.Expr{logicalplan.Col("labels.label1")},
+			DistinctColumns:    []logicalplan.Expr{logicalplan.Col("labels.label1")},
+			Filter:             filter,
+		})
+		t.Cleanup(c.Close)
+		require.NoError(t, c.Convert(ctx, buf))
+		return c.NewRecord()
+	}
+
+	// A filter of the distinct column only reads the dictionary.
+	r := convert(logicalplan.Col("labels.label1").RegexMatch("^a"))
+	defer r.Release()
+	require.Equal(t, int64(4), r.NumRows())
+	require.Equal(t, 1, r.Column(0).NullN())
+
+	// A filter of another column needs all rows.
+	r = convert(logicalplan.Col("timestamp").Gt(logicalplan.Literal(int64(0))))
+	defer r.Release()
+	require.Equal(t, int64(6), r.NumRows())
+
+	// Without column indexes, the values are read to find the nulls.
+	c := NewParquetConverter(memory.DefaultAllocator, logicalplan.IterOptions{
+		PhysicalProjection: []logicalplan.Expr{logicalplan.Col("labels.label1")},
+		DistinctColumns:    []logicalplan.Expr{logicalplan.Col("labels.label1")},
+		Filter:             logicalplan.Col("labels.label1").RegexMatch("^a"),
+	})
+	defer c.Close()
+	require.NoError(t, c.Convert(ctx, unindexedRowGroup{buf}))
+	r = c.NewRecord()
+	defer r.Release()
+	require.Equal(t, 1, r.Column(0).NullN())
+}
+
+// unindexedRowGroup is a row group whose column chunks have no column index.
+type unindexedRowGroup struct {
+	parquet.RowGroup
+}
+
+func (rg unindexedRowGroup) ColumnChunks() []parquet.ColumnChunk {
+	chunks := rg.RowGroup.ColumnChunks()
+	unindexed := make([]parquet.ColumnChunk, len(chunks))
+	for i, chunk := range chunks {
+		unindexed[i] = unindexedColumnChunk{chunk}
+	}
+	return unindexed
+}
+
+type unindexedColumnChunk struct {
+	parquet.ColumnChunk
+}
+
+func (unindexedColumnChunk) ColumnIndex() (parquet.ColumnIndex, error) {
+	return nil, parquet.ErrMissingColumnIndex
+}
+
 func TestList(t *testing.T) {
 	type model struct {
 		Data []int
@@ -811,6 +888,43 @@ func Test_ParquetToArrowV2(t *testing.T) {
 	require.Equal(t, int64(n), r.NumRows())
 }
 
+func Test_ParquetToArrowV2_NestedProjection(t *testing.T) {
+	dynSchema := dynparquet.NewNestedSampleSchema(t)
+	schema, err := dynparquet.SchemaFromDefinition(dynSchema)
+	require.NoError(t, err)
+	pb, err := schema.NewBufferV2(
+		dynparquet.LabelColumn("label1"),
+		dynparquet.LabelColumn("label2"),
+	)
+	require.NoError(t, err)
+	_, err = pb.WriteRows([]parquet.Row{
+		{
+			parquet.ValueOf("a").Level(0, 1, 0), // labels.label1
+			parquet.ValueOf("b").Level(0, 1, 1), // labels.label2
+			parquet.ValueOf(1).Level(0, 2, 2),   // timestamps: [1]
+			parquet.ValueOf(2).Level(0, 2, 3),   // values: [2]
+		},
+	})
+	require.NoError(t, err)
+
+	alloc := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer alloc.AssertSize(t, 0)
+	c := NewParquetConverter(alloc, logicalplan.IterOptions{
+		PhysicalProjection: []logicalplan.Expr{logicalplan.Col("labels.label2")},
+	})
+	defer c.Close()
+	require.NoError(t, c.Convert(context.Background(), pb))
+	r := c.NewRecord()
+	defer r.Release()
+
+	flat := arrowutils.FlattenStructs(alloc, r)
+	defer flat.Release()
+	require.Equal(t, int64(1), flat.NumCols())
+	require.Equal(t, "labels.label2", flat.Schema().Field(0).Name)
+	label2 := flat.Column(0).(*array.Dictionary)
+	require.Equal(t, "b", label2.Dictionary().(*array.Binary).ValueString(label2.GetValueIndex(0)))
+}
+
 func Test_ParquetToArrow(t *testing.T) {
 	dynSchema := dynparquet.NewSampleSchema()
 
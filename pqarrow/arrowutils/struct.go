@@ -0,0 +1,116 @@
+package arrowutils
+
+import (
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/bitutil"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+)
+
+// FlattenStructs returns a record in which every struct column is replaced by
+// its fields, named by their dotted path (e.g. "labels.foo" for the field
+// "foo" of the struct column "labels"). Nested structs are flattened
+// recursively. A field is null wherever its struct is null. If the record has
+// no struct columns, it is returned with an additional reference.
+func FlattenStructs(mem memory.Allocator, r arrow.Record) arrow.Record {
+	return FlattenStructPaths(mem, r, func(string) bool { return true })
+}
+
+// FlattenStructPaths is like FlattenStructs, but only flattens the struct
+// columns whose dotted path flatten returns true for. The schema metadata of
+// the record is kept.
+func FlattenStructPaths(mem memory.Allocator, r arrow.Record, flatten func(path string) bool) arrow.Record {
+	hasStructs := false
+	for _, f := range r.Schema().Fields() {
+		if f.Type.ID() == arrow.STRUCT && flatten(f.Name) {
+			hasStructs = true
+			break
+		}
+	}
+	if !hasStructs {
+		r.Retain()
+		return r
+	}
+
+	fields := make([]arrow.Field, 0, r.Schema().NumFields())
+	cols := make([]arrow.Array, 0, r.Schema().NumFields())
+	for i, f := range r.Schema().Fields() {
+		fields, cols = flattenColumn(mem, flatten, f, r.Column(i), fields, cols)
+	}
+	defer func() {
+		for _, c := range cols {
+			c.Release()
+		}
+	}()
+
+	metadata := r.Schema().Metadata()
+	return array.NewRecord(arrow.NewSchema(fields, &metadata), cols, r.NumRows())
+}
+
+// flattenColumn appends the field and column, or the flattened fields and
+// columns of a struct, to fields and cols. Every appended column holds a
+// reference that is owned by the caller.
+func flattenColumn(
+	mem memory.Allocator,
+	flatten func(path string) bool,
+	f arrow.Field,
+	col arrow.Array,
+	fields []arrow.Field,
+	cols []arrow.Array,
+) ([]arrow.Field, []arrow.Array) {
+	s, ok := col.(*array.Struct)
+	if !ok || !flatten(f.Name) {
+		col.Retain()
+		return append(fields, f), append(cols, col)
+	}
+
+	structType := s.DataType().(*arrow.StructType)
+	for i, child := range structType.Fields() {
+		childCol := withParentNulls(mem, s, s.Field(i))
+		child.Name = f.Name + "." + child.Name
+		child.Nullable = child.Nullable || f.Nullable
+		fields, cols = flattenColumn(mem, flatten, child, childCol, fields, cols)
+		childCol.Release()
+	}
+	return fields, cols
+}
+
+// withParentNulls returns the field of a struct in which the rows the struct
+// is null in are null as well.
+func withParentNulls(mem memory.Allocator, s *array.Struct, field arrow.Array) arrow.Array {
+	if s.NullN() == 0 {
+		field.Retain()
+		return field
+	}
+
+	data := field.Data()
+	length := int64(field.Len())
+	offset := int64(data.Offset())
+	bitmap := memory.NewResizableBuffer(mem)
+	bitmap.Resize(int(bitutil.BytesForBits(offset + length)))
+	defer bitmap.Release()
+
+	parentBitmap := s.NullBitmapBytes()
+	if fieldBitmap := field.NullBitmapBytes(); fieldBitmap != nil {
+		bitutil.BitmapAnd(
+			parentBitmap, fieldBitmap,
+			int64(s.Data().Offset()), offset,
+			bitmap.Bytes(), offset, length,
+		)
+	} else {
+		bitutil.CopyBitmap(parentBitmap, s.Data().Offset(), int(length), bitmap.Bytes(), int(offset))
+	}
+
+	buffers := make([]*memory.Buffer, len(data.Buffers()))
+	copy(buffers, data.Buffers())
+	buffers[0] = bitmap
+
+	var newData *array.Data
+	if data.DataType().ID() == arrow.DICTIONARY {
+		newData = array.NewDataWithDictionary(data.DataType(), int(length), buffers, array.UnknownNullCount, int(offset), data.Dictionary().(*array.Data))
+	} else {
+		newData = array.NewData(data.DataType(), int(length), buffers, data.Children(), array.UnknownNullCount, int(offset))
+	}
+	defer newData.Release()
+	return array.MakeFromData(newData)
+}
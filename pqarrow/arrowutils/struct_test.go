@@ -0,0 +1,100 @@
+package arrowutils_test
+
+import (
+	"testing"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polarsignals/frostdb/pqarrow/arrowutils"
+)
+
+func TestFlattenStructs(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	hostType := arrow.StructOf(arrow.Field{Name: "cpu", Type: arrow.PrimitiveTypes.Int64, Nullable: true})
+	attributesType := arrow.StructOf(
+		arrow.Field{Name: "service", Type: arrow.BinaryTypes.String, Nullable: true},
+		arrow.Field{Name: "host", Type: hostType, Nullable: true},
+	)
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "attributes", Type: attributesType, Nullable: true},
+		{Name: "value", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	attributes := b.Field(0).(*array.StructBuilder)
+	service := attributes.FieldBuilder(0).(*array.StringBuilder)
+	host := attributes.FieldBuilder(1).(*array.StructBuilder)
+	cpu := host.FieldBuilder(0).(*array.Int64Builder)
+	value := b.Field(1).(*array.Int64Builder)
+
+	// Row 0 is fully set, row 1 has a null attributes struct and row 2 has a
+	// null host struct.
+	attributes.Append(true)
+	service.Append("api")
+	host.Append(true)
+	cpu.Append(4)
+	value.Append(0)
+
+	attributes.AppendNull()
+	value.Append(1)
+
+	attributes.Append(true)
+	service.Append("db")
+	host.AppendNull()
+	value.Append(2)
+
+	r := b.NewRecord()
+	defer r.Release()
+
+	// Slice the record to verify offsets are taken into account.
+	for _, offset := range []int64{0, 1} {
+		sliced := r.NewSlice(offset, r.NumRows())
+		flat := arrowutils.FlattenStructs(mem, sliced)
+		sliced.Release()
+
+		require.Equal(t, []arrow.Field{
+			{Name: "attributes.service", Type: arrow.BinaryTypes.String, Nullable: true},
+			{Name: "attributes.host.cpu", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "value", Type: arrow.PrimitiveTypes.Int64},
+		}, flat.Schema().Fields())
+
+		var rows [][3]string
+		for i := 0; i < int(flat.NumRows()); i++ {
+			rows = append(rows, [3]string{flat.Column(0).ValueStr(i), flat.Column(1).ValueStr(i), flat.Column(2).ValueStr(i)})
+		}
+		expected := [][3]string{
+			{"api", "4", "0"},
+			{"(null)", "(null)", "1"},
+			{"db", "(null)", "2"},
+		}
+		require.Equal(t, expected[offset:], rows)
+		flat.Release()
+	}
+
+	// Only the structs at the given paths are flattened, keeping the schema
+	// metadata.
+	md := arrow.NewMetadata([]string{"key"}, []string{"value"})
+	withMetadata := array.NewRecord(arrow.NewSchema(schema.Fields(), &md), r.Columns(), r.NumRows())
+	defer withMetadata.Release()
+	partial := arrowutils.FlattenStructPaths(mem, withMetadata, func(path string) bool { return path == "attributes" })
+	require.Equal(t, []arrow.Field{
+		{Name: "attributes.service", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "attributes.host", Type: hostType, Nullable: true},
+		{Name: "value", Type: arrow.PrimitiveTypes.Int64},
+	}, partial.Schema().Fields())
+	require.Equal(t, md, partial.Schema().Metadata())
+	partial.Release()
+
+	// Records without structs are returned as is.
+	plain := array.NewRecord(arrow.NewSchema(schema.Fields()[1:], nil), r.Columns()[1:], r.NumRows())
+	defer plain.Release()
+	flat := arrowutils.FlattenStructs(mem, plain)
+	require.Same(t, plain, flat)
+	flat.Release()
+}
@@ -65,6 +65,8 @@ func AppendValue(cb ColumnBuilder, arr arrow.Array, i int) error {
 		b.AppendSingle(arr.(*array.Boolean).Value(i))
 	case *array.Int64Builder:
 		b.Append(arr.(*array.Int64).Value(i))
+	case *array.Float64Builder:
+		b.Append(arr.(*array.Float64).Value(i))
 	case *array.StringBuilder:
 		b.Append(arr.(*array.String).Value(i))
 	case *array.BinaryBuilder:
@@ -161,6 +163,8 @@ func AppendGoValue(cb ColumnBuilder, v any) error {
 		b.AppendSingle(v.(bool))
 	case *array.Int64Builder:
 		b.Append(v.(int64))
+	case *array.Float64Builder:
+		b.Append(v.(float64))
 	case *array.StringBuilder:
 		b.Append(v.(string))
 	case *array.BinaryBuilder:
@@ -6,6 +6,7 @@ import (
 
 	"github.com/apache/arrow/go/v14/arrow/array"
 	"github.com/parquet-go/parquet-go"
+	"golang.org/x/exp/slices"
 
 	"github.com/polarsignals/frostdb/pqarrow/builder"
 )
@@ -301,7 +302,11 @@ func (w *booleanValueWriter) WritePage(p parquet.Page) error {
 type structWriter struct {
 	// offset is the column index offset that this node has in the overall schema
 	offset int
-	b      *array.StructBuilder
+	// columns, if set, are the column indexes of the leaves of the struct
+	// builder in the overall schema. It is used instead of offset if the
+	// struct only contains a subset of the leaves of the node.
+	columns []int
+	b       *array.StructBuilder
 }
 
 func NewStructWriterFromOffset(offset int) NewWriterFunc {
@@ -313,6 +318,17 @@ func NewStructWriterFromOffset(offset int) NewWriterFunc {
 	}
 }
 
+// NewStructWriterFromColumns returns a struct writer whose leaves are written
+// from the given column indexes, in order.
+func NewStructWriterFromColumns(columns []int) NewWriterFunc {
+	return func(b builder.ColumnBuilder, _ int) ValueWriter {
+		return &structWriter{
+			columns: columns,
+			b:       b.(*array.StructBuilder),
+		}
+	}
+}
+
 func (s *structWriter) WritePage(p parquet.Page) error {
 	// TODO: there's probably a more optimized way to handle a page of values here; but doing this for simplicity of implementation right meow.
 	values := make([]parquet.Value, p.NumValues())
@@ -336,8 +352,12 @@ func (s *structWriter) Write(values []parquet.Value) {
 			}
 		}
 	}
+	searchIndex, offset := values[0].Column(), s.offset
+	if s.columns != nil {
+		searchIndex, offset = slices.Index(s.columns, searchIndex), 0
+	}
 	// recursively search the struct builder for the leaf that matches the values column index
-	_, ok := s.findLeafBuilder(values[0].Column(), s.offset, s.b, values)
+	_, ok := s.findLeafBuilder(searchIndex, offset, s.b, values)
 	if !ok {
 		panic("unable to write values to builder")
 	}
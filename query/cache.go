@@ -0,0 +1,321 @@
+package query
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/ipc"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+
+	"github.com/polarsignals/frostdb/query/logicalplan"
+	"github.com/polarsignals/frostdb/query/physicalplan"
+)
+
+// ResultCache caches the partial results of aggregations over the persisted
+// blocks of a table. Persisted blocks are immutable, so a query only needs to
+// recompute the results of the data that is still held in memory and merge
+// them with the cached results. Entries are invalidated once a block is rotated and
+// persisted, and the least recently used entries are evicted once the cache
+// exceeds its byte budget.
+type ResultCache struct {
+	budget int64
+
+	mtx     sync.Mutex
+	size    int64
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type resultCacheEntry struct {
+	key string
+	// lastBlockTimestamp is the timestamp before which the blocks the results
+	// were computed from were created.
+	lastBlockTimestamp uint64
+	// records are the results encoded as Arrow IPC streams.
+	records [][]byte
+	size    int64
+}
+
+// NewResultCache returns a cache that holds up to budget bytes of results.
+func NewResultCache(budget int64) *ResultCache {
+	return &ResultCache{
+		budget:  budget,
+		entries: map[string]*list.Element{},
+		lru:     list.New(),
+	}
+}
+
+// Size returns the number of bytes held by the cache.
+func (c *ResultCache) Size() int64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.size
+}
+
+func (c *ResultCache) get(key string, lastBlockTimestamp uint64) ([][]byte, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := e.Value.(*resultCacheEntry)
+	if entry.lastBlockTimestamp != lastBlockTimestamp {
+		// A block was rotated since the results were computed.
+		c.remove(e)
+		return nil, false
+	}
+	c.lru.MoveToFront(e)
+	return entry.records, true
+}
+
+func (c *ResultCache) put(key string, lastBlockTimestamp uint64, records [][]byte) {
+	entry := &resultCacheEntry{
+		key:                key,
+		lastBlockTimestamp: lastBlockTimestamp,
+		records:            records,
+		size:               int64(len(key)),
+	}
+	for _, r := range records {
+		entry.size += int64(len(r))
+	}
+	if entry.size > c.budget {
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		c.remove(e)
+	}
+	for c.size+entry.size > c.budget {
+		c.remove(c.lru.Back())
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	c.size += entry.size
+}
+
+func (c *ResultCache) remove(e *list.Element) {
+	entry := c.lru.Remove(e).(*resultCacheEntry)
+	delete(c.entries, entry.key)
+	c.size -= entry.size
+}
+
+// cacheableTable is implemented by tables whose data is split into immutable
+// persisted blocks and blocks held in memory.
+type cacheableTable interface {
+	// ID identifies the table among all tables, including the tables of
+	// other databases sharing the cache.
+	ID() string
+	LastBlockTimestamp() (uint64, bool)
+}
+
+// cacheKey returns a key identifying the plan over the table including all of
+// its expressions. False is returned if the plan contains a node the key
+// can't describe, whose results must not be cached.
+func cacheKey(table cacheableTable, plan *logicalplan.LogicalPlan) (string, bool) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Table %s\n", table.ID())
+	for p := plan; p != nil; p = p.Input {
+		switch {
+		case p.TableScan != nil:
+			scan := p.TableScan
+			if scan.SkipSources || scan.PersistedBefore != 0 ||
+				(scan.Sample != nil && scan.Sample.Seed == 0) {
+				// Unseeded samples differ between queries.
+				return "", false
+			}
+			fmt.Fprintf(&sb, "%s PhysicalProjection: %v", scan, scan.PhysicalProjection)
+		case p.Filter != nil:
+			sb.WriteString(p.Filter.String())
+		case p.Projection != nil:
+			fmt.Fprintf(&sb, "Projection %v", p.Projection.Exprs)
+		case p.Distinct != nil:
+			fmt.Fprintf(&sb, "%s %v", p.Distinct, p.Distinct.Exprs)
+		case p.Aggregation != nil:
+			sb.WriteString(p.Aggregation.String())
+		case p.Unnest != nil:
+			sb.WriteString(p.Unnest.String())
+		default:
+			return "", false
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), true
+}
+
+// executeCached executes the plan using the cached results of the table's
+// persisted blocks, if the plan is a mergeable aggregation. Only the partial
+// results of aggregations are cached, as they are usually much smaller than
+// the data they are computed from. False is returned if the plan was not
+// executed.
+//
+// Results are merged while they are read, so the partial results of the
+// persisted blocks are only held in memory to be put into the cache, and only
+// until they exceed the cache's budget.
+func (b LocalQueryBuilder) executeCached(
+	ctx context.Context,
+	pool memory.Allocator,
+	plan *logicalplan.LogicalPlan,
+	schema *arrow.Schema,
+	callback func(ctx context.Context, r arrow.Record) error,
+) (bool, error) {
+	c, ok := splitMergeablePlan(plan)
+	if !ok || c.merge == nil || c.merge.Aggregation == nil {
+		return false, nil
+	}
+	table, err := c.partial.TableReader()
+	if err != nil {
+		return false, nil
+	}
+	tsTable, ok := table.(cacheableTable)
+	if !ok {
+		return false, nil
+	}
+	key, ok := cacheKey(tsTable, c.partial)
+	if !ok {
+		return false, nil
+	}
+	lastBlockTimestamp, ok := tsTable.LastBlockTimestamp()
+	if !ok {
+		return false, nil
+	}
+
+	// The merged aggregation only passes on its results once it finishes,
+	// so nothing is emitted before it is known whether the results of the
+	// persisted blocks and the in-memory data fit together.
+	var emitted bool
+	merge, err := c.mergeOperator(pool, b.tracer, func(ctx context.Context, r arrow.Record) error {
+		emitted = true
+		return callback(ctx, r)
+	})
+	if err != nil {
+		return true, err
+	}
+	defer merge.Close()
+	var mtx sync.Mutex
+	mergeRecord := func(ctx context.Context, r arrow.Record) error {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return merge.Callback(ctx, r)
+	}
+
+	if persisted, ok := b.cache.get(key, lastBlockTimestamp); ok {
+		for _, result := range persisted {
+			if err := readResult(pool, result, func(r arrow.Record) error {
+				return merge.Callback(ctx, r)
+			}); err != nil {
+				return true, err
+			}
+		}
+	} else {
+		capture := &resultCapture{budget: b.cache.budget - int64(len(key))}
+		if err := b.executePartial(ctx, pool, c.partial, func(ctx context.Context, r arrow.Record) error {
+			if err := capture.add(pool, r); err != nil {
+				return err
+			}
+			return mergeRecord(ctx, r)
+		}, physicalplan.WithPersistedOnly(lastBlockTimestamp)); err != nil {
+			return true, err
+		}
+		if !capture.exceeded {
+			b.cache.put(key, lastBlockTimestamp, capture.records)
+		}
+	}
+
+	if err := b.executePartial(ctx, pool, c.partial, mergeRecord, physicalplan.WithInMemoryOnly()); err != nil {
+		return true, err
+	}
+	if ts, _ := tsTable.LastBlockTimestamp(); ts != lastBlockTimestamp {
+		// A block was persisted while the in-memory data was read, so its
+		// data might have been missed by both parts.
+		return false, nil
+	}
+
+	if err := merge.Finish(ctx); err != nil || emitted {
+		return true, err
+	}
+	return true, emitEmpty(ctx, pool, schema, callback)
+}
+
+// executePartial executes the partial plan and passes its partial aggregation
+// results on to the callback.
+func (b LocalQueryBuilder) executePartial(
+	ctx context.Context,
+	pool memory.Allocator,
+	plan *logicalplan.LogicalPlan,
+	callback func(ctx context.Context, r arrow.Record) error,
+	opts ...physicalplan.Option,
+) error {
+	phyPlan, err := b.buildPhysicalPlan(ctx, pool, plan, append(opts, physicalplan.WithPartialAggregation())...)
+	if err != nil {
+		return err
+	}
+	return phyPlan.Execute(ctx, pool, callback)
+}
+
+// resultCapture encodes the records of a result as Arrow IPC streams to put
+// them into the cache. Records are no longer captured once they exceed the
+// budget, as the result can't be cached anyway.
+type resultCapture struct {
+	budget int64
+
+	mtx      sync.Mutex
+	size     int64
+	records  [][]byte
+	exceeded bool
+}
+
+func (c *resultCapture) add(pool memory.Allocator, r arrow.Record) error {
+	c.mtx.Lock()
+	exceeded := c.exceeded
+	c.mtx.Unlock()
+	if exceeded {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	w := ipc.NewWriter(&buf, ipc.WithSchema(r.Schema()), ipc.WithAllocator(pool))
+	if err := w.Write(r); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.exceeded {
+		return nil
+	}
+	c.size += int64(buf.Len())
+	if c.size > c.budget {
+		c.exceeded = true
+		c.records = nil
+		return nil
+	}
+	c.records = append(c.records, buf.Bytes())
+	return nil
+}
+
+func readResult(pool memory.Allocator, result []byte, fn func(arrow.Record) error) error {
+	r, err := ipc.NewReader(bytes.NewReader(result), ipc.WithAllocator(pool))
+	if err != nil {
+		return err
+	}
+	defer r.Release()
+
+	for r.Next() {
+		if err := fn(r.Record()); err != nil {
+			return err
+		}
+	}
+	return r.Err()
+}
@@ -2,8 +2,12 @@ package query
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
 
 	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
 	"github.com/apache/arrow/go/v14/arrow/memory"
 	"go.opentelemetry.io/otel/trace"
 
@@ -20,11 +24,36 @@ type Builder interface {
 	Explain(ctx context.Context) (string, error)
 }
 
+// ExtendedBuilder is implemented by builders supporting operations beyond
+// the ones of Builder, such as LocalQueryBuilder. Callers needing them
+// type-assert the Builder; its own operations keep returning an
+// ExtendedBuilder so they can be chained.
+type ExtendedBuilder interface {
+	Builder
+	DistinctLimit(limit uint64, expr ...logicalplan.Expr) ExtendedBuilder
+	Unnest(expr logicalplan.Expr) ExtendedBuilder
+	Sample(sample logicalplan.Sample) ExtendedBuilder
+	GapFill(gapFill logicalplan.GapFill) ExtendedBuilder
+	Pivot(pivot logicalplan.Pivot) ExtendedBuilder
+	Unpivot(unpivot logicalplan.Unpivot) ExtendedBuilder
+	SemiJoin(inner Builder, join logicalplan.SemiJoin) ExtendedBuilder
+	ExecuteReader(ctx context.Context) (array.RecordReader, error)
+	ExplainAnalyze(ctx context.Context) (string, error)
+	OutputSchema() (*arrow.Schema, error)
+	ExecutePartial(ctx context.Context, callback func(ctx context.Context, r arrow.Record) error) error
+	PartialSchema() (*arrow.Schema, error)
+	MergeAggregate(ctx context.Context, partials []array.RecordReader, callback func(ctx context.Context, r arrow.Record) error) error
+}
+
+var _ ExtendedBuilder = LocalQueryBuilder{}
+
 type LocalEngine struct {
-	pool          memory.Allocator
-	tracer        trace.Tracer
-	tableProvider logicalplan.TableProvider
-	execOpts      []physicalplan.Option
+	pool             memory.Allocator
+	tracer           trace.Tracer
+	tableProvider    logicalplan.TableProvider
+	execOpts         []physicalplan.Option
+	queryMemoryLimit int64
+	cache            *ResultCache
 }
 
 type Option func(*LocalEngine)
@@ -41,6 +70,24 @@ func WithPhysicalplanOptions(opts ...physicalplan.Option) Option {
 	}
 }
 
+// WithQueryMemoryLimit limits the memory each query may allocate. It only
+// applies if the engine's allocator is a LimitAllocator. Queries exceeding
+// the limit fail with ErrMemoryLimitExceeded.
+func WithQueryMemoryLimit(limit int64) Option {
+	return func(e *LocalEngine) {
+		e.queryMemoryLimit = limit
+	}
+}
+
+// WithResultCache caches the results of aggregations over the persisted
+// blocks of tables, so that repeated queries only compute the results of the
+// data held in memory.
+func WithResultCache(cache *ResultCache) Option {
+	return func(e *LocalEngine) {
+		e.cache = cache
+	}
+}
+
 func NewEngine(
 	pool memory.Allocator,
 	tableProvider logicalplan.TableProvider,
@@ -60,111 +107,366 @@ func NewEngine(
 }
 
 type LocalQueryBuilder struct {
-	pool        memory.Allocator
-	tracer      trace.Tracer
-	planBuilder logicalplan.Builder
-	execOpts    []physicalplan.Option
+	pool             memory.Allocator
+	tracer           trace.Tracer
+	planBuilder      logicalplan.Builder
+	execOpts         []physicalplan.Option
+	queryMemoryLimit int64
+	cache            *ResultCache
 }
 
 func (e *LocalEngine) ScanTable(name string) Builder {
-	return LocalQueryBuilder{
-		pool:        e.pool,
-		tracer:      e.tracer,
-		planBuilder: (&logicalplan.Builder{}).Scan(e.tableProvider, name),
-		execOpts:    e.execOpts,
-	}
+	return e.builder((&logicalplan.Builder{}).Scan(e.tableProvider, name))
 }
 
 func (e *LocalEngine) ScanSchema(name string) Builder {
+	return e.builder((&logicalplan.Builder{}).ScanSchema(e.tableProvider, name))
+}
+
+func (e *LocalEngine) builder(planBuilder logicalplan.Builder) LocalQueryBuilder {
 	return LocalQueryBuilder{
-		pool:        e.pool,
-		tracer:      e.tracer,
-		planBuilder: (&logicalplan.Builder{}).ScanSchema(e.tableProvider, name),
-		execOpts:    e.execOpts,
+		pool:             e.pool,
+		tracer:           e.tracer,
+		planBuilder:      planBuilder,
+		execOpts:         e.execOpts,
+		queryMemoryLimit: e.queryMemoryLimit,
+		cache:            e.cache,
 	}
 }
 
+// with returns a copy of the builder continuing with the given plan.
+func (b LocalQueryBuilder) with(planBuilder logicalplan.Builder) LocalQueryBuilder {
+	b.planBuilder = planBuilder
+	return b
+}
+
 func (b LocalQueryBuilder) Aggregate(
 	aggExpr []logicalplan.Expr,
 	groupExprs []logicalplan.Expr,
 ) Builder {
-	return LocalQueryBuilder{
-		pool:        b.pool,
-		tracer:      b.tracer,
-		planBuilder: b.planBuilder.Aggregate(aggExpr, groupExprs),
-		execOpts:    b.execOpts,
-	}
+	return b.with(b.planBuilder.Aggregate(aggExpr, groupExprs))
 }
 
 func (b LocalQueryBuilder) Filter(
 	expr logicalplan.Expr,
 ) Builder {
-	return LocalQueryBuilder{
-		pool:        b.pool,
-		tracer:      b.tracer,
-		planBuilder: b.planBuilder.Filter(expr),
-		execOpts:    b.execOpts,
-	}
+	return b.with(b.planBuilder.Filter(expr))
 }
 
 func (b LocalQueryBuilder) Distinct(
 	expr ...logicalplan.Expr,
 ) Builder {
-	return LocalQueryBuilder{
-		pool:        b.pool,
-		tracer:      b.tracer,
-		planBuilder: b.planBuilder.Distinct(expr...),
-		execOpts:    b.execOpts,
-	}
+	return b.with(b.planBuilder.Distinct(expr...))
+}
+
+func (b LocalQueryBuilder) DistinctLimit(
+	limit uint64,
+	expr ...logicalplan.Expr,
+) ExtendedBuilder {
+	return b.with(b.planBuilder.DistinctLimit(limit, expr...))
 }
 
 func (b LocalQueryBuilder) Project(
 	projections ...logicalplan.Expr,
 ) Builder {
-	return LocalQueryBuilder{
-		pool:        b.pool,
-		tracer:      b.tracer,
-		planBuilder: b.planBuilder.Project(projections...),
-		execOpts:    b.execOpts,
+	return b.with(b.planBuilder.Project(projections...))
+}
+
+func (b LocalQueryBuilder) Unnest(
+	expr logicalplan.Expr,
+) ExtendedBuilder {
+	return b.with(b.planBuilder.Unnest(expr))
+}
+
+func (b LocalQueryBuilder) Sample(
+	sample logicalplan.Sample,
+) ExtendedBuilder {
+	return b.with(b.planBuilder.Sample(sample))
+}
+
+func (b LocalQueryBuilder) GapFill(
+	gapFill logicalplan.GapFill,
+) ExtendedBuilder {
+	return b.with(b.planBuilder.GapFill(gapFill))
+}
+
+func (b LocalQueryBuilder) Pivot(
+	pivot logicalplan.Pivot,
+) ExtendedBuilder {
+	return b.with(b.planBuilder.Pivot(pivot))
+}
+
+func (b LocalQueryBuilder) Unpivot(
+	unpivot logicalplan.Unpivot,
+) ExtendedBuilder {
+	return b.with(b.planBuilder.Unpivot(unpivot))
+}
+
+func (b LocalQueryBuilder) SemiJoin(
+	inner Builder,
+	join logicalplan.SemiJoin,
+) ExtendedBuilder {
+	// Inner queries of other engines are left unset, which fails the
+	// validation of the semi join.
+	var innerPlan logicalplan.Builder
+	if lb, ok := inner.(LocalQueryBuilder); ok {
+		innerPlan = lb.planBuilder
 	}
+	return b.with(b.planBuilder.SemiJoin(innerPlan, join))
 }
 
 func (b LocalQueryBuilder) Execute(ctx context.Context, callback func(ctx context.Context, r arrow.Record) error) error {
 	ctx, span := b.tracer.Start(ctx, "LocalQueryBuilder/Execute")
 	defer span.End()
 
-	phyPlan, err := b.buildPhysical(ctx)
+	ctx, pool, done := b.queryAllocator(ctx)
+	logicalPlan, schema, release, err := b.resolvedPlan(ctx, pool)
+	if err != nil {
+		return done(err)
+	}
+	err = b.executePlan(ctx, pool, logicalPlan, schema, callback)
+	release()
+	return done(err)
+}
+
+// executePlan executes the optimized logical plan with the query's allocator,
+// emitting an empty record with the given schema if it doesn't produce any
+// records.
+func (b LocalQueryBuilder) executePlan(
+	ctx context.Context,
+	pool memory.Allocator,
+	logicalPlan *logicalplan.LogicalPlan,
+	schema *arrow.Schema,
+	callback func(ctx context.Context, r arrow.Record) error,
+) error {
+	if b.cache != nil {
+		if handled, err := b.executeCached(ctx, pool, logicalPlan, schema, callback); handled {
+			return err
+		}
+	}
+
+	phyPlan, err := b.buildPhysicalPlan(ctx, pool, logicalPlan)
 	if err != nil {
 		return err
 	}
 
-	return phyPlan.Execute(ctx, b.pool, callback)
+	return executeWithSchema(ctx, phyPlan, pool, schema, callback)
+}
+
+// queryAllocator returns the allocator a single execution of the query
+// allocates from. If the engine's allocator is a LimitAllocator, every
+// execution allocates from its own child allocator, which cancels the
+// returned context once a memory limit is exceeded. done must be called with
+// the result of the execution and returns the error the query failed with.
+func (b LocalQueryBuilder) queryAllocator(ctx context.Context) (context.Context, memory.Allocator, func(error) error) {
+	limiter, ok := b.pool.(*LimitAllocator)
+	if !ok {
+		return ctx, b.pool, func(err error) error { return err }
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	pool := limiter.NewChild(b.queryMemoryLimit, WithCancel(cancel))
+	return ctx, pool, func(err error) error {
+		cancel(nil)
+		pool.Close()
+		if limitErr := pool.Err(); limitErr != nil {
+			return limitErr
+		}
+		return err
+	}
 }
 
+// OutputSchema returns the Arrow schema of the records the query produces.
+func (b LocalQueryBuilder) OutputSchema() (*arrow.Schema, error) {
+	logicalPlan, err := b.planBuilder.Build()
+	if err != nil {
+		return nil, err
+	}
+	return logicalPlan.OutputSchema()
+}
+
+// executeWithSchema executes the plan and emits an empty record with the given
+// schema if the plan did not produce any records. The schema may be nil, in
+// which case no empty record is emitted.
+func executeWithSchema(
+	ctx context.Context,
+	plan *physicalplan.OutputPlan,
+	pool memory.Allocator,
+	schema *arrow.Schema,
+	callback func(ctx context.Context, r arrow.Record) error,
+) error {
+	var emitted atomic.Bool
+	if err := plan.Execute(ctx, pool, func(ctx context.Context, r arrow.Record) error {
+		emitted.Store(true)
+		return callback(ctx, r)
+	}); err != nil {
+		return err
+	}
+
+	if emitted.Load() {
+		return nil
+	}
+	return emitEmpty(ctx, pool, schema, callback)
+}
+
+// emitEmpty emits an empty record with the given schema, unless the schema is
+// nil.
+func emitEmpty(
+	ctx context.Context,
+	pool memory.Allocator,
+	schema *arrow.Schema,
+	callback func(ctx context.Context, r arrow.Record) error,
+) error {
+	if schema == nil {
+		return nil
+	}
+
+	bldr := array.NewRecordBuilder(pool, schema)
+	defer bldr.Release()
+	r := bldr.NewRecord()
+	defer r.Release()
+	return callback(ctx, r)
+}
+
+// ExecuteReader executes the query in the background and returns a reader
+// that delivers the resulting records in order. Execution only progresses as
+// records are read. Releasing the reader cancels any remaining work.
+func (b LocalQueryBuilder) ExecuteReader(ctx context.Context) (array.RecordReader, error) {
+	ctx, pool, done := b.queryAllocator(ctx)
+	logicalPlan, schema, release, err := b.resolvedPlan(ctx, pool)
+	if err != nil {
+		return nil, done(err)
+	}
+	phyPlan, err := b.buildPhysicalPlan(ctx, pool, logicalPlan)
+	if err != nil {
+		release()
+		return nil, done(err)
+	}
+
+	var concrete *arrow.Schema
+	if schema == nil {
+		// Like the output schema, the concrete one is computed before the
+		// plan is optimized.
+		if concrete, err = b.concreteOutputSchema(); err != nil {
+			release()
+			return nil, done(err)
+		}
+	}
+
+	return newRecordReader(ctx, phyPlan, pool, schema, concrete, func(err error) error {
+		release()
+		return done(err)
+	})
+}
+
+func (b LocalQueryBuilder) concreteOutputSchema() (*arrow.Schema, error) {
+	logicalPlan, err := b.planBuilder.Build()
+	if err != nil {
+		return nil, err
+	}
+	schema, err := logicalPlan.ConcreteOutputSchema()
+	if errors.Is(err, logicalplan.ErrUnknownInputSchema) {
+		// Nothing is known of the columns of a table without a schema.
+		return arrow.NewSchema(nil, nil), nil
+	}
+	return schema, err
+}
+
+// Explain returns the physical plan of the query. The inner plans of semi
+// joins aren't executed, so their filters are drawn without keys.
 func (b LocalQueryBuilder) Explain(ctx context.Context) (string, error) {
-	phyPlan, err := b.buildPhysical(ctx)
+	logicalPlan, _, err := b.optimizedPlan(nil)
+	if err != nil {
+		return "", err
+	}
+	phyPlan, err := b.buildPhysicalPlan(ctx, b.pool, logicalPlan)
 	if err != nil {
 		return "", err
 	}
 	return phyPlan.DrawString(), nil
 }
 
-func (b LocalQueryBuilder) buildPhysical(ctx context.Context) (*physicalplan.OutputPlan, error) {
+// ExplainAnalyze executes the query, discarding its results, and returns the
+// physical plan annotated with the runtime statistics of each operator.
+func (b LocalQueryBuilder) ExplainAnalyze(ctx context.Context) (string, error) {
+	ctx, span := b.tracer.Start(ctx, "LocalQueryBuilder/ExplainAnalyze")
+	defer span.End()
+
+	ctx, pool, done := b.queryAllocator(ctx)
+	logicalPlan, _, release, err := b.resolvedPlan(ctx, pool)
+	if err != nil {
+		return "", done(err)
+	}
+	phyPlan, err := b.buildPhysicalPlan(ctx, pool, logicalPlan, physicalplan.WithAnalyze())
+	if err != nil {
+		release()
+		return "", done(err)
+	}
+
+	err = phyPlan.Execute(ctx, pool, func(_ context.Context, _ arrow.Record) error {
+		return nil
+	})
+	release()
+	if err := done(err); err != nil {
+		return "", err
+	}
+	return phyPlan.AnalyzeString(), nil
+}
+
+// optimizedPlan builds the query's plan, resolves its semi joins with the
+// values of their inner keys and optimizes it. It also returns the output
+// schema of the plan, which is computed before the plan is optimized. Plans
+// whose output schema is not known up front have a nil schema and simply
+// don't emit an empty record.
+func (b LocalQueryBuilder) optimizedPlan(values semiJoinValues) (*logicalplan.LogicalPlan, *arrow.Schema, error) {
 	logicalPlan, err := b.planBuilder.Build()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	schema, err := outputSchema(logicalPlan)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logicalPlan, err = resolveSemiJoins(logicalPlan, values)
+	if err != nil {
+		return nil, nil, err
+	}
+	return optimize(logicalPlan), schema, nil
+}
+
+// outputSchema returns the output schema of the plan, or nil if it contains
+// dynamic columns or the schema of the scanned table isn't known.
+func outputSchema(logicalPlan *logicalplan.LogicalPlan) (*arrow.Schema, error) {
+	schema, err := logicalPlan.OutputSchema()
+	if errors.Is(err, logicalplan.ErrDynamicOutputSchema) || errors.Is(err, logicalplan.ErrUnknownInputSchema) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("output schema: %w", err)
 	}
+	return schema, nil
+}
 
+func optimize(logicalPlan *logicalplan.LogicalPlan) *logicalplan.LogicalPlan {
 	for _, optimizer := range logicalplan.DefaultOptimizers() {
 		logicalPlan = optimizer.Optimize(logicalPlan)
 	}
+	return logicalPlan
+}
 
+func (b LocalQueryBuilder) buildPhysicalPlan(
+	ctx context.Context,
+	pool memory.Allocator,
+	logicalPlan *logicalplan.LogicalPlan,
+	opts ...physicalplan.Option,
+) (*physicalplan.OutputPlan, error) {
 	return physicalplan.Build(
 		ctx,
-		b.pool,
+		pool,
 		b.tracer,
 		logicalPlan.InputSchema(),
 		logicalPlan,
-		b.execOpts...,
+		append(append([]physicalplan.Option{}, b.execOpts...), opts...)...,
 	)
 }
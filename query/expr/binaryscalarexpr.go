@@ -3,8 +3,11 @@ package expr
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/parquet-go/parquet-go"
+	"golang.org/x/exp/slices"
 
 	"github.com/polarsignals/frostdb/query/logicalplan"
 )
@@ -24,13 +27,29 @@ func (c *ColumnRef) Column(p Particulate) (parquet.ColumnChunk, bool, error) {
 	return columnChunk, columnIndex != -1, nil
 }
 
+// findColumnIndex returns the index of the leaf column chunk of the column.
+// Columns nested in groups are referred to by their dotted path. A path to a
+// group with a single leaf, like a list, refers to that leaf.
 func findColumnIndex(s *parquet.Schema, columnName string) int {
-	for i, field := range s.Fields() {
-		if field.Name() == columnName {
-			return i
+	if leaf, ok := s.Lookup(columnName); ok {
+		return leaf.ColumnIndex
+	}
+
+	path := strings.Split(columnName, ".")
+	if leaf, ok := s.Lookup(path...); ok {
+		return leaf.ColumnIndex
+	}
+
+	columnIndex := -1
+	for i, column := range s.Columns() {
+		if len(column) > len(path) && slices.Equal(column[:len(path)], path) {
+			if columnIndex != -1 {
+				return -1
+			}
+			columnIndex = i
 		}
 	}
-	return -1
+	return columnIndex
 }
 
 type BinaryScalarExpr struct {
@@ -73,6 +92,95 @@ func (e BinaryScalarExpr) Eval(p Particulate) (bool, error) {
 	return BinaryScalarOperation(leftData, e.Right, e.Op)
 }
 
+// InExpr is a filter that is satisfied if the column contains any of the
+// given values. It is equivalent to a disjunction of equality comparisons on
+// the same column, but only needs to look up the column chunk and its index
+// once. The values are kept sorted, so that only the values within the bounds
+// of the column chunk are looked up in its bloom filter, which keeps large
+// sets like the ones of the inner plans of semi joins cheap to evaluate.
+type InExpr struct {
+	Left *ColumnRef
+	// Values are the sorted non-null values.
+	Values []parquet.Value
+	// Null is whether null is one of the values.
+	Null bool
+}
+
+// NewInExpr returns an InExpr of the column and the values.
+func NewInExpr(left *ColumnRef, values []parquet.Value) *InExpr {
+	e := &InExpr{Left: left}
+	for _, v := range values {
+		if v.IsNull() {
+			e.Null = true
+			continue
+		}
+		e.Values = append(e.Values, v)
+	}
+	sort.Slice(e.Values, func(i, j int) bool {
+		return compare(e.Values[i], e.Values[j]) < 0
+	})
+	return e
+}
+
+func (e InExpr) Eval(p Particulate) (bool, error) {
+	leftData, exists, err := e.Left.Column(p)
+	if err != nil {
+		return false, err
+	}
+
+	if !exists {
+		if e.Null {
+			return BinaryScalarExpr{Left: e.Left, Op: logicalplan.OpEq, Right: parquet.NullValue()}.Eval(p)
+		}
+		for _, v := range e.Values {
+			ok, err := BinaryScalarExpr{Left: e.Left, Op: logicalplan.OpEq, Right: v}.Eval(p)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	}
+
+	leftColumnIndex, err := leftData.ColumnIndex()
+	if err != nil {
+		return true, err
+	}
+	numNulls := NullCount(leftColumnIndex)
+	if e.Null && numNulls > 0 {
+		return true, nil
+	}
+	if numNulls == leftData.NumValues() {
+		return false, nil
+	}
+
+	// Only the values within the bounds of the column chunk may be
+	// contained in it.
+	values := e.Values
+	lower, upper := Min(leftColumnIndex), Max(leftColumnIndex)
+	if !lower.IsNull() && !upper.IsNull() {
+		lo := sort.Search(len(values), func(i int) bool { return compare(values[i], lower) >= 0 })
+		hi := sort.Search(len(values), func(i int) bool { return compare(values[i], upper) > 0 })
+		if hi < lo {
+			hi = lo
+		}
+		values = values[lo:hi]
+	}
+
+	bloomFilter := leftData.BloomFilter()
+	if bloomFilter == nil || len(values) == 0 {
+		// If there is no bloom filter then we cannot make a statement about
+		// true negatives.
+		return len(values) > 0, nil
+	}
+	for _, v := range values {
+		ok, err := bloomFilter.Check(v)
+		if err != nil || ok {
+			return true, err
+		}
+	}
+	return false, nil
+}
+
 var ErrUnsupportedBinaryOperation = errors.New("unsupported binary operation")
 
 // BinaryScalarOperation applies the given operator between the given column
@@ -97,23 +205,7 @@ func BinaryScalarOperation(left parquet.ColumnChunk, right parquet.Value, operat
 			return false, nil
 		}
 
-		bloomFilter := left.BloomFilter()
-		if bloomFilter == nil {
-			// If there is no bloom filter then we cannot make a statement about true negative, instead check the min max values of the column chunk
-			return compare(right, Max(leftColumnIndex)) <= 0 && compare(right, Min(leftColumnIndex)) >= 0, nil
-		}
-
-		ok, err := bloomFilter.Check(right)
-		if err != nil {
-			return true, err
-		}
-		if !ok {
-			// Bloom filters may return false positives, but never return false
-			// negatives, we know this column chunk does not contain the value.
-			return false, nil
-		}
-
-		return true, nil
+		return valueMayBeContained(left, leftColumnIndex, right)
 	}
 
 	// If right is NULL automatically return that the column chunk needs further
@@ -173,6 +265,33 @@ func BinaryScalarOperation(left parquet.ColumnChunk, right parquet.Value, operat
 	}
 }
 
+// valueMayBeContained returns false if the given non-null value is definitely
+// not contained in the column chunk. The min/max statistics are checked first
+// as they are cheap, the bloom filter (if present) is only consulted if the
+// value falls within the bounds of the column chunk.
+func valueMayBeContained(chunk parquet.ColumnChunk, index parquet.ColumnIndex, v parquet.Value) (bool, error) {
+	min, max := Min(index), Max(index)
+	if !min.IsNull() && !max.IsNull() && (compare(v, max) > 0 || compare(v, min) < 0) {
+		return false, nil
+	}
+
+	bloomFilter := chunk.BloomFilter()
+	if bloomFilter == nil {
+		// If there is no bloom filter then we cannot make a statement about true negative.
+		return true, nil
+	}
+
+	ok, err := bloomFilter.Check(v)
+	if err != nil {
+		return true, err
+	}
+
+	// Bloom filters may return false positives, but never return false
+	// negatives, so if the check fails we know this column chunk does not
+	// contain the value.
+	return ok, nil
+}
+
 // Min returns the minimum value found in the column chunk across all pages.
 func Min(columnIndex parquet.ColumnIndex) parquet.Value {
 	min := columnIndex.MinValue(0)
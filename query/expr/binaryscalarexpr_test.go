@@ -3,6 +3,7 @@ package expr
 import (
 	"testing"
 
+	"github.com/apache/arrow/go/v14/arrow/scalar"
 	"github.com/parquet-go/parquet-go"
 	"github.com/stretchr/testify/require"
 
@@ -10,8 +11,9 @@ import (
 )
 
 type FakeColumnChunk struct {
-	index     *FakeColumnIndex
-	numValues int64
+	index       *FakeColumnIndex
+	numValues   int64
+	bloomFilter parquet.BloomFilter
 }
 
 func (f *FakeColumnChunk) Type() parquet.Type                        { return nil }
@@ -19,7 +21,7 @@ func (f *FakeColumnChunk) Column() int                               { return 0
 func (f *FakeColumnChunk) Pages() parquet.Pages                      { return nil }
 func (f *FakeColumnChunk) ColumnIndex() (parquet.ColumnIndex, error) { return f.index, nil }
 func (f *FakeColumnChunk) OffsetIndex() (parquet.OffsetIndex, error) { return nil, nil }
-func (f *FakeColumnChunk) BloomFilter() parquet.BloomFilter          { return nil }
+func (f *FakeColumnChunk) BloomFilter() parquet.BloomFilter          { return f.bloomFilter }
 func (f *FakeColumnChunk) NumValues() int64                          { return f.numValues }
 
 type FakeColumnIndex struct {
@@ -39,6 +41,29 @@ func (f *FakeColumnIndex) MaxValue(int) parquet.Value { return f.max }
 func (f *FakeColumnIndex) IsAscending() bool          { return false }
 func (f *FakeColumnIndex) IsDescending() bool         { return false }
 
+// FakeBloomFilter is an exact bloom filter that contains the given int64
+// values. It counts the number of checks to verify whether it was consulted.
+type FakeBloomFilter struct {
+	values map[int64]struct{}
+	checks int
+}
+
+func (f *FakeBloomFilter) ReadAt([]byte, int64) (int, error) { return 0, nil }
+func (f *FakeBloomFilter) Size() int64                       { return 0 }
+func (f *FakeBloomFilter) Check(v parquet.Value) (bool, error) {
+	f.checks++
+	_, ok := f.values[v.Int64()]
+	return ok, nil
+}
+
+type FakeParticulate struct {
+	schema *parquet.Schema
+	chunks []parquet.ColumnChunk
+}
+
+func (f *FakeParticulate) Schema() *parquet.Schema             { return f.schema }
+func (f *FakeParticulate) ColumnChunks() []parquet.ColumnChunk { return f.chunks }
+
 // This is a regression test that ensures the Min/Max functions return a null
 // value (instead of panicing) should they be passed a column chunk that only
 // has null values.
@@ -211,3 +236,201 @@ func TestBinaryScalarOperation(t *testing.T) {
 		})
 	}
 }
+
+func TestBinaryScalarOperationBloomFilter(t *testing.T) {
+	bloomFilter := &FakeBloomFilter{values: map[int64]struct{}{1: {}, 10: {}}}
+	fakeChunk := &FakeColumnChunk{
+		index: &FakeColumnIndex{
+			numPages: 1,
+			min:      parquet.ValueOf(int64(1)),
+			max:      parquet.ValueOf(int64(10)),
+		},
+		numValues:   10,
+		bloomFilter: bloomFilter,
+	}
+
+	// Out of min/max bounds, the bloom filter must not be consulted.
+	res, err := BinaryScalarOperation(fakeChunk, parquet.ValueOf(int64(11)), logicalplan.OpEq)
+	require.NoError(t, err)
+	require.False(t, res)
+	require.Equal(t, 0, bloomFilter.checks)
+
+	// Within bounds but not in the bloom filter.
+	res, err = BinaryScalarOperation(fakeChunk, parquet.ValueOf(int64(5)), logicalplan.OpEq)
+	require.NoError(t, err)
+	require.False(t, res)
+	require.Equal(t, 1, bloomFilter.checks)
+
+	res, err = BinaryScalarOperation(fakeChunk, parquet.ValueOf(int64(10)), logicalplan.OpEq)
+	require.NoError(t, err)
+	require.True(t, res)
+}
+
+func TestInExpr(t *testing.T) {
+	schema := parquet.NewSchema("test", parquet.Group{
+		"value": parquet.Int(64),
+	})
+	p := &FakeParticulate{
+		schema: schema,
+		chunks: []parquet.ColumnChunk{
+			&FakeColumnChunk{
+				index: &FakeColumnIndex{
+					numPages: 1,
+					min:      parquet.ValueOf(int64(1)),
+					max:      parquet.ValueOf(int64(10)),
+				},
+				numValues:   10,
+				bloomFilter: &FakeBloomFilter{values: map[int64]struct{}{1: {}, 10: {}}},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name            string
+		expr            logicalplan.Expr
+		expectSatisfies bool
+	}{
+		{
+			name: "NoneContained",
+			expr: logicalplan.Or(
+				logicalplan.Col("value").Eq(logicalplan.Literal(int64(0))),
+				logicalplan.Col("value").Eq(logicalplan.Literal(int64(5))),
+				logicalplan.Col("value").Eq(logicalplan.Literal(int64(11))),
+			),
+			expectSatisfies: false,
+		},
+		{
+			name: "OneContained",
+			expr: logicalplan.Or(
+				logicalplan.Col("value").Eq(logicalplan.Literal(int64(5))),
+				logicalplan.Col("value").Eq(logicalplan.Literal(int64(10))),
+			),
+			expectSatisfies: true,
+		},
+		{
+			name: "MissingColumn",
+			expr: logicalplan.Or(
+				logicalplan.Col("other").Eq(logicalplan.Literal("a")),
+				logicalplan.Col("other").Eq(logicalplan.Literal("")),
+			),
+			expectSatisfies: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := BooleanExpr(tc.expr)
+			require.NoError(t, err)
+			require.IsType(t, &InExpr{}, f)
+			require.True(t, UsesBloomFilters(f))
+
+			res, err := f.Eval(p)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectSatisfies, res)
+		})
+	}
+}
+
+func TestInExprValues(t *testing.T) {
+	schema := parquet.NewSchema("test", parquet.Group{
+		"value": parquet.Int(64),
+	})
+	p := &FakeParticulate{
+		schema: schema,
+		chunks: []parquet.ColumnChunk{
+			&FakeColumnChunk{
+				index: &FakeColumnIndex{
+					numPages: 1,
+					min:      parquet.ValueOf(int64(10)),
+					max:      parquet.ValueOf(int64(20)),
+				},
+				numValues:   10,
+				bloomFilter: &FakeBloomFilter{values: map[int64]struct{}{10: {}, 20: {}}},
+			},
+		},
+	}
+	values := func(values ...int64) []scalar.Scalar {
+		scalars := make([]scalar.Scalar, len(values))
+		for i, v := range values {
+			scalars[i] = scalar.NewInt64Scalar(v)
+		}
+		return scalars
+	}
+
+	for _, tc := range []struct {
+		name            string
+		expr            logicalplan.Expr
+		expectSatisfies bool
+	}{
+		{
+			name:            "OutOfBounds",
+			expr:            logicalplan.In(logicalplan.Col("value"), values(30, 1, 21, 9)...),
+			expectSatisfies: false,
+		},
+		{
+			name:            "WithinBoundsNotInBloomFilter",
+			expr:            logicalplan.In(logicalplan.Col("value"), values(30, 15, 1)...),
+			expectSatisfies: false,
+		},
+		{
+			name:            "Contained",
+			expr:            logicalplan.In(logicalplan.Col("value"), values(30, 15, 20, 1)...),
+			expectSatisfies: true,
+		},
+		{
+			name:            "NotIn",
+			expr:            logicalplan.NotIn(logicalplan.Col("value"), values(10, 20)...),
+			expectSatisfies: true,
+		},
+		{
+			name:            "MissingColumn",
+			expr:            logicalplan.In(logicalplan.Col("other"), values(10)...),
+			expectSatisfies: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := BooleanExpr(tc.expr)
+			require.NoError(t, err)
+
+			res, err := f.Eval(p)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectSatisfies, res)
+		})
+	}
+}
+
+func TestColumnRefNestedPath(t *testing.T) {
+	schema := parquet.NewSchema("nested", parquet.Group{
+		"labels": parquet.Group{
+			"label1": parquet.Optional(parquet.String()),
+			"label2": parquet.Optional(parquet.String()),
+		},
+		"timestamps": parquet.List(parquet.Int(64)),
+		"value":      parquet.Int(64),
+	})
+	chunks := make([]parquet.ColumnChunk, 0, 4)
+	for i := 0; i < 4; i++ {
+		chunks = append(chunks, &FakeColumnChunk{numValues: int64(i)})
+	}
+	p := &FakeParticulate{schema: schema, chunks: chunks}
+
+	for _, tc := range []struct {
+		column string
+		chunk  int
+	}{
+		{column: "labels.label1", chunk: 0},
+		{column: "labels.label2", chunk: 1},
+		{column: "timestamps", chunk: 2}, // The single leaf of the list.
+		{column: "value", chunk: 3},
+		{column: "labels", chunk: -1}, // Groups with multiple leaves aren't columns.
+		{column: "labels.label3", chunk: -1},
+		{column: "labels.label", chunk: -1},
+	} {
+		t.Run(tc.column, func(t *testing.T) {
+			chunk, exists, err := (&ColumnRef{ColumnName: tc.column}).Column(p)
+			require.NoError(t, err)
+			require.Equal(t, tc.chunk != -1, exists)
+			if exists {
+				require.Equal(t, chunks[tc.chunk], chunk)
+			}
+		})
+	}
+}
@@ -108,6 +108,10 @@ func binaryBooleanExpr(expr *logicalplan.BinaryExpr) (TrueNegativeFilter, error)
 			Right: right,
 		}, nil
 	case logicalplan.OpOr:
+		if in := inExpr(expr); in != nil {
+			return in, nil
+		}
+
 		left, err := BooleanExpr(expr.Left)
 		if err != nil {
 			return nil, err
@@ -127,6 +131,93 @@ func binaryBooleanExpr(expr *logicalplan.BinaryExpr) (TrueNegativeFilter, error)
 	}
 }
 
+// inExpr returns an InExpr if the given expression is a disjunction of
+// equality comparisons between the same column and literals, for example
+// `a = 1 OR a = 2 OR a = 3`. Otherwise nil is returned.
+func inExpr(expr *logicalplan.BinaryExpr) *InExpr {
+	var (
+		columnName string
+		values     []parquet.Value
+	)
+	var collect func(expr logicalplan.Expr) bool
+	collect = func(expr logicalplan.Expr) bool {
+		e, ok := expr.(*logicalplan.BinaryExpr)
+		if !ok {
+			return false
+		}
+
+		switch e.Op {
+		case logicalplan.OpOr:
+			return collect(e.Left) && collect(e.Right)
+		case logicalplan.OpEq:
+			col, ok := e.Left.(*logicalplan.Column)
+			if !ok {
+				return false
+			}
+			lit, ok := e.Right.(*logicalplan.LiteralExpr)
+			if !ok {
+				return false
+			}
+			if columnName != "" && columnName != col.ColumnName {
+				return false
+			}
+			v, err := pqarrow.ArrowScalarToParquetValue(lit.Value)
+			if err != nil {
+				return false
+			}
+			columnName = col.ColumnName
+			values = append(values, v)
+			return true
+		default:
+			return false
+		}
+	}
+
+	if !collect(expr) {
+		return nil
+	}
+
+	return NewInExpr(&ColumnRef{ColumnName: columnName}, values)
+}
+
+// inValuesExpr returns the filter of the row groups that may contain one of
+// the values of the InExpr. Row groups can't be ruled out by the values they
+// don't contain, so negated InExprs are always true.
+func inValuesExpr(expr *logicalplan.InExpr) TrueNegativeFilter {
+	col, ok := expr.Expr.(*logicalplan.Column)
+	if !ok || expr.Not {
+		return &AlwaysTrueFilter{}
+	}
+
+	values := make([]parquet.Value, len(expr.Values))
+	for i, v := range expr.Values {
+		value, err := pqarrow.ArrowScalarToParquetValue(v)
+		if err != nil {
+			return &AlwaysTrueFilter{}
+		}
+		values[i] = value
+	}
+	return NewInExpr(&ColumnRef{ColumnName: col.ColumnName}, values)
+}
+
+// UsesBloomFilters returns true if evaluating the given filter can make use of
+// column chunk bloom filters. This allows callers to avoid loading bloom
+// filters for filters that would never consult them.
+func UsesBloomFilters(f TrueNegativeFilter) bool {
+	switch e := f.(type) {
+	case *AndExpr:
+		return UsesBloomFilters(e.Left) || UsesBloomFilters(e.Right)
+	case *OrExpr:
+		return UsesBloomFilters(e.Left) || UsesBloomFilters(e.Right)
+	case *BinaryScalarExpr:
+		return e.Op == logicalplan.OpEq && !e.Right.IsNull()
+	case *InExpr:
+		return true
+	default:
+		return false
+	}
+}
+
 type AndExpr struct {
 	Left  TrueNegativeFilter
 	Right TrueNegativeFilter
@@ -180,6 +271,8 @@ func BooleanExpr(expr logicalplan.Expr) (TrueNegativeFilter, error) {
 	switch e := expr.(type) {
 	case *logicalplan.BinaryExpr:
 		return binaryBooleanExpr(e)
+	case *logicalplan.InExpr:
+		return inValuesExpr(e), nil
 	default:
 		return nil, fmt.Errorf("unsupported boolean expression %T", e)
 	}
@@ -0,0 +1,96 @@
+package expr
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"math/rand"
+
+	"github.com/cespare/xxhash/v2"
+
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+type sampleKey struct{}
+
+// ContextWithSample returns a context that carries the given sample. Data
+// sources only pass on the row groups that are part of it.
+func ContextWithSample(ctx context.Context, s *logicalplan.Sample) context.Context {
+	return context.WithValue(ctx, sampleKey{}, s)
+}
+
+// SampleFromContext returns the sample carried by the context or nil if
+// there is none.
+func SampleFromContext(ctx context.Context) *logicalplan.Sample {
+	s, _ := ctx.Value(sampleKey{}).(*logicalplan.Sample)
+	return s
+}
+
+// SampleRowGroup returns whether the row group identified by the key is part
+// of the sample. Only samples of whole row groups exclude row groups; nil
+// samples include all of them. The decision only depends on the seed and the
+// key, so that queries with the same seed sample the same row groups.
+func SampleRowGroup(s *logicalplan.Sample, key string) bool {
+	if s == nil || s.Method != logicalplan.SampleSystem {
+		return true
+	}
+	return sampled(s, key, -1)
+}
+
+// SampleRow returns whether the row at the offset of the row group or record
+// identified by the key is part of the sample. Only Bernoulli samples exclude
+// rows; nil samples include all of them. The decision only depends on the
+// seed, the key and the offset, so that queries with the same seed sample the
+// same rows no matter how they are read.
+func SampleRow(s *logicalplan.Sample, key string, offset int) bool {
+	if s == nil || s.Method != logicalplan.SampleBernoulli {
+		return true
+	}
+	return sampled(s, key, offset)
+}
+
+// SeededSample returns the sample with a random seed if it has none. Scans
+// seed their sample once, so that all of their row groups and rows are sampled
+// by their keys with the same seed, just like those of seeded samples.
+func SeededSample(s *logicalplan.Sample) *logicalplan.Sample {
+	if s == nil || s.Seed != 0 {
+		return s
+	}
+	seeded := *s
+	for seeded.Seed == 0 {
+		seeded.Seed = rand.Int63()
+	}
+	return &seeded
+}
+
+func sampled(s *logicalplan.Sample, key string, offset int) bool {
+	var buf [16]byte
+	binary.LittleEndian.PutUint64(buf[:8], uint64(s.Seed))
+	binary.LittleEndian.PutUint64(buf[8:], uint64(offset))
+	h := xxhash.New()
+	_, _ = h.Write(buf[:8])
+	_, _ = h.WriteString(key)
+	if offset >= 0 {
+		_, _ = h.Write(buf[8:])
+	}
+	return float64(h.Sum64())/math.MaxUint64 < s.Fraction
+}
+
+// SampledRowGroup is a row group or record whose rows are sampled by the
+// table reader, see SampleRow.
+type SampledRowGroup struct {
+	// RowGroup is the dynparquet.DynamicRowGroup or arrow.Record.
+	RowGroup any
+	// Key identifies the row group in the sample.
+	Key string
+}
+
+// RowGroupOfSample returns the row group or record v identified by the key as
+// it is passed on to the table reader. The row groups of Bernoulli samples
+// are passed on as SampledRowGroups, all others as is.
+func RowGroupOfSample(s *logicalplan.Sample, key string, v any) any {
+	if s == nil || s.Method != logicalplan.SampleBernoulli {
+		return v
+	}
+	return &SampledRowGroup{RowGroup: v, Key: key}
+}
@@ -0,0 +1,57 @@
+package expr
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// ScanStats collects statistics about how effective filters were at pruning
+// data during a scan. A part is a unit of data that is scanned as a whole,
+// e.g. an in-memory part of the index or a block file in a bucket. Parts
+// consist of row groups which are individually evaluated against the filter.
+type ScanStats struct {
+	PartsConsidered     atomic.Int64
+	PartsPruned         atomic.Int64
+	RowGroupsConsidered atomic.Int64
+	RowGroupsPruned     atomic.Int64
+}
+
+// ObservePart records that a part was considered. If pruned is true, none of
+// the part's data was passed on to the scan.
+func (s *ScanStats) ObservePart(pruned bool) {
+	if s == nil {
+		return
+	}
+	s.PartsConsidered.Add(1)
+	if pruned {
+		s.PartsPruned.Add(1)
+	}
+}
+
+// ObserveRowGroup records that a row group was evaluated against the filter.
+// If pruned is true, the filter ruled out that the row group contains data.
+func (s *ScanStats) ObserveRowGroup(pruned bool) {
+	if s == nil {
+		return
+	}
+	s.RowGroupsConsidered.Add(1)
+	if pruned {
+		s.RowGroupsPruned.Add(1)
+	}
+}
+
+type scanStatsKey struct{}
+
+// ContextWithScanStats returns a context that carries the given scan stats.
+// Data sources record the parts and row groups they consider into them.
+func ContextWithScanStats(ctx context.Context, s *ScanStats) context.Context {
+	return context.WithValue(ctx, scanStatsKey{}, s)
+}
+
+// ScanStatsFromContext returns the scan stats carried by the context or nil
+// if there are none. It is safe to call the Observe methods on a nil
+// *ScanStats.
+func ScanStatsFromContext(ctx context.Context) *ScanStats {
+	s, _ := ctx.Value(scanStatsKey{}).(*ScanStats)
+	return s
+}
@@ -117,6 +117,23 @@ func (b Builder) Distinct(
 	}
 }
 
+// DistinctLimit is like Distinct, but stops reading the input once limit
+// distinct values were found.
+func (b Builder) DistinctLimit(
+	limit uint64,
+	exprs ...Expr,
+) Builder {
+	return Builder{
+		plan: &LogicalPlan{
+			Input: b.plan,
+			Distinct: &Distinct{
+				Exprs: exprs,
+				Limit: limit,
+			},
+		},
+	}
+}
+
 func (b Builder) Aggregate(
 	aggExpr []Expr,
 	groupExprs []Expr,
@@ -132,6 +149,74 @@ func (b Builder) Aggregate(
 	}
 }
 
+// Unnest flattens the list column the expression refers to.
+func (b Builder) Unnest(expr Expr) Builder {
+	return Builder{
+		plan: &LogicalPlan{
+			Input: b.plan,
+			Unnest: &Unnest{
+				Expr: expr,
+			},
+		},
+	}
+}
+
+// Sample restricts the rows of the table scan to a random fraction of them.
+// It must directly follow the scan.
+func (b Builder) Sample(sample Sample) Builder {
+	return Builder{
+		plan: &LogicalPlan{
+			Input:  b.plan,
+			Sample: &sample,
+		},
+	}
+}
+
+// GapFill fills in the buckets missing from the results of the aggregation
+// grouped by a duration it follows.
+func (b Builder) GapFill(gapFill GapFill) Builder {
+	return Builder{
+		plan: &LogicalPlan{
+			Input:   b.plan,
+			GapFill: &gapFill,
+		},
+	}
+}
+
+// Pivot turns the values of a column into columns.
+func (b Builder) Pivot(pivot Pivot) Builder {
+	return Builder{
+		plan: &LogicalPlan{
+			Input: b.plan,
+			Pivot: &pivot,
+		},
+	}
+}
+
+// Unpivot turns columns, such as the concrete columns of a dynamic column,
+// into rows of keys and values.
+func (b Builder) Unpivot(unpivot Unpivot) Builder {
+	return Builder{
+		plan: &LogicalPlan{
+			Input:   b.plan,
+			Unpivot: &unpivot,
+		},
+	}
+}
+
+// SemiJoin keeps the rows whose key is one of the keys of the results of the
+// inner plan, or with an anti join, the rows whose key isn't. The inner plan of
+// the semi join is the plan of the inner builder.
+func (b Builder) SemiJoin(inner Builder, join SemiJoin) Builder {
+	join.Inner = inner.plan
+	return Builder{
+		plan: &LogicalPlan{
+			Input:    b.plan,
+			SemiJoin: &join,
+		},
+	}
+}
+
 func (b Builder) Build() (*LogicalPlan, error) {
 	if err := Validate(b.plan); err != nil {
 		return nil, err
@@ -2,13 +2,18 @@ package logicalplan
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/apache/arrow/go/v14/arrow"
 	"github.com/apache/arrow/go/v14/arrow/scalar"
+	"github.com/cespare/xxhash/v2"
 	"github.com/parquet-go/parquet-go"
+	"golang.org/x/exp/slices"
 
 	"github.com/polarsignals/frostdb/pqarrow/convert"
 )
@@ -124,6 +129,97 @@ func (e *BinaryExpr) Alias(alias string) *AliasExpr {
 	return &AliasExpr{Expr: e, Alias: alias}
 }
 
+// InExpr is true for the rows whose value of Expr is one of Values, or with
+// Not, for the rows whose value isn't. Semi joins filter by the set of values
+// of their inner plan's results.
+type InExpr struct {
+	Expr   Expr
+	Values []scalar.Scalar
+	Not    bool
+}
+
+// In returns an expression that is true for the rows whose value of expr is
+// one of the values.
+func In(expr Expr, values ...scalar.Scalar) *InExpr {
+	return &InExpr{Expr: expr, Values: values}
+}
+
+// NotIn returns an expression that is true for the rows whose value of expr
+// is none of the values.
+func NotIn(expr Expr, values ...scalar.Scalar) *InExpr {
+	return &InExpr{Expr: expr, Values: values, Not: true}
+}
+
+func (e *InExpr) Clone() Expr {
+	return &InExpr{
+		Expr:   e.Expr.Clone(),
+		Values: slices.Clone(e.Values),
+		Not:    e.Not,
+	}
+}
+
+func (e *InExpr) Accept(visitor Visitor) bool {
+	continu := visitor.PreVisit(e)
+	if !continu {
+		return false
+	}
+
+	continu = e.Expr.Accept(visitor)
+	if !continu {
+		return false
+	}
+
+	return visitor.PostVisit(e)
+}
+
+func (e *InExpr) DataType(_ *parquet.Schema) (arrow.DataType, error) {
+	return &arrow.BooleanType{}, nil
+}
+
+// maxInExprNameValues is the number of values up to which the name of an
+// InExpr lists them. Larger sets, like the ones of semi joins, are named by
+// their size and a hash of their values instead.
+const maxInExprNameValues = 16
+
+func (e *InExpr) Name() string {
+	op := " IN "
+	if e.Not {
+		op = " NOT IN "
+	}
+	if len(e.Values) > maxInExprNameValues {
+		h := xxhash.New()
+		for _, v := range e.Values {
+			_, _ = h.WriteString(v.String())
+			_, _ = h.Write([]byte{0})
+		}
+		return fmt.Sprintf("%s%s(%d values, hash %016x)", e.Expr.Name(), op, len(e.Values), h.Sum64())
+	}
+
+	values := make([]string, len(e.Values))
+	for i, v := range e.Values {
+		values[i] = v.String()
+	}
+	return e.Expr.Name() + op + "(" + strings.Join(values, ", ") + ")"
+}
+
+func (e *InExpr) String() string { return e.Name() }
+
+func (e *InExpr) ColumnsUsedExprs() []Expr {
+	return e.Expr.ColumnsUsedExprs()
+}
+
+func (e *InExpr) MatchPath(path string) bool {
+	return strings.HasPrefix(e.Name(), path)
+}
+
+func (e *InExpr) MatchColumn(columnName string) bool {
+	return e.Name() == columnName
+}
+
+func (e *InExpr) Computed() bool {
+	return true
+}
+
 type Column struct {
 	ColumnName string
 }
@@ -153,11 +249,12 @@ func (c *Column) String() string { return c.Name() }
 
 func (c *Column) DataType(s *parquet.Schema) (arrow.DataType, error) {
 	for _, field := range s.Fields() {
-		af, err := c.findField("", field)
-		if err != nil {
-			return nil, err
-		}
-		if af.Name != "" {
+		f, ok := findField("", field, c.ColumnName)
+		if ok {
+			af, err := convert.ParquetFieldToArrowField(f)
+			if err != nil {
+				return nil, err
+			}
 			return af.Type, nil
 		}
 	}
@@ -172,31 +269,28 @@ func fullPath(prefix string, parquetField parquet.Field) string {
 	return strings.Join([]string{prefix, parquetField.Name()}, ".")
 }
 
-func (c *Column) findField(prefix string, field parquet.Field) (arrow.Field, error) {
-	if c.ColumnName == fullPath(prefix, field) {
-		return convert.ParquetFieldToArrowField(field)
+// matchPath returns whether the dotted path refers to the column name itself
+// or to one of the groups the column is nested in.
+func matchPath(columnName, path string) bool {
+	return columnName == path || strings.HasPrefix(columnName, path+".")
+}
+
+// findField returns the field at the dotted path, descending into the groups
+// the path is nested in.
+func findField(prefix string, field parquet.Field, path string) (parquet.Field, bool) {
+	name := fullPath(prefix, field)
+	if name == path {
+		return field, true
 	}
 
-	if !field.Leaf() && strings.HasPrefix(c.ColumnName, fullPath(prefix, field)) {
-		group := []arrow.Field{}
+	if !field.Leaf() && matchPath(path, name) {
 		for _, f := range field.Fields() {
-			af, err := c.findField(fullPath(prefix, field), f)
-			if err != nil {
-				return arrow.Field{}, err
+			if found, ok := findField(name, f, path); ok {
+				return found, true
 			}
-			if af.Name != "" {
-				group = append(group, af)
-			}
-		}
-		if len(group) > 0 {
-			return arrow.Field{
-				Name:     field.Name(),
-				Type:     arrow.StructOf(group...),
-				Nullable: field.Optional(),
-			}, nil
 		}
 	}
-	return arrow.Field{}, nil
+	return nil, false
 }
 
 func (c *Column) Alias(alias string) *AliasExpr {
@@ -208,7 +302,7 @@ func (c *Column) ColumnsUsedExprs() []Expr {
 }
 
 func (c *Column) MatchPath(path string) bool {
-	return strings.HasPrefix(c.Name(), path)
+	return matchPath(c.ColumnName, path)
 }
 
 func (c *Column) MatchColumn(columnName string) bool {
@@ -361,7 +455,7 @@ func (c *DynamicColumn) ColumnsUsedExprs() []Expr {
 }
 
 func (c *DynamicColumn) MatchPath(path string) bool {
-	return strings.HasPrefix(c.Name(), path)
+	return matchPath(c.ColumnName, path)
 }
 
 func (c *DynamicColumn) MatchColumn(columnName string) bool {
@@ -435,16 +529,81 @@ func (e *LiteralExpr) MatchColumn(columnName string) bool {
 	return e.Name() == columnName
 }
 
+// ParamExpr is a named placeholder for a literal. Plans with parameters are
+// prepared once and executed with the values of their parameters bound.
+type ParamExpr struct {
+	ParamName string
+}
+
+// Param returns a placeholder for the literal bound to the parameter name.
+func Param(name string) *ParamExpr {
+	return &ParamExpr{ParamName: name}
+}
+
+func (e *ParamExpr) Clone() Expr {
+	return &ParamExpr{ParamName: e.ParamName}
+}
+
+func (e *ParamExpr) Computed() bool {
+	return false
+}
+
+func (e *ParamExpr) DataType(_ *parquet.Schema) (arrow.DataType, error) {
+	return nil, fmt.Errorf("parameter %s is not bound", e.ParamName)
+}
+
+func (e *ParamExpr) Name() string {
+	return "$" + e.ParamName
+}
+
+func (e *ParamExpr) String() string { return e.Name() }
+
+func (e *ParamExpr) Accept(visitor Visitor) bool {
+	continu := visitor.PreVisit(e)
+	if !continu {
+		return false
+	}
+
+	return visitor.PostVisit(e)
+}
+
+func (e *ParamExpr) ColumnsUsedExprs() []Expr { return nil }
+
+func (e *ParamExpr) MatchPath(_ string) bool { return false }
+
+func (e *ParamExpr) MatchColumn(columnName string) bool {
+	return e.Name() == columnName
+}
+
 type AggregationFunction struct {
 	Func AggFunc
 	Expr Expr
+	// OrderBy is the expression the first, last, rate and increase
+	// aggregations order the aggregated values by. It is nil for all other
+	// aggregations.
+	OrderBy Expr
+	// Paired is the second expression of aggregations of pairs of values,
+	// such as covariance and correlation. It is nil for all other
+	// aggregations.
+	Paired Expr
+	// Buckets are the increasing upper bounds of the buckets of histogram
+	// aggregations. It is nil for all other aggregations.
+	Buckets HistogramBuckets
 }
 
 func (f *AggregationFunction) Clone() Expr {
-	return &AggregationFunction{
-		Func: f.Func,
-		Expr: f.Expr.Clone(),
+	clone := &AggregationFunction{
+		Func:    f.Func,
+		Expr:    f.Expr.Clone(),
+		Buckets: slices.Clone(f.Buckets),
+	}
+	if f.OrderBy != nil {
+		clone.OrderBy = f.OrderBy.Clone()
 	}
+	if f.Paired != nil {
+		clone.Paired = f.Paired.Clone()
+	}
+	return clone
 }
 
 func (f *AggregationFunction) DataType(s *parquet.Schema) (arrow.DataType, error) {
@@ -462,6 +621,20 @@ func (f *AggregationFunction) Accept(visitor Visitor) bool {
 		return false
 	}
 
+	if f.OrderBy != nil {
+		continu = f.OrderBy.Accept(visitor)
+		if !continu {
+			return false
+		}
+	}
+
+	if f.Paired != nil {
+		continu = f.Paired.Accept(visitor)
+		if !continu {
+			return false
+		}
+	}
+
 	return visitor.PostVisit(f)
 }
 
@@ -470,13 +643,23 @@ func (f *AggregationFunction) Computed() bool {
 }
 
 func (f *AggregationFunction) Name() string {
+	if f.Paired != nil {
+		return f.Func.String() + "(" + f.Expr.Name() + ", " + f.Paired.Name() + ")"
+	}
 	return f.Func.String() + "(" + f.Expr.Name() + ")"
 }
 
 func (f *AggregationFunction) String() string { return f.Name() }
 
 func (f *AggregationFunction) ColumnsUsedExprs() []Expr {
-	return f.Expr.ColumnsUsedExprs()
+	cols := f.Expr.ColumnsUsedExprs()
+	if f.OrderBy != nil {
+		cols = append(cols, f.OrderBy.ColumnsUsedExprs()...)
+	}
+	if f.Paired != nil {
+		cols = append(cols, f.Paired.ColumnsUsedExprs()...)
+	}
+	return cols
 }
 
 func (f *AggregationFunction) MatchColumn(columnName string) bool {
@@ -496,6 +679,18 @@ const (
 	AggFuncMax
 	AggFuncCount
 	AggFuncAvg
+	AggFuncFirst
+	AggFuncLast
+	AggFuncStddevPop
+	AggFuncStddevSamp
+	AggFuncVarPop
+	AggFuncVarSamp
+	AggFuncCovarPop
+	AggFuncCovarSamp
+	AggFuncCorr
+	AggFuncHistogram
+	AggFuncRate
+	AggFuncIncrease
 )
 
 func (f AggFunc) String() string {
@@ -510,11 +705,91 @@ func (f AggFunc) String() string {
 		return "count"
 	case AggFuncAvg:
 		return "avg"
+	case AggFuncFirst:
+		return "first"
+	case AggFuncLast:
+		return "last"
+	case AggFuncStddevPop:
+		return "stddev_pop"
+	case AggFuncStddevSamp:
+		return "stddev_samp"
+	case AggFuncVarPop:
+		return "var_pop"
+	case AggFuncVarSamp:
+		return "var_samp"
+	case AggFuncCovarPop:
+		return "covar_pop"
+	case AggFuncCovarSamp:
+		return "covar_samp"
+	case AggFuncCorr:
+		return "corr"
+	case AggFuncHistogram:
+		return "histogram"
+	case AggFuncRate:
+		return "rate"
+	case AggFuncIncrease:
+		return "increase"
 	default:
 		panic("unknown aggregation function")
 	}
 }
 
+// Statistical returns whether the aggregation function computes a statistic
+// from the moments of the aggregated values, such as the variance.
+func (f AggFunc) Statistical() bool {
+	switch f {
+	case AggFuncStddevPop, AggFuncStddevSamp, AggFuncVarPop, AggFuncVarSamp,
+		AggFuncCovarPop, AggFuncCovarSamp, AggFuncCorr:
+		return true
+	default:
+		return false
+	}
+}
+
+// Paired returns whether the aggregation function aggregates pairs of
+// values.
+func (f AggFunc) Paired() bool {
+	switch f {
+	case AggFuncCovarPop, AggFuncCovarSamp, AggFuncCorr:
+		return true
+	default:
+		return false
+	}
+}
+
+// Counter returns whether the aggregation function computes how much a
+// monotonically increasing counter increased, accounting for counter resets.
+func (f AggFunc) Counter() bool {
+	switch f {
+	case AggFuncRate, AggFuncIncrease:
+		return true
+	default:
+		return false
+	}
+}
+
+// ResultNameWithConcreteColumn returns the name of the result of the
+// aggregation function applied to the given concrete column.
+func ResultNameWithConcreteColumn(function AggFunc, col string) string {
+	switch function {
+	case AggFuncSum:
+		return Sum(Col(col)).Name()
+	case AggFuncMin:
+		return Min(Col(col)).Name()
+	case AggFuncMax:
+		return Max(Col(col)).Name()
+	case AggFuncCount:
+		return Count(Col(col)).Name()
+	case AggFuncAvg:
+		return Avg(Col(col)).Name()
+	case AggFuncFirst, AggFuncLast, AggFuncStddevPop, AggFuncStddevSamp, AggFuncVarPop, AggFuncVarSamp,
+		AggFuncHistogram, AggFuncRate, AggFuncIncrease:
+		return function.String() + "(" + col + ")"
+	default:
+		return ""
+	}
+}
+
 func Sum(expr Expr) *AggregationFunction {
 	return &AggregationFunction{
 		Func: AggFuncSum,
@@ -550,6 +825,152 @@ func Avg(expr Expr) *AggregationFunction {
 	}
 }
 
+// First returns the value of expr of the row with the lowest value of
+// orderBy.
+func First(expr, orderBy Expr) *AggregationFunction {
+	return &AggregationFunction{
+		Func:    AggFuncFirst,
+		Expr:    expr,
+		OrderBy: orderBy,
+	}
+}
+
+// Last returns the value of expr of the row with the highest value of
+// orderBy.
+func Last(expr, orderBy Expr) *AggregationFunction {
+	return &AggregationFunction{
+		Func:    AggFuncLast,
+		Expr:    expr,
+		OrderBy: orderBy,
+	}
+}
+
+// StddevPop returns the population standard deviation of expr.
+func StddevPop(expr Expr) *AggregationFunction {
+	return &AggregationFunction{
+		Func: AggFuncStddevPop,
+		Expr: expr,
+	}
+}
+
+// StddevSamp returns the sample standard deviation of expr.
+func StddevSamp(expr Expr) *AggregationFunction {
+	return &AggregationFunction{
+		Func: AggFuncStddevSamp,
+		Expr: expr,
+	}
+}
+
+// VarPop returns the population variance of expr.
+func VarPop(expr Expr) *AggregationFunction {
+	return &AggregationFunction{
+		Func: AggFuncVarPop,
+		Expr: expr,
+	}
+}
+
+// VarSamp returns the sample variance of expr.
+func VarSamp(expr Expr) *AggregationFunction {
+	return &AggregationFunction{
+		Func: AggFuncVarSamp,
+		Expr: expr,
+	}
+}
+
+// CovarPop returns the population covariance of x and y.
+func CovarPop(x, y Expr) *AggregationFunction {
+	return &AggregationFunction{
+		Func:   AggFuncCovarPop,
+		Expr:   x,
+		Paired: y,
+	}
+}
+
+// CovarSamp returns the sample covariance of x and y.
+func CovarSamp(x, y Expr) *AggregationFunction {
+	return &AggregationFunction{
+		Func:   AggFuncCovarSamp,
+		Expr:   x,
+		Paired: y,
+	}
+}
+
+// Corr returns the Pearson correlation coefficient of x and y.
+func Corr(x, y Expr) *AggregationFunction {
+	return &AggregationFunction{
+		Func:   AggFuncCorr,
+		Expr:   x,
+		Paired: y,
+	}
+}
+
+// Rate returns the per-second rate at which the counter expr increased within
+// each duration its samples are grouped by. The samples are ordered by their
+// timestamps. Like Prometheus' rate, counter resets are accounted for and the
+// increase is extrapolated to the bounds of the duration.
+func Rate(expr, timestamp Expr) *AggregationFunction {
+	return &AggregationFunction{
+		Func:    AggFuncRate,
+		Expr:    expr,
+		OrderBy: timestamp,
+	}
+}
+
+// Increase returns how much the counter expr increased within each duration
+// its samples are grouped by, extrapolated like Rate.
+func Increase(expr, timestamp Expr) *AggregationFunction {
+	return &AggregationFunction{
+		Func:    AggFuncIncrease,
+		Expr:    expr,
+		OrderBy: timestamp,
+	}
+}
+
+// HistogramBuckets are the increasing upper bounds of the buckets of a
+// histogram. Each bucket counts the values that are less than or equal to its
+// upper bound and greater than the upper bound of the previous bucket. Values
+// greater than the last upper bound are counted by an additional bucket with
+// an infinite upper bound.
+type HistogramBuckets []float64
+
+// LinearBuckets returns count buckets of the given width, the first of which
+// has the upper bound start.
+func LinearBuckets(start, width float64, count int) HistogramBuckets {
+	buckets := make(HistogramBuckets, count)
+	for i := range buckets {
+		buckets[i] = start + float64(i)*width
+	}
+	return buckets
+}
+
+// ExponentialBuckets returns count buckets, the first of which has the upper
+// bound start. The upper bound of each following bucket is factor times the
+// upper bound of the previous one.
+func ExponentialBuckets(start, factor float64, count int) HistogramBuckets {
+	buckets := make(HistogramBuckets, count)
+	for i := range buckets {
+		buckets[i] = start * math.Pow(factor, float64(i))
+	}
+	return buckets
+}
+
+// HistogramType is the type of the results of histogram aggregations. They
+// are lists of the histogram's buckets with their upper bounds and the number
+// of values they count.
+var HistogramType = arrow.ListOf(arrow.StructOf(
+	arrow.Field{Name: "upper_bound", Type: arrow.PrimitiveTypes.Float64},
+	arrow.Field{Name: "count", Type: arrow.PrimitiveTypes.Int64},
+))
+
+// Histogram counts the values of expr falling into each of the buckets.
+func Histogram(expr Expr, buckets HistogramBuckets) *AggregationFunction {
+	return &AggregationFunction{
+		Func:    AggFuncHistogram,
+		Expr:    expr,
+		Buckets: buckets,
+	}
+}
+
 type AliasExpr struct {
 	Expr  Expr
 	Alias string
@@ -609,6 +1030,85 @@ func (f *AggregationFunction) Alias(alias string) *AliasExpr {
 	}
 }
 
+// WidthBucketExpr is the number of the bucket the value of its expression
+// falls into, out of Buckets buckets of equal width spanning [Min, Max).
+// Buckets are numbered from 1, values less than Min fall into bucket 0 and
+// values of at least Max into bucket Buckets+1.
+type WidthBucketExpr struct {
+	Expr    Expr
+	Min     float64
+	Max     float64
+	Buckets int64
+}
+
+// WidthBucket returns the number of the bucket the value of expr falls into,
+// out of n buckets of equal width spanning [min, max). It is meant to be
+// grouped by, e.g. to count the values of each bucket.
+func WidthBucket(expr Expr, min, max float64, n int64) *WidthBucketExpr {
+	return &WidthBucketExpr{
+		Expr:    expr,
+		Min:     min,
+		Max:     max,
+		Buckets: n,
+	}
+}
+
+func (e *WidthBucketExpr) Clone() Expr {
+	return &WidthBucketExpr{
+		Expr:    e.Expr.Clone(),
+		Min:     e.Min,
+		Max:     e.Max,
+		Buckets: e.Buckets,
+	}
+}
+
+func (e *WidthBucketExpr) DataType(_ *parquet.Schema) (arrow.DataType, error) {
+	return arrow.PrimitiveTypes.Int64, nil
+}
+
+func (e *WidthBucketExpr) Accept(visitor Visitor) bool {
+	continu := visitor.PreVisit(e)
+	if !continu {
+		return false
+	}
+
+	continu = e.Expr.Accept(visitor)
+	if !continu {
+		return false
+	}
+
+	return visitor.PostVisit(e)
+}
+
+func (e *WidthBucketExpr) Name() string {
+	return "width_bucket(" + e.Expr.Name() + ", " +
+		strconv.FormatFloat(e.Min, 'g', -1, 64) + ", " +
+		strconv.FormatFloat(e.Max, 'g', -1, 64) + ", " +
+		strconv.FormatInt(e.Buckets, 10) + ")"
+}
+
+func (e *WidthBucketExpr) String() string { return e.Name() }
+
+func (e *WidthBucketExpr) ColumnsUsedExprs() []Expr {
+	return e.Expr.ColumnsUsedExprs()
+}
+
+func (e *WidthBucketExpr) MatchPath(path string) bool {
+	return strings.HasPrefix(e.Name(), path)
+}
+
+func (e *WidthBucketExpr) MatchColumn(columnName string) bool {
+	return e.Name() == columnName
+}
+
+func (e *WidthBucketExpr) Computed() bool {
+	return true
+}
+
+func (e *WidthBucketExpr) Alias(alias string) *AliasExpr {
+	return &AliasExpr{Expr: e, Alias: alias}
+}
+
 func Duration(d time.Duration) *DurationExpr {
 	return &DurationExpr{duration: d}
 }
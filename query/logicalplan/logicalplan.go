@@ -3,10 +3,13 @@ package logicalplan
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/apache/arrow/go/v14/arrow"
 	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/apache/arrow/go/v14/arrow/scalar"
 
 	"github.com/polarsignals/frostdb/dynparquet"
 )
@@ -23,6 +26,12 @@ type LogicalPlan struct {
 	Distinct    *Distinct
 	Projection  *Projection
 	Aggregation *Aggregation
+	Unnest      *Unnest
+	Sample      *Sample
+	GapFill     *GapFill
+	Pivot       *Pivot
+	Unpivot     *Unpivot
+	SemiJoin    *SemiJoin
 }
 
 // Callback is a function that is called throughout a chain of operators
@@ -36,6 +45,28 @@ type IterOptions struct {
 	Filter             Expr
 	DistinctColumns    []Expr
 	InMemoryOnly       bool
+	// PersistedBefore, if set, restricts the iteration to the blocks of the
+	// table's sources that were created before the given timestamp.
+	PersistedBefore uint64
+	// MetadataAggregations, if set, are the ungrouped aggregations whose
+	// partial results are produced instead of rows.
+	MetadataAggregations []Expr
+	// Sample, if set, restricts the iteration to a random fraction of the
+	// rows or row groups of the table.
+	Sample *Sample
+	// Watermarks, if set, makes the iteration report how far it progressed
+	// through the values of a column.
+	Watermarks *Watermarks
+}
+
+// Watermarks reports the progress of an iteration through the values of an
+// int64 column. The watermark is a lower bound of the values of the rows that
+// are yet to be passed on to the callbacks, so once Advance is called no more
+// rows with smaller values will follow. Advance may be called from any of the
+// callbacks' goroutines, but not concurrently, and with increasing watermarks.
+type Watermarks struct {
+	Column  string
+	Advance func(ctx context.Context, watermark int64) error
 }
 
 type Option func(opts *IterOptions)
@@ -46,6 +77,15 @@ func WithInMemoryOnly() Option {
 	}
 }
 
+// WithPersistedOnly restricts the iteration to the immutable blocks of the
+// table's sources that were created before the given block timestamp. Data
+// that is still held in memory is skipped.
+func WithPersistedOnly(before uint64) Option {
+	return func(opts *IterOptions) {
+		opts.PersistedBefore = before
+	}
+}
+
 func WithPhysicalProjection(e ...Expr) Option {
 	return func(opts *IterOptions) {
 		opts.PhysicalProjection = append(opts.PhysicalProjection, e...)
@@ -64,6 +104,33 @@ func WithFilter(e Expr) Option {
 	}
 }
 
+// WithMetadataAggregations makes the iteration produce the partial results of
+// the given ungrouped aggregations instead of rows. The table reader must
+// implement MetadataAggregationReader.
+func WithMetadataAggregations(e ...Expr) Option {
+	return func(opts *IterOptions) {
+		opts.MetadataAggregations = append(opts.MetadataAggregations, e...)
+	}
+}
+
+// WithSample restricts the iteration to a random fraction of the rows or row
+// groups of the table. Row groups that are not part of a sample of whole row
+// groups are not read.
+func WithSample(s *Sample) Option {
+	return func(opts *IterOptions) {
+		opts.Sample = s
+	}
+}
+
+// WithWatermarks makes the iteration report the watermark of the column to
+// advance as it progresses. Table readers that don't track the progress of
+// their iteration never call advance.
+func WithWatermarks(column string, advance func(ctx context.Context, watermark int64) error) Option {
+	return func(opts *IterOptions) {
+		opts.Watermarks = &Watermarks{Column: column, Advance: advance}
+	}
+}
+
 func WithDistinctColumns(e ...Expr) Option {
 	return func(opts *IterOptions) {
 		opts.DistinctColumns = append(opts.DistinctColumns, e...)
@@ -89,6 +156,18 @@ func (plan *LogicalPlan) string(indent int) string {
 		res = plan.Aggregation.String()
 	case plan.Distinct != nil:
 		res = plan.Distinct.String()
+	case plan.Unnest != nil:
+		res = plan.Unnest.String()
+	case plan.Sample != nil:
+		res = plan.Sample.String()
+	case plan.GapFill != nil:
+		res = plan.GapFill.String()
+	case plan.Pivot != nil:
+		res = plan.Pivot.String()
+	case plan.Unpivot != nil:
+		res = plan.Unpivot.String()
+	case plan.SemiJoin != nil:
+		res = plan.SemiJoin.String()
 	default:
 		res = "Unknown LogicalPlan"
 	}
@@ -163,6 +242,16 @@ type TableReader interface {
 	) error
 	Schema() *dynparquet.Schema
 }
+
+// MetadataAggregationReader is a TableReader that supports the
+// WithMetadataAggregations option.
+type MetadataAggregationReader interface {
+	TableReader
+	// SupportsMetadataAggregations returns whether the reader supports the
+	// WithMetadataAggregations option.
+	SupportsMetadataAggregations() bool
+}
+
 type TableProvider interface {
 	GetTable(name string) (TableReader, error)
 }
@@ -187,6 +276,19 @@ type TableScan struct {
 
 	// SkipSources indicates to skip scanning the tables sources.
 	SkipSources bool
+
+	// PersistedBefore indicates to only scan the blocks of the table's
+	// sources created before the given timestamp.
+	PersistedBefore uint64
+
+	// MetadataAggregations are the ungrouped aggregations the table reader
+	// computes from the metadata of its data. The scan then produces the
+	// partial results of the aggregations instead of rows.
+	MetadataAggregations []Expr
+
+	// Sample restricts the scan to a random fraction of the rows or row
+	// groups.
+	Sample *Sample
 }
 
 func (scan *TableScan) String() string {
@@ -194,7 +296,52 @@ func (scan *TableScan) String() string {
 		" Table: " + scan.TableName +
 		" Projection: " + fmt.Sprint(scan.Projection) +
 		" Filter: " + fmt.Sprint(scan.Filter) +
-		" Distinct: " + fmt.Sprint(scan.Distinct)
+		" Distinct: " + fmt.Sprint(scan.Distinct) +
+		scan.metadataAggregationsString() +
+		scan.sampleString()
+}
+
+// FlattensPath returns whether the scan replaces the struct column of the
+// group at the dotted path by the group's fields. Only groups whose fields are
+// referred to by the scan's projections or filter are flattened, other groups
+// are kept as struct columns.
+func (scan *TableScan) FlattensPath(path string) bool {
+	exprs := make([]Expr, 0, len(scan.PhysicalProjection)+len(scan.Projection)+len(scan.Distinct)+1)
+	exprs = append(exprs, scan.PhysicalProjection...)
+	exprs = append(exprs, scan.Projection...)
+	exprs = append(exprs, scan.Distinct...)
+	if scan.Filter != nil {
+		exprs = append(exprs, scan.Filter)
+	}
+	return flattensPath(exprs, path)
+}
+
+// flattensPath returns whether any of the expressions refers to a field nested
+// in the group at the dotted path.
+func flattensPath(exprs []Expr, path string) bool {
+	prefix := path + "."
+	for _, e := range exprs {
+		for _, used := range e.ColumnsUsedExprs() {
+			if strings.HasPrefix(used.Name(), prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (scan *TableScan) metadataAggregationsString() string {
+	if len(scan.MetadataAggregations) == 0 {
+		return ""
+	}
+	return " MetadataAggregations: " + fmt.Sprint(scan.MetadataAggregations)
+}
+
+func (scan *TableScan) sampleString() string {
+	if scan.Sample == nil {
+		return ""
+	}
+	return " Sample: " + scan.Sample.String()
 }
 
 type SchemaScan struct {
@@ -233,9 +380,15 @@ func (f *Filter) String() string {
 
 type Distinct struct {
 	Exprs []Expr
+	// Limit, if set, is the number of distinct values after which the
+	// input stops being read.
+	Limit uint64
 }
 
 func (d *Distinct) String() string {
+	if d.Limit > 0 {
+		return fmt.Sprintf("Distinct Limit: %d", d.Limit)
+	}
 	return "Distinct"
 }
 
@@ -255,3 +408,463 @@ type Aggregation struct {
 func (a *Aggregation) String() string {
 	return "Aggregation " + fmt.Sprint(a.AggExprs) + " Group: " + fmt.Sprint(a.GroupExprs)
 }
+
+// Unnest flattens a list column into a row per list element, repeating the
+// values of the other columns. Rows with null or empty lists are dropped.
+type Unnest struct {
+	Expr Expr
+}
+
+func (u *Unnest) String() string {
+	return "Unnest " + u.Expr.String()
+}
+
+// SampleMethod is the method used to sample the rows of a table.
+type SampleMethod int
+
+const (
+	// SampleBernoulli samples every row independently.
+	SampleBernoulli SampleMethod = iota
+	// SampleSystem samples whole row groups. Row groups that are not part of
+	// the sample are not read at all.
+	SampleSystem
+)
+
+func (m SampleMethod) String() string {
+	switch m {
+	case SampleBernoulli:
+		return "BERNOULLI"
+	case SampleSystem:
+		return "SYSTEM"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Sample restricts the rows of a table scan to a random fraction of them,
+// which is useful for approximate exploratory queries.
+type Sample struct {
+	Method SampleMethod
+	// Fraction is the fraction of the rows that are sampled, in (0, 1].
+	Fraction float64
+	// Seed makes the sample repeatable if it is non-zero. Rows and row groups
+	// are sampled by where they are stored, so that queries with the same seed
+	// sample the same ones as long as the data isn't compacted in between.
+	Seed int64
+	// ScaleAggregations scales the results of sum and count aggregations of
+	// the sample up to the full data by dividing them by the fraction.
+	ScaleAggregations bool
+}
+
+func (s *Sample) String() string {
+	res := fmt.Sprintf("Sample %s Fraction: %g", s.Method, s.Fraction)
+	if s.Seed != 0 {
+		res += fmt.Sprintf(" Seed: %d", s.Seed)
+	}
+	if s.ScaleAggregations {
+		res += " Scaled"
+	}
+	return res
+}
+
+// FillMode is how the values of the buckets filled in by a GapFill are
+// computed.
+type FillMode int
+
+const (
+	// FillNull fills the values of missing buckets with nulls.
+	FillNull FillMode = iota
+	// FillPrevious carries the values of the previous bucket of the series
+	// forward, also known as last observation carried forward (LOCF).
+	FillPrevious
+	// FillLinear interpolates linearly between the values of the buckets of
+	// the series before and after the missing bucket. Values that aren't
+	// numeric or lack a bucket on either side are filled with nulls.
+	FillLinear
+)
+
+func (m FillMode) String() string {
+	switch m {
+	case FillNull:
+		return "NULL"
+	case FillPrevious:
+		return "PREVIOUS"
+	case FillLinear:
+		return "LINEAR"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// GapFill emits the buckets of an aggregation grouped by a duration that have
+// no samples, so that every series has a row for every bucket within a time
+// range. The series are identified by the other columns grouped by. The
+// timestamps of all rows are set to the start of their buckets.
+type GapFill struct {
+	// Start and End are the bounds of the time range [Start, End) whose
+	// buckets are filled, in milliseconds like the timestamp column.
+	Start int64
+	End   int64
+	// Step is the duration of the buckets. It must be the duration the
+	// aggregation is grouped by.
+	Step time.Duration
+	Fill FillMode
+}
+
+// MaxGapFillBuckets is the maximum number of buckets a gap fill may fill per
+// series, since all of them are held in memory.
+const MaxGapFillBuckets = 100_000
+
+func (g *GapFill) String() string {
+	return fmt.Sprintf("GapFill Start: %d End: %d Step: %s Fill: %s", g.Start, g.End, g.Step, g.Fill)
+}
+
+// Pivot turns the values of the key column into columns holding the values
+// of the value column. The rows are grouped by the values of all other
+// columns, so that each group becomes a single row with a column for every
+// key. Rows with null keys are dropped. If a key occurs more than once in a
+// group, the value of its last row is kept.
+type Pivot struct {
+	Key   Expr
+	Value Expr
+	// Prefix, if set, is prepended to the names of the pivoted columns,
+	// separated by a dot, so that they form a dynamic column.
+	Prefix string
+}
+
+func (p *Pivot) String() string {
+	res := "Pivot Key: " + p.Key.String() + " Value: " + p.Value.String()
+	if p.Prefix != "" {
+		res += " Prefix: " + p.Prefix
+	}
+	return res
+}
+
+// Unpivot turns the columns the expression matches, usually the concrete
+// columns of a dynamic column, into rows of key and value columns, repeating
+// the values of the other columns. The keys of the concrete columns of a
+// dynamic column are their names without the dynamic column's name, the keys
+// of other columns are their full names. Null values don't produce rows.
+type Unpivot struct {
+	Expr Expr
+	// Key and Value are the names of the key and value columns. The keys are
+	// strings, the values have the type of the unpivoted columns.
+	Key   string
+	Value string
+}
+
+func (u *Unpivot) String() string {
+	return "Unpivot " + u.Expr.String() + " Key: " + u.Key + " Value: " + u.Value
+}
+
+// SemiJoin keeps the rows whose value of Key is one of the values of InnerKey
+// in the results of the Inner plan, or with Anti, the rows whose value isn't.
+// The inner plan is executed first, and its set of values is then used to
+// filter the rows like an InExpr, which allows pruning row groups by the
+// set's values.
+type SemiJoin struct {
+	Key      Expr
+	Inner    *LogicalPlan
+	InnerKey Expr
+	Anti     bool
+}
+
+func (j *SemiJoin) String() string {
+	name := "SemiJoin"
+	if j.Anti {
+		name = "AntiJoin"
+	}
+	// The nodes of the inner plan are listed on a single line.
+	inner := strings.Split(j.Inner.String(), "\n")
+	for i := range inner {
+		inner[i] = strings.TrimSpace(inner[i])
+	}
+	return name + " Key: " + j.Key.String() + " InnerKey: " + j.InnerKey.String() +
+		" Inner: (" + strings.Join(inner, " <- ") + ")"
+}
+
+// Filter returns the filter of the semi join by the inner plan's values.
+func (j *SemiJoin) Filter(values []scalar.Scalar) *Filter {
+	if j.Anti {
+		return &Filter{Expr: NotIn(j.Key, values...)}
+	}
+	return &Filter{Expr: In(j.Key, values...)}
+}
+
+// GapFillAggregation returns the aggregation whose buckets the plan's gap fill
+// fills in. Only projections may come in between them. Nil is returned if the
+// plan isn't a gap fill of an aggregation.
+func (plan *LogicalPlan) GapFillAggregation() *Aggregation {
+	if plan.GapFill == nil {
+		return nil
+	}
+	for p := plan.Input; p != nil; p = p.Input {
+		switch {
+		case p.Aggregation != nil:
+			return p.Aggregation
+		case p.Projection == nil:
+			return nil
+		}
+	}
+	return nil
+}
+
+// Params returns the sorted names of the parameters of the plan's
+// expressions.
+func (plan *LogicalPlan) Params() []string {
+	collector := paramCollector{seen: map[string]struct{}{}}
+	plan.visitExprs(func(_ *LogicalPlan, expr Expr) {
+		expr.Accept(&collector)
+	})
+	params := make([]string, 0, len(collector.seen))
+	for name := range collector.seen {
+		params = append(params, name)
+	}
+	sort.Strings(params)
+	return params
+}
+
+// paramCollector is a Visitor collecting the names of the parameters of the
+// visited expressions.
+type paramCollector struct {
+	seen map[string]struct{}
+}
+
+func (c *paramCollector) PreVisit(_ Expr) bool {
+	return true
+}
+
+func (c *paramCollector) Visit(_ Expr) bool {
+	return true
+}
+
+func (c *paramCollector) PostVisit(expr Expr) bool {
+	if p, ok := expr.(*ParamExpr); ok {
+		c.seen[p.ParamName] = struct{}{}
+	}
+	return true
+}
+
+// Bind returns a copy of the plan with its parameters replaced by literals of
+// the given values. The plan itself is left untouched, so that it can be bound
+// concurrently. An error is returned if the value of a parameter is missing,
+// or if it can't be compared with the column it is compared with.
+func (plan *LogicalPlan) Bind(params map[string]any) (*LogicalPlan, error) {
+	return plan.transformExprs(func(node *LogicalPlan, expr Expr) (Expr, error) {
+		return bindExpr(expr, params, node.validationSchema())
+	})
+}
+
+// bindExpr returns the expression with its parameters bound. The literals
+// bound to parameters compared with columns are validated against the schema,
+// unless it is nil.
+func bindExpr(expr Expr, params map[string]any, schema *dynparquet.Schema) (Expr, error) {
+	bind := func(expr Expr) (Expr, error) {
+		if expr == nil {
+			return nil, nil
+		}
+		return bindExpr(expr, params, schema)
+	}
+
+	var err error
+	switch e := expr.(type) {
+	case *ParamExpr:
+		v, ok := params[e.ParamName]
+		if !ok {
+			return nil, fmt.Errorf("missing value of parameter %s", e.ParamName)
+		}
+		if s, ok := v.(scalar.Scalar); ok {
+			return &LiteralExpr{Value: s}, nil
+		}
+		return Literal(v), nil
+	case *BinaryExpr:
+		bound := *e
+		if bound.Left, err = bind(e.Left); err != nil {
+			return nil, err
+		}
+		if bound.Right, err = bind(e.Right); err != nil {
+			return nil, err
+		}
+		if _, ok := e.Right.(*ParamExpr); ok {
+			if err := validateComparedLiteral(schema, &bound); err != nil {
+				return nil, err
+			}
+		}
+		return &bound, nil
+	case *AggregationFunction:
+		bound := *e
+		if bound.Expr, err = bind(e.Expr); err != nil {
+			return nil, err
+		}
+		if bound.OrderBy, err = bind(e.OrderBy); err != nil {
+			return nil, err
+		}
+		if bound.Paired, err = bind(e.Paired); err != nil {
+			return nil, err
+		}
+		return &bound, nil
+	case *AliasExpr:
+		bound := *e
+		bound.Expr, err = bind(e.Expr)
+		return &bound, err
+	case *WidthBucketExpr:
+		bound := *e
+		bound.Expr, err = bind(e.Expr)
+		return &bound, err
+	case *AverageExpr:
+		bound := *e
+		bound.Expr, err = bind(e.Expr)
+		return &bound, err
+	case *NotExpr:
+		bound := *e
+		bound.Expr, err = bind(e.Expr)
+		return &bound, err
+	case *InExpr:
+		bound := *e
+		bound.Expr, err = bind(e.Expr)
+		return &bound, err
+	default:
+		return expr, nil
+	}
+}
+
+// visitExprs calls f with every expression of the plan's nodes, including the
+// nodes of the inner plans of semi joins, and the node it belongs to. Unlike
+// transformExprs, the plan isn't copied.
+func (plan *LogicalPlan) visitExprs(f func(node *LogicalPlan, expr Expr)) {
+	for p := plan; p != nil; p = p.Input {
+		var exprs []Expr
+		switch {
+		case p.TableScan != nil:
+			scan := p.TableScan
+			exprs = append(exprs, scan.PhysicalProjection...)
+			exprs = append(exprs, scan.Filter)
+			exprs = append(exprs, scan.Distinct...)
+			exprs = append(exprs, scan.Projection...)
+			exprs = append(exprs, scan.MetadataAggregations...)
+		case p.SchemaScan != nil:
+			scan := p.SchemaScan
+			exprs = append(exprs, scan.PhysicalProjection...)
+			exprs = append(exprs, scan.Filter)
+			exprs = append(exprs, scan.Distinct...)
+			exprs = append(exprs, scan.Projection...)
+		case p.Filter != nil:
+			exprs = []Expr{p.Filter.Expr}
+		case p.Distinct != nil:
+			exprs = p.Distinct.Exprs
+		case p.Projection != nil:
+			exprs = p.Projection.Exprs
+		case p.Aggregation != nil:
+			exprs = append(exprs, p.Aggregation.AggExprs...)
+			exprs = append(exprs, p.Aggregation.GroupExprs...)
+		case p.Unnest != nil:
+			exprs = []Expr{p.Unnest.Expr}
+		case p.Pivot != nil:
+			exprs = []Expr{p.Pivot.Key, p.Pivot.Value}
+		case p.Unpivot != nil:
+			exprs = []Expr{p.Unpivot.Expr}
+		case p.SemiJoin != nil:
+			exprs = []Expr{p.SemiJoin.Key, p.SemiJoin.InnerKey}
+			p.SemiJoin.Inner.visitExprs(f)
+		}
+		for _, expr := range exprs {
+			if expr != nil {
+				f(p, expr)
+			}
+		}
+	}
+}
+
+// transformExprs returns a copy of the plan with every expression of its nodes
+// replaced by the result of f, which is called with the node of the plan the
+// expression belongs to. Nodes without expressions are shared with the plan.
+func (plan *LogicalPlan) transformExprs(f func(node *LogicalPlan, expr Expr) (Expr, error)) (*LogicalPlan, error) {
+	if plan == nil {
+		return nil, nil
+	}
+
+	var err error
+	exprs := func(exprs []Expr) []Expr {
+		if exprs == nil {
+			return nil
+		}
+		transformed := make([]Expr, len(exprs))
+		for i, expr := range exprs {
+			transformed[i] = transform(f, plan, expr, &err)
+		}
+		return transformed
+	}
+	expr := func(expr Expr) Expr {
+		return transform(f, plan, expr, &err)
+	}
+
+	transformed := *plan
+	if transformed.Input, err = plan.Input.transformExprs(f); err != nil {
+		return nil, err
+	}
+	switch {
+	case plan.TableScan != nil:
+		scan := *plan.TableScan
+		scan.PhysicalProjection = exprs(scan.PhysicalProjection)
+		scan.Filter = expr(scan.Filter)
+		scan.Distinct = exprs(scan.Distinct)
+		scan.Projection = exprs(scan.Projection)
+		scan.MetadataAggregations = exprs(scan.MetadataAggregations)
+		transformed.TableScan = &scan
+	case plan.SchemaScan != nil:
+		scan := *plan.SchemaScan
+		scan.PhysicalProjection = exprs(scan.PhysicalProjection)
+		scan.Filter = expr(scan.Filter)
+		scan.Distinct = exprs(scan.Distinct)
+		scan.Projection = exprs(scan.Projection)
+		transformed.SchemaScan = &scan
+	case plan.Filter != nil:
+		transformed.Filter = &Filter{Expr: expr(plan.Filter.Expr)}
+	case plan.Distinct != nil:
+		transformed.Distinct = &Distinct{Exprs: exprs(plan.Distinct.Exprs), Limit: plan.Distinct.Limit}
+	case plan.Projection != nil:
+		transformed.Projection = &Projection{Exprs: exprs(plan.Projection.Exprs)}
+	case plan.Aggregation != nil:
+		transformed.Aggregation = &Aggregation{
+			AggExprs:   exprs(plan.Aggregation.AggExprs),
+			GroupExprs: exprs(plan.Aggregation.GroupExprs),
+		}
+	case plan.Unnest != nil:
+		transformed.Unnest = &Unnest{Expr: expr(plan.Unnest.Expr)}
+	case plan.Pivot != nil:
+		pivot := *plan.Pivot
+		pivot.Key = expr(pivot.Key)
+		pivot.Value = expr(pivot.Value)
+		transformed.Pivot = &pivot
+	case plan.Unpivot != nil:
+		unpivot := *plan.Unpivot
+		unpivot.Expr = expr(unpivot.Expr)
+		transformed.Unpivot = &unpivot
+	case plan.SemiJoin != nil:
+		join := *plan.SemiJoin
+		join.Key = expr(join.Key)
+		join.InnerKey = expr(join.InnerKey)
+		if err == nil {
+			join.Inner, err = join.Inner.transformExprs(f)
+		}
+		transformed.SemiJoin = &join
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &transformed, nil
+}
+
+// transform returns the result of f for a non-nil expression of the node. Once
+// err is set, the expressions are returned as they are.
+func transform(f func(*LogicalPlan, Expr) (Expr, error), node *LogicalPlan, expr Expr, err *error) Expr {
+	if expr == nil || *err != nil {
+		return expr
+	}
+	transformed, ferr := f(node, expr)
+	if ferr != nil {
+		*err = ferr
+		return expr
+	}
+	return transformed
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"testing"
 
+	"github.com/apache/arrow/go/v14/arrow"
 	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/apache/arrow/go/v14/arrow/scalar"
 	"github.com/stretchr/testify/require"
 
 	"github.com/polarsignals/frostdb/dynparquet"
+	schemav2pb "github.com/polarsignals/frostdb/gen/proto/go/frostdb/schema/v1alpha2"
 )
 
 type mockTableReader struct {
@@ -101,3 +104,127 @@ func Test_ExprClone(t *testing.T) {
 	expr.Op = OpGt
 	require.NotEqual(t, expr, expr2)
 }
+
+func TestInExprName(t *testing.T) {
+	values := func(n int) []scalar.Scalar {
+		values := make([]scalar.Scalar, n)
+		for i := range values {
+			values[i] = scalar.NewInt64Scalar(int64(i))
+		}
+		return values
+	}
+	require.Equal(t, "value IN (0, 1, 2)", In(Col("value"), values(3)...).Name())
+	require.Equal(t, "value NOT IN (0, 1, 2)", NotIn(Col("value"), values(3)...).Name())
+
+	// The names of large sets are bounded, but still tell sets apart.
+	large := In(Col("value"), values(1000)...).Name()
+	require.Regexp(t, `^value IN \(1000 values, hash [0-9a-f]{16}\)$`, large)
+	require.Equal(t, large, In(Col("value"), values(1000)...).Name())
+	require.NotEqual(t, large, In(Col("value"), values(1001)[1:]...).Name())
+}
+
+func TestNestedColumnPath(t *testing.T) {
+	leaf := func(name string, typ schemav2pb.StorageLayout_Type) *schemav2pb.Node {
+		return &schemav2pb.Node{Type: &schemav2pb.Node_Leaf{Leaf: &schemav2pb.Leaf{
+			Name:          name,
+			StorageLayout: &schemav2pb.StorageLayout{Type: typ, Nullable: true},
+		}}}
+	}
+	schema, err := dynparquet.SchemaFromDefinition(&schemav2pb.Schema{
+		Root: &schemav2pb.Group{
+			Name: "nested",
+			Nodes: []*schemav2pb.Node{
+				{Type: &schemav2pb.Node_Group{Group: &schemav2pb.Group{
+					Name: "attributes",
+					Nodes: []*schemav2pb.Node{
+						leaf("service", schemav2pb.StorageLayout_TYPE_STRING),
+						{Type: &schemav2pb.Node_Group{Group: &schemav2pb.Group{
+							Name:  "host",
+							Nodes: []*schemav2pb.Node{leaf("cpu", schemav2pb.StorageLayout_TYPE_INT64)},
+						}}},
+					},
+				}}},
+				leaf("value", schemav2pb.StorageLayout_TYPE_INT64),
+			},
+		},
+		SortingColumns: []*schemav2pb.SortingColumn{{
+			Path:      "value",
+			Direction: schemav2pb.SortingColumn_DIRECTION_ASCENDING,
+		}},
+	})
+	require.NoError(t, err)
+
+	// Nested fields resolve to the type of the field at the path.
+	dataType, err := Col("attributes.service").DataType(schema.ParquetSchema())
+	require.NoError(t, err)
+	require.Equal(t, arrow.BinaryTypes.Binary, dataType)
+	dataType, err = Col("attributes.host.cpu").DataType(schema.ParquetSchema())
+	require.NoError(t, err)
+	require.Equal(t, arrow.PrimitiveTypes.Int64, dataType)
+	_, err = Col("attributes.unknown").DataType(schema.ParquetSchema())
+	require.Error(t, err)
+
+	// A path matches the column itself and the groups it is nested in.
+	require.True(t, Col("attributes.host.cpu").MatchPath("attributes.host.cpu"))
+	require.True(t, Col("attributes.host.cpu").MatchPath("attributes.host"))
+	require.True(t, Col("attributes.host.cpu").MatchPath("attributes"))
+	require.False(t, Col("attributes.hostname").MatchPath("attributes.host"))
+	require.False(t, Col("attributes").MatchPath("attributes.host"))
+
+	// Nested fields are output as columns named by their path.
+	plan, err := (&Builder{}).
+		Scan(&mockTableProvider{schema}, "table1").
+		Filter(Col("attributes.service").Eq(Literal("api"))).
+		Project(Col("attributes.host.cpu"), Col("value")).
+		Build()
+	require.NoError(t, err)
+	outputSchema, err := plan.OutputSchema()
+	require.NoError(t, err)
+	require.Equal(t, []arrow.Field{
+		{Name: "attributes.host.cpu", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "value", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+	}, outputSchema.Fields())
+
+	// Groups none of whose fields are referred to are kept as structs.
+	plan, err = (&Builder{}).
+		Scan(&mockTableProvider{schema}, "table1").
+		Project(Col("attributes"), Col("value")).
+		Build()
+	require.NoError(t, err)
+	outputSchema, err = plan.OutputSchema()
+	require.NoError(t, err)
+	require.Equal(t, []string{"attributes", "value"}, []string{outputSchema.Field(0).Name, outputSchema.Field(1).Name})
+	require.Equal(t, arrow.STRUCT, outputSchema.Field(0).Type.ID())
+}
+
+func TestBindParams(t *testing.T) {
+	plan, err := (&Builder{}).
+		Scan(&mockTableProvider{dynparquet.NewSampleSchema()}, "table1").
+		Filter(And(
+			Col("labels.label1").Eq(Param("label")),
+			Col("timestamp").Gt(Param("start")),
+		)).
+		Aggregate([]Expr{Sum(Col("value"))}, []Expr{Col("stacktrace")}).
+		Build()
+	require.NoError(t, err)
+	for _, optimizer := range DefaultOptimizers() {
+		plan = optimizer.Optimize(plan)
+	}
+	require.Equal(t, []string{"label", "start"}, plan.Params())
+
+	bound, err := plan.Bind(map[string]any{"label": "value1", "start": int64(10)})
+	require.NoError(t, err)
+	require.Empty(t, bound.Params())
+	require.Equal(t, "labels.label1 == value1 && timestamp > 10", bound.Input.Filter.Expr.String())
+	require.Equal(t, bound.Input.Filter.Expr.String(), bound.Input.Input.TableScan.Filter.String())
+
+	// The prepared plan keeps its parameters.
+	require.Equal(t, "labels.label1 == $label && timestamp > $start", plan.Input.Filter.Expr.String())
+
+	_, err = plan.Bind(map[string]any{"label": "value1"})
+	require.EqualError(t, err, "missing value of parameter start")
+
+	// Bound values are validated like literals.
+	_, err = plan.Bind(map[string]any{"label": "value1", "start": "10"})
+	require.EqualError(t, err, "incompatible types: numeric column cannot be compared with string literal: timestamp > 10")
+}
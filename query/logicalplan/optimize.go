@@ -1,6 +1,7 @@
 package logicalplan
 
 import (
+	"github.com/parquet-go/parquet-go"
 	"golang.org/x/exp/slices"
 )
 
@@ -13,12 +14,14 @@ type Optimizer interface {
 func DefaultOptimizers() []Optimizer {
 	return []Optimizer{
 		&AverageAggregationPushDown{},
+		&SamplePushDown{},
 		&PhysicalProjectionPushDown{
 			defaultProjections: []Expr{
 				Not(DynCol(hashedMatch)),
 			},
 		},
 		&FilterPushDown{},
+		&MetadataAggregationPushDown{},
 		&DistinctPushDown{},
 		&ProjectionPushDown{},
 	}
@@ -27,6 +30,11 @@ func DefaultOptimizers() []Optimizer {
 type AverageAggregationPushDown struct{}
 
 func (p *AverageAggregationPushDown) Optimize(plan *LogicalPlan) *LogicalPlan {
+	if plan.GapFill != nil && plan.Input != nil {
+		// The averages are projected before their gaps are filled.
+		plan.Input = p.Optimize(plan.Input)
+		return plan
+	}
 	if plan.Aggregation == nil {
 		return plan
 	}
@@ -84,6 +92,102 @@ func (p *AverageAggregationPushDown) Optimize(plan *LogicalPlan) *LogicalPlan {
 	return plan
 }
 
+// The MetadataAggregationPushDown optimizer pushes ungrouped min, max and
+// count aggregations of a table scan without a filter down to the table
+// reader, so that the reader can answer them from the metadata of its data
+// instead of decoding every row. Only table readers implementing
+// MetadataAggregationReader support this, and min and max are only pushed
+// down for non-nullable int64 columns. It modifies the plan in place and needs
+// to run after the FilterPushDown optimizer, so that the filters of the
+// aggregated rows are found on the scan.
+type MetadataAggregationPushDown struct{}
+
+func (p *MetadataAggregationPushDown) Optimize(plan *LogicalPlan) *LogicalPlan {
+	for node := plan; node != nil; node = node.Input {
+		if node.Aggregation == nil {
+			continue
+		}
+		input := node.Input
+		for input != nil && input.Filter != nil {
+			input = input.Input
+		}
+		if input == nil || input.TableScan == nil {
+			continue
+		}
+		scan := input.TableScan
+		if scan.Filter != nil || len(node.Aggregation.GroupExprs) > 0 {
+			continue
+		}
+		if p.supported(node, node.Aggregation.AggExprs) {
+			scan.MetadataAggregations = node.Aggregation.AggExprs
+		}
+	}
+	return plan
+}
+
+func (p *MetadataAggregationPushDown) supported(plan *LogicalPlan, aggExprs []Expr) bool {
+	table, err := plan.TableReader()
+	if err != nil {
+		return false
+	}
+	if r, ok := table.(MetadataAggregationReader); !ok || !r.SupportsMetadataAggregations() {
+		return false
+	}
+	schema := table.Schema()
+	if schema == nil {
+		return false
+	}
+
+	for _, expr := range aggExprs {
+		if alias, ok := expr.(*AliasExpr); ok {
+			expr = alias.Expr
+		}
+		f, ok := expr.(*AggregationFunction)
+		if !ok {
+			return false
+		}
+		col, ok := f.Expr.(*Column)
+		if !ok {
+			return false
+		}
+		def, ok := schema.ColumnByName(col.ColumnName)
+		if !ok || def.Dynamic {
+			return false
+		}
+
+		switch f.Func {
+		case AggFuncCount:
+		case AggFuncMin, AggFuncMax:
+			if def.StorageLayout.Optional() || def.StorageLayout.Type().Kind() != parquet.Int64 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// The SamplePushDown optimizer pushes samples down to the table scan. The
+// table reader never reads the row groups that are not part of a sample of
+// whole row groups, and samples the rows of a Bernoulli sample by where they
+// are stored, which makes seeded samples repeatable. The sample node is kept,
+// so that the results still carry the sampling fraction. It modifies the plan
+// in place.
+type SamplePushDown struct{}
+
+func (p *SamplePushDown) Optimize(plan *LogicalPlan) *LogicalPlan {
+	for node := plan; node != nil; node = node.Input {
+		if node.Sample == nil {
+			continue
+		}
+		if node.Input != nil && node.Input.TableScan != nil {
+			node.Input.TableScan.Sample = node.Sample
+		}
+	}
+	return plan
+}
+
 // The PhysicalProjectionPushDown optimizer tries to push down the actual
 // physical columns used by the query to the table scan, so the table provider
 // can decide to only read the columns that are actually going to be used by
@@ -106,6 +210,9 @@ func (p *PhysicalProjectionPushDown) optimize(plan *LogicalPlan, columnsUsedExpr
 	case plan.Filter != nil:
 		p.defaultProjections = []Expr{}
 		columnsUsedExprs = append(columnsUsedExprs, plan.Filter.Expr.ColumnsUsedExprs()...)
+	case plan.SemiJoin != nil:
+		p.defaultProjections = []Expr{}
+		columnsUsedExprs = append(columnsUsedExprs, plan.SemiJoin.Key.ColumnsUsedExprs()...)
 	case plan.Distinct != nil:
 		p.defaultProjections = []Expr{}
 		for _, expr := range plan.Distinct.Exprs {
@@ -125,6 +232,21 @@ func (p *PhysicalProjectionPushDown) optimize(plan *LogicalPlan, columnsUsedExpr
 		}
 		p.defaultProjections = []Expr{}
 		columnsUsedExprs = append(columnsUsedExprs, DynCol(hashedMatch))
+	case plan.Unnest != nil:
+		// Without any columns used further up, all columns are read and
+		// the list column is read anyway.
+		if len(columnsUsedExprs) > 0 {
+			columnsUsedExprs = append(columnsUsedExprs, plan.Unnest.Expr.ColumnsUsedExprs()...)
+		}
+	case plan.Unpivot != nil:
+		// The key and value columns are read from the unpivoted columns.
+		if len(columnsUsedExprs) > 0 {
+			columnsUsedExprs = append(columnsUsedExprs, plan.Unpivot.Expr.ColumnsUsedExprs()...)
+		}
+	case plan.Pivot != nil:
+		// The rows are pivoted by the values of all columns, so all of
+		// them are read.
+		columnsUsedExprs = nil
 	}
 
 	if plan.Input != nil {
@@ -145,6 +267,14 @@ func (p *ProjectionPushDown) Optimize(plan *LogicalPlan) *LogicalPlan {
 	// Don't perform the optimization if filters or aggregations contain a column that projections do not.
 	// Otherwise we'll removed the columns we're filtering/aggregating.
 	// Also never remove prehashed columns if there is an aggregation being performed.
+	// Projections above an unnest operate on list elements, so they can't be
+	// pushed below it. The same goes for the columns of pivots and unpivots.
+	for p := plan; p != nil; p = p.Input {
+		if p.Unnest != nil || p.Pivot != nil || p.Unpivot != nil {
+			return plan
+		}
+	}
+
 	projectColumns := projectionColumns(plan)
 	projectMap := map[string]bool{}
 	filterColumns := filterColumns(plan)
@@ -305,6 +435,20 @@ func (p *FilterPushDown) optimize(plan *LogicalPlan, exprs []Expr) {
 		}
 	case plan.Filter != nil:
 		exprs = append(exprs, plan.Filter.Expr)
+	case plan.Unnest != nil:
+		// Filters of the list elements can't be evaluated against the lists.
+		exprs = slices.DeleteFunc(exprs, func(e Expr) bool {
+			for _, c := range e.ColumnsUsedExprs() {
+				if plan.Unnest.Expr.MatchColumn(c.Name()) {
+					return true
+				}
+			}
+			return false
+		})
+	case plan.Pivot != nil, plan.Unpivot != nil:
+		// Filters of the pivoted and unpivoted columns can't be evaluated
+		// against the columns they are made from.
+		exprs = nil
 	}
 
 	if plan.Input != nil {
@@ -333,6 +477,12 @@ func (p *DistinctPushDown) optimize(plan *LogicalPlan, distinctColumns []Expr) {
 		}
 	case plan.Distinct != nil:
 		distinctColumns = append(distinctColumns, plan.Distinct.Exprs...)
+	case plan.Unnest != nil, plan.Pivot != nil, plan.Unpivot != nil:
+		// Rows are only distinct once they are unnested or (un)pivoted.
+		distinctColumns = nil
+	case plan.Sample != nil && plan.Sample.Method == SampleBernoulli:
+		// Rows need to be sampled before they are deduplicated.
+		distinctColumns = nil
 	}
 
 	if plan.Input != nil {
@@ -96,6 +96,84 @@ func TestOptimizeFilterPushDown(t *testing.T) {
 	)
 }
 
+func TestOptimizeFilterPushDownUnnest(t *testing.T) {
+	tableProvider := &mockTableProvider{schema: dynparquet.NewSampleSchema()}
+	p, _ := (&Builder{}).
+		Scan(tableProvider, "table1").
+		Unnest(Col("stacktrace")).
+		Filter(And(
+			Col("stacktrace").Eq(Literal("abc")),
+			Col("labels.test").Eq(Literal("abc")),
+		)).
+		Build()
+
+	optimizer := &FilterPushDown{}
+	optimizer.Optimize(p)
+
+	// The filter of the list elements is not pushed below the unnest.
+	require.Equal(t, &TableScan{
+		TableName:     "table1",
+		TableProvider: tableProvider,
+	},
+		// Filter -> Unnest -> TableScan
+		p.Input.Input.TableScan,
+	)
+
+	p, _ = (&Builder{}).
+		Scan(tableProvider, "table1").
+		Filter(Col("labels.test").Eq(Literal("abc"))).
+		Unnest(Col("stacktrace")).
+		Filter(Col("stacktrace").Eq(Literal("abc"))).
+		Build()
+	optimizer.Optimize(p)
+
+	require.Equal(t, &TableScan{
+		TableName:     "table1",
+		TableProvider: tableProvider,
+		Filter: &BinaryExpr{
+			Left: &Column{ColumnName: "labels.test"},
+			Op:   OpEq,
+			Right: &LiteralExpr{
+				Value: scalar.MakeScalar("abc"),
+			},
+		},
+	},
+		// Filter -> Unnest -> Filter -> TableScan
+		p.Input.Input.Input.TableScan,
+	)
+}
+
+func TestOptimizeSamplePushDown(t *testing.T) {
+	tableProvider := &mockTableProvider{schema: dynparquet.NewSampleSchema()}
+	build := func(method SampleMethod) *LogicalPlan {
+		p, err := (&Builder{}).
+			Scan(tableProvider, "table1").
+			Sample(Sample{Method: method, Fraction: 0.5}).
+			Distinct(Col("stacktrace")).
+			Build()
+		require.NoError(t, err)
+		for _, optimizer := range DefaultOptimizers() {
+			p = optimizer.Optimize(p)
+		}
+		return p
+	}
+
+	// Row group samples are pushed down to the scan, which can still
+	// deduplicate the rows of the sampled row groups.
+	p := build(SampleSystem)
+	// Distinct -> Sample -> Projection -> TableScan
+	scan := p.Input.Input.Input.TableScan
+	require.Equal(t, &Sample{Method: SampleSystem, Fraction: 0.5}, scan.Sample)
+	require.Equal(t, []Expr{Col("stacktrace")}, scan.Distinct)
+
+	// Rows are sampled by the scan too, but need to be sampled before they
+	// are deduplicated.
+	p = build(SampleBernoulli)
+	scan = p.Input.Input.Input.TableScan
+	require.Equal(t, &Sample{Method: SampleBernoulli, Fraction: 0.5}, scan.Sample)
+	require.Nil(t, scan.Distinct)
+}
+
 func TestRemoveProjectionAtRoot(t *testing.T) {
 	p, _ := (&Builder{}).
 		Scan(&mockTableProvider{schema: dynparquet.NewSampleSchema()}, "table1").
@@ -0,0 +1,716 @@
+package logicalplan
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/scalar"
+
+	pb "github.com/polarsignals/frostdb/gen/proto/go/frostdb/logicalplan/v1alpha1"
+)
+
+// Marshal serializes the plan, so that it can be executed by a remote
+// process. The table providers of the plan's scans are not serialized.
+func Marshal(plan *LogicalPlan) ([]byte, error) {
+	node, err := PlanToProto(plan)
+	if err != nil {
+		return nil, err
+	}
+	return node.MarshalVT()
+}
+
+// Unmarshal deserializes a plan serialized by Marshal. The tables the plan
+// scans are resolved by name by the given table provider.
+func Unmarshal(data []byte, provider TableProvider) (*LogicalPlan, error) {
+	node := &pb.PlanNode{}
+	if err := node.UnmarshalVT(data); err != nil {
+		return nil, err
+	}
+	return PlanFromProto(node, provider)
+}
+
+// PlanToProto converts the plan to its protobuf representation.
+func PlanToProto(plan *LogicalPlan) (*pb.PlanNode, error) {
+	if plan == nil {
+		return nil, nil
+	}
+
+	input, err := PlanToProto(plan.Input)
+	if err != nil {
+		return nil, err
+	}
+	node := &pb.PlanNode{Input: input}
+
+	switch {
+	case plan.TableScan != nil:
+		scan := &pb.TableScan{
+			TableName:       plan.TableScan.TableName,
+			SkipSources:     plan.TableScan.SkipSources,
+			PersistedBefore: plan.TableScan.PersistedBefore,
+		}
+		if scan.PhysicalProjection, err = exprsToProto(plan.TableScan.PhysicalProjection); err != nil {
+			return nil, err
+		}
+		if scan.Filter, err = ExprToProto(plan.TableScan.Filter); err != nil {
+			return nil, err
+		}
+		if scan.Distinct, err = exprsToProto(plan.TableScan.Distinct); err != nil {
+			return nil, err
+		}
+		if scan.Projection, err = exprsToProto(plan.TableScan.Projection); err != nil {
+			return nil, err
+		}
+		if scan.MetadataAggregations, err = exprsToProto(plan.TableScan.MetadataAggregations); err != nil {
+			return nil, err
+		}
+		if plan.TableScan.Sample != nil {
+			scan.Sample = sampleToProto(plan.TableScan.Sample)
+		}
+		node.Spec = &pb.PlanNode_TableScan{TableScan: scan}
+	case plan.SchemaScan != nil:
+		scan := &pb.SchemaScan{
+			TableName:   plan.SchemaScan.TableName,
+			SkipSources: plan.SchemaScan.SkipSources,
+		}
+		if scan.PhysicalProjection, err = exprsToProto(plan.SchemaScan.PhysicalProjection); err != nil {
+			return nil, err
+		}
+		if scan.Filter, err = ExprToProto(plan.SchemaScan.Filter); err != nil {
+			return nil, err
+		}
+		if scan.Distinct, err = exprsToProto(plan.SchemaScan.Distinct); err != nil {
+			return nil, err
+		}
+		if scan.Projection, err = exprsToProto(plan.SchemaScan.Projection); err != nil {
+			return nil, err
+		}
+		node.Spec = &pb.PlanNode_SchemaScan{SchemaScan: scan}
+	case plan.Filter != nil:
+		expr, err := ExprToProto(plan.Filter.Expr)
+		if err != nil {
+			return nil, err
+		}
+		node.Spec = &pb.PlanNode_Filter{Filter: &pb.Filter{Expr: expr}}
+	case plan.Distinct != nil:
+		exprs, err := exprsToProto(plan.Distinct.Exprs)
+		if err != nil {
+			return nil, err
+		}
+		node.Spec = &pb.PlanNode_Distinct{Distinct: &pb.Distinct{Exprs: exprs, Limit: plan.Distinct.Limit}}
+	case plan.Projection != nil:
+		exprs, err := exprsToProto(plan.Projection.Exprs)
+		if err != nil {
+			return nil, err
+		}
+		node.Spec = &pb.PlanNode_Projection{Projection: &pb.Projection{Exprs: exprs}}
+	case plan.Aggregation != nil:
+		aggExprs, err := exprsToProto(plan.Aggregation.AggExprs)
+		if err != nil {
+			return nil, err
+		}
+		groupExprs, err := exprsToProto(plan.Aggregation.GroupExprs)
+		if err != nil {
+			return nil, err
+		}
+		node.Spec = &pb.PlanNode_Aggregation{Aggregation: &pb.Aggregation{
+			AggExprs:   aggExprs,
+			GroupExprs: groupExprs,
+		}}
+	case plan.Unnest != nil:
+		expr, err := ExprToProto(plan.Unnest.Expr)
+		if err != nil {
+			return nil, err
+		}
+		node.Spec = &pb.PlanNode_Unnest{Unnest: &pb.Unnest{Expr: expr}}
+	case plan.Sample != nil:
+		node.Spec = &pb.PlanNode_Sample{Sample: sampleToProto(plan.Sample)}
+	case plan.GapFill != nil:
+		node.Spec = &pb.PlanNode_GapFill{GapFill: gapFillToProto(plan.GapFill)}
+	case plan.Pivot != nil:
+		key, err := ExprToProto(plan.Pivot.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := ExprToProto(plan.Pivot.Value)
+		if err != nil {
+			return nil, err
+		}
+		node.Spec = &pb.PlanNode_Pivot{Pivot: &pb.Pivot{
+			Key:    key,
+			Value:  value,
+			Prefix: plan.Pivot.Prefix,
+		}}
+	case plan.Unpivot != nil:
+		expr, err := ExprToProto(plan.Unpivot.Expr)
+		if err != nil {
+			return nil, err
+		}
+		node.Spec = &pb.PlanNode_Unpivot{Unpivot: &pb.Unpivot{
+			Expr:  expr,
+			Key:   plan.Unpivot.Key,
+			Value: plan.Unpivot.Value,
+		}}
+	case plan.SemiJoin != nil:
+		key, err := ExprToProto(plan.SemiJoin.Key)
+		if err != nil {
+			return nil, err
+		}
+		inner, err := PlanToProto(plan.SemiJoin.Inner)
+		if err != nil {
+			return nil, err
+		}
+		innerKey, err := ExprToProto(plan.SemiJoin.InnerKey)
+		if err != nil {
+			return nil, err
+		}
+		node.Spec = &pb.PlanNode_SemiJoin{SemiJoin: &pb.SemiJoin{
+			Key:      key,
+			Inner:    inner,
+			InnerKey: innerKey,
+			Anti:     plan.SemiJoin.Anti,
+		}}
+	default:
+		return nil, errors.New("unsupported plan node")
+	}
+
+	return node, nil
+}
+
+// PlanFromProto converts the protobuf representation of a plan to the plan.
+// The tables the plan scans are resolved by name by the given table provider.
+func PlanFromProto(node *pb.PlanNode, provider TableProvider) (*LogicalPlan, error) {
+	if node == nil {
+		return nil, nil
+	}
+
+	input, err := PlanFromProto(node.Input, provider)
+	if err != nil {
+		return nil, err
+	}
+	plan := &LogicalPlan{Input: input}
+
+	switch spec := node.Spec.(type) {
+	case *pb.PlanNode_TableScan:
+		scan := &TableScan{
+			TableProvider:   provider,
+			TableName:       spec.TableScan.TableName,
+			SkipSources:     spec.TableScan.SkipSources,
+			PersistedBefore: spec.TableScan.PersistedBefore,
+		}
+		if scan.PhysicalProjection, err = exprsFromProto(spec.TableScan.PhysicalProjection); err != nil {
+			return nil, err
+		}
+		if scan.Filter, err = ExprFromProto(spec.TableScan.Filter); err != nil {
+			return nil, err
+		}
+		if scan.Distinct, err = exprsFromProto(spec.TableScan.Distinct); err != nil {
+			return nil, err
+		}
+		if scan.Projection, err = exprsFromProto(spec.TableScan.Projection); err != nil {
+			return nil, err
+		}
+		if scan.MetadataAggregations, err = exprsFromProto(spec.TableScan.MetadataAggregations); err != nil {
+			return nil, err
+		}
+		if spec.TableScan.Sample != nil {
+			if scan.Sample, err = sampleFromProto(spec.TableScan.Sample); err != nil {
+				return nil, err
+			}
+		}
+		plan.TableScan = scan
+	case *pb.PlanNode_SchemaScan:
+		scan := &SchemaScan{
+			TableProvider: provider,
+			TableName:     spec.SchemaScan.TableName,
+			SkipSources:   spec.SchemaScan.SkipSources,
+		}
+		if scan.PhysicalProjection, err = exprsFromProto(spec.SchemaScan.PhysicalProjection); err != nil {
+			return nil, err
+		}
+		if scan.Filter, err = ExprFromProto(spec.SchemaScan.Filter); err != nil {
+			return nil, err
+		}
+		if scan.Distinct, err = exprsFromProto(spec.SchemaScan.Distinct); err != nil {
+			return nil, err
+		}
+		if scan.Projection, err = exprsFromProto(spec.SchemaScan.Projection); err != nil {
+			return nil, err
+		}
+		plan.SchemaScan = scan
+	case *pb.PlanNode_Filter:
+		expr, err := ExprFromProto(spec.Filter.Expr)
+		if err != nil {
+			return nil, err
+		}
+		plan.Filter = &Filter{Expr: expr}
+	case *pb.PlanNode_Distinct:
+		exprs, err := exprsFromProto(spec.Distinct.Exprs)
+		if err != nil {
+			return nil, err
+		}
+		plan.Distinct = &Distinct{Exprs: exprs, Limit: spec.Distinct.Limit}
+	case *pb.PlanNode_Projection:
+		exprs, err := exprsFromProto(spec.Projection.Exprs)
+		if err != nil {
+			return nil, err
+		}
+		plan.Projection = &Projection{Exprs: exprs}
+	case *pb.PlanNode_Aggregation:
+		aggExprs, err := exprsFromProto(spec.Aggregation.AggExprs)
+		if err != nil {
+			return nil, err
+		}
+		groupExprs, err := exprsFromProto(spec.Aggregation.GroupExprs)
+		if err != nil {
+			return nil, err
+		}
+		plan.Aggregation = &Aggregation{
+			AggExprs:   aggExprs,
+			GroupExprs: groupExprs,
+		}
+	case *pb.PlanNode_Unnest:
+		expr, err := ExprFromProto(spec.Unnest.Expr)
+		if err != nil {
+			return nil, err
+		}
+		plan.Unnest = &Unnest{Expr: expr}
+	case *pb.PlanNode_Sample:
+		if plan.Sample, err = sampleFromProto(spec.Sample); err != nil {
+			return nil, err
+		}
+	case *pb.PlanNode_GapFill:
+		if plan.GapFill, err = gapFillFromProto(spec.GapFill); err != nil {
+			return nil, err
+		}
+	case *pb.PlanNode_Pivot:
+		key, err := ExprFromProto(spec.Pivot.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := ExprFromProto(spec.Pivot.Value)
+		if err != nil {
+			return nil, err
+		}
+		plan.Pivot = &Pivot{
+			Key:    key,
+			Value:  value,
+			Prefix: spec.Pivot.Prefix,
+		}
+	case *pb.PlanNode_Unpivot:
+		expr, err := ExprFromProto(spec.Unpivot.Expr)
+		if err != nil {
+			return nil, err
+		}
+		plan.Unpivot = &Unpivot{
+			Expr:  expr,
+			Key:   spec.Unpivot.Key,
+			Value: spec.Unpivot.Value,
+		}
+	case *pb.PlanNode_SemiJoin:
+		key, err := ExprFromProto(spec.SemiJoin.Key)
+		if err != nil {
+			return nil, err
+		}
+		inner, err := PlanFromProto(spec.SemiJoin.Inner, provider)
+		if err != nil {
+			return nil, err
+		}
+		innerKey, err := ExprFromProto(spec.SemiJoin.InnerKey)
+		if err != nil {
+			return nil, err
+		}
+		plan.SemiJoin = &SemiJoin{
+			Key:      key,
+			Inner:    inner,
+			InnerKey: innerKey,
+			Anti:     spec.SemiJoin.Anti,
+		}
+	default:
+		return nil, fmt.Errorf("unsupported plan node: %T", spec)
+	}
+
+	return plan, nil
+}
+
+func sampleToProto(s *Sample) *pb.Sample {
+	method := pb.Sample_METHOD_BERNOULLI
+	if s.Method == SampleSystem {
+		method = pb.Sample_METHOD_SYSTEM
+	}
+	return &pb.Sample{
+		Method:            method,
+		Fraction:          s.Fraction,
+		Seed:              s.Seed,
+		ScaleAggregations: s.ScaleAggregations,
+	}
+}
+
+func sampleFromProto(s *pb.Sample) (*Sample, error) {
+	var method SampleMethod
+	switch s.Method {
+	case pb.Sample_METHOD_BERNOULLI:
+		method = SampleBernoulli
+	case pb.Sample_METHOD_SYSTEM:
+		method = SampleSystem
+	default:
+		return nil, fmt.Errorf("unsupported sample method: %s", s.Method)
+	}
+	return &Sample{
+		Method:            method,
+		Fraction:          s.Fraction,
+		Seed:              s.Seed,
+		ScaleAggregations: s.ScaleAggregations,
+	}, nil
+}
+
+func gapFillToProto(g *GapFill) *pb.GapFill {
+	var fill pb.GapFill_Fill
+	switch g.Fill {
+	case FillNull:
+		fill = pb.GapFill_FILL_NULL
+	case FillPrevious:
+		fill = pb.GapFill_FILL_PREVIOUS
+	case FillLinear:
+		fill = pb.GapFill_FILL_LINEAR
+	}
+	return &pb.GapFill{
+		Start: g.Start,
+		End:   g.End,
+		Step:  &pb.Duration{Nanoseconds: int64(g.Step)},
+		Fill:  fill,
+	}
+}
+
+func gapFillFromProto(g *pb.GapFill) (*GapFill, error) {
+	var fill FillMode
+	switch g.Fill {
+	case pb.GapFill_FILL_NULL:
+		fill = FillNull
+	case pb.GapFill_FILL_PREVIOUS:
+		fill = FillPrevious
+	case pb.GapFill_FILL_LINEAR:
+		fill = FillLinear
+	default:
+		return nil, fmt.Errorf("unsupported gap fill mode: %s", g.Fill)
+	}
+	return &GapFill{
+		Start: g.Start,
+		End:   g.End,
+		Step:  time.Duration(g.Step.GetNanoseconds()),
+		Fill:  fill,
+	}, nil
+}
+
+func exprsToProto(exprs []Expr) ([]*pb.Expr, error) {
+	if exprs == nil {
+		return nil, nil
+	}
+
+	res := make([]*pb.Expr, 0, len(exprs))
+	for _, expr := range exprs {
+		e, err := ExprToProto(expr)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, e)
+	}
+	return res, nil
+}
+
+func exprsFromProto(exprs []*pb.Expr) ([]Expr, error) {
+	if exprs == nil {
+		return nil, nil
+	}
+
+	res := make([]Expr, 0, len(exprs))
+	for _, expr := range exprs {
+		e, err := ExprFromProto(expr)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, e)
+	}
+	return res, nil
+}
+
+// ExprToProto converts the expression to its protobuf representation.
+func ExprToProto(expr Expr) (*pb.Expr, error) {
+	if expr == nil {
+		return nil, nil
+	}
+
+	switch e := expr.(type) {
+	case *BinaryExpr:
+		left, err := ExprToProto(e.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := ExprToProto(e.Right)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.Expr{Def: &pb.Expr_Binary{Binary: &pb.BinaryExpr{
+			Left:  left,
+			Op:    pb.BinaryExpr_Op(e.Op),
+			Right: right,
+		}}}, nil
+	case *Column:
+		return &pb.Expr{Def: &pb.Expr_Column{Column: &pb.Column{Name: e.ColumnName}}}, nil
+	case *DynamicColumn:
+		return &pb.Expr{Def: &pb.Expr_DynamicColumn{DynamicColumn: &pb.DynamicColumn{Name: e.ColumnName}}}, nil
+	case *LiteralExpr:
+		literal, err := literalToProto(e.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.Expr{Def: &pb.Expr_Literal{Literal: literal}}, nil
+	case *AggregationFunction:
+		inner, err := ExprToProto(e.Expr)
+		if err != nil {
+			return nil, err
+		}
+		orderBy, err := ExprToProto(e.OrderBy)
+		if err != nil {
+			return nil, err
+		}
+		paired, err := ExprToProto(e.Paired)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.Expr{Def: &pb.Expr_AggregationFunction{AggregationFunction: &pb.AggregationFunction{
+			Type:    pb.AggregationFunction_Type(e.Func),
+			Expr:    inner,
+			OrderBy: orderBy,
+			Paired:  paired,
+			Buckets: e.Buckets,
+		}}}, nil
+	case *AliasExpr:
+		inner, err := ExprToProto(e.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.Expr{Def: &pb.Expr_Alias{Alias: &pb.Alias{Expr: inner, Name: e.Alias}}}, nil
+	case *DurationExpr:
+		return &pb.Expr{Def: &pb.Expr_Duration{Duration: &pb.Duration{Nanoseconds: int64(e.duration)}}}, nil
+	case *AverageExpr:
+		inner, err := ExprToProto(e.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.Expr{Def: &pb.Expr_Average{Average: &pb.Average{Expr: inner}}}, nil
+	case *RegexpColumnMatch:
+		return &pb.Expr{Def: &pb.Expr_RegexpColumnMatch{RegexpColumnMatch: &pb.RegexpColumnMatch{
+			Match:   e.match.String(),
+			Inverse: e.inverse,
+		}}}, nil
+	case *AllExpr:
+		return &pb.Expr{Def: &pb.Expr_All{All: &pb.All{}}}, nil
+	case *NotExpr:
+		inner, err := ExprToProto(e.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.Expr{Def: &pb.Expr_Not{Not: &pb.Not{Expr: inner}}}, nil
+	case *WidthBucketExpr:
+		inner, err := ExprToProto(e.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.Expr{Def: &pb.Expr_WidthBucket{WidthBucket: &pb.WidthBucket{
+			Expr:    inner,
+			Min:     e.Min,
+			Max:     e.Max,
+			Buckets: e.Buckets,
+		}}}, nil
+	case *InExpr:
+		inner, err := ExprToProto(e.Expr)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]*pb.Literal, len(e.Values))
+		for i, v := range e.Values {
+			if values[i], err = literalToProto(v); err != nil {
+				return nil, err
+			}
+		}
+		return &pb.Expr{Def: &pb.Expr_In{In: &pb.In{
+			Expr:   inner,
+			Values: values,
+			Not:    e.Not,
+		}}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported expression: %T", expr)
+	}
+}
+
+// ExprFromProto converts the protobuf representation of an expression to the
+// expression.
+func ExprFromProto(expr *pb.Expr) (Expr, error) {
+	if expr == nil {
+		return nil, nil
+	}
+
+	switch e := expr.Def.(type) {
+	case *pb.Expr_Binary:
+		if e.Binary.Op <= pb.BinaryExpr_OP_UNKNOWN_UNSPECIFIED || e.Binary.Op > pb.BinaryExpr_OP_OR {
+			return nil, fmt.Errorf("unsupported binary operator: %s", e.Binary.Op)
+		}
+		left, err := ExprFromProto(e.Binary.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := ExprFromProto(e.Binary.Right)
+		if err != nil {
+			return nil, err
+		}
+		return &BinaryExpr{
+			Left:  left,
+			Op:    Op(e.Binary.Op),
+			Right: right,
+		}, nil
+	case *pb.Expr_Column:
+		return Col(e.Column.Name), nil
+	case *pb.Expr_DynamicColumn:
+		return DynCol(e.DynamicColumn.Name), nil
+	case *pb.Expr_Literal:
+		value, err := literalFromProto(e.Literal)
+		if err != nil {
+			return nil, err
+		}
+		return &LiteralExpr{Value: value}, nil
+	case *pb.Expr_AggregationFunction:
+		if e.AggregationFunction.Type <= pb.AggregationFunction_TYPE_UNKNOWN_UNSPECIFIED ||
+			e.AggregationFunction.Type > pb.AggregationFunction_TYPE_INCREASE {
+			return nil, fmt.Errorf("unsupported aggregation function: %s", e.AggregationFunction.Type)
+		}
+		inner, err := ExprFromProto(e.AggregationFunction.Expr)
+		if err != nil {
+			return nil, err
+		}
+		orderBy, err := ExprFromProto(e.AggregationFunction.OrderBy)
+		if err != nil {
+			return nil, err
+		}
+		paired, err := ExprFromProto(e.AggregationFunction.Paired)
+		if err != nil {
+			return nil, err
+		}
+		return &AggregationFunction{
+			Func:    AggFunc(e.AggregationFunction.Type),
+			Expr:    inner,
+			OrderBy: orderBy,
+			Paired:  paired,
+			Buckets: e.AggregationFunction.Buckets,
+		}, nil
+	case *pb.Expr_Alias:
+		inner, err := ExprFromProto(e.Alias.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return &AliasExpr{Expr: inner, Alias: e.Alias.Name}, nil
+	case *pb.Expr_Duration:
+		return Duration(time.Duration(e.Duration.Nanoseconds)), nil
+	case *pb.Expr_Average:
+		inner, err := ExprFromProto(e.Average.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return &AverageExpr{Expr: inner}, nil
+	case *pb.Expr_RegexpColumnMatch:
+		match, err := regexp.Compile(e.RegexpColumnMatch.Match)
+		if err != nil {
+			return nil, err
+		}
+		if e.RegexpColumnMatch.Inverse {
+			return RegExpNotColumnMatch(match), nil
+		}
+		return RegExpColumnMatch(match), nil
+	case *pb.Expr_All:
+		return All(), nil
+	case *pb.Expr_Not:
+		inner, err := ExprFromProto(e.Not.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return Not(inner), nil
+	case *pb.Expr_WidthBucket:
+		inner, err := ExprFromProto(e.WidthBucket.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return WidthBucket(inner, e.WidthBucket.Min, e.WidthBucket.Max, e.WidthBucket.Buckets), nil
+	case *pb.Expr_In:
+		inner, err := ExprFromProto(e.In.Expr)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]scalar.Scalar, len(e.In.Values))
+		for i, v := range e.In.Values {
+			if values[i], err = literalFromProto(v); err != nil {
+				return nil, err
+			}
+		}
+		return &InExpr{Expr: inner, Values: values, Not: e.In.Not}, nil
+	default:
+		return nil, fmt.Errorf("unsupported expression: %T", e)
+	}
+}
+
+func literalToProto(value scalar.Scalar) (*pb.Literal, error) {
+	if !value.IsValid() {
+		return &pb.Literal{Value: &pb.Literal_Null{Null: &pb.Null{}}}, nil
+	}
+
+	switch v := value.(type) {
+	case *scalar.Boolean:
+		return &pb.Literal{Value: &pb.Literal_BoolValue{BoolValue: v.Value}}, nil
+	case *scalar.Int32:
+		return &pb.Literal{Value: &pb.Literal_Int32Value{Int32Value: v.Value}}, nil
+	case *scalar.Uint32:
+		return &pb.Literal{Value: &pb.Literal_Uint32Value{Uint32Value: v.Value}}, nil
+	case *scalar.Int64:
+		return &pb.Literal{Value: &pb.Literal_Int64Value{Int64Value: v.Value}}, nil
+	case *scalar.Uint64:
+		return &pb.Literal{Value: &pb.Literal_Uint64Value{Uint64Value: v.Value}}, nil
+	case *scalar.Float32:
+		return &pb.Literal{Value: &pb.Literal_FloatValue{FloatValue: v.Value}}, nil
+	case *scalar.Float64:
+		return &pb.Literal{Value: &pb.Literal_DoubleValue{DoubleValue: v.Value}}, nil
+	case *scalar.String:
+		return &pb.Literal{Value: &pb.Literal_StringValue{StringValue: string(v.Data())}}, nil
+	case *scalar.Binary:
+		return &pb.Literal{Value: &pb.Literal_BinaryValue{BinaryValue: v.Data()}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported literal type: %s", value.DataType())
+	}
+}
+
+func literalFromProto(literal *pb.Literal) (scalar.Scalar, error) {
+	switch v := literal.Value.(type) {
+	case *pb.Literal_Null:
+		return scalar.MakeNullScalar(arrow.Null), nil
+	case *pb.Literal_BoolValue:
+		return scalar.NewBooleanScalar(v.BoolValue), nil
+	case *pb.Literal_Int32Value:
+		return scalar.NewInt32Scalar(v.Int32Value), nil
+	case *pb.Literal_Uint32Value:
+		return scalar.NewUint32Scalar(v.Uint32Value), nil
+	case *pb.Literal_Int64Value:
+		return scalar.NewInt64Scalar(v.Int64Value), nil
+	case *pb.Literal_Uint64Value:
+		return scalar.NewUint64Scalar(v.Uint64Value), nil
+	case *pb.Literal_FloatValue:
+		return scalar.NewFloat32Scalar(v.FloatValue), nil
+	case *pb.Literal_DoubleValue:
+		return scalar.NewFloat64Scalar(v.DoubleValue), nil
+	case *pb.Literal_StringValue:
+		return scalar.MakeScalar(v.StringValue), nil
+	case *pb.Literal_BinaryValue:
+		return scalar.MakeScalar(v.BinaryValue), nil
+	default:
+		return nil, fmt.Errorf("unsupported literal: %T", v)
+	}
+}
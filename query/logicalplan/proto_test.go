@@ -0,0 +1,246 @@
+package logicalplan
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v14/arrow/scalar"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/polarsignals/frostdb/dynparquet"
+	pb "github.com/polarsignals/frostdb/gen/proto/go/frostdb/logicalplan/v1alpha1"
+)
+
+// roundTripPlans returns the plans serialized by TestPlanProtoRoundTrip.
+// Together they cover every node and expression type. The builders are
+// created on every call, as optimizers modify the plans they build.
+func roundTripPlans(tableProvider TableProvider) map[string]Builder {
+	scan := func() Builder { return (&Builder{}).Scan(tableProvider, "table1") }
+	return map[string]Builder{
+		"aggregate": scan().
+			Filter(Col("labels.test").Eq(Literal("abc"))).
+			Aggregate(
+				[]Expr{Sum(Col("value")).Alias("value_sum")},
+				[]Expr{Col("stacktrace")},
+			).
+			Project(Col("stacktrace")),
+		"aggregate dynamic projection": scan().
+			Filter(Col("labels.test").Eq(Literal("abc"))).
+			Aggregate(
+				[]Expr{Sum(Col("value")).Alias("value_sum")},
+				[]Expr{Col("stacktrace")},
+			).
+			Project(Col("labels")),
+		"middle projection": scan().
+			Filter(Col("labels.test").Eq(Literal("abc"))).
+			Project(Col("stacktrace")).
+			Aggregate(
+				[]Expr{Sum(Col("value")).Alias("value_sum")},
+				[]Expr{Col("stacktrace")},
+			),
+		"lowest projection": scan().
+			Project(Col("stacktrace")).
+			Filter(Col("labels.test").Eq(Literal("abc"))).
+			Aggregate(
+				[]Expr{Sum(Col("value")).Alias("value_sum")},
+				[]Expr{Col("stacktrace")},
+			),
+		"distinct": scan().
+			Distinct(Col("labels.test")),
+		"distinct dynamic": scan().
+			Distinct(DynCol("labels")),
+		"distinct limit": scan().
+			Filter(Col("labels.test").RegexMatch("^a")).
+			DistinctLimit(10, Col("labels.test")),
+		"unnest": scan().
+			Unnest(Col("stacktrace")).
+			Filter(And(
+				Col("stacktrace").Eq(Literal("abc")),
+				Col("labels.test").Eq(Literal("abc")),
+			)),
+		"filter unnest": scan().
+			Filter(Col("labels.test").Eq(Literal("abc"))).
+			Unnest(Col("stacktrace")).
+			Filter(Col("stacktrace").Eq(Literal("abc"))),
+		"sample system": scan().
+			Sample(Sample{Method: SampleSystem, Fraction: 0.5}).
+			Distinct(Col("stacktrace")),
+		"sample bernoulli": scan().
+			Sample(Sample{Method: SampleBernoulli, Fraction: 0.5}).
+			Distinct(Col("stacktrace")),
+		"sample aggregation": scan().
+			Sample(Sample{Method: SampleSystem, Fraction: 0.25, Seed: 42, ScaleAggregations: true}).
+			Aggregate([]Expr{Count(Col("value"))}, []Expr{Col("stacktrace")}),
+		"gap fill": scan().
+			Aggregate([]Expr{Avg(Col("value"))}, []Expr{Col("stacktrace"), Duration(time.Minute)}).
+			GapFill(GapFill{Start: 60000, End: 600000, Step: time.Minute, Fill: FillLinear}),
+		"pivot and unpivot": scan().
+			Unpivot(Unpivot{Expr: DynCol("labels"), Key: "label", Value: "label_value"}).
+			Pivot(Pivot{Key: Col("label"), Value: Col("label_value"), Prefix: "labels"}),
+		"semi join": scan().
+			SemiJoin(
+				(&Builder{}).Scan(tableProvider, "table1").Distinct(Col("stacktrace")),
+				SemiJoin{Key: Col("stacktrace"), InnerKey: Col("stacktrace"), Anti: true},
+			).
+			Filter(In(Col("labels.label1"), scalar.NewStringScalar("a"), scalar.NewStringScalar("b"))),
+		"schema scan": (&Builder{}).ScanSchema(tableProvider, "table1"),
+		"expressions": scan().
+			Filter(And(
+				Col("value").Gt(Literal(int64(-1))),
+				Or(
+					Col("timestamp").LtEq(Literal(int32(2))),
+					Col("value").NotEq(Literal(uint64(3))),
+				),
+				Col("labels.test").RegexMatch("ab.*"),
+				Col("labels.test").RegexNotMatch("c"),
+				Col("stacktrace").Eq(Literal([]byte("stack"))),
+				Col("value").Lt(Literal(4.6)),
+				Col("value").GtEq(Literal(float32(1))),
+				Col("value").Eq(Literal(uint32(1))),
+				Col("labels.test").Eq(Literal(true)),
+				Col("labels.test").NotEq(Literal(nil)),
+			)).
+			Project(
+				DynCol("labels"),
+				RegExpColumnMatch(regexp.MustCompile("^labels\\..*")),
+				RegExpNotColumnMatch(regexp.MustCompile("stacktrace")),
+				Not(Col("value")),
+				All(),
+			).
+			Aggregate(
+				[]Expr{
+					Sum(Col("value")),
+					Min(Col("value")),
+					Max(Col("value")),
+					Count(Col("value")),
+					Avg(Col("value")).Alias("value_avg"),
+					First(Col("value"), Col("timestamp")),
+					Last(Col("value"), Col("timestamp")).Alias("value_last"),
+					StddevPop(Col("value")),
+					StddevSamp(Col("value")),
+					VarPop(Col("value")),
+					VarSamp(Col("value")),
+					CovarPop(Col("value"), Col("timestamp")),
+					CovarSamp(Col("value"), Col("timestamp")),
+					Corr(Col("value"), Col("timestamp")).Alias("value_corr"),
+					Histogram(Col("value"), ExponentialBuckets(1, 2, 4)).Alias("value_histogram"),
+					Rate(Col("value"), Col("timestamp")).Alias("value_rate"),
+					Increase(Col("value"), Col("timestamp")),
+				},
+				[]Expr{DynCol("labels"), Duration(time.Minute), WidthBucket(Col("timestamp"), 0, 100, 10)},
+			).
+			Project(&AverageExpr{Expr: Col("value_avg")}),
+	}
+}
+
+func TestPlanProtoRoundTrip(t *testing.T) {
+	tableProvider := &mockTableProvider{schema: dynparquet.NewSampleSchema()}
+
+	covered := map[protoreflect.FullName]map[string]bool{}
+	for name := range roundTripPlans(tableProvider) {
+		for _, optimize := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/optimize=%t", name, optimize), func(t *testing.T) {
+				plan, err := roundTripPlans(tableProvider)[name].Build()
+				require.NoError(t, err)
+				if optimize {
+					for _, optimizer := range DefaultOptimizers() {
+						plan = optimizer.Optimize(plan)
+					}
+				}
+
+				node, err := PlanToProto(plan)
+				require.NoError(t, err)
+				protoCoverage(node.ProtoReflect(), covered)
+
+				data, err := Marshal(plan)
+				require.NoError(t, err)
+				res, err := Unmarshal(data, tableProvider)
+				require.NoError(t, err)
+				require.Equal(t, plan, res)
+			})
+		}
+	}
+
+	// Every plan node, expression, literal, operator and aggregation
+	// function needs to be part of a test plan.
+	for _, msg := range []protoreflect.MessageDescriptor{
+		(&pb.PlanNode{}).ProtoReflect().Descriptor(),
+		(&pb.Expr{}).ProtoReflect().Descriptor(),
+		(&pb.Literal{}).ProtoReflect().Descriptor(),
+	} {
+		for i := 0; i < msg.Oneofs().Len(); i++ {
+			oneof := msg.Oneofs().Get(i)
+			for j := 0; j < oneof.Fields().Len(); j++ {
+				field := oneof.Fields().Get(j)
+				require.True(t, covered[oneof.FullName()][string(field.Name())], "%s not covered", field.FullName())
+			}
+		}
+	}
+	for _, enum := range []protoreflect.EnumDescriptor{
+		pb.BinaryExpr_OP_UNKNOWN_UNSPECIFIED.Descriptor(),
+		pb.AggregationFunction_TYPE_UNKNOWN_UNSPECIFIED.Descriptor(),
+	} {
+		for i := 0; i < enum.Values().Len(); i++ {
+			value := enum.Values().Get(i)
+			if value.Number() == 0 {
+				continue
+			}
+			require.True(t, covered[enum.FullName()][string(value.Name())], "%s not covered", value.FullName())
+		}
+	}
+}
+
+// protoCoverage records the oneof fields and enum values set in msg and the
+// messages it contains.
+func protoCoverage(msg protoreflect.Message, covered map[protoreflect.FullName]map[string]bool) {
+	add := func(name protoreflect.FullName, value string) {
+		if covered[name] == nil {
+			covered[name] = map[string]bool{}
+		}
+		covered[name][value] = true
+	}
+	msg.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		if oneof := field.ContainingOneof(); oneof != nil {
+			add(oneof.FullName(), string(field.Name()))
+		}
+		switch {
+		case field.Kind() == protoreflect.EnumKind:
+			if enum := field.Enum().Values().ByNumber(value.Enum()); enum != nil {
+				add(field.Enum().FullName(), string(enum.Name()))
+			}
+		case field.Kind() != protoreflect.MessageKind:
+		case field.IsList():
+			for i := 0; i < value.List().Len(); i++ {
+				protoCoverage(value.List().Get(i).Message(), covered)
+			}
+		case !field.IsMap():
+			protoCoverage(value.Message(), covered)
+		}
+		return true
+	})
+}
+
+func TestPlanProtoTableProvider(t *testing.T) {
+	plan, err := (&Builder{}).
+		Scan(&mockTableProvider{schema: dynparquet.NewSampleSchema()}, "table1").
+		Build()
+	require.NoError(t, err)
+
+	data, err := Marshal(plan)
+	require.NoError(t, err)
+
+	// The table is rebound to the provider of the receiving side.
+	tableProvider := &mockTableProvider{schema: dynparquet.NewSampleSchema()}
+	res, err := Unmarshal(data, tableProvider)
+	require.NoError(t, err)
+	require.Same(t, tableProvider, res.TableScan.TableProvider)
+	require.Equal(t, "table1", res.TableScan.TableName)
+}
+
+func TestPlanProtoUnsupportedExpr(t *testing.T) {
+	_, err := ExprToProto(&LiteralExpr{Value: scalar.NewDate32Scalar(1)})
+	require.Error(t, err)
+}
@@ -0,0 +1,482 @@
+package logicalplan
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/apache/arrow/go/v14/arrow"
+
+	"github.com/polarsignals/frostdb/pqarrow/convert"
+)
+
+// ErrDynamicOutputSchema is returned when the output schema of a plan depends
+// on the concrete columns of a dynamic column, which are only known once the
+// data is read.
+var ErrDynamicOutputSchema = errors.New("output schema depends on concrete dynamic columns")
+
+// ErrUnknownInputSchema is returned when the output schema of a plan is
+// computed for a table whose schema isn't known, such as one that is only
+// read from storage.
+var ErrUnknownInputSchema = errors.New("schema of the scanned table is unknown")
+
+// outputField is a field of the records produced by a plan. Dynamic fields
+// are placeholders for all concrete columns of a dynamic column.
+type outputField struct {
+	arrow.Field
+	dynamic bool
+}
+
+// OutputSchema returns the Arrow schema of the records produced by the plan
+// without executing it. ErrDynamicOutputSchema is returned if the output
+// contains dynamic columns that are not narrowed down to concrete columns.
+func (plan *LogicalPlan) OutputSchema() (*arrow.Schema, error) {
+	return plan.outputSchema(false)
+}
+
+// ConcreteOutputSchema returns the Arrow schema of the records produced by the
+// plan that hold no concrete columns of its dynamic columns, such as those of
+// an empty result. The fields of dynamic columns are left out.
+func (plan *LogicalPlan) ConcreteOutputSchema() (*arrow.Schema, error) {
+	return plan.outputSchema(true)
+}
+
+func (plan *LogicalPlan) outputSchema(skipDynamic bool) (*arrow.Schema, error) {
+	fields, err := plan.outputFields(plan.exprs())
+	if err != nil {
+		return nil, err
+	}
+
+	arrowFields := make([]arrow.Field, 0, len(fields))
+	for _, f := range fields {
+		if f.dynamic {
+			if skipDynamic {
+				continue
+			}
+			return nil, fmt.Errorf("%w: %s", ErrDynamicOutputSchema, f.Name)
+		}
+		arrowFields = append(arrowFields, f.Field)
+	}
+	if sample := plan.sample(); sample != nil {
+		md := SampleMetadata(sample.Fraction)
+		return arrow.NewSchema(arrowFields, &md), nil
+	}
+	return arrow.NewSchema(arrowFields, nil), nil
+}
+
+// SampleFractionMetadataKey is the key of the schema metadata of the results
+// of a sampled query that holds the fraction of the rows that were sampled.
+const SampleFractionMetadataKey = "frostdb.sample_fraction"
+
+// SampleMetadata returns the schema metadata of the results of a query that
+// sampled the given fraction of the rows.
+func SampleMetadata(fraction float64) arrow.Metadata {
+	return arrow.NewMetadata(
+		[]string{SampleFractionMetadataKey},
+		[]string{strconv.FormatFloat(fraction, 'g', -1, 64)},
+	)
+}
+
+// SampleFraction returns the fraction of the rows that were sampled to
+// produce results of the schema. False is returned if they aren't sampled.
+func SampleFraction(schema *arrow.Schema) (float64, bool) {
+	md := schema.Metadata()
+	i := md.FindKey(SampleFractionMetadataKey)
+	if i == -1 {
+		return 0, false
+	}
+	fraction, err := strconv.ParseFloat(md.Values()[i], 64)
+	if err != nil {
+		return 0, false
+	}
+	return fraction, true
+}
+
+func (plan *LogicalPlan) sample() *Sample {
+	for p := plan; p != nil; p = p.Input {
+		if p.Sample != nil {
+			return p.Sample
+		}
+	}
+	return nil
+}
+
+// exprs returns the expressions of the plan's nodes, not including the ones of
+// the inner plans of semi joins. They decide the groups whose struct columns
+// the table scan flattens, as the optimized scan's projection is made of them.
+func (plan *LogicalPlan) exprs() []Expr {
+	nodes := map[*LogicalPlan]struct{}{}
+	for p := plan; p != nil; p = p.Input {
+		nodes[p] = struct{}{}
+	}
+	var exprs []Expr
+	plan.visitExprs(func(node *LogicalPlan, expr Expr) {
+		if _, ok := nodes[node]; ok {
+			exprs = append(exprs, expr)
+		}
+	})
+	return exprs
+}
+
+// outputFields returns the fields of the records produced by the plan. exprs
+// are the expressions of the whole plan the scan flattens groups by.
+func (plan *LogicalPlan) outputFields(exprs []Expr) ([]outputField, error) {
+	switch {
+	case plan.SchemaScan != nil:
+		return []outputField{{Field: arrow.Field{Name: "name", Type: arrow.BinaryTypes.String}}}, nil
+	case plan.TableScan != nil:
+		return scanOutputFields(plan, exprs)
+	}
+
+	if plan.Input == nil {
+		return nil, errors.New("plan has no input")
+	}
+	input, err := plan.Input.outputFields(exprs)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case plan.Filter != nil, plan.Sample != nil, plan.SemiJoin != nil:
+		return input, nil
+	case plan.Distinct != nil:
+		return distinctOutputFields(input, plan.Distinct.Exprs)
+	case plan.Projection != nil:
+		return projectionOutputFields(input, plan.Projection.Exprs)
+	case plan.Aggregation != nil:
+		return aggregationOutputFields(input, plan.Aggregation)
+	case plan.Unnest != nil:
+		return unnestOutputFields(input, plan.Unnest.Expr)
+	case plan.GapFill != nil:
+		return gapFillOutputFields(input, plan.GapFillAggregation())
+	case plan.Pivot != nil:
+		return pivotOutputFields(input, plan.Pivot)
+	case plan.Unpivot != nil:
+		return unpivotOutputFields(input, plan.Unpivot)
+	default:
+		return nil, fmt.Errorf("unsupported plan for output schema: %s", plan)
+	}
+}
+
+func scanOutputFields(plan *LogicalPlan, exprs []Expr) ([]outputField, error) {
+	schema := plan.InputSchema()
+	if schema == nil {
+		return nil, ErrUnknownInputSchema
+	}
+
+	parquetFields := schema.ParquetSchema().Fields()
+	fields := make([]outputField, 0, len(parquetFields))
+	for _, pf := range parquetFields {
+		f, err := convert.ParquetFieldToArrowField(pf)
+		if err != nil {
+			return nil, err
+		}
+		def, _ := schema.ColumnByName(pf.Name())
+		if def.Dynamic {
+			fields = append(fields, outputField{Field: f, dynamic: true})
+			continue
+		}
+		for _, flat := range flattenStructField(exprs, f) {
+			fields = append(fields, outputField{Field: flat})
+		}
+	}
+	return fields, nil
+}
+
+// flattenStructField returns the fields of a struct, named by their dotted
+// path, the way the table scan produces them for groups of nested schemas.
+func flattenStructField(exprs []Expr, f arrow.Field) []arrow.Field {
+	structType, ok := f.Type.(*arrow.StructType)
+	if !ok || !flattensPath(exprs, f.Name) {
+		return []arrow.Field{f}
+	}
+
+	var fields []arrow.Field
+	for _, child := range structType.Fields() {
+		child.Name = f.Name + "." + child.Name
+		child.Nullable = child.Nullable || f.Nullable
+		fields = append(fields, flattenStructField(exprs, child)...)
+	}
+	return fields
+}
+
+// matchFields returns the input fields the expression matches. Concrete
+// columns of a dynamic column are resolved using the dynamic column's type.
+func matchFields(input []outputField, expr Expr) []outputField {
+	var matched []outputField
+	for _, f := range input {
+		if f.dynamic {
+			switch e := expr.(type) {
+			case *DynamicColumn:
+				if e.ColumnName == f.Name {
+					matched = append(matched, f)
+				}
+			case *Column:
+				if strings.HasPrefix(e.ColumnName, f.Name+".") {
+					concrete := f.Field
+					concrete.Name = e.ColumnName
+					concrete.Nullable = true
+					matched = append(matched, outputField{Field: concrete})
+				}
+			}
+			continue
+		}
+		if expr.MatchColumn(f.Name) {
+			matched = append(matched, f)
+		}
+	}
+	return matched
+}
+
+func distinctOutputFields(input []outputField, exprs []Expr) ([]outputField, error) {
+	fields := make([]outputField, 0, len(exprs))
+	for _, f := range input {
+		for _, expr := range exprs {
+			if m := matchFields([]outputField{f}, expr); len(m) > 0 {
+				fields = append(fields, m...)
+				break
+			}
+		}
+	}
+	// Computed expressions are pushed down to the scan, which appends them.
+	for _, expr := range exprs {
+		if _, ok := expr.(*BinaryExpr); ok && len(matchFields(input, expr)) == 0 {
+			fields = append(fields, outputField{Field: arrow.Field{Name: expr.Name(), Type: arrow.FixedWidthTypes.Boolean}})
+		}
+	}
+	return fields, nil
+}
+
+func projectionOutputFields(input []outputField, exprs []Expr) ([]outputField, error) {
+	fields := make([]outputField, 0, len(exprs))
+	for _, expr := range exprs {
+		switch e := expr.(type) {
+		case *AllExpr:
+			fields = append(fields, input...)
+		case *Column, *DynamicColumn:
+			if m := matchFields(input, e); len(m) > 0 {
+				if _, ok := e.(*Column); ok {
+					m = m[:1]
+				}
+				fields = append(fields, m...)
+			}
+		case *BinaryExpr:
+			fields = append(fields, outputField{Field: arrow.Field{Name: e.Name(), Type: arrow.FixedWidthTypes.Boolean}})
+		case *WidthBucketExpr:
+			fields = append(fields, outputField{Field: arrow.Field{Name: e.Name(), Type: arrow.PrimitiveTypes.Int64}})
+		case *AliasExpr:
+			switch inner := e.Expr.(type) {
+			case *BinaryExpr:
+				fields = append(fields, outputField{Field: arrow.Field{Name: e.Alias, Type: arrow.FixedWidthTypes.Boolean}})
+			case *WidthBucketExpr:
+				fields = append(fields, outputField{Field: arrow.Field{Name: e.Alias, Type: arrow.PrimitiveTypes.Int64}})
+			case *Column:
+				if m := matchFields(input, inner); len(m) > 0 {
+					f := m[0]
+					f.Name = e.Alias
+					fields = append(fields, f)
+				}
+			default:
+				return nil, fmt.Errorf("unsupported alias expression for output schema: %T", inner)
+			}
+		case *AverageExpr:
+			// The average is computed from the sum and count aggregations,
+			// which are replaced by the average.
+			columnName, resultName := e.Expr.Name(), "avg("+e.Expr.Name()+")"
+			if alias, ok := e.Expr.(*AliasExpr); ok {
+				columnName, resultName = alias.Expr.Name(), alias.Alias
+			}
+			sumName := ResultNameWithConcreteColumn(AggFuncSum, columnName)
+			countName := ResultNameWithConcreteColumn(AggFuncCount, columnName)
+			for _, f := range input {
+				if f.Name != sumName && f.Name != countName {
+					fields = append(fields, f)
+				}
+			}
+			fields = append(fields, outputField{Field: arrow.Field{Name: resultName, Type: arrow.PrimitiveTypes.Int64}})
+		default:
+			return nil, fmt.Errorf("unsupported expression type for projection: %T", expr)
+		}
+	}
+	return fields, nil
+}
+
+func aggregationOutputFields(input []outputField, agg *Aggregation) ([]outputField, error) {
+	fields := make([]outputField, 0, len(agg.GroupExprs)+len(agg.AggExprs))
+	var computed []Expr
+	for _, expr := range agg.GroupExprs {
+		if expr.Computed() {
+			computed = append(computed, expr)
+		}
+	}
+	for _, f := range input {
+		for _, expr := range agg.GroupExprs {
+			if expr.Computed() {
+				continue
+			}
+			if m := matchFields([]outputField{f}, expr); len(m) > 0 {
+				fields = append(fields, m...)
+				break
+			}
+		}
+	}
+	// Computed group by values follow the group by columns.
+	computedFields, err := projectionOutputFields(input, computed)
+	if err != nil {
+		return nil, err
+	}
+	fields = append(fields, computedFields...)
+
+	// Averages are computed by a projection after the aggregation, which
+	// appends them after all other aggregations.
+	var averages []outputField
+	for _, expr := range agg.AggExprs {
+		var aggFunc *AggregationFunction
+		switch e := expr.(type) {
+		case *AggregationFunction:
+			aggFunc = e
+		case *AliasExpr:
+			aggFunc, _ = e.Expr.(*AggregationFunction)
+		}
+		if aggFunc == nil {
+			return nil, fmt.Errorf("aggregation function not found in %s", expr.Name())
+		}
+
+		columns := matchFields(input, aggFunc.Expr)
+		if len(columns) == 0 {
+			return nil, fmt.Errorf("aggregation column %s not found", aggFunc.Expr.Name())
+		}
+		if columns[0].dynamic {
+			// Aggregations of dynamic columns are computed per concrete
+			// column.
+			fields = append(fields, columns[0])
+			continue
+		}
+
+		name := expr.Name()
+		if _, ok := expr.(*AliasExpr); !ok && aggFunc.Paired == nil {
+			name = ResultNameWithConcreteColumn(aggFunc.Func, columns[0].Name)
+		}
+		f := outputField{Field: arrow.Field{Name: name, Type: aggregationDataType(aggFunc.Func, columns[0].Type)}}
+		if aggFunc.Func == AggFuncAvg {
+			averages = append(averages, f)
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return append(fields, averages...), nil
+}
+
+func unnestOutputFields(input []outputField, expr Expr) ([]outputField, error) {
+	fields := make([]outputField, 0, len(input))
+	found := false
+	for _, f := range input {
+		if !f.dynamic && expr.MatchColumn(f.Name) {
+			list, ok := f.Type.(*arrow.ListType)
+			if !ok {
+				return nil, fmt.Errorf("unnest column %s is not a list: %s", f.Name, f.Type)
+			}
+			f.Field = arrow.Field{Name: f.Name, Type: list.Elem(), Nullable: list.ElemField().Nullable}
+			found = true
+		}
+		fields = append(fields, f)
+	}
+	if !found {
+		return nil, fmt.Errorf("unnest column %s not found", expr.Name())
+	}
+	return fields, nil
+}
+
+// gapFillOutputFields returns the fields of the gap filled aggregation, whose
+// aggregated values are null for the buckets that may be filled with nulls.
+func gapFillOutputFields(input []outputField, agg *Aggregation) ([]outputField, error) {
+	if agg == nil {
+		return nil, errors.New("gap fill input must be an aggregation")
+	}
+	fields := make([]outputField, 0, len(input))
+	for _, f := range input {
+		grouped := f.dynamic
+		for _, expr := range agg.GroupExprs {
+			if expr.MatchColumn(f.Name) {
+				grouped = true
+			}
+		}
+		if !grouped {
+			f.Nullable = true
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// pivotOutputFields returns the fields of the pivot, whose pivoted columns
+// are only known once the data is read. They are represented by a dynamic
+// field named after the pivot's prefix.
+func pivotOutputFields(input []outputField, pivot *Pivot) ([]outputField, error) {
+	values := matchFields(input, pivot.Value)
+	if len(values) == 0 {
+		return nil, fmt.Errorf("pivot value column %s not found", pivot.Value.Name())
+	}
+	fields := make([]outputField, 0, len(input))
+	for _, f := range input {
+		if len(matchFields([]outputField{f}, pivot.Key)) > 0 || len(matchFields([]outputField{f}, pivot.Value)) > 0 {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	pivoted := values[0].Field
+	pivoted.Name = pivot.Prefix
+	pivoted.Nullable = true
+	return append(fields, outputField{Field: pivoted, dynamic: true}), nil
+}
+
+// unpivotOutputFields returns the fields of the unpivot, which replaces the
+// columns it matches by the key and value columns. The value column has the
+// type of the matched columns.
+func unpivotOutputFields(input []outputField, unpivot *Unpivot) ([]outputField, error) {
+	fields := make([]outputField, 0, len(input)+2)
+	var value *arrow.Field
+	for _, f := range input {
+		matched := len(matchFields([]outputField{f}, unpivot.Expr)) > 0
+		if _, ok := unpivot.Expr.(*RegexpColumnMatch); ok && f.dynamic {
+			matched = unpivot.Expr.MatchColumn(f.Name + ".")
+		}
+		if !matched {
+			fields = append(fields, f)
+			continue
+		}
+		if value == nil {
+			value = &arrow.Field{Name: unpivot.Value, Type: f.Type, Nullable: f.Nullable}
+		} else if !arrow.TypeEqual(value.Type, f.Type) {
+			return nil, fmt.Errorf("unpivot columns %s have different types: %s and %s", unpivot.Expr.Name(), value.Type, f.Type)
+		}
+	}
+	if value == nil {
+		return nil, fmt.Errorf("unpivot columns %s not found", unpivot.Expr.Name())
+	}
+	return append(fields,
+		outputField{Field: arrow.Field{Name: unpivot.Key, Type: arrow.BinaryTypes.String}},
+		outputField{Field: *value},
+	), nil
+}
+
+func aggregationDataType(f AggFunc, input arrow.DataType) arrow.DataType {
+	switch {
+	case f == AggFuncCount:
+		return arrow.PrimitiveTypes.Int64
+	case f == AggFuncAvg:
+		// Averages are computed from the sum, so averages of floats are
+		// floats while all others are integers.
+		if arrow.TypeEqual(input, arrow.PrimitiveTypes.Float64) {
+			return arrow.PrimitiveTypes.Float64
+		}
+		return arrow.PrimitiveTypes.Int64
+	case f.Statistical(), f.Counter():
+		return arrow.PrimitiveTypes.Float64
+	case f == AggFuncHistogram:
+		return HistogramType
+	default:
+		return input
+	}
+}
@@ -2,11 +2,15 @@ package logicalplan
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"strings"
 
 	"github.com/apache/arrow/go/v14/arrow/scalar"
+	"github.com/parquet-go/parquet-go"
 	"github.com/parquet-go/parquet-go/format"
+
+	"github.com/polarsignals/frostdb/dynparquet"
 )
 
 // PlanValidationError is the error representing a logical plan that is not valid.
@@ -78,6 +82,18 @@ func Validate(plan *LogicalPlan) error {
 			err = nil
 		case plan.Aggregation != nil:
 			err = ValidateAggregation(plan)
+		case plan.Unnest != nil:
+			err = ValidateUnnest(plan)
+		case plan.Sample != nil:
+			err = ValidateSample(plan)
+		case plan.GapFill != nil:
+			err = ValidateGapFill(plan)
+		case plan.Pivot != nil:
+			err = ValidatePivot(plan)
+		case plan.Unpivot != nil:
+			err = ValidateUnpivot(plan)
+		case plan.SemiJoin != nil:
+			err = ValidateSemiJoin(plan)
 		}
 	}
 
@@ -118,10 +134,28 @@ func ValidateSingleFieldSet(plan *LogicalPlan) *PlanValidationError {
 	if plan.Aggregation != nil {
 		fieldsSet = append(fieldsSet, 5)
 	}
+	if plan.Unnest != nil {
+		fieldsSet = append(fieldsSet, 6)
+	}
+	if plan.Sample != nil {
+		fieldsSet = append(fieldsSet, 7)
+	}
+	if plan.GapFill != nil {
+		fieldsSet = append(fieldsSet, 8)
+	}
+	if plan.Pivot != nil {
+		fieldsSet = append(fieldsSet, 9)
+	}
+	if plan.Unpivot != nil {
+		fieldsSet = append(fieldsSet, 10)
+	}
+	if plan.SemiJoin != nil {
+		fieldsSet = append(fieldsSet, 11)
+	}
 
 	if len(fieldsSet) != 1 {
 		fieldsFound := make([]string, 0)
-		fields := []string{"SchemaScan", "TableScan", "Filter", "Distinct", "Projection", "Aggregation"}
+		fields := []string{"SchemaScan", "TableScan", "Filter", "Distinct", "Projection", "Aggregation", "Unnest", "Sample", "GapFill", "Pivot", "Unpivot", "SemiJoin"}
 		for _, i := range fieldsSet {
 			fieldsFound = append(fieldsFound, fields[i])
 		}
@@ -153,6 +187,29 @@ func ValidateAggregation(plan *LogicalPlan) *PlanValidationError {
 		}
 	}
 
+	// check that the values grouped by don't depend on the aggregation
+	for _, expr := range plan.Aggregation.GroupExprs {
+		aggFuncFinder := newTypeFinder((*AggregationFunction)(nil))
+		expr.Accept(&aggFuncFinder)
+		if aggFuncFinder.result != nil {
+			return &PlanValidationError{
+				plan:    plan,
+				message: fmt.Sprintf("invalid aggregation: cannot group by aggregation %s", expr.Name()),
+			}
+		}
+
+		widthBucketFinder := newTypeFinder((*WidthBucketExpr)(nil))
+		expr.Accept(&widthBucketFinder)
+		if widthBucket, ok := widthBucketFinder.result.(*WidthBucketExpr); ok {
+			if !(widthBucket.Min < widthBucket.Max) || widthBucket.Buckets < 1 {
+				return &PlanValidationError{
+					plan:    plan,
+					message: fmt.Sprintf("invalid aggregation: %s must have at least one bucket between min and max", widthBucket.Name()),
+				}
+			}
+		}
+	}
+
 	// check that the expression is valid
 	aggExprError := ValidateAggregationExpr(plan)
 	if aggExprError != nil {
@@ -166,6 +223,188 @@ func ValidateAggregation(plan *LogicalPlan) *PlanValidationError {
 	return nil
 }
 
+// ValidateUnnest validates the logical plan's unnest step.
+func ValidateUnnest(plan *LogicalPlan) *PlanValidationError {
+	if _, ok := plan.Unnest.Expr.(*Column); !ok {
+		return &PlanValidationError{
+			plan:    plan,
+			message: "invalid unnest: expression must be a column",
+		}
+	}
+	return nil
+}
+
+// ValidateSample validates the logical plan's sample step.
+func ValidateSample(plan *LogicalPlan) *PlanValidationError {
+	if plan.Input == nil || plan.Input.TableScan == nil {
+		return &PlanValidationError{
+			plan:    plan,
+			message: "invalid sample: input must be a table scan",
+		}
+	}
+	if f := plan.Sample.Fraction; !(f > 0 && f <= 1) {
+		return &PlanValidationError{
+			plan:    plan,
+			message: fmt.Sprintf("invalid sample: fraction must be in (0, 1], got %g", f),
+		}
+	}
+	switch plan.Sample.Method {
+	case SampleBernoulli, SampleSystem:
+	default:
+		return &PlanValidationError{
+			plan:    plan,
+			message: fmt.Sprintf("invalid sample: unknown method %d", plan.Sample.Method),
+		}
+	}
+	return nil
+}
+
+// ValidateGapFill validates the logical plan's gap fill step.
+func ValidateGapFill(plan *LogicalPlan) *PlanValidationError {
+	agg := plan.GapFillAggregation()
+	var duration *DurationExpr
+	if agg != nil {
+		for _, expr := range agg.GroupExprs {
+			if d, ok := expr.(*DurationExpr); ok {
+				duration = d
+			}
+		}
+	}
+	if duration == nil {
+		return &PlanValidationError{
+			plan:    plan,
+			message: "invalid gap fill: input must be an aggregation grouped by a duration",
+		}
+	}
+
+	gapFill := plan.GapFill
+	if gapFill.Step.Milliseconds() <= 0 {
+		return &PlanValidationError{
+			plan:    plan,
+			message: "invalid gap fill: step must be at least a millisecond",
+		}
+	}
+	if gapFill.Step != duration.Value() {
+		return &PlanValidationError{
+			plan:    plan,
+			message: fmt.Sprintf("invalid gap fill: step %s must be the duration %s the aggregation is grouped by", gapFill.Step, duration.Value()),
+		}
+	}
+	if gapFill.Start >= gapFill.End {
+		return &PlanValidationError{
+			plan:    plan,
+			message: "invalid gap fill: start must be before end",
+		}
+	}
+	// The difference is computed unsigned, since it may overflow an int64.
+	if buckets := (uint64(gapFill.End) - uint64(gapFill.Start)) / uint64(gapFill.Step.Milliseconds()); buckets > MaxGapFillBuckets {
+		return &PlanValidationError{
+			plan:    plan,
+			message: fmt.Sprintf("invalid gap fill: %d buckets exceed the maximum of %d", buckets, MaxGapFillBuckets),
+		}
+	}
+	switch gapFill.Fill {
+	case FillNull, FillPrevious, FillLinear:
+	default:
+		return &PlanValidationError{
+			plan:    plan,
+			message: fmt.Sprintf("invalid gap fill: unknown fill mode %d", gapFill.Fill),
+		}
+	}
+	return nil
+}
+
+// validationSchema returns the schema the columns the plan uses are validated
+// against. Nil is returned if the columns of its input aren't the table's
+// columns, since they were pivoted or unpivoted.
+func (plan *LogicalPlan) validationSchema() *dynparquet.Schema {
+	for p := plan.Input; p != nil; p = p.Input {
+		if p.Pivot != nil || p.Unpivot != nil {
+			return nil
+		}
+	}
+	return plan.InputSchema()
+}
+
+// ValidatePivot validates the logical plan's pivot step.
+func ValidatePivot(plan *LogicalPlan) *PlanValidationError {
+	if plan.Pivot.Key == nil || plan.Pivot.Value == nil {
+		return &PlanValidationError{
+			plan:    plan,
+			message: "invalid pivot: key and value must be set",
+		}
+	}
+	if plan.Pivot.Key.Name() == plan.Pivot.Value.Name() {
+		return &PlanValidationError{
+			plan:    plan,
+			message: "invalid pivot: key and value must be different columns",
+		}
+	}
+	return nil
+}
+
+// ValidateUnpivot validates the logical plan's unpivot step.
+func ValidateUnpivot(plan *LogicalPlan) *PlanValidationError {
+	switch plan.Unpivot.Expr.(type) {
+	case *DynamicColumn, *RegexpColumnMatch:
+	default:
+		return &PlanValidationError{
+			plan:    plan,
+			message: "invalid unpivot: expression must be a dynamic column or a regexp column match",
+		}
+	}
+	if plan.Unpivot.Key == "" || plan.Unpivot.Value == "" || plan.Unpivot.Key == plan.Unpivot.Value {
+		return &PlanValidationError{
+			plan:    plan,
+			message: "invalid unpivot: key and value must be different column names",
+		}
+	}
+	return nil
+}
+
+// ValidateSemiJoin validates the logical plan's semi join step and its inner
+// plan.
+func ValidateSemiJoin(plan *LogicalPlan) *PlanValidationError {
+	join := plan.SemiJoin
+	if join.Key == nil || join.InnerKey == nil || join.Inner == nil {
+		return &PlanValidationError{
+			plan:    plan,
+			message: "invalid semi join: key, inner key and inner plan must be set",
+		}
+	}
+	if _, ok := join.Key.(*Column); !ok {
+		return &PlanValidationError{
+			plan:    plan,
+			message: "invalid semi join: key must be a column",
+		}
+	}
+	if err := Validate(join.Inner); err != nil {
+		return &PlanValidationError{
+			plan:    plan,
+			message: "invalid semi join: invalid inner plan",
+			input:   err.(*PlanValidationError),
+		}
+	}
+	// Inner plans whose output schema isn't known up front are checked when
+	// they are executed.
+	if schema, err := join.Inner.OutputSchema(); err == nil {
+		found := false
+		for _, f := range schema.Fields() {
+			if join.InnerKey.MatchColumn(f.Name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &PlanValidationError{
+				plan:    plan,
+				message: fmt.Sprintf("invalid semi join: inner key %s is not a column of the inner plan's results", join.InnerKey.Name()),
+			}
+		}
+	}
+	return nil
+}
+
 type Named interface {
 	Name() string
 }
@@ -191,8 +430,94 @@ func ValidateAggregationExpr(plan *LogicalPlan) *ExprValidationError {
 			}
 		}
 
+		// check that first, last and counters are ordered by a column
+		aggFuncExpr := aggFuncFinder.result.(*AggregationFunction)
+		switch aggFuncExpr.Func {
+		case AggFuncFirst, AggFuncLast, AggFuncRate, AggFuncIncrease:
+			if _, ok := aggFuncExpr.OrderBy.(*Column); !ok {
+				return &ExprValidationError{
+					message: fmt.Sprintf("%s must be ordered by a column", aggFuncExpr.Func),
+					expr:    expr,
+				}
+			}
+			if _, ok := aggFuncExpr.Expr.(*Column); !ok {
+				return &ExprValidationError{
+					message: fmt.Sprintf("%s must aggregate a column", aggFuncExpr.Func),
+					expr:    expr,
+				}
+			}
+		default:
+			if aggFuncExpr.OrderBy != nil {
+				return &ExprValidationError{
+					message: fmt.Sprintf("%s cannot be ordered", aggFuncExpr.Func),
+					expr:    expr,
+				}
+			}
+		}
+
+		// check that counters are grouped by durations of their timestamps
+		if aggFuncExpr.Func.Counter() {
+			grouped := false
+			for _, group := range plan.Aggregation.GroupExprs {
+				if d, ok := group.(*DurationExpr); ok && d.MatchColumn(aggFuncExpr.OrderBy.Name()) {
+					grouped = true
+				}
+			}
+			if !grouped {
+				return &ExprValidationError{
+					message: fmt.Sprintf("%s must be grouped by a duration of %s", aggFuncExpr.Func, aggFuncExpr.OrderBy.Name()),
+					expr:    expr,
+				}
+			}
+		}
+
+		// check that histograms have increasing buckets
+		if aggFuncExpr.Func == AggFuncHistogram {
+			if len(aggFuncExpr.Buckets) == 0 {
+				return &ExprValidationError{
+					message: "histogram must have buckets",
+					expr:    expr,
+				}
+			}
+			for i, bound := range aggFuncExpr.Buckets {
+				if math.IsNaN(bound) || (i > 0 && bound <= aggFuncExpr.Buckets[i-1]) {
+					return &ExprValidationError{
+						message: "histogram buckets must be increasing",
+						expr:    expr,
+					}
+				}
+			}
+		} else if aggFuncExpr.Buckets != nil {
+			return &ExprValidationError{
+				message: fmt.Sprintf("%s cannot have buckets", aggFuncExpr.Func),
+				expr:    expr,
+			}
+		}
+
+		// check that statistics and histograms are computed from columns
+		if aggFuncExpr.Func.Statistical() || aggFuncExpr.Func == AggFuncHistogram {
+			if _, ok := aggFuncExpr.Expr.(*Column); !ok {
+				return &ExprValidationError{
+					message: fmt.Sprintf("%s must aggregate a column", aggFuncExpr.Func),
+					expr:    expr,
+				}
+			}
+		}
+		if _, ok := aggFuncExpr.Paired.(*Column); aggFuncExpr.Func.Paired() && !ok {
+			return &ExprValidationError{
+				message: fmt.Sprintf("%s must aggregate a pair of columns", aggFuncExpr.Func),
+				expr:    expr,
+			}
+		}
+		if !aggFuncExpr.Func.Paired() && aggFuncExpr.Paired != nil {
+			return &ExprValidationError{
+				message: fmt.Sprintf("%s cannot aggregate pairs", aggFuncExpr.Func),
+				expr:    expr,
+			}
+		}
+
 		// check that column being aggregated on exists in the schema
-		schema := plan.InputSchema()
+		schema := plan.validationSchema()
 		if schema == nil {
 			return nil // cannot check column type if there's no input schema
 		}
@@ -221,10 +546,52 @@ func ValidateAggregationExpr(plan *LogicalPlan) *ExprValidationError {
 			aliases[alias.Alias] = struct{}{}
 		}
 
+		// check that the values are ordered by an int64 column
+		if aggFuncExpr.OrderBy != nil {
+			orderColumn, found := schema.ColumnByName(aggFuncExpr.OrderBy.Name())
+			if !found {
+				return &ExprValidationError{
+					message: fmt.Sprintf("column not found: %s", aggFuncExpr.OrderBy.Name()),
+					expr:    expr,
+				}
+			}
+			if orderColumn.StorageLayout.Type().Kind() != parquet.Int64 {
+				return &ExprValidationError{
+					message: fmt.Sprintf("cannot order %s by non-int64 column %s", aggFuncExpr.Func, orderColumn.Name),
+					expr:    expr,
+				}
+			}
+		}
+
+		// check that statistics, histograms and counters are computed from
+		// numbers
+		if aggFuncExpr.Func.Statistical() || aggFuncExpr.Func == AggFuncHistogram || aggFuncExpr.Func.Counter() {
+			columns := []Expr{aggFuncExpr.Expr}
+			if aggFuncExpr.Paired != nil {
+				columns = append(columns, aggFuncExpr.Paired)
+			}
+			for _, col := range columns {
+				def, found := schema.ColumnByName(col.Name())
+				if !found {
+					return &ExprValidationError{
+						message: fmt.Sprintf("column not found: %s", col.Name()),
+						expr:    expr,
+					}
+				}
+				switch def.StorageLayout.Type().Kind() {
+				case parquet.Int64, parquet.Double:
+				default:
+					return &ExprValidationError{
+						message: fmt.Sprintf("cannot compute %s of non-numeric column %s", aggFuncExpr.Func, def.Name),
+						expr:    expr,
+					}
+				}
+			}
+		}
+
 		// check that the column type can be aggregated by the function type
 		columnType := column.StorageLayout.Type()
-		aggFuncExpr := aggFuncFinder.result.(*AggregationFunction)
-		if columnType.LogicalType().UTF8 != nil {
+		if lt := columnType.LogicalType(); lt != nil && lt.UTF8 != nil {
 			switch aggFuncExpr.Func {
 			case AggFuncSum:
 				return &ExprValidationError{
@@ -299,27 +666,42 @@ func ValidateFilterBinaryExpr(plan *LogicalPlan, expr *BinaryExpr) *ExprValidati
 		}
 	}
 
+	return validateComparedLiteral(plan.validationSchema(), expr)
+}
+
+// validateComparedLiteral validates that the literal on the right side of the
+// binary expression can be compared with the column of the schema on its left
+// side. Expressions without such a column or literal are valid.
+func validateComparedLiteral(schema *dynparquet.Schema, expr *BinaryExpr) *ExprValidationError {
+	if schema == nil {
+		return nil
+	}
+
 	// try to find the column in the schema
-	columnExpr := leftColumnFinder.result.(*Column)
-	schema := plan.InputSchema()
-	if schema != nil {
-		column, found := schema.ColumnByName(columnExpr.ColumnName)
-		if found {
-			// try to find the literal on the other side of the expression
-			rightLiteralFinder := newTypeFinder((*LiteralExpr)(nil))
-			expr.Right.Accept(&rightLiteralFinder)
-			if rightLiteralFinder.result != nil {
-				// ensure that the column type is compatible with the literal being compared to it
-				t := column.StorageLayout.Type()
-				literalExpr := rightLiteralFinder.result.(*LiteralExpr)
-				if err := ValidateComparingTypes(t.LogicalType(), literalExpr.Value); err != nil {
-					err.expr = expr
-					return err
-				}
-			}
-		}
+	leftColumnFinder := newTypeFinder((*Column)(nil))
+	expr.Left.Accept(&leftColumnFinder)
+	if leftColumnFinder.result == nil {
+		return nil
+	}
+	column, found := schema.ColumnByName(leftColumnFinder.result.(*Column).ColumnName)
+	if !found {
+		return nil
+	}
+
+	// try to find the literal on the other side of the expression
+	rightLiteralFinder := newTypeFinder((*LiteralExpr)(nil))
+	expr.Right.Accept(&rightLiteralFinder)
+	if rightLiteralFinder.result == nil {
+		return nil
 	}
 
+	// ensure that the column type is compatible with the literal being compared to it
+	t := column.StorageLayout.Type()
+	literalExpr := rightLiteralFinder.result.(*LiteralExpr)
+	if err := ValidateComparingTypes(t.LogicalType(), literalExpr.Value); err != nil {
+		err.expr = expr
+		return err
+	}
 	return nil
 }
 
@@ -1,8 +1,10 @@
 package logicalplan
 
 import (
+	"math"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -155,6 +157,206 @@ func TestAggregationCannotUseAliasTwice(t *testing.T) {
 	require.True(t, strings.HasPrefix(exprErr.message, "alias used twice: value"))
 }
 
+func TestAggregationFirstAndLastMustBeOrderedByInt64Column(t *testing.T) {
+	for _, testCase := range []struct {
+		expr   Expr
+		errMsg string
+	}{
+		{
+			expr:   First(Col("value"), nil),
+			errMsg: "first must be ordered by a column",
+		},
+		{
+			expr:   Last(Col("value"), Col("example_type")),
+			errMsg: "cannot order last by non-int64 column example_type",
+		},
+		{
+			expr:   Last(Col("value"), Col("unknown")),
+			errMsg: "column not found: unknown",
+		},
+	} {
+		_, err := (&Builder{}).
+			Scan(&mockTableProvider{dynparquet.NewSampleSchema()}, "table1").
+			Aggregate([]Expr{testCase.expr}, []Expr{Col("stacktrace")}).
+			Build()
+
+		require.NotNil(t, err)
+		planErr, ok := err.(*PlanValidationError)
+		require.True(t, ok)
+		require.True(t, strings.HasPrefix(planErr.message, "invalid aggregation"))
+		require.Len(t, planErr.children, 1)
+		require.Equal(t, testCase.errMsg, planErr.children[0].message)
+	}
+
+	_, err := (&Builder{}).
+		Scan(&mockTableProvider{dynparquet.NewSampleSchema()}, "table1").
+		Aggregate([]Expr{Last(Col("value"), Col("timestamp"))}, []Expr{Col("stacktrace")}).
+		Build()
+	require.NoError(t, err)
+}
+
+func TestAggregationCountersMustBeGroupedByDuration(t *testing.T) {
+	for _, testCase := range []struct {
+		expr   Expr
+		groups []Expr
+		errMsg string
+	}{
+		{
+			expr:   Rate(Col("value"), Col("timestamp")),
+			groups: []Expr{Col("stacktrace")},
+			errMsg: "rate must be grouped by a duration of timestamp",
+		},
+		{
+			expr:   Increase(Col("value"), nil),
+			groups: []Expr{Duration(time.Minute)},
+			errMsg: "increase must be ordered by a column",
+		},
+		{
+			expr:   Increase(Col("example_type"), Col("timestamp")),
+			groups: []Expr{Duration(time.Minute)},
+			errMsg: "cannot compute increase of non-numeric column example_type",
+		},
+	} {
+		_, err := (&Builder{}).
+			Scan(&mockTableProvider{dynparquet.NewSampleSchema()}, "table1").
+			Aggregate([]Expr{testCase.expr}, testCase.groups).
+			Build()
+
+		require.NotNil(t, err)
+		planErr, ok := err.(*PlanValidationError)
+		require.True(t, ok)
+		require.True(t, strings.HasPrefix(planErr.message, "invalid aggregation"))
+		require.Len(t, planErr.children, 1)
+		require.Equal(t, testCase.errMsg, planErr.children[0].message)
+	}
+
+	_, err := (&Builder{}).
+		Scan(&mockTableProvider{dynparquet.NewSampleSchema()}, "table1").
+		Aggregate([]Expr{Rate(Col("value"), Col("timestamp"))}, []Expr{Col("stacktrace"), Duration(time.Minute)}).
+		Build()
+	require.NoError(t, err)
+}
+
+func TestAggregationStatisticsMustAggregateNumericColumns(t *testing.T) {
+	for _, testCase := range []struct {
+		expr   Expr
+		errMsg string
+	}{
+		{
+			expr:   StddevPop(Col("example_type")),
+			errMsg: "cannot compute stddev_pop of non-numeric column example_type",
+		},
+		{
+			expr:   Corr(Col("value"), Col("stacktrace")),
+			errMsg: "cannot compute corr of non-numeric column stacktrace",
+		},
+		{
+			expr:   CovarSamp(Col("value"), nil),
+			errMsg: "covar_samp must aggregate a pair of columns",
+		},
+	} {
+		_, err := (&Builder{}).
+			Scan(&mockTableProvider{dynparquet.NewSampleSchema()}, "table1").
+			Aggregate([]Expr{testCase.expr}, []Expr{Col("example_type")}).
+			Build()
+
+		require.NotNil(t, err)
+		planErr, ok := err.(*PlanValidationError)
+		require.True(t, ok)
+		require.True(t, strings.HasPrefix(planErr.message, "invalid aggregation"))
+		require.Len(t, planErr.children, 1)
+		require.Equal(t, testCase.errMsg, planErr.children[0].message)
+	}
+
+	_, err := (&Builder{}).
+		Scan(&mockTableProvider{dynparquet.NewSampleSchema()}, "table1").
+		Aggregate([]Expr{Corr(Col("value"), Col("timestamp"))}, []Expr{Col("example_type")}).
+		Build()
+	require.NoError(t, err)
+}
+
+func TestAggregationHistogramMustHaveIncreasingBuckets(t *testing.T) {
+	for _, testCase := range []struct {
+		expr   Expr
+		errMsg string
+	}{
+		{
+			expr:   Histogram(Col("value"), nil),
+			errMsg: "histogram must have buckets",
+		},
+		{
+			expr:   Histogram(Col("value"), HistogramBuckets{1, 10, 5}),
+			errMsg: "histogram buckets must be increasing",
+		},
+		{
+			expr:   Histogram(Col("example_type"), LinearBuckets(0, 10, 3)),
+			errMsg: "cannot compute histogram of non-numeric column example_type",
+		},
+		{
+			expr:   &AggregationFunction{Func: AggFuncSum, Expr: Col("value"), Buckets: HistogramBuckets{1}},
+			errMsg: "sum cannot have buckets",
+		},
+	} {
+		_, err := (&Builder{}).
+			Scan(&mockTableProvider{dynparquet.NewSampleSchema()}, "table1").
+			Aggregate([]Expr{testCase.expr}, []Expr{Col("example_type")}).
+			Build()
+
+		require.NotNil(t, err)
+		planErr, ok := err.(*PlanValidationError)
+		require.True(t, ok)
+		require.True(t, strings.HasPrefix(planErr.message, "invalid aggregation"))
+		require.Len(t, planErr.children, 1)
+		require.Equal(t, testCase.errMsg, planErr.children[0].message)
+	}
+
+	_, err := (&Builder{}).
+		Scan(&mockTableProvider{dynparquet.NewSampleSchema()}, "table1").
+		Aggregate(
+			[]Expr{Histogram(Col("value"), ExponentialBuckets(1, 2, 8))},
+			[]Expr{Col("example_type"), WidthBucket(Col("timestamp"), 0, 100, 10)},
+		).
+		Build()
+	require.NoError(t, err)
+
+	_, err = (&Builder{}).
+		Scan(&mockTableProvider{dynparquet.NewSampleSchema()}, "table1").
+		Aggregate(
+			[]Expr{Count(Col("value"))},
+			[]Expr{WidthBucket(Col("timestamp"), 100, 0, 10)},
+		).
+		Build()
+	require.NotNil(t, err)
+	planErr, ok := err.(*PlanValidationError)
+	require.True(t, ok)
+	require.Equal(t, "invalid aggregation: width_bucket(timestamp, 100, 0, 10) must have at least one bucket between min and max", planErr.message)
+}
+
+func TestAggregationCannotGroupByAggregation(t *testing.T) {
+	_, err := (&Builder{}).
+		Scan(&mockTableProvider{dynparquet.NewSampleSchema()}, "table1").
+		Aggregate(
+			[]Expr{Sum(Col("value"))},
+			[]Expr{Max(Col("value")).Alias("value_max")},
+		).
+		Build()
+
+	require.NotNil(t, err)
+	planErr, ok := err.(*PlanValidationError)
+	require.True(t, ok)
+	require.Equal(t, "invalid aggregation: cannot group by aggregation value_max", planErr.message)
+
+	// Computed values can be grouped by.
+	_, err = (&Builder{}).
+		Scan(&mockTableProvider{dynparquet.NewSampleSchema()}, "table1").
+		Aggregate(
+			[]Expr{Sum(Col("value"))},
+			[]Expr{Col("value").Gt(Literal(int64(100)))},
+		).
+		Build()
+	require.NoError(t, err)
+}
+
 func TestFilterBinaryExprLeftSideMustBeColumn(t *testing.T) {
 	_, err := (&Builder{}).
 		Scan(&mockTableProvider{dynparquet.NewSampleSchema()}, "table1").
@@ -245,3 +447,134 @@ func TestFilterAndExprEvaluatesEachAndedRule(t *testing.T) {
 	rightErr := exprErr.children[1]
 	require.True(t, strings.HasPrefix(rightErr.message, "left side of binary expression must be a column"))
 }
+
+func TestGapFillMustFollowAggregationGroupedByDuration(t *testing.T) {
+	scan := func() Builder {
+		return (&Builder{}).Scan(&mockTableProvider{dynparquet.NewSampleSchema()}, "table1")
+	}
+	aggregate := func(groups ...Expr) Builder {
+		return scan().Aggregate([]Expr{Sum(Col("value"))}, groups)
+	}
+	gapFill := GapFill{Start: 0, End: 600000, Step: time.Minute}
+
+	for _, testCase := range []struct {
+		builder Builder
+		errMsg  string
+	}{
+		{
+			builder: scan().GapFill(gapFill),
+			errMsg:  "invalid gap fill: input must be an aggregation grouped by a duration",
+		},
+		{
+			builder: aggregate(Col("stacktrace")).GapFill(gapFill),
+			errMsg:  "invalid gap fill: input must be an aggregation grouped by a duration",
+		},
+		{
+			builder: aggregate(Duration(time.Second)).GapFill(gapFill),
+			errMsg:  "invalid gap fill: step 1m0s must be the duration 1s the aggregation is grouped by",
+		},
+		{
+			builder: aggregate(Duration(time.Minute)).GapFill(GapFill{Start: 600000, End: 0, Step: time.Minute}),
+			errMsg:  "invalid gap fill: start must be before end",
+		},
+		{
+			builder: aggregate(Duration(time.Millisecond)).GapFill(GapFill{Start: math.MinInt64, End: math.MaxInt64, Step: time.Millisecond}),
+			errMsg:  "invalid gap fill: 18446744073709551615 buckets exceed the maximum of 100000",
+		},
+	} {
+		_, err := testCase.builder.Build()
+		require.NotNil(t, err)
+		planErr, ok := err.(*PlanValidationError)
+		require.True(t, ok)
+		require.Equal(t, testCase.errMsg, planErr.message)
+	}
+
+	_, err := aggregate(Col("stacktrace"), Duration(time.Minute)).
+		Project(Col("stacktrace"), Col("timestamp"), Col("sum(value)")).
+		GapFill(gapFill).
+		Build()
+	require.NoError(t, err)
+}
+
+func TestPivotUnpivotColumns(t *testing.T) {
+	scan := func() Builder {
+		return (&Builder{}).Scan(&mockTableProvider{dynparquet.NewSampleSchema()}, "table1")
+	}
+
+	for _, testCase := range []struct {
+		builder Builder
+		errMsg  string
+	}{
+		{
+			builder: scan().Pivot(Pivot{Key: Col("stacktrace")}),
+			errMsg:  "invalid pivot: key and value must be set",
+		},
+		{
+			builder: scan().Pivot(Pivot{Key: Col("stacktrace"), Value: Col("stacktrace")}),
+			errMsg:  "invalid pivot: key and value must be different columns",
+		},
+		{
+			builder: scan().Unpivot(Unpivot{Expr: Col("stacktrace"), Key: "key", Value: "value"}),
+			errMsg:  "invalid unpivot: expression must be a dynamic column or a regexp column match",
+		},
+		{
+			builder: scan().Unpivot(Unpivot{Expr: DynCol("labels"), Key: "label", Value: "label"}),
+			errMsg:  "invalid unpivot: key and value must be different column names",
+		},
+	} {
+		_, err := testCase.builder.Build()
+		require.NotNil(t, err)
+		planErr, ok := err.(*PlanValidationError)
+		require.True(t, ok)
+		require.Equal(t, testCase.errMsg, planErr.message)
+	}
+
+	_, err := scan().
+		Unpivot(Unpivot{Expr: DynCol("labels"), Key: "label", Value: "label_value"}).
+		Aggregate([]Expr{Count(Col("label_value"))}, []Expr{Col("label")}).
+		Build()
+	require.NoError(t, err)
+}
+
+func TestSemiJoinKeys(t *testing.T) {
+	scan := func() Builder {
+		return (&Builder{}).Scan(&mockTableProvider{dynparquet.NewSampleSchema()}, "table1")
+	}
+	inner := scan().Distinct(Col("stacktrace"))
+
+	for _, testCase := range []struct {
+		builder Builder
+		errMsg  string
+	}{
+		{
+			builder: scan().SemiJoin(inner, SemiJoin{Key: Col("stacktrace")}),
+			errMsg:  "invalid semi join: key, inner key and inner plan must be set",
+		},
+		{
+			builder: scan().SemiJoin(inner, SemiJoin{Key: DynCol("labels"), InnerKey: Col("stacktrace")}),
+			errMsg:  "invalid semi join: key must be a column",
+		},
+		{
+			builder: scan().SemiJoin(inner, SemiJoin{Key: Col("stacktrace"), InnerKey: Col("timestamp")}),
+			errMsg:  "invalid semi join: inner key timestamp is not a column of the inner plan's results",
+		},
+		{
+			builder: scan().SemiJoin(
+				scan().Filter(Col("example_type").Eq(Literal(4.6))),
+				SemiJoin{Key: Col("stacktrace"), InnerKey: Col("stacktrace")},
+			),
+			errMsg: "invalid semi join: invalid inner plan",
+		},
+	} {
+		_, err := testCase.builder.Build()
+		require.NotNil(t, err)
+		planErr, ok := err.(*PlanValidationError)
+		require.True(t, ok)
+		require.Equal(t, testCase.errMsg, planErr.message)
+	}
+
+	_, err := scan().
+		SemiJoin(inner, SemiJoin{Key: Col("stacktrace"), InnerKey: Col("stacktrace")}).
+		Build()
+	require.NoError(t, err)
+}
@@ -1,24 +1,61 @@
 package query
 
 import (
-	"runtime/debug"
+	"context"
+	"errors"
+	"fmt"
 	"sync/atomic"
 
 	"github.com/apache/arrow/go/v14/arrow/memory"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/polarsignals/frostdb/query/physicalplan"
 )
 
+// PanicMemoryLimit is the value allocations panic with when they exceed a
+// limit and can't be attributed to a query to cancel.
 const PanicMemoryLimit = "memory limit exceeded"
 
-var _ memory.Allocator = (*LimitAllocator)(nil)
+// ErrMemoryLimitExceeded is the error a query fails with when it exceeds its
+// own memory limit or the limit of an allocator it allocates from.
+var ErrMemoryLimitExceeded = errors.New(PanicMemoryLimit)
+
+var (
+	_ memory.Allocator               = (*LimitAllocator)(nil)
+	_ physicalplan.OperatorAllocator = (*LimitAllocator)(nil)
+)
 
-// LimitAllocator is a wrapper around a memory.Allocator that panics if the memory usage exceeds the defined limit.
+// LimitAllocator is a wrapper around a memory.Allocator that limits the
+// memory allocated through it. Allocators form a hierarchy: allocations of a
+// child count towards the limits of the child and all of its ancestors. The
+// engine creates a child for every query, so that a query that exceeds a
+// limit has its context cancelled with ErrMemoryLimitExceeded while other
+// queries sharing the parent continue.
+//
+// Limits are soft: an allocation that exceeds a limit completes, and the
+// offending query is cancelled afterwards. The allocated memory may therefore
+// exceed the limit until the query observes the cancellation and releases its
+// memory. Allocations that exceed a limit and can't be attributed to a query
+// fail instead, and panic with PanicMemoryLimit.
 type LimitAllocator struct {
 	limit     int64
 	allocated *atomic.Int64
+	peak      atomic.Int64
 	allocator memory.Allocator
 	reg       prometheus.Registerer
+
+	parent  *LimitAllocator
+	metrics *allocatorMetrics
+	// cancel cancels the context of the query the allocator belongs to.
+	cancel context.CancelCauseFunc
+	err    atomic.Pointer[error]
+}
+
+type allocatorMetrics struct {
+	limitExceeded     *prometheus.CounterVec
+	queryPeak         prometheus.Histogram
+	operatorAllocated *prometheus.CounterVec
 }
 
 type AllocatorOption func(*LimitAllocator)
@@ -29,6 +66,14 @@ func WithRegistry(reg prometheus.Registerer) AllocatorOption {
 	}
 }
 
+// WithCancel sets the function used to cancel the query the allocator
+// belongs to once it exceeds a limit.
+func WithCancel(cancel context.CancelCauseFunc) AllocatorOption {
+	return func(a *LimitAllocator) {
+		a.cancel = cancel
+	}
+}
+
 func NewLimitAllocator(limit int64, allocator memory.Allocator, options ...AllocatorOption) *LimitAllocator {
 	l := &LimitAllocator{
 		limit:     limit,
@@ -47,21 +92,55 @@ func NewLimitAllocator(limit int64, allocator memory.Allocator, options ...Alloc
 	}, func() float64 {
 		return float64(l.allocated.Load())
 	})
+	promauto.With(l.reg).NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "memory_limit_bytes",
+		Help: "The maximum number of bytes the allocator allows to be allocated.",
+	}, func() float64 {
+		return float64(l.limit)
+	})
+	l.metrics = &allocatorMetrics{
+		limitExceeded: promauto.With(l.reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "memory_limit_exceeded_total",
+			Help: "The number of queries cancelled because they exceeded a memory limit, by the level of the exceeded limit.",
+		}, []string{"level"}),
+		queryPeak: promauto.With(l.reg).NewHistogram(prometheus.HistogramOpts{
+			Name:    "memory_query_peak_bytes",
+			Help:    "The peak number of bytes allocated by a query.",
+			Buckets: prometheus.ExponentialBuckets(1024, 4, 12),
+		}),
+		operatorAllocated: promauto.With(l.reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "memory_operator_allocated_bytes_total",
+			Help: "The total number of bytes allocated by query operators, by operator.",
+		}, []string{"operator"}),
+	}
 
 	return l
 }
 
-func (a *LimitAllocator) Allocate(size int) []byte {
-	for {
-		allocated := a.allocated.Load()
-		if allocated+int64(size) > a.limit {
-			panic(PanicMemoryLimit)
-		}
+// NewChild returns an allocator whose allocations count towards its own limit
+// and the limits of all of its ancestors. A limit of zero means that the child
+// is only limited by its ancestors. Metrics are reported through the registry
+// of the root allocator.
+func (a *LimitAllocator) NewChild(limit int64, options ...AllocatorOption) *LimitAllocator {
+	l := &LimitAllocator{
+		limit:     limit,
+		allocated: &atomic.Int64{},
+		allocator: a.allocator,
+		reg:       a.reg,
+		parent:    a,
+		metrics:   a.metrics,
+	}
 
-		if a.allocated.CompareAndSwap(allocated, allocated+int64(size)) {
-			return a.allocator.Allocate(size)
-		}
+	for _, option := range options {
+		option(l)
 	}
+
+	return l
+}
+
+func (a *LimitAllocator) Allocate(size int) []byte {
+	a.reserve(int64(size))
+	return a.allocator.Allocate(size)
 }
 
 func (a *LimitAllocator) Reallocate(size int, b []byte) []byte {
@@ -69,25 +148,117 @@ func (a *LimitAllocator) Reallocate(size int, b []byte) []byte {
 		return b
 	}
 
-	diff := int64(size - len(b))
-	for {
-		allocated := a.allocated.Load()
-		if allocated+diff > a.limit {
-			debug.PrintStack()
-			panic(PanicMemoryLimit)
-		}
-
-		if a.allocated.CompareAndSwap(allocated, allocated+diff) {
-			return a.allocator.Reallocate(size, b)
-		}
-	}
+	a.reserve(int64(size - len(b)))
+	return a.allocator.Reallocate(size, b)
 }
 
 func (a *LimitAllocator) Free(b []byte) {
-	a.allocated.Add(-int64(len(b)))
+	for l := a; l != nil; l = l.parent {
+		l.allocated.Add(-int64(len(b)))
+	}
 	a.allocator.Free(b)
 }
 
 func (a *LimitAllocator) Allocated() int {
 	return int(a.allocated.Load())
 }
+
+// Peak returns the maximum number of bytes that were allocated at any time.
+func (a *LimitAllocator) Peak() int {
+	return int(a.peak.Load())
+}
+
+// Err returns the error of the first allocation that exceeded a limit or nil
+// if no limit was exceeded.
+func (a *LimitAllocator) Err() error {
+	if err := a.err.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// Close reports the peak memory usage of a query's allocator. It must be
+// called once the query finished.
+func (a *LimitAllocator) Close() {
+	a.metrics.queryPeak.Observe(float64(a.peak.Load()))
+}
+
+// ForOperator returns an allocator that attributes its allocations to the
+// given operator.
+func (a *LimitAllocator) ForOperator(operator string) memory.Allocator {
+	return &operatorAllocator{
+		LimitAllocator: a,
+		allocated:      a.metrics.operatorAllocated.WithLabelValues(operator),
+	}
+}
+
+// reserve accounts the size to the allocator and its ancestors and cancels the
+// query if a limit is exceeded.
+func (a *LimitAllocator) reserve(size int64) {
+	var exceeded *LimitAllocator
+	for l := a; l != nil; l = l.parent {
+		allocated := l.allocated.Add(size)
+		if size <= 0 {
+			continue
+		}
+		for {
+			peak := l.peak.Load()
+			if allocated <= peak || l.peak.CompareAndSwap(peak, allocated) {
+				break
+			}
+		}
+		if exceeded == nil && l.limit > 0 && allocated > l.limit {
+			exceeded = l
+		}
+	}
+	if exceeded == nil {
+		return
+	}
+	if !a.exceed(exceeded) {
+		// There is no query to cancel, so the allocation itself has to fail.
+		for l := a; l != nil; l = l.parent {
+			l.allocated.Add(-size)
+		}
+		panic(PanicMemoryLimit)
+	}
+}
+
+// exceed cancels the query that allocated beyond the limit of the given
+// allocator. It returns false if there is no query to cancel.
+func (a *LimitAllocator) exceed(limited *LimitAllocator) bool {
+	err := fmt.Errorf("%w: %d bytes allocated, limit is %d bytes", ErrMemoryLimitExceeded, limited.allocated.Load(), limited.limit)
+
+	for l := a; l != nil; l = l.parent {
+		if l.cancel == nil {
+			continue
+		}
+		if l.err.CompareAndSwap(nil, &err) {
+			level := "query"
+			if limited.parent == nil {
+				level = "global"
+			}
+			l.metrics.limitExceeded.WithLabelValues(level).Inc()
+			l.cancel(err)
+		}
+		return true
+	}
+	return false
+}
+
+// operatorAllocator attributes the allocations of an operator.
+type operatorAllocator struct {
+	*LimitAllocator
+	allocated prometheus.Counter
+}
+
+func (o *operatorAllocator) Allocate(size int) []byte {
+	o.allocated.Add(float64(size))
+	return o.LimitAllocator.Allocate(size)
+}
+
+func (o *operatorAllocator) Reallocate(size int, b []byte) []byte {
+	if grown := size - len(b); grown > 0 {
+		o.allocated.Add(float64(grown))
+	}
+	return o.LimitAllocator.Reallocate(size, b)
+}
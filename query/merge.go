@@ -0,0 +1,304 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"hash/maphash"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/polarsignals/frostdb/query/logicalplan"
+	"github.com/polarsignals/frostdb/query/physicalplan"
+)
+
+// ErrNotMergeable is returned when the partial results of a query can't be
+// merged.
+var ErrNotMergeable = errors.New("query does not have a mergeable aggregation")
+
+// mergeablePlan is a plan split into the part that is computed separately for
+// different parts of the data, and the operators merging the results.
+type mergeablePlan struct {
+	partial *logicalplan.LogicalPlan
+	// merge is the aggregation or distinct the partial results are merged
+	// with. It is nil if the results only need to be concatenated.
+	merge *logicalplan.LogicalPlan
+	// post are the row-wise operators following the merge, bottom up.
+	post []*logicalplan.LogicalPlan
+}
+
+// splitMergeablePlan splits an optimized plan into a mergeablePlan. False is
+// returned if the results of the plan can't be merged.
+func splitMergeablePlan(plan *logicalplan.LogicalPlan) (*mergeablePlan, bool) {
+	var nodes []*logicalplan.LogicalPlan
+	for p := plan; p != nil; p = p.Input {
+		nodes = append(nodes, p)
+	}
+	if nodes[len(nodes)-1].TableScan == nil {
+		return nil, false
+	}
+
+	c := &mergeablePlan{partial: plan}
+	for i, p := range nodes {
+		switch {
+		case p.Filter != nil, p.Projection != nil, p.Unnest != nil, p.TableScan != nil:
+			if c.merge == nil {
+				continue
+			}
+			if p.Projection != nil && !rowWiseProjection(p.Projection) {
+				return nil, false
+			}
+		case p.Aggregation != nil:
+			if c.merge != nil || !mergeableAggregation(p.Aggregation) {
+				return nil, false
+			}
+			c.merge, c.partial = p, p
+			c.post = reversed(nodes[:i])
+		case p.Distinct != nil:
+			if c.merge != nil || !mergeableDistinct(p.Distinct) {
+				return nil, false
+			}
+			c.merge, c.partial = p, p
+			c.post = reversed(nodes[:i])
+		default:
+			return nil, false
+		}
+	}
+	return c, true
+}
+
+func reversed(nodes []*logicalplan.LogicalPlan) []*logicalplan.LogicalPlan {
+	res := make([]*logicalplan.LogicalPlan, 0, len(nodes))
+	for i := len(nodes) - 1; i >= 0; i-- {
+		res = append(res, nodes[i])
+	}
+	return res
+}
+
+// rowWiseProjection returns whether the projection computes each row
+// independently of others, so that projecting partial results and
+// concatenating them is equivalent to projecting all data.
+func rowWiseProjection(p *logicalplan.Projection) bool {
+	for _, e := range p.Exprs {
+		if _, ok := e.(*logicalplan.AverageExpr); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeableAggregation returns whether the results of the aggregation over
+// different parts of the data can be merged by aggregating them again.
+func mergeableAggregation(agg *logicalplan.Aggregation) bool {
+	for _, e := range agg.GroupExprs {
+		switch e.(type) {
+		// Timestamps are already truncated to their duration in the partial
+		// results, so that truncating them again when merging is a no-op.
+		case *logicalplan.Column, *logicalplan.DynamicColumn, *logicalplan.DurationExpr:
+		default:
+			return false
+		}
+	}
+	for _, e := range agg.AggExprs {
+		if alias, ok := e.(*logicalplan.AliasExpr); ok {
+			e = alias.Expr
+		}
+		f, ok := e.(*logicalplan.AggregationFunction)
+		if !ok {
+			return false
+		}
+		for _, e := range []logicalplan.Expr{f.Expr, f.OrderBy, f.Paired} {
+			if _, ok := e.(*logicalplan.Column); !ok && e != nil {
+				return false
+			}
+		}
+		// First and last values are merged by their order keys, the other
+		// aggregations by their partial states.
+		switch {
+		case f.Func == logicalplan.AggFuncSum, f.Func == logicalplan.AggFuncMin,
+			f.Func == logicalplan.AggFuncMax, f.Func == logicalplan.AggFuncCount,
+			f.Func == logicalplan.AggFuncFirst, f.Func == logicalplan.AggFuncLast,
+			f.Func == logicalplan.AggFuncHistogram, f.Func.Statistical(), f.Func.Counter():
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func mergeableDistinct(d *logicalplan.Distinct) bool {
+	for _, e := range d.Exprs {
+		switch e.(type) {
+		case *logicalplan.Column, *logicalplan.DynamicColumn:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// mergeOperator returns the operators merging partial results and passing the
+// merged results on to the callback.
+func (c *mergeablePlan) mergeOperator(
+	pool memory.Allocator,
+	tracer trace.Tracer,
+	callback func(ctx context.Context, r arrow.Record) error,
+) (physicalplan.PhysicalPlan, error) {
+	output := &physicalplan.OutputPlan{}
+	output.SetNextCallback(callback)
+
+	var next physicalplan.PhysicalPlan = output
+	for i := len(c.post) - 1; i >= 0; i-- {
+		var (
+			op  physicalplan.PhysicalPlan
+			err error
+		)
+		switch p := c.post[i]; {
+		case p.Filter != nil:
+			op, err = physicalplan.Filter(pool, tracer, p.Filter.Expr)
+		case p.Projection != nil:
+			op, err = physicalplan.Project(pool, tracer, p.Projection.Exprs)
+		case p.Unnest != nil:
+			op = physicalplan.Unnest(pool, tracer, p.Unnest.Expr.Name())
+		}
+		if err != nil {
+			return nil, err
+		}
+		op.SetNext(next)
+		next = op
+	}
+
+	switch {
+	case c.merge == nil:
+	case c.merge.Aggregation != nil:
+		op, err := physicalplan.Aggregate(pool, tracer, c.merge.Aggregation, true, false, maphash.MakeSeed())
+		if err != nil {
+			return nil, err
+		}
+		op.SetNext(next)
+		next = op
+	case c.merge.Distinct != nil:
+		op := physicalplan.Distinct(pool, tracer, c.merge.Distinct.Exprs, c.merge.Distinct.Limit)
+		op.SetNext(next)
+		next = op
+	}
+	return next, nil
+}
+
+// aggregationPlan returns the query's optimized plan split at its aggregation.
+func aggregationPlan(plan *logicalplan.LogicalPlan) (*mergeablePlan, error) {
+	c, ok := splitMergeablePlan(plan)
+	if !ok || c.merge == nil || c.merge.Aggregation == nil {
+		return nil, ErrNotMergeable
+	}
+	return c, nil
+}
+
+// PartialSchema returns the Arrow schema of the records ExecutePartial
+// produces. The schema consists of the group by columns followed by one
+// column per partial aggregation, e.g. an average is computed from the
+// columns of its sum and count. Statistical, histogram, rate and increase
+// aggregations are passed on as binary partial states, and first and last
+// aggregations are followed by the columns of their order keys.
+func (b LocalQueryBuilder) PartialSchema() (*arrow.Schema, error) {
+	plan, _, err := b.optimizedPlan(nil)
+	if err != nil {
+		return nil, err
+	}
+	c, err := aggregationPlan(plan)
+	if err != nil {
+		return nil, err
+	}
+	return c.partialSchema()
+}
+
+// partialSchema returns the schema of the partial results of the plan's
+// aggregation.
+func (c *mergeablePlan) partialSchema() (*arrow.Schema, error) {
+	final, err := c.partial.OutputSchema()
+	if err != nil {
+		return nil, err
+	}
+	return physicalplan.PartialAggregationSchema(c.partial.Aggregation, c.partial.InputSchema(), final)
+}
+
+// ExecutePartial executes the query up to and including its aggregation and
+// passes the partial aggregation results on to the callback. The partial
+// results of instances holding different parts of the data are merged into
+// the query's results by MergeAggregate.
+func (b LocalQueryBuilder) ExecutePartial(ctx context.Context, callback func(ctx context.Context, r arrow.Record) error) error {
+	ctx, span := b.tracer.Start(ctx, "LocalQueryBuilder/ExecutePartial")
+	defer span.End()
+
+	ctx, pool, done := b.queryAllocator(ctx)
+	plan, _, release, err := b.resolvedPlan(ctx, pool)
+	if err != nil {
+		return done(err)
+	}
+	defer release()
+	c, err := aggregationPlan(plan)
+	if err != nil {
+		return done(err)
+	}
+	schema, err := c.partialSchema()
+	if err != nil {
+		return done(err)
+	}
+
+	phyPlan, err := b.buildPhysicalPlan(ctx, pool, c.partial, physicalplan.WithPartialAggregation())
+	if err != nil {
+		return done(err)
+	}
+
+	return done(executeWithSchema(ctx, phyPlan, pool, schema, callback))
+}
+
+// MergeAggregate merges the records produced by ExecutePartial of the same
+// query on several sources into the query's results.
+func (b LocalQueryBuilder) MergeAggregate(
+	ctx context.Context,
+	partials []array.RecordReader,
+	callback func(ctx context.Context, r arrow.Record) error,
+) error {
+	ctx, span := b.tracer.Start(ctx, "LocalQueryBuilder/MergeAggregate")
+	defer span.End()
+
+	// The merge only involves the nodes above the aggregation, so the semi
+	// joins below it don't need to be resolved.
+	ctx, pool, done := b.queryAllocator(ctx)
+	plan, schema, err := b.optimizedPlan(nil)
+	if err != nil {
+		return done(err)
+	}
+	c, err := aggregationPlan(plan)
+	if err != nil {
+		return done(err)
+	}
+
+	var emitted bool
+	merge, err := c.mergeOperator(pool, b.tracer, func(ctx context.Context, r arrow.Record) error {
+		emitted = true
+		return callback(ctx, r)
+	})
+	if err != nil {
+		return done(err)
+	}
+	defer merge.Close()
+
+	for _, partial := range partials {
+		for partial.Next() {
+			if err := merge.Callback(ctx, partial.Record()); err != nil {
+				return done(err)
+			}
+		}
+		if err := partial.Err(); err != nil {
+			return done(err)
+		}
+	}
+	if err := merge.Finish(ctx); err != nil || emitted {
+		return done(err)
+	}
+	return done(emitEmpty(ctx, pool, schema, callback))
+}
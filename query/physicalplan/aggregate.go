@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"hash/maphash"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/apache/arrow/go/v14/arrow"
 	"github.com/apache/arrow/go/v14/arrow/array"
@@ -28,8 +30,52 @@ func Aggregate(
 	ordered bool,
 	seed maphash.Seed,
 ) (PhysicalPlan, error) {
+	aggregations, err := aggregationsFromPlan(agg)
+	if err != nil {
+		return nil, err
+	}
+
+	if ordered {
+		if len(aggregations) > 1 {
+			return nil, fmt.Errorf(
+				"OrderedAggregate does not support multiple aggregations, found %d", len(aggregations),
+			)
+		}
+		return NewOrderedAggregate(
+			pool,
+			tracer,
+			// TODO(asubiotto): Multiple aggregation functions are not yet
+			// supported. The planning code should already have planned a hash
+			// aggregation in this case.
+			aggregations[0],
+			agg.GroupExprs,
+			final,
+		), nil
+	}
+	return NewHashAggregate(
+		pool,
+		tracer,
+		aggregations,
+		agg.GroupExprs,
+		seed,
+		final,
+	), nil
+}
+
+// aggregationsFromPlan returns the aggregations of the aggregation's
+// expressions.
+func aggregationsFromPlan(agg *logicalplan.Aggregation) ([]Aggregation, error) {
 	aggregations := make([]Aggregation, 0, len(agg.AggExprs))
 
+	// Rates and increases are computed within the durations timestamps are
+	// grouped into.
+	var window time.Duration
+	for _, expr := range agg.GroupExprs {
+		if d, ok := expr.(*logicalplan.DurationExpr); ok {
+			window = d.Value()
+		}
+	}
+
 	for _, expr := range agg.AggExprs {
 		var (
 			aggregation    Aggregation
@@ -42,6 +88,16 @@ func Aggregate(
 			case *logicalplan.AggregationFunction:
 				aggFunc = e.Func
 				aggFuncFound = true
+				aggregation.buckets = e.Buckets
+				if e.OrderBy != nil || e.Paired != nil {
+					// The order by and paired columns must not be mistaken
+					// for the aggregated column.
+					aggregation.expr = e.Expr
+					aggregation.orderBy = e.OrderBy
+					aggregation.paired = e.Paired
+					aggColumnFound = true
+					return false
+				}
 			case *logicalplan.Column:
 				aggregation.expr = e
 				aggColumnFound = true
@@ -64,35 +120,13 @@ func Aggregate(
 
 		aggregation.resultName = expr.Name()
 		aggregation.function = aggFunc
+		if aggFunc.Counter() {
+			aggregation.window = window
+		}
 
 		aggregations = append(aggregations, aggregation)
 	}
-
-	if ordered {
-		if len(aggregations) > 1 {
-			return nil, fmt.Errorf(
-				"OrderedAggregate does not support multiple aggregations, found %d", len(aggregations),
-			)
-		}
-		return NewOrderedAggregate(
-			pool,
-			tracer,
-			// TODO(asubiotto): Multiple aggregation functions are not yet
-			// supported. The planning code should already have planned a hash
-			// aggregation in this case.
-			aggregations[0],
-			agg.GroupExprs,
-			final,
-		), nil
-	}
-	return NewHashAggregate(
-		pool,
-		tracer,
-		aggregations,
-		agg.GroupExprs,
-		seed,
-		final,
-	), nil
+	return aggregations, nil
 }
 
 func chooseAggregationFunction(
@@ -104,6 +138,8 @@ func chooseAggregationFunction(
 		switch dataType.ID() {
 		case arrow.INT64:
 			return &Int64SumAggregation{}, nil
+		case arrow.FLOAT64:
+			return &Float64SumAggregation{}, nil
 		default:
 			return nil, fmt.Errorf("unsupported sum of type: %s", dataType.Name())
 		}
@@ -135,6 +171,93 @@ type Aggregation struct {
 	resultName string
 	function   logicalplan.AggFunc
 	arrays     []builder.ColumnBuilder // TODO: These can actually live outside this struct and be shared. Only at the very end will they be read by each column and then aggregated separately.
+
+	// orderBy is the expression the values of first and last aggregations
+	// are ordered by.
+	orderBy logicalplan.Expr
+	// orderKey is the result name of the hidden aggregation that buffers the
+	// order keys of a first or last aggregation.
+	orderKey string
+	// paired is the second expression of aggregations of pairs of values.
+	paired logicalplan.Expr
+	// pairedInput is the result name of the hidden aggregation that buffers
+	// the second values of the pairs, or the timestamps of rate and increase
+	// aggregations.
+	pairedInput string
+	// hidden aggregations buffer order keys or paired values. Their results
+	// are only passed on to later stages of the aggregation.
+	hidden bool
+	// rawInput indicates a hidden aggregation of values that are only
+	// buffered by the first stage of the aggregation, which aggregates them
+	// into partial states. It has no results.
+	rawInput bool
+	// buckets are the upper bounds of the buckets of histogram aggregations.
+	buckets []float64
+	// window is the duration the samples of rate and increase aggregations
+	// are grouped into.
+	window time.Duration
+	// arrayType is the type of the partial aggregates of aggregations added
+	// while merging spilled state, whose columns are missing from the
+	// spilled records of other groups.
+	arrayType arrow.DataType
+}
+
+// withoutArrays returns a copy of the aggregation without any groups.
+func (a Aggregation) withoutArrays() Aggregation {
+	a.arrays = nil
+	return a
+}
+
+// orderKeyColumnName returns the name of the column holding the order keys of
+// the first or last aggregation with the given result name.
+func orderKeyColumnName(resultName string) string {
+	return "order_key." + resultName
+}
+
+// PartialAggregationSchema returns the schema of the partial results of the
+// aggregation passed on by plans built WithPartialAggregation, given the
+// schema of the table scanned and of the aggregation's final results. Aggregations
+// whose partial results are partial states pass them on as binary values,
+// and the values of first and last aggregations are followed by the columns
+// of their order keys.
+func PartialAggregationSchema(agg *logicalplan.Aggregation, s *dynparquet.Schema, final *arrow.Schema) (*arrow.Schema, error) {
+	aggregations, err := aggregationsFromPlan(agg)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := append([]arrow.Field{}, final.Fields()...)
+	var orderKeys []arrow.Field
+	for _, aggregation := range aggregations {
+		i := slices.IndexFunc(fields, func(f arrow.Field) bool {
+			return f.Name == aggregation.resultName
+		})
+		if i == -1 {
+			return nil, fmt.Errorf("result of %s not found", aggregation.resultName)
+		}
+		newState, _, err := newPartialState(aggregation)
+		switch {
+		case err != nil:
+			return nil, err
+		case newState != nil:
+			fields[i].Type = arrow.BinaryTypes.Binary
+		case aggregation.orderBy != nil:
+			typ, err := aggregation.orderBy.DataType(s.ParquetSchema())
+			if err != nil {
+				return nil, fmt.Errorf("order keys of %s: %w", aggregation.resultName, err)
+			}
+			orderKeys = append(orderKeys, arrow.Field{
+				Name: orderKeyColumnName(aggregation.resultName), Type: typ,
+			})
+		}
+	}
+	return arrow.NewSchema(append(fields, orderKeys...), nil), nil
+}
+
+// pairedInputName returns the name of the hidden aggregation buffering the
+// second values of the aggregation of pairs with the given result name.
+func pairedInputName(resultName string) string {
+	return "paired." + resultName
 }
 
 type AggregationFunction interface {
@@ -150,6 +273,15 @@ type HashAggregate struct {
 	// Indicate is this is the last aggregation or
 	// if this is a aggregation with another aggregation to follow after synchronizing.
 	finalStage bool
+	// partialOutput indicates that the final stage passes on the partial
+	// results of the groups rather than their final results, to be merged
+	// with those of other instances.
+	partialOutput bool
+
+	// computedGroupings evaluate the computed group by expressions. They are
+	// only evaluated on the raw data, later stages find their results by the
+	// expressions' names.
+	computedGroupings []columnProjection
 
 	// Buffers that are reused across callback calls.
 	groupByFields      []arrow.Field
@@ -160,6 +292,10 @@ type HashAggregate struct {
 	// aggregates are the collection of all the hash aggregates for this hash aggregation. This is useful when a single hash aggregate cannot fit
 	// into a single record and needs to be split into multiple records.
 	aggregates []*hashAggregate
+
+	// spill is set if the aggregation spills its state to disk once it
+	// exceeds the configured memory limit.
+	spill *aggregateSpill
 }
 
 type hashtuple struct {
@@ -202,6 +338,41 @@ func NewHashAggregate(
 			static = append(static, agg)
 		}
 	}
+	// The values of first and last aggregations are buffered along with the
+	// keys they are ordered by, so that partial results can be merged by
+	// their keys. Aggregations of pairs buffer the second values of the pairs
+	// until they are aggregated into partial states, and so do rate and
+	// increase aggregations with the timestamps of their samples.
+	for i, agg := range static {
+		switch {
+		case agg.function.Counter():
+			static[i].pairedInput = pairedInputName(agg.resultName)
+			static = append(static, Aggregation{
+				expr:       agg.orderBy,
+				resultName: static[i].pairedInput,
+				function:   agg.function,
+				hidden:     true,
+				rawInput:   true,
+			})
+		case agg.orderBy != nil:
+			static[i].orderKey = orderKeyColumnName(agg.resultName)
+			static = append(static, Aggregation{
+				expr:       agg.orderBy,
+				resultName: static[i].orderKey,
+				function:   agg.function,
+				hidden:     true,
+			})
+		case agg.paired != nil:
+			static[i].pairedInput = pairedInputName(agg.resultName)
+			static = append(static, Aggregation{
+				expr:       agg.paired,
+				resultName: static[i].pairedInput,
+				function:   agg.function,
+				hidden:     true,
+				rawInput:   true,
+			})
+		}
+	}
 
 	return &HashAggregate{
 		pool:   pool,
@@ -215,16 +386,19 @@ func NewHashAggregate(
 		groupByFieldHashes: make([]hashCombiner, 0, 10),
 		groupByArrays:      make([]arrow.Array, 0, 10),
 		hashToAggregate:    map[uint64]hashtuple{},
-		aggregates: []*hashAggregate{ // initialize a single hash aggregate; we expect this array to only every grow during very large aggregations.
-			{
-				dynamicAggregations:          dynamic,
-				dynamicAggregationsConverted: make(map[string]struct{}),
-				aggregations:                 static,
-				concreteAggregations:         len(static),
-				groupByCols:                  map[string]builder.ColumnBuilder{},
-				colOrdering:                  []string{},
-			},
-		},
+		// initialize a single hash aggregate; we expect this array to only every grow during very large aggregations.
+		aggregates: []*hashAggregate{newHashAggregate(dynamic, static)},
+	}
+}
+
+func newHashAggregate(dynamic, static []Aggregation) *hashAggregate {
+	return &hashAggregate{
+		dynamicAggregations:          dynamic,
+		dynamicAggregationsConverted: make(map[string]struct{}),
+		aggregations:                 static,
+		concreteAggregations:         len(static),
+		groupByCols:                  map[string]builder.ColumnBuilder{},
+		colOrdering:                  []string{},
 	}
 }
 
@@ -232,6 +406,12 @@ func (a *HashAggregate) Close() {
 	for _, arr := range a.groupByArrays {
 		arr.Release()
 	}
+	a.releaseState()
+	a.next.Close()
+}
+
+// releaseState releases the builders of all groups.
+func (a *HashAggregate) releaseState() {
 	for _, aggregate := range a.aggregates {
 		for _, aggregation := range aggregate.aggregations {
 			for _, bldr := range aggregation.arrays {
@@ -242,7 +422,6 @@ func (a *HashAggregate) Close() {
 			bldr.Release()
 		}
 	}
-	a.next.Close()
 }
 
 func (a *HashAggregate) SetNext(next PhysicalPlan) {
@@ -257,6 +436,9 @@ func (a *HashAggregate) Draw() *Diagram {
 
 	names := make([]string, 0, len(a.aggregates[0].aggregations))
 	for _, agg := range a.aggregates[0].aggregations {
+		if agg.hidden {
+			continue
+		}
 		names = append(names, agg.resultName)
 	}
 
@@ -310,6 +492,15 @@ func (a *HashAggregate) Callback(_ context.Context, r arrow.Record) error {
 	// ctx, span := a.tracer.Start(ctx, "HashAggregate/Callback")
 	// defer span.End()
 
+	if !a.finalStage {
+		computed, err := a.projectComputedGroupings(r)
+		if err != nil {
+			return err
+		}
+		defer computed.Release()
+		r = computed
+	}
+
 	// aggregate is the current aggregation
 	aggregate := a.aggregates[len(a.aggregates)-1]
 
@@ -327,6 +518,14 @@ func (a *HashAggregate) Callback(_ context.Context, r arrow.Record) error {
 	columnToAggregate := make([]arrow.Array, len(aggregate.aggregations))
 	concreteAggregateFieldsFound := 0
 	dynamicAggregateFieldsFound := 0
+	if a.finalStage {
+		// Raw inputs were already aggregated by the previous stage.
+		for _, agg := range aggregate.aggregations[:aggregate.concreteAggregations] {
+			if agg.rawInput {
+				concreteAggregateFieldsFound++
+			}
+		}
+	}
 
 	for i := 0; i < r.Schema().NumFields(); i++ {
 		field := r.Schema().Field(i)
@@ -365,7 +564,7 @@ func (a *HashAggregate) Callback(_ context.Context, r arrow.Record) error {
 						aggregate.aggregations = append(aggregate.aggregations, Aggregation{
 							expr:       logicalplan.Col(field.Name),
 							dynamic:    true,
-							resultName: resultNameWithConcreteColumn(col.function, field.Name),
+							resultName: logicalplan.ResultNameWithConcreteColumn(col.function, field.Name),
 							function:   col.function,
 						})
 						aggregate.dynamicAggregationsConverted[field.Name] = struct{}{}
@@ -451,62 +650,12 @@ func (a *HashAggregate) Callback(_ context.Context, r arrow.Record) error {
 
 		tuple, ok := a.hashToAggregate[hash]
 		if !ok {
-			aggregate = a.aggregates[len(a.aggregates)-1]
-			for j, col := range columnToAggregate {
-				agg := builder.NewBuilder(a.pool, col.DataType())
-				aggregate.aggregations[j].arrays = append(aggregate.aggregations[j].arrays, agg)
-			}
-			tuple = hashtuple{
-				aggregate: len(a.aggregates) - 1, // always add new aggregates to the current aggregate
-				array:     len(aggregate.aggregations[0].arrays) - 1,
-			}
-			a.hashToAggregate[hash] = tuple
-			aggregate.rowCount++
-
-			// insert new row into columns grouped by and create new aggregate array to append to.
-			if err := a.updateGroupByCols(i, groupByArrays, groupByFields); err != nil {
-				if !errors.Is(err, builder.ErrMaxSizeReached) {
-					return err
-				}
-
-				// Max size reached, rollback the aggregation creation and create new aggregate
-				aggregate.rowCount--
-				for j := range columnToAggregate {
-					l := len(aggregate.aggregations[j].arrays)
-					aggregate.aggregations[j].arrays = aggregate.aggregations[j].arrays[:l-1]
-				}
-
-				// Create new aggregation
-				aggregations := make([]Aggregation, 0, len(a.aggregates[0].aggregations))
-				for _, agg := range a.aggregates[0].aggregations {
-					aggregations = append(aggregations, Aggregation{
-						expr:       agg.expr,
-						resultName: agg.resultName,
-						function:   agg.function,
-					})
-				}
-				a.aggregates = append(a.aggregates, &hashAggregate{
-					aggregations: aggregations,
-					groupByCols:  map[string]builder.ColumnBuilder{},
-					colOrdering:  []string{},
-				})
-
-				aggregate = a.aggregates[len(a.aggregates)-1]
-				for j, col := range columnToAggregate {
-					agg := builder.NewBuilder(a.pool, col.DataType())
-					aggregate.aggregations[j].arrays = append(aggregate.aggregations[j].arrays, agg)
-				}
-				tuple = hashtuple{
-					aggregate: len(a.aggregates) - 1, // always add new aggregates to the current aggregate
-					array:     len(aggregate.aggregations[0].arrays) - 1,
-				}
-				a.hashToAggregate[hash] = tuple
-				aggregate.rowCount++
-
-				if err := a.updateGroupByCols(i, groupByArrays, groupByFields); err != nil {
-					return err
-				}
+			var err error
+			tuple, err = a.newGroup(hash, i, groupByArrays, groupByFields, columnToAggregate)
+			if err != nil {
+				return err
 			}
+			aggregate = a.aggregates[len(a.aggregates)-1]
 		}
 
 		for j, col := range columnToAggregate {
@@ -518,7 +667,7 @@ func (a *HashAggregate) Callback(_ context.Context, r arrow.Record) error {
 				// This can happen with dynamic column aggregations without
 				// groupings. The group exists, but the array to append to does
 				// not.
-				agg := builder.NewBuilder(a.pool, col.DataType())
+				agg := a.newBuilder(col.DataType())
 				aggregate.aggregations[j].arrays = append(aggregate.aggregations[j].arrays, agg)
 			}
 			if err := builder.AppendValue(a.aggregates[tuple.aggregate].aggregations[j].arrays[tuple.array], col, i); err != nil {
@@ -527,9 +676,142 @@ func (a *HashAggregate) Callback(_ context.Context, r arrow.Record) error {
 		}
 	}
 
+	if a.spill != nil && a.spill.exceeded() {
+		return a.spillState()
+	}
 	return nil
 }
 
+// projectComputedGroupings returns the record with the results of the
+// computed group by expressions appended, each named after its expression.
+// The returned record must be released.
+func (a *HashAggregate) projectComputedGroupings(r arrow.Record) (arrow.Record, error) {
+	if a.computedGroupings == nil {
+		a.computedGroupings = []columnProjection{}
+		for _, expr := range a.groupByColumnMatchers {
+			if !expr.Computed() {
+				continue
+			}
+			proj, err := projectionFromExpr(expr)
+			if err != nil {
+				return nil, fmt.Errorf("group by %s: %w", expr.Name(), err)
+			}
+			a.computedGroupings = append(a.computedGroupings, proj)
+		}
+	}
+	if len(a.computedGroupings) == 0 {
+		r.Retain()
+		return r, nil
+	}
+
+	fields := r.Schema().Fields()
+	cols := append([]arrow.Array{}, r.Columns()...)
+	var computed []arrow.Array
+	defer func() {
+		for _, arr := range computed {
+			arr.Release()
+		}
+	}()
+	i := 0
+	for _, expr := range a.groupByColumnMatchers {
+		if !expr.Computed() {
+			continue
+		}
+		projFields, arrs, err := a.computedGroupings[i].Project(a.pool, r)
+		i++
+		computed = append(computed, arrs...)
+		if err != nil {
+			return nil, err
+		}
+		if len(arrs) != 1 {
+			return nil, fmt.Errorf("group by %s: expected a single column, got %d", expr.Name(), len(arrs))
+		}
+		field := projFields[0]
+		field.Name = expr.Name()
+		fields = append(fields, field)
+		cols = append(cols, arrs[0])
+	}
+	return array.NewRecord(arrow.NewSchema(fields, nil), cols, r.NumRows()), nil
+}
+
+// newGroup creates the group with the given hash. The values grouped by are
+// taken from the row of groupByArrays. It returns the location of the
+// group's aggregation arrays.
+func (a *HashAggregate) newGroup(
+	hash uint64,
+	i int,
+	groupByArrays []arrow.Array,
+	groupByFields []arrow.Field,
+	columnToAggregate []arrow.Array,
+) (hashtuple, error) {
+	aggregate := a.aggregates[len(a.aggregates)-1]
+	for j, col := range columnToAggregate {
+		agg := a.newBuilder(aggregationArrayType(col, aggregate.aggregations[j]))
+		aggregate.aggregations[j].arrays = append(aggregate.aggregations[j].arrays, agg)
+	}
+	tuple := hashtuple{
+		aggregate: len(a.aggregates) - 1, // always add new aggregates to the current aggregate
+		array:     len(aggregate.aggregations[0].arrays) - 1,
+	}
+	a.hashToAggregate[hash] = tuple
+	aggregate.rowCount++
+
+	// insert new row into columns grouped by and create new aggregate array to append to.
+	if err := a.updateGroupByCols(i, groupByArrays, groupByFields); err != nil {
+		if !errors.Is(err, builder.ErrMaxSizeReached) {
+			return hashtuple{}, err
+		}
+
+		// Max size reached, rollback the aggregation creation and create new aggregate
+		aggregate.rowCount--
+		for j := range columnToAggregate {
+			l := len(aggregate.aggregations[j].arrays)
+			aggregate.aggregations[j].arrays = aggregate.aggregations[j].arrays[:l-1]
+		}
+
+		// Create new aggregation
+		aggregations := make([]Aggregation, 0, len(a.aggregates[0].aggregations))
+		for _, agg := range a.aggregates[0].aggregations {
+			aggregations = append(aggregations, agg.withoutArrays())
+		}
+		a.aggregates = append(a.aggregates, &hashAggregate{
+			aggregations: aggregations,
+			groupByCols:  map[string]builder.ColumnBuilder{},
+			colOrdering:  []string{},
+		})
+
+		aggregate = a.aggregates[len(a.aggregates)-1]
+		for j, col := range columnToAggregate {
+			agg := a.newBuilder(aggregationArrayType(col, aggregate.aggregations[j]))
+			aggregate.aggregations[j].arrays = append(aggregate.aggregations[j].arrays, agg)
+		}
+		tuple = hashtuple{
+			aggregate: len(a.aggregates) - 1, // always add new aggregates to the current aggregate
+			array:     len(aggregate.aggregations[0].arrays) - 1,
+		}
+		a.hashToAggregate[hash] = tuple
+		aggregate.rowCount++
+
+		if err := a.updateGroupByCols(i, groupByArrays, groupByFields); err != nil {
+			return hashtuple{}, err
+		}
+	}
+	return tuple, nil
+}
+
+// aggregationArrayType returns the type of the arrays the values of col are
+// buffered in. Columns might be missing when merging spilled state, in which
+// case the arrays hold partial aggregates of the type they were spilled with.
+func aggregationArrayType(col arrow.Array, agg Aggregation) arrow.DataType {
+	if col != nil {
+		return col.DataType()
+	}
+	if agg.arrayType != nil {
+		return agg.arrayType
+	}
+	return arrow.PrimitiveTypes.Int64
+}
+
 func (a *HashAggregate) updateGroupByCols(row int, groupByArrays []arrow.Array, groupByFields []arrow.Field) error {
 	// aggregate is the current aggregation
 	aggregate := a.aggregates[len(a.aggregates)-1]
@@ -539,7 +821,7 @@ func (a *HashAggregate) updateGroupByCols(row int, groupByArrays []arrow.Array,
 
 		groupByCol, found := aggregate.groupByCols[fieldName]
 		if !found {
-			groupByCol = builder.NewBuilder(a.pool, groupByFields[i].Type)
+			groupByCol = a.newBuilder(groupByFields[i].Type)
 			aggregate.groupByCols[fieldName] = groupByCol
 			aggregate.colOrdering = append(aggregate.colOrdering, fieldName)
 		}
@@ -570,9 +852,19 @@ func (a *HashAggregate) Finish(ctx context.Context) error {
 	span.SetAttributes(attribute.Bool("finalStage", a.finalStage))
 	defer span.End()
 
+	if a.spill != nil && a.spill.spilled() {
+		span.SetAttributes(attribute.Int64("spilledBytes", a.spill.written.Load()))
+		totalRows, err := a.finishSpilled(ctx)
+		if err != nil {
+			return err
+		}
+		span.SetAttributes(attribute.Int64("rows", int64(totalRows)))
+		return a.next.Finish(ctx)
+	}
+
 	totalRows := 0
 	for i, aggregate := range a.aggregates {
-		if err := a.finishAggregate(ctx, i, aggregate); err != nil {
+		if err := a.finishAggregate(ctx, i, aggregate, false); err != nil {
 			return err
 		}
 		totalRows += aggregate.rowCount
@@ -581,7 +873,10 @@ func (a *HashAggregate) Finish(ctx context.Context) error {
 	return a.next.Finish(ctx)
 }
 
-func (a *HashAggregate) finishAggregate(ctx context.Context, aggIdx int, aggregate *hashAggregate) error {
+// finishAggregate passes on the aggregated groups of the given aggregate.
+// mergePartials indicates that the aggregation arrays hold partial aggregates
+// that were spilled to disk rather than raw values.
+func (a *HashAggregate) finishAggregate(ctx context.Context, aggIdx int, aggregate *hashAggregate, mergePartials bool) error {
 	numCols := len(aggregate.groupByCols) + len(aggregate.aggregations)
 	numRows := aggregate.rowCount
 
@@ -638,18 +933,20 @@ func (a *HashAggregate) finishAggregate(ctx context.Context, aggIdx int, aggrega
 	// Rename to clarity upon appending aggregations later
 	aggregateFields := groupByFields
 
-	for _, aggregation := range aggregate.aggregations {
-		arr := make([]arrow.Array, 0, numRows)
-		for _, a := range aggregation.arrays {
-			arr = append(arr, a.NewArray())
-		}
-
-		aggregateArray, err := runAggregation(a.finalStage, aggregation.function, a.pool, arr)
-		for _, a := range arr {
-			a.Release()
+	final := a.finalStage && !a.partialOutput
+	results, err := a.aggregateGroups(aggregate, a.finalStage || mergePartials, final)
+	if err != nil {
+		return err
+	}
+	for i, aggregation := range aggregate.aggregations {
+		aggregateArray := results[i]
+		if aggregation.rawInput {
+			continue
 		}
-		if err != nil {
-			return fmt.Errorf("aggregate batched arrays: %w", err)
+		if final && aggregation.hidden {
+			// The order keys are only needed to merge partial results.
+			aggregateArray.Release()
+			continue
 		}
 		groupByArrays = append(groupByArrays, aggregateArray)
 
@@ -664,7 +961,7 @@ func (a *HashAggregate) finishAggregate(ctx context.Context, aggIdx int, aggrega
 		int64(numRows),
 	)
 	defer r.Release()
-	err := a.next.Callback(ctx, r)
+	err = a.next.Callback(ctx, r)
 	if err != nil {
 		return err
 	}
@@ -672,6 +969,82 @@ func (a *HashAggregate) finishAggregate(ctx context.Context, aggIdx int, aggrega
 	return nil
 }
 
+// aggregateGroups runs each aggregation of the aggregate over the values
+// buffered for each group. The values of first and last aggregations are
+// picked together with their order keys, which are the results of the hidden
+// aggregations. partialInputs indicates that the buffered values are partial
+// aggregates, finalStage that the results are final rather than partial.
+func (a *HashAggregate) aggregateGroups(aggregate *hashAggregate, partialInputs, finalStage bool) ([]arrow.Array, error) {
+	newArrays := func(builders []builder.ColumnBuilder) []arrow.Array {
+		arrs := make([]arrow.Array, 0, len(builders))
+		for _, b := range builders {
+			arrs = append(arrs, b.NewArray())
+		}
+		return arrs
+	}
+	release := func(arrs []arrow.Array) {
+		for _, arr := range arrs {
+			if arr != nil {
+				arr.Release()
+			}
+		}
+	}
+
+	results := make([]arrow.Array, len(aggregate.aggregations))
+	for i, aggregation := range aggregate.aggregations {
+		if aggregation.hidden {
+			continue
+		}
+
+		hidden := func(name string) int {
+			return slices.IndexFunc(aggregate.aggregations, func(agg Aggregation) bool {
+				return agg.resultName == name
+			})
+		}
+
+		values := newArrays(aggregation.arrays)
+		newState, typ, err := newPartialState(aggregation)
+		switch {
+		case err != nil:
+		case aggregation.orderKey != "":
+			j := hidden(aggregation.orderKey)
+			if j == -1 {
+				release(values)
+				release(results)
+				return nil, fmt.Errorf("order keys of %s not found", aggregation.resultName)
+			}
+			keys := newArrays(aggregate.aggregations[j].arrays)
+			results[i], results[j], err = pickOrderedValues(
+				aggregation.function, a.pool, aggregationArrayType(nil, aggregation), values, keys,
+			)
+			release(keys)
+		case newState != nil:
+			var paired []arrow.Array
+			if aggregation.pairedInput != "" && !partialInputs {
+				j := hidden(aggregation.pairedInput)
+				if j == -1 {
+					release(values)
+					release(results)
+					return nil, fmt.Errorf("paired values of %s not found", aggregation.resultName)
+				}
+				paired = newArrays(aggregate.aggregations[j].arrays)
+			}
+			results[i], err = runPartialStateAggregation(
+				aggregation.function, a.pool, typ, newState, values, paired, partialInputs, finalStage,
+			)
+			release(paired)
+		default:
+			results[i], err = runAggregation(partialInputs, aggregation.function, a.pool, values)
+		}
+		release(values)
+		if err != nil {
+			release(results)
+			return nil, fmt.Errorf("aggregate batched arrays: %w", err)
+		}
+	}
+	return results, nil
+}
+
 type Int64SumAggregation struct{}
 
 var ErrUnsupportedSumType = errors.New("unsupported type for sum aggregation, expected int64")
@@ -704,6 +1077,22 @@ func sumInt64array(arr *array.Int64) int64 {
 	return math.Int64.Sum(arr)
 }
 
+type Float64SumAggregation struct{}
+
+func (a *Float64SumAggregation) Aggregate(pool memory.Allocator, arrs []arrow.Array) (arrow.Array, error) {
+	res := array.NewFloat64Builder(pool)
+	defer res.Release()
+	for _, arr := range arrs {
+		floats, ok := arr.(*array.Float64)
+		if !ok {
+			return nil, fmt.Errorf("sum array of %s: unsupported type for sum aggregation, expected float64", arr.DataType())
+		}
+		res.Append(math.Float64.Sum(floats))
+	}
+
+	return res.NewArray(), nil
+}
+
 var ErrUnsupportedMinType = errors.New("unsupported type for max aggregation, expected int64")
 
 type Int64MinAggregation struct{}
@@ -815,6 +1204,59 @@ func (a *CountAggregation) Aggregate(pool memory.Allocator, arrs []arrow.Array)
 	return res.NewArray(), nil
 }
 
+// pickOrderedValues returns the value of each group with the lowest order key
+// for first aggregations or the highest order key for last aggregations,
+// along with that key. values[i] and keys[i] hold the values of group i and
+// their int64 order keys. Values with null keys are ignored. typ is the type
+// of the values, which the empty result has if there are no groups.
+func pickOrderedValues(
+	fn logicalplan.AggFunc, pool memory.Allocator, typ arrow.DataType, values, keys []arrow.Array,
+) (arrow.Array, arrow.Array, error) {
+	if len(values) != len(keys) {
+		return nil, nil, fmt.Errorf("%s of %d groups with %d groups of order keys", fn, len(values), len(keys))
+	}
+	if len(values) > 0 {
+		typ = values[0].DataType()
+	}
+
+	keyBuilder := array.NewInt64Builder(pool)
+	defer keyBuilder.Release()
+	valueBuilder := builder.NewBuilder(pool, typ)
+	defer valueBuilder.Release()
+	for i, arr := range values {
+		groupKeys, ok := keys[i].(*array.Int64)
+		if !ok {
+			return nil, nil, fmt.Errorf("unsupported order key of type: %s", keys[i].DataType())
+		}
+		if groupKeys.Len() != arr.Len() {
+			return nil, nil, fmt.Errorf("%s of %d values with %d order keys", fn, arr.Len(), groupKeys.Len())
+		}
+
+		picked := -1
+		for j := 0; j < groupKeys.Len(); j++ {
+			if groupKeys.IsNull(j) {
+				continue
+			}
+			switch {
+			case picked == -1,
+				fn == logicalplan.AggFuncFirst && groupKeys.Value(j) < groupKeys.Value(picked),
+				fn == logicalplan.AggFuncLast && groupKeys.Value(j) >= groupKeys.Value(picked):
+				picked = j
+			}
+		}
+		if picked == -1 {
+			valueBuilder.AppendNull()
+			keyBuilder.AppendNull()
+			continue
+		}
+		if err := builder.AppendValue(valueBuilder, arr, picked); err != nil {
+			return nil, nil, err
+		}
+		keyBuilder.Append(groupKeys.Value(picked))
+	}
+	return valueBuilder.NewArray(), keyBuilder.NewArray(), nil
+}
+
 // runAggregation is a helper to run the given aggregation function given
 // the set of values. It is aware of the final stage and chooses the aggregation
 // function appropriately.
@@ -835,20 +1277,3 @@ func runAggregation(finalStage bool, fn logicalplan.AggFunc, pool memory.Allocat
 	}
 	return aggFunc.Aggregate(pool, arrs)
 }
-
-func resultNameWithConcreteColumn(function logicalplan.AggFunc, col string) string {
-	switch function {
-	case logicalplan.AggFuncSum:
-		return logicalplan.Sum(logicalplan.Col(col)).Name()
-	case logicalplan.AggFuncMin:
-		return logicalplan.Min(logicalplan.Col(col)).Name()
-	case logicalplan.AggFuncMax:
-		return logicalplan.Max(logicalplan.Col(col)).Name()
-	case logicalplan.AggFuncCount:
-		return logicalplan.Count(logicalplan.Col(col)).Name()
-	case logicalplan.AggFuncAvg:
-		return logicalplan.Avg(logicalplan.Col(col)).Name()
-	default:
-		return ""
-	}
-}
@@ -115,3 +115,16 @@ func Test_Aggregate_ArrayOverflow(t *testing.T) {
 	require.NoError(t, agg.Finish(ctx))
 	require.Equal(t, int64(n*rows), totalRows)
 }
+
+func TestPickOrderedValuesWithoutGroups(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	values, keys, err := pickOrderedValues(logicalplan.AggFuncFirst, mem, arrow.BinaryTypes.String, nil, nil)
+	require.NoError(t, err)
+	defer values.Release()
+	defer keys.Release()
+	require.Equal(t, arrow.BinaryTypes.String, values.DataType())
+	require.Equal(t, 0, values.Len())
+	require.Equal(t, arrow.PrimitiveTypes.Int64, keys.DataType())
+}
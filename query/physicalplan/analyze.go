@@ -0,0 +1,242 @@
+package physicalplan
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+
+	"github.com/polarsignals/frostdb/query/expr"
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+// OperatorStats are the runtime statistics of a stage of the physical plan.
+// A stage may consist of multiple concurrent instances of the same operator,
+// in which case the stats are aggregated across all instances.
+type OperatorStats struct {
+	InputRows      atomic.Int64
+	InputRecords   atomic.Int64
+	OutputRows     atomic.Int64
+	OutputRecords  atomic.Int64
+	BytesAllocated atomic.Int64
+
+	// Scan is only set for scan operators.
+	Scan *expr.ScanStats
+
+	// busy is the time spent in the operator summed across all instances,
+	// including the time spent in downstream operators that are called
+	// synchronously. downstream is the time spent in those downstream
+	// operators, the difference of both is the operator's CPU time.
+	busy       atomic.Int64
+	downstream atomic.Int64
+	// start and end are the unix nanosecond timestamps of the first and last
+	// time the operator was active.
+	start atomic.Int64
+	end   atomic.Int64
+}
+
+// WallTime returns the time between the operator first receiving data and it
+// finishing.
+func (s *OperatorStats) WallTime() time.Duration {
+	start, end := s.start.Load(), s.end.Load()
+	if start == 0 || end < start {
+		return 0
+	}
+	return time.Duration(end - start)
+}
+
+// CPUTime returns the time spent executing the operator across all of its
+// concurrent instances, excluding time spent in downstream operators. It is
+// not tracked for scans as they are driven by the table iterator.
+func (s *OperatorStats) CPUTime() time.Duration {
+	return time.Duration(s.busy.Load() - s.downstream.Load())
+}
+
+func (s *OperatorStats) observeInput(r arrow.Record) {
+	s.InputRows.Add(r.NumRows())
+	s.InputRecords.Add(1)
+}
+
+func (s *OperatorStats) observeOutput(r arrow.Record) {
+	s.OutputRows.Add(r.NumRows())
+	s.OutputRecords.Add(1)
+}
+
+func (s *OperatorStats) observeActivity(start, end time.Time) {
+	s.start.CompareAndSwap(0, start.UnixNano())
+	for {
+		prev := s.end.Load()
+		if prev >= end.UnixNano() || s.end.CompareAndSwap(prev, end.UnixNano()) {
+			return
+		}
+	}
+}
+
+func (s *OperatorStats) String() string {
+	fields := make([]string, 0, 10)
+	if s.Scan != nil {
+		fields = append(fields,
+			fmt.Sprintf("parts=%d", s.Scan.PartsConsidered.Load()),
+			fmt.Sprintf("parts_pruned=%d", s.Scan.PartsPruned.Load()),
+			fmt.Sprintf("row_groups=%d", s.Scan.RowGroupsConsidered.Load()),
+			fmt.Sprintf("row_groups_pruned=%d", s.Scan.RowGroupsPruned.Load()),
+		)
+	} else {
+		fields = append(fields,
+			fmt.Sprintf("rows_in=%d", s.InputRows.Load()),
+			fmt.Sprintf("records_in=%d", s.InputRecords.Load()),
+		)
+	}
+	fields = append(fields,
+		fmt.Sprintf("rows_out=%d", s.OutputRows.Load()),
+		fmt.Sprintf("records_out=%d", s.OutputRecords.Load()),
+		fmt.Sprintf("alloc_bytes=%d", s.BytesAllocated.Load()),
+		fmt.Sprintf("wall=%s", s.WallTime()),
+	)
+	if s.Scan == nil {
+		fields = append(fields, fmt.Sprintf("cpu=%s", s.CPUTime()))
+	}
+	return "[" + strings.Join(fields, " ") + "]"
+}
+
+// analyzeOperator wraps an operator of a stage to collect the stage's
+// OperatorStats.
+type analyzeOperator struct {
+	stats *OperatorStats
+	op    PhysicalPlan
+}
+
+func (a *analyzeOperator) Callback(ctx context.Context, r arrow.Record) error {
+	a.stats.observeInput(r)
+	start := time.Now()
+	err := a.op.Callback(ctx, r)
+	end := time.Now()
+	a.stats.busy.Add(int64(end.Sub(start)))
+	a.stats.observeActivity(start, end)
+	return err
+}
+
+func (a *analyzeOperator) Finish(ctx context.Context) error {
+	start := time.Now()
+	err := a.op.Finish(ctx)
+	end := time.Now()
+	a.stats.busy.Add(int64(end.Sub(start)))
+	a.stats.observeActivity(start, end)
+	return err
+}
+
+func (a *analyzeOperator) SetNext(next PhysicalPlan) {
+	a.op.SetNext(&analyzeOutput{stats: a.stats, next: next})
+}
+
+func (a *analyzeOperator) Draw() *Diagram {
+	d := a.op.Draw()
+	if d != nil {
+		d.Stats = a.stats
+	}
+	return d
+}
+
+func (a *analyzeOperator) Close() {
+	a.op.Close()
+}
+
+// analyzeOutput sits between an analyzed operator and its next operator to
+// count the output and measure the time spent downstream.
+type analyzeOutput struct {
+	stats *OperatorStats
+	next  PhysicalPlan
+}
+
+func (a *analyzeOutput) Callback(ctx context.Context, r arrow.Record) error {
+	a.stats.observeOutput(r)
+	start := time.Now()
+	defer func() { a.stats.downstream.Add(int64(time.Since(start))) }()
+	return a.next.Callback(ctx, r)
+}
+
+func (a *analyzeOutput) Finish(ctx context.Context) error {
+	start := time.Now()
+	defer func() { a.stats.downstream.Add(int64(time.Since(start))) }()
+	return a.next.Finish(ctx)
+}
+
+func (a *analyzeOutput) SetNext(next PhysicalPlan) {
+	a.next = next
+}
+
+func (a *analyzeOutput) Draw() *Diagram {
+	return a.next.Draw()
+}
+
+func (a *analyzeOutput) Close() {
+	a.next.Close()
+}
+
+// analyzeCallbacks wraps the callbacks a scan pushes its results to in order
+// to count the scan's output.
+func analyzeCallbacks(stats *OperatorStats, callbacks []logicalplan.Callback) []logicalplan.Callback {
+	if stats == nil {
+		return callbacks
+	}
+	wrapped := make([]logicalplan.Callback, 0, len(callbacks))
+	for _, callback := range callbacks {
+		callback := callback
+		wrapped = append(wrapped, func(ctx context.Context, r arrow.Record) error {
+			stats.observeOutput(r)
+			return callback(ctx, r)
+		})
+	}
+	return wrapped
+}
+
+// analyzeAllocator counts the bytes allocated by the operators of a stage.
+type analyzeAllocator struct {
+	memory.Allocator
+	stats *OperatorStats
+}
+
+func (a *analyzeAllocator) Allocate(size int) []byte {
+	a.stats.BytesAllocated.Add(int64(size))
+	return a.Allocator.Allocate(size)
+}
+
+func (a *analyzeAllocator) Reallocate(size int, b []byte) []byte {
+	if grown := size - len(b); grown > 0 {
+		a.stats.BytesAllocated.Add(int64(grown))
+	}
+	return a.Allocator.Reallocate(size, b)
+}
+
+// stage holds the stats and allocator of a stage of the physical plan. The
+// stats are nil if the plan is not being analyzed. The allocator attributes
+// its allocations to the stage's operator if the plan's allocator supports it.
+type stage struct {
+	stats *OperatorStats
+	pool  memory.Allocator
+}
+
+func newStage(analyze bool, pool memory.Allocator, operator string) stage {
+	pool = operatorPool(pool, operator)
+	if !analyze {
+		return stage{pool: pool}
+	}
+	stats := &OperatorStats{}
+	return stage{
+		stats: stats,
+		pool:  &analyzeAllocator{Allocator: pool, stats: stats},
+	}
+}
+
+// instrument wraps the operator to collect the stage's stats if the plan is
+// being analyzed.
+func (s stage) instrument(p PhysicalPlan) PhysicalPlan {
+	if s.stats == nil {
+		return p
+	}
+	return &analyzeOperator{stats: s.stats, op: p}
+}
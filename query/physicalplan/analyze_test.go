@@ -0,0 +1,92 @@
+package physicalplan
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/polarsignals/frostdb/dynparquet"
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+type recordTableReader struct {
+	mockTableReader
+	record arrow.Record
+}
+
+func (r *recordTableReader) View(ctx context.Context, fn func(ctx context.Context, tx uint64) error) error {
+	return fn(ctx, 0)
+}
+
+func (r *recordTableReader) Iterator(
+	ctx context.Context,
+	_ uint64,
+	_ memory.Allocator,
+	callbacks []logicalplan.Callback,
+	_ ...logicalplan.Option,
+) error {
+	return callbacks[0](ctx, r.record)
+}
+
+type recordTableProvider struct {
+	reader *recordTableReader
+}
+
+func (p *recordTableProvider) GetTable(_ string) (logicalplan.TableReader, error) {
+	return p.reader, nil
+}
+
+func TestAnalyze(t *testing.T) {
+	record, err := dynparquet.NewTestSamples().ToRecord()
+	require.NoError(t, err)
+	defer record.Release()
+
+	schema := dynparquet.NewSampleSchema()
+	p, err := (&logicalplan.Builder{}).
+		Scan(&recordTableProvider{reader: &recordTableReader{
+			mockTableReader: mockTableReader{schema: schema},
+			record:          record,
+		}}, "table1").
+		Filter(logicalplan.Col("labels.node").Eq(logicalplan.Literal("test3"))).
+		Build()
+	require.NoError(t, err)
+
+	pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer pool.AssertSize(t, 0)
+	plan, err := Build(
+		context.Background(),
+		pool,
+		trace.NewNoopTracerProvider().Tracer(""),
+		schema,
+		p,
+		WithAnalyze(),
+	)
+	require.NoError(t, err)
+
+	rows := int64(0)
+	require.NoError(t, plan.Execute(context.Background(), pool, func(_ context.Context, r arrow.Record) error {
+		rows += r.NumRows()
+		return nil
+	}))
+	require.Equal(t, int64(1), rows)
+
+	scan := plan.scan.Draw()
+	require.NotNil(t, scan.Stats)
+	require.Equal(t, int64(3), scan.Stats.OutputRows.Load())
+	require.Equal(t, int64(1), scan.Stats.OutputRecords.Load())
+	require.Greater(t, scan.Stats.WallTime(), time.Duration(0))
+
+	filter := scan.Child
+	require.NotNil(t, filter.Stats)
+	require.Equal(t, int64(3), filter.Stats.InputRows.Load())
+	require.Equal(t, int64(1), filter.Stats.OutputRows.Load())
+	require.Greater(t, filter.Stats.BytesAllocated.Load(), int64(0))
+	require.GreaterOrEqual(t, filter.Stats.CPUTime(), time.Duration(0))
+
+	require.Contains(t, plan.AnalyzeString(), "PredicateFilter (labels.node == test3) [rows_in=3 records_in=1 rows_out=1 records_out=1")
+}
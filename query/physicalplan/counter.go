@@ -0,0 +1,309 @@
+package physicalplan
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+// counterState summarizes the samples of a counter, added in the order of
+// their timestamps, by the first and last samples and how much the counter
+// increased between them. Timestamps are in milliseconds.
+type counterState struct {
+	count    int64
+	firstT   int64
+	firstV   float64
+	lastT    int64
+	lastV    float64
+	increase float64
+}
+
+// counterIncrease returns how much a counter increased from prev to cur. A
+// decrease is a counter reset, after which the counter increased from zero.
+func counterIncrease(prev, cur float64) float64 {
+	if cur < prev {
+		return cur
+	}
+	return cur - prev
+}
+
+// add adds the sample following the samples of the state.
+func (s *counterState) add(t int64, v float64) {
+	if s.count == 0 {
+		*s = counterState{count: 1, firstT: t, firstV: v, lastT: t, lastV: v}
+		return
+	}
+	s.increase += counterIncrease(s.lastV, v)
+	s.count++
+	s.lastT, s.lastV = t, v
+}
+
+// extrapolatedIncrease returns the increase of the counter extrapolated to the
+// window of the given duration the samples fall into, the way Prometheus
+// extrapolates the increase within a range. The increase is extrapolated to
+// a window bound if the samples come close to it, otherwise by half the
+// average interval between the samples. It is never extrapolated below zero.
+// False is returned if there are fewer than two samples.
+func (s *counterState) extrapolatedIncrease(window time.Duration) (float64, bool) {
+	if s.count < 2 {
+		return 0, false
+	}
+	windowMillis := window.Milliseconds()
+	start := s.firstT / windowMillis * windowMillis
+	end := start + windowMillis
+
+	sampledInterval := float64(s.lastT - s.firstT)
+	if sampledInterval == 0 {
+		return 0, false
+	}
+	averageInterval := sampledInterval / float64(s.count-1)
+	durationToStart := float64(s.firstT - start)
+	durationToEnd := float64(end - s.lastT)
+	if s.increase > 0 && s.firstV >= 0 {
+		// The counter started at zero at the earliest.
+		if durationToZero := sampledInterval * (s.firstV / s.increase); durationToZero < durationToStart {
+			durationToStart = durationToZero
+		}
+	}
+
+	threshold := averageInterval * 1.1
+	interval := sampledInterval
+	if durationToStart < threshold {
+		interval += durationToStart
+	} else {
+		interval += averageInterval / 2
+	}
+	if durationToEnd < threshold {
+		interval += durationToEnd
+	} else {
+		interval += averageInterval / 2
+	}
+	return s.increase * interval / sampledInterval, true
+}
+
+// join adds the samples of the run r, whose first sample doesn't precede the
+// first sample of the state. If the samples of the two overlap in time, their
+// order is unknown, and the counter is assumed not to be reset within the
+// overlap: the increase from the last sample of the state to the last sample
+// of r is added, which is exact for counters that only increase.
+func (s *counterState) join(r counterState) {
+	if s.count == 0 {
+		*s = r
+		return
+	}
+	s.count += r.count
+	if r.firstT > s.lastT {
+		s.increase += counterIncrease(s.lastV, r.firstV) + r.increase
+		s.lastT, s.lastV = r.lastT, r.lastV
+		return
+	}
+	if r.lastT > s.lastT {
+		s.increase += counterIncrease(s.lastV, r.lastV)
+		s.lastT, s.lastV = r.lastT, r.lastV
+	}
+}
+
+// counterSample is a sample of a counter at a timestamp in milliseconds.
+type counterSample struct {
+	t int64
+	v float64
+}
+
+const (
+	// counterStateSize is the size of an encoded counterState.
+	counterStateSize = 6 * 8
+	// counterSampleSize is the size of an encoded counterSample.
+	counterSampleSize = 2 * 8
+)
+
+// counterAggregationState is the partialState of rate and increase
+// aggregations over samples grouped into windows of the duration. Samples
+// that follow each other in time are summarized by runs. Samples that fall
+// into the time range of a run are buffered, so that the runs the samples
+// of other runs or partial states fall in between can be combined exactly.
+// Runs of different partial states that overlap in time can't, and are
+// joined assuming that the counter isn't reset within the overlap.
+type counterAggregationState struct {
+	fn     logicalplan.AggFunc
+	window time.Duration
+	// runs are ordered by their first timestamps and don't overlap once the
+	// state is compacted.
+	runs []counterState
+	// samples are the samples that don't follow the last sample of the last
+	// run, or that fall into the time range of a run once compacted.
+	samples []counterSample
+}
+
+// add adds the samples with the values of arr and the timestamps of ts.
+// Samples with null values or timestamps are skipped. The samples of a series
+// mostly arrive in the order of their timestamps, since tables are sorted by
+// series, so that they extend the last run.
+func (c *counterAggregationState) add(arr, ts arrow.Array) error {
+	values, err := float64Values(arr)
+	if err != nil {
+		return err
+	}
+	timestamps, ok := ts.(*array.Int64)
+	if !ok {
+		return fmt.Errorf("unsupported timestamps of type: %s", ts.DataType())
+	}
+	if timestamps.Len() != arr.Len() {
+		return fmt.Errorf("%d values with %d timestamps", arr.Len(), timestamps.Len())
+	}
+
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) || timestamps.IsNull(i) {
+			continue
+		}
+		t, v := timestamps.Value(i), values(i)
+		switch last := len(c.runs) - 1; {
+		case last == -1:
+			c.runs = append(c.runs, counterState{count: 1, firstT: t, firstV: v, lastT: t, lastV: v})
+		case t > c.runs[last].lastT:
+			c.runs[last].add(t, v)
+		default:
+			c.samples = append(c.samples, counterSample{t: t, v: v})
+		}
+	}
+	return nil
+}
+
+func (c *counterAggregationState) merge(b []byte) error {
+	if len(b) < 8 {
+		return fmt.Errorf("invalid counter state of %d bytes", len(b))
+	}
+	u := func(i int) uint64 {
+		return binary.LittleEndian.Uint64(b[i:])
+	}
+	runs := int(u(0))
+	b = b[8:]
+	if runs < 0 || runs > len(b)/counterStateSize || (len(b)-runs*counterStateSize)%counterSampleSize != 0 {
+		return fmt.Errorf("invalid counter state of %d runs in %d bytes", runs, len(b))
+	}
+	for i := 0; i < runs*counterStateSize; i += counterStateSize {
+		c.runs = append(c.runs, counterState{
+			count:    int64(u(i)),
+			firstT:   int64(u(i + 8)),
+			firstV:   math.Float64frombits(u(i + 16)),
+			lastT:    int64(u(i + 24)),
+			lastV:    math.Float64frombits(u(i + 32)),
+			increase: math.Float64frombits(u(i + 40)),
+		})
+	}
+	for i := runs * counterStateSize; i < len(b); i += counterSampleSize {
+		c.samples = append(c.samples, counterSample{
+			t: int64(u(i)),
+			v: math.Float64frombits(u(i + 8)),
+		})
+	}
+	return nil
+}
+
+func (c *counterAggregationState) encode() []byte {
+	c.compact()
+	b := make([]byte, 0, 8+len(c.runs)*counterStateSize+len(c.samples)*counterSampleSize)
+	b = binary.LittleEndian.AppendUint64(b, uint64(len(c.runs)))
+	for _, r := range c.runs {
+		b = binary.LittleEndian.AppendUint64(b, uint64(r.count))
+		b = binary.LittleEndian.AppendUint64(b, uint64(r.firstT))
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(r.firstV))
+		b = binary.LittleEndian.AppendUint64(b, uint64(r.lastT))
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(r.lastV))
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(r.increase))
+	}
+	for _, s := range c.samples {
+		b = binary.LittleEndian.AppendUint64(b, uint64(s.t))
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(s.v))
+	}
+	return b
+}
+
+// compact orders the runs by time and joins the ones that overlap. Buffered
+// samples that don't fall into the time range of a run are summarized by new
+// runs. Runs and samples are ordered by their values as well, so that the
+// result doesn't depend on the order in which partial states were merged.
+func (c *counterAggregationState) compact() {
+	sort.Slice(c.runs, func(i, j int) bool {
+		a, b := c.runs[i], c.runs[j]
+		if a.firstT != b.firstT {
+			return a.firstT < b.firstT
+		}
+		if a.lastT != b.lastT {
+			return a.lastT < b.lastT
+		}
+		if a.firstV != b.firstV {
+			return a.firstV < b.firstV
+		}
+		return a.lastV < b.lastV
+	})
+	runs := c.runs[:0]
+	for _, r := range c.runs {
+		if last := len(runs) - 1; last >= 0 && r.firstT <= runs[last].lastT {
+			runs[last].join(r)
+			continue
+		}
+		runs = append(runs, r)
+	}
+
+	less := func(i, j int) bool {
+		if c.samples[i].t != c.samples[j].t {
+			return c.samples[i].t < c.samples[j].t
+		}
+		return c.samples[i].v < c.samples[j].v
+	}
+	if !sort.SliceIsSorted(c.samples, less) {
+		sort.Slice(c.samples, less)
+	}
+	// Samples between the same two runs are summarized by a single run.
+	samples := c.samples[:0]
+	c.runs = runs
+	gap := -1
+	for _, s := range c.samples {
+		// i is the index of the first run starting after the sample.
+		i := sort.Search(len(runs), func(i int) bool { return runs[i].firstT > s.t })
+		if i > 0 && s.t <= runs[i-1].lastT {
+			samples = append(samples, s)
+			continue
+		}
+		if i != gap {
+			gap = i
+			c.runs = append(c.runs, counterState{count: 1, firstT: s.t, firstV: s.v, lastT: s.t, lastV: s.v})
+			continue
+		}
+		c.runs[len(c.runs)-1].add(s.t, s.v)
+	}
+	c.samples = samples
+	if len(c.runs) > len(runs) {
+		sort.Slice(c.runs, func(i, j int) bool { return c.runs[i].firstT < c.runs[j].firstT })
+	}
+}
+
+// final appends the rate or increase of the samples. Samples that fall into
+// the time range of a run are only counted, as their order relative to the
+// samples of the run is unknown.
+func (c *counterAggregationState) final(b array.Builder) error {
+	c.compact()
+	var s counterState
+	for _, r := range c.runs {
+		s.join(r)
+	}
+	s.count += int64(len(c.samples))
+
+	increase, ok := s.extrapolatedIncrease(c.window)
+	if !ok {
+		b.AppendNull()
+		return nil
+	}
+	if c.fn == logicalplan.AggFuncRate {
+		increase /= c.window.Seconds()
+	}
+	b.(*array.Float64Builder).Append(increase)
+	return nil
+}
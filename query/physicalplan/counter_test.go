@@ -0,0 +1,142 @@
+package physicalplan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+func TestCounterAggregationStateMerge(t *testing.T) {
+	timestamps := []int64{5000, 15000, 25000, 35000, 45000, 55000}
+	// The counter is reset twice, once right at the third sample.
+	resets := []float64{4, 8, 2, 6, 1, 3}
+	monotonic := []float64{4, 8, 10, 16, 17, 20}
+
+	final := func(s *counterAggregationState) float64 {
+		b := array.NewFloat64Builder(memory.DefaultAllocator)
+		defer b.Release()
+		require.NoError(t, s.final(b))
+		arr := b.NewFloat64Array()
+		defer arr.Release()
+		return arr.Value(0)
+	}
+	add := func(s *counterAggregationState, values []float64, indices ...int) {
+		ts := array.NewInt64Builder(memory.DefaultAllocator)
+		defer ts.Release()
+		vs := array.NewFloat64Builder(memory.DefaultAllocator)
+		defer vs.Release()
+		for _, i := range indices {
+			ts.Append(timestamps[i])
+			vs.Append(values[i])
+		}
+		tsArr, vsArr := ts.NewArray(), vs.NewArray()
+		defer tsArr.Release()
+		defer vsArr.Release()
+		require.NoError(t, s.add(vsArr, tsArr))
+	}
+	merge := func(values []float64, split [2][]int, order [2]int) float64 {
+		merged := &counterAggregationState{fn: logicalplan.AggFuncIncrease, window: time.Minute}
+		for _, i := range order {
+			partial := &counterAggregationState{}
+			add(partial, values, split[i]...)
+			require.NoError(t, merged.merge(partial.encode()))
+		}
+		return final(merged)
+	}
+
+	indices := []int{0, 1, 2, 3, 4, 5}
+	all := &counterAggregationState{fn: logicalplan.AggFuncIncrease, window: time.Minute}
+	add(all, resets, indices...)
+	expected := final(all)
+	require.InDelta(t, (4.0+2+4+1+2)*60/50, expected, 1e-9)
+	// Ordered samples are encoded as a single run.
+	require.Len(t, all.encode(), 8+counterStateSize)
+
+	// Samples that don't arrive in order are buffered and summarized once
+	// they are ordered.
+	reversed := &counterAggregationState{fn: logicalplan.AggFuncIncrease, window: time.Minute}
+	add(reversed, resets, 5, 4, 3, 2, 1, 0)
+	require.Equal(t, expected, final(reversed))
+	reversed = &counterAggregationState{fn: logicalplan.AggFuncIncrease, window: time.Minute}
+	add(reversed, resets, 3, 4, 5, 0, 1, 2)
+	require.Equal(t, expected, final(reversed))
+	require.Equal(t, expected, merge(resets, [2][]int{{3, 4, 5, 0, 1, 2}, nil}, [2]int{0, 1}))
+
+	// The partial states are split at any sample, so that their runs
+	// don't overlap.
+	for i := 0; i <= len(indices); i++ {
+		split := [2][]int{indices[:i], indices[i:]}
+		for _, order := range [][2]int{{0, 1}, {1, 0}} {
+			require.Equal(t, expected, merge(resets, split, order), "split %v", split)
+		}
+	}
+
+	// The runs of interleaved partial states overlap, which is exact for
+	// counters that aren't reset within the overlap.
+	all = &counterAggregationState{fn: logicalplan.AggFuncIncrease, window: time.Minute}
+	add(all, monotonic, indices...)
+	expected = final(all)
+	for _, split := range [][2][]int{{{0, 2, 4}, {1, 3, 5}}, {{1, 4}, {0, 2, 3, 5}}} {
+		for _, order := range [][2]int{{0, 1}, {1, 0}} {
+			require.InDelta(t, expected, merge(monotonic, split, order), 1e-9, "split %v", split)
+			require.Equal(t, merge(resets, split, [2]int{0, 1}), merge(resets, split, order), "split %v", split)
+		}
+	}
+}
+
+func TestCounterStateExtrapolatedIncrease(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		timestamps []int64
+		values     []float64
+		expected   float64
+		ok         bool
+	}{
+		{
+			name:       "single sample",
+			timestamps: []int64{65000},
+			values:     []float64{10},
+		},
+		{
+			name:       "close to bounds",
+			timestamps: []int64{65000, 85000, 105000},
+			values:     []float64{10, 20, 30},
+			// Extrapolated by 5s to both bounds of the window.
+			expected: 30,
+			ok:       true,
+		},
+		{
+			name:       "far from end",
+			timestamps: []int64{61000, 71000},
+			values:     []float64{100, 110},
+			// Extrapolated by 1s to the start and by half the average
+			// interval to the end.
+			expected: 16,
+			ok:       true,
+		},
+		{
+			name:       "started at zero",
+			timestamps: []int64{65000, 75000, 85000, 95000, 105000, 115000},
+			values:     []float64{1, 11, 21, 31, 41, 51},
+			// The counter was zero 1s before the first sample, which is
+			// closer than the start of the window.
+			expected: 56,
+			ok:       true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var s counterState
+			for i := range tc.timestamps {
+				s.add(tc.timestamps[i], tc.values[i])
+			}
+			increase, ok := s.extrapolatedIncrease(time.Minute)
+			require.Equal(t, tc.ok, ok)
+			require.InDelta(t, tc.expected, increase, 1e-9)
+		})
+	}
+}
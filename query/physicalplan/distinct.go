@@ -2,6 +2,7 @@ package physicalplan
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"hash/maphash"
 	"strings"
@@ -18,12 +19,20 @@ import (
 	"github.com/polarsignals/frostdb/query/logicalplan"
 )
 
+// ErrLimitReached is returned by operators that have produced all the rows
+// they were limited to. The scan feeding them stops reading its input and
+// finishes the plan as if the input was exhausted.
+var ErrLimitReached = errors.New("limit reached")
+
 type Distinction struct {
 	pool     memory.Allocator
 	tracer   trace.Tracer
 	next     PhysicalPlan
 	columns  []logicalplan.Expr
 	hashSeed maphash.Seed
+	// limit, if set, is the number of distinct values after which no more
+	// values are passed on.
+	limit uint64
 
 	mtx  *sync.RWMutex
 	seen map[uint64]struct{}
@@ -40,15 +49,22 @@ func (d *Distinction) Draw() *Diagram {
 		columns = append(columns, c.Name())
 	}
 
-	return &Diagram{Details: fmt.Sprintf("Distinction (%s)", strings.Join(columns, ",")), Child: child}
+	details := fmt.Sprintf("Distinction (%s)", strings.Join(columns, ","))
+	if d.limit > 0 {
+		details += fmt.Sprintf(" limit %d", d.limit)
+	}
+	return &Diagram{Details: details, Child: child}
 }
 
-func Distinct(pool memory.Allocator, tracer trace.Tracer, columns []logicalplan.Expr) *Distinction {
+// Distinct returns a Distinction of the given columns. If limit is set, it
+// returns ErrLimitReached once it has passed on limit distinct values.
+func Distinct(pool memory.Allocator, tracer trace.Tracer, columns []logicalplan.Expr, limit uint64) *Distinction {
 	return &Distinction{
 		pool:     pool,
 		tracer:   tracer,
 		columns:  columns,
 		hashSeed: maphash.MakeSeed(),
+		limit:    limit,
 
 		mtx:  &sync.RWMutex{},
 		seen: make(map[uint64]struct{}),
@@ -72,6 +88,10 @@ func (d *Distinction) Callback(ctx context.Context, r arrow.Record) error {
 	// ctx, span := d.tracer.Start(ctx, "Distinction/Callback")
 	// defer span.End()
 
+	if d.limitReached() {
+		return ErrLimitReached
+	}
+
 	distinctFields := make([]arrow.Field, 0, 10)
 	distinctFieldHashes := make([]uint64, 0, 10)
 	distinctArrays := make([]arrow.Array, 0, 10)
@@ -99,6 +119,7 @@ func (d *Distinction) Callback(ctx context.Context, r arrow.Record) error {
 	rows := int64(0)
 
 	numRows := int(r.NumRows())
+	limitReached := false
 
 	colHashes := make([][]uint64, len(distinctFields))
 	for i, arr := range distinctArrays {
@@ -121,12 +142,22 @@ func (d *Distinction) Callback(ctx context.Context, r arrow.Record) error {
 			)
 		}
 
-		d.mtx.RLock()
+		// Concurrent callbacks must not both pass on the same value, nor more
+		// values than the limit, so the value is checked and marked as seen
+		// under the same lock.
+		d.mtx.Lock()
 		if _, ok := d.seen[hash]; ok {
-			d.mtx.RUnlock()
+			d.mtx.Unlock()
 			continue
 		}
-		d.mtx.RUnlock()
+		if d.limit > 0 && uint64(len(d.seen)) >= d.limit {
+			d.mtx.Unlock()
+			limitReached = true
+			break
+		}
+		d.seen[hash] = struct{}{}
+		limitReached = d.limit > 0 && uint64(len(d.seen)) >= d.limit
+		d.mtx.Unlock()
 
 		for j, arr := range distinctArrays {
 			err := builder.AppendValue(resBuilders[j], arr, i)
@@ -136,14 +167,17 @@ func (d *Distinction) Callback(ctx context.Context, r arrow.Record) error {
 		}
 
 		rows++
-		d.mtx.Lock()
-		d.seen[hash] = struct{}{}
-		d.mtx.Unlock()
+		if limitReached {
+			break
+		}
 	}
 
 	if rows == 0 {
 		// No need to call anything further down the chain, no new values were
 		// seen so we can skip.
+		if limitReached {
+			return ErrLimitReached
+		}
 		return nil
 	}
 
@@ -166,5 +200,20 @@ func (d *Distinction) Callback(ctx context.Context, r arrow.Record) error {
 	)
 
 	defer distinctRecord.Release()
-	return d.next.Callback(ctx, distinctRecord)
+	if err := d.next.Callback(ctx, distinctRecord); err != nil {
+		return err
+	}
+	if limitReached {
+		return ErrLimitReached
+	}
+	return nil
+}
+
+func (d *Distinction) limitReached() bool {
+	if d.limit == 0 {
+		return false
+	}
+	d.mtx.RLock()
+	defer d.mtx.RUnlock()
+	return uint64(len(d.seen)) >= d.limit
 }
@@ -0,0 +1,73 @@
+package physicalplan
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+func TestDistinctConcurrentCallbacks(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	b := array.NewInt64Builder(mem)
+	defer b.Release()
+	for i := 0; i < 100; i++ {
+		b.Append(int64(i))
+	}
+	values := b.NewArray()
+	defer values.Release()
+	r := array.NewRecord(arrow.NewSchema([]arrow.Field{
+		{Name: "value", Type: arrow.PrimitiveTypes.Int64},
+	}, nil), []arrow.Array{values}, 100)
+	defer r.Release()
+
+	for _, limit := range []uint64{0, 10} {
+		var (
+			mtx  sync.Mutex
+			seen []int64
+		)
+		output := &OutputPlan{}
+		output.SetNextCallback(func(_ context.Context, r arrow.Record) error {
+			mtx.Lock()
+			defer mtx.Unlock()
+			seen = append(seen, r.Column(0).(*array.Int64).Int64Values()...)
+			return nil
+		})
+		d := Distinct(mem, trace.NewNoopTracerProvider().Tracer(""), []logicalplan.Expr{logicalplan.Col("value")}, limit)
+		d.SetNext(output)
+
+		// Every callback sees the same values, which must be passed on once.
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := d.Callback(context.Background(), r); err != nil && !errors.Is(err, ErrLimitReached) {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		expected := 100
+		if limit > 0 {
+			expected = int(limit)
+		}
+		require.Len(t, seen, expected)
+		unique := make(map[int64]struct{}, len(seen))
+		for _, v := range seen {
+			unique[v] = struct{}{}
+		}
+		require.Len(t, unique, expected)
+	}
+}
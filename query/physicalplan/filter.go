@@ -205,6 +205,8 @@ func booleanExpr(expr logicalplan.Expr) (BooleanExpression, error) {
 	switch e := expr.(type) {
 	case *logicalplan.BinaryExpr:
 		return binaryBooleanExpr(e)
+	case *logicalplan.InExpr:
+		return setFilter(e)
 	default:
 		return nil, ErrUnsupportedBooleanExpression
 	}
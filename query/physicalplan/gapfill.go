@@ -0,0 +1,313 @@
+package physicalplan
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/polarsignals/frostdb/pqarrow/builder"
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+// GapFiller fills in the buckets missing from the results of an aggregation
+// grouped by a duration. Every series, identified by the values of the other
+// columns grouped by, gets a row for every bucket within the time range of
+// the gap fill. Rows of buckets outside of it are passed on as well. The
+// results are buffered until the aggregation finishes, since the buckets of a
+// series arrive in any order.
+type GapFiller struct {
+	pool       memory.Allocator
+	tracer     trace.Tracer
+	gapFill    *logicalplan.GapFill
+	groupExprs []logicalplan.Expr
+	records    []arrow.Record
+	next       PhysicalPlan
+}
+
+// GapFill returns a GapFiller for the results of an aggregation grouped by the
+// given expressions.
+func GapFill(
+	pool memory.Allocator,
+	tracer trace.Tracer,
+	gapFill *logicalplan.GapFill,
+	groupExprs []logicalplan.Expr,
+) *GapFiller {
+	return &GapFiller{
+		pool:       pool,
+		tracer:     tracer,
+		gapFill:    gapFill,
+		groupExprs: groupExprs,
+	}
+}
+
+func (g *GapFiller) Callback(_ context.Context, r arrow.Record) error {
+	r.Retain()
+	g.records = append(g.records, r)
+	return nil
+}
+
+func (g *GapFiller) Finish(ctx context.Context) error {
+	ctx, span := g.tracer.Start(ctx, "GapFiller/Finish")
+	defer span.End()
+	defer g.release()
+
+	// Records with different schemas are filled separately. All rows of a
+	// series have the same columns, so they share a schema.
+	records := g.records
+	for len(records) > 0 {
+		var same, rest []arrow.Record
+		for _, r := range records {
+			if r.Schema().Equal(records[0].Schema()) {
+				same = append(same, r)
+			} else {
+				rest = append(rest, r)
+			}
+		}
+		filled, err := g.fill(same)
+		if err != nil {
+			return err
+		}
+		err = g.next.Callback(ctx, filled)
+		filled.Release()
+		if err != nil {
+			return err
+		}
+		records = rest
+	}
+	return g.next.Finish(ctx)
+}
+
+// gapFillRow is a row of the gap filled results. It is either a row of the
+// aggregated records or a bucket that is missing from them.
+type gapFillRow struct {
+	record  int
+	row     int
+	bucket  int64
+	missing bool
+}
+
+// fill returns the rows of the records, which share a schema, with the
+// missing buckets of every series filled in.
+func (g *GapFiller) fill(records []arrow.Record) (arrow.Record, error) {
+	schema := records[0].Schema()
+	timestampIdx := -1
+	grouped := make([]bool, schema.NumFields())
+	fields := make([]arrow.Field, schema.NumFields())
+	for i, f := range schema.Fields() {
+		for _, expr := range g.groupExprs {
+			if !expr.MatchColumn(f.Name) {
+				continue
+			}
+			if _, ok := expr.(*logicalplan.DurationExpr); ok {
+				timestampIdx = i
+			} else {
+				grouped[i] = true
+			}
+		}
+		if i != timestampIdx && !grouped[i] {
+			f.Nullable = true
+		}
+		fields[i] = f
+	}
+	if timestampIdx == -1 {
+		return nil, fmt.Errorf("gap fill: timestamp column not found")
+	}
+	if fields[timestampIdx].Type.ID() != arrow.INT64 {
+		return nil, fmt.Errorf("gap fill: unsupported timestamps of type: %s", fields[timestampIdx].Type)
+	}
+
+	// Rows without a timestamp don't belong to any bucket, so they are
+	// passed on as they are.
+	step := g.gapFill.Step.Milliseconds()
+	var (
+		unbucketed []gapFillRow
+		series     [][]gapFillRow
+		seriesIdx  = map[string]int{}
+		key        []byte
+	)
+	for i, r := range records {
+		timestamps := r.Column(timestampIdx).(*array.Int64)
+		for j := 0; j < int(r.NumRows()); j++ {
+			if timestamps.IsNull(j) {
+				unbucketed = append(unbucketed, gapFillRow{record: i, row: j})
+				continue
+			}
+			key = key[:0]
+			for k, col := range r.Columns() {
+				if !grouped[k] {
+					continue
+				}
+				if col.IsNull(j) {
+					key = append(key, 0)
+					continue
+				}
+				v := col.ValueStr(j)
+				key = append(key, 1)
+				key = binary.AppendUvarint(key, uint64(len(v)))
+				key = append(key, v...)
+			}
+			idx, ok := seriesIdx[string(key)]
+			if !ok {
+				idx = len(series)
+				seriesIdx[string(key)] = idx
+				series = append(series, nil)
+			}
+			bucket := timestamps.Value(j) / step * step
+			series[idx] = append(series[idx], gapFillRow{record: i, row: j, bucket: bucket})
+		}
+	}
+
+	numRows := len(unbucketed)
+	for i, rows := range series {
+		series[i] = g.fillSeries(rows, step)
+		numRows += len(series[i])
+	}
+
+	b := builder.NewRecordBuilder(g.pool, arrow.NewSchema(fields, nil))
+	defer b.Release()
+	b.Reserve(numRows)
+
+	for _, row := range unbucketed {
+		for k := range fields {
+			if err := builder.AppendValue(b.Field(k), records[row.record].Column(k), row.row); err != nil {
+				return nil, fmt.Errorf("gap fill column %s: %w", fields[k].Name, err)
+			}
+		}
+	}
+	for _, rows := range series {
+		// The values of the columns identifying the series are taken from
+		// any of its rows. Every series has at least one.
+		var first gapFillRow
+		next := make([]int, len(rows))
+		for i, n := len(rows)-1, -1; i >= 0; i-- {
+			next[i] = n
+			if !rows[i].missing {
+				first, n = rows[i], i
+			}
+		}
+		prev := -1
+		for i, row := range rows {
+			for k := range fields {
+				var err error
+				cb := b.Field(k)
+				switch {
+				case k == timestampIdx:
+					err = builder.AppendGoValue(cb, row.bucket)
+				case !row.missing:
+					err = builder.AppendValue(cb, records[row.record].Column(k), row.row)
+				case grouped[k]:
+					err = builder.AppendValue(cb, records[first.record].Column(k), first.row)
+				default:
+					err = g.appendFilled(cb, records, k, rows, prev, i, next[i])
+				}
+				if err != nil {
+					return nil, fmt.Errorf("gap fill column %s: %w", fields[k].Name, err)
+				}
+			}
+			if !row.missing {
+				prev = i
+			}
+		}
+	}
+	return b.NewRecord(), nil
+}
+
+// fillSeries returns the rows of a series ordered by their buckets, with the
+// buckets that are missing within the time range of the gap fill in between.
+func (g *GapFiller) fillSeries(rows []gapFillRow, step int64) []gapFillRow {
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].bucket < rows[j].bucket
+	})
+	end := g.gapFill.End
+	next := g.gapFill.Start / step * step
+	filled := make([]gapFillRow, 0, len(rows))
+	for _, row := range rows {
+		for ; next < row.bucket && next < end; next += step {
+			filled = append(filled, gapFillRow{bucket: next, missing: true})
+		}
+		filled = append(filled, row)
+		if row.bucket >= next {
+			next = row.bucket + step
+		}
+	}
+	for ; next < end; next += step {
+		filled = append(filled, gapFillRow{bucket: next, missing: true})
+	}
+	return filled
+}
+
+// appendFilled appends the value of column k of the missing bucket rows[i],
+// given the indices of the rows of the series before and after it. Indices
+// of -1 mean that there is no such row.
+func (g *GapFiller) appendFilled(
+	cb builder.ColumnBuilder,
+	records []arrow.Record,
+	k int,
+	rows []gapFillRow,
+	prev, i, next int,
+) error {
+	switch g.gapFill.Fill {
+	case logicalplan.FillPrevious:
+		if prev != -1 {
+			return builder.AppendValue(cb, records[rows[prev].record].Column(k), rows[prev].row)
+		}
+	case logicalplan.FillLinear:
+		if prev == -1 || next == -1 {
+			break
+		}
+		p, n := rows[prev], rows[next]
+		prevCol, nextCol := records[p.record].Column(k), records[n.record].Column(k)
+		if prevCol.IsNull(p.row) || nextCol.IsNull(n.row) {
+			break
+		}
+		prevValues, err := float64Values(prevCol)
+		if err != nil {
+			// Values that aren't numeric can't be interpolated.
+			break
+		}
+		nextValues, err := float64Values(nextCol)
+		if err != nil {
+			break
+		}
+		from, to := prevValues(p.row), nextValues(n.row)
+		v := from + (to-from)*float64(rows[i].bucket-p.bucket)/float64(n.bucket-p.bucket)
+		if prevCol.DataType().ID() == arrow.INT64 {
+			return builder.AppendGoValue(cb, int64(math.Round(v)))
+		}
+		return builder.AppendGoValue(cb, v)
+	}
+	cb.AppendNull()
+	return nil
+}
+
+func (g *GapFiller) SetNext(next PhysicalPlan) {
+	g.next = next
+}
+
+func (g *GapFiller) Draw() *Diagram {
+	var child *Diagram
+	if g.next != nil {
+		child = g.next.Draw()
+	}
+	details := fmt.Sprintf("GapFill (%s %s)", g.gapFill.Step, g.gapFill.Fill)
+	return &Diagram{Details: details, Child: child}
+}
+
+func (g *GapFiller) Close() {
+	g.release()
+	g.next.Close()
+}
+
+func (g *GapFiller) release() {
+	for _, r := range g.records {
+		r.Release()
+	}
+	g.records = nil
+}
@@ -0,0 +1,114 @@
+package physicalplan
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+func TestGapFill(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "name", Type: arrow.BinaryTypes.Binary},
+		{Name: "timestamp", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "count", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "avg", Type: arrow.PrimitiveTypes.Float64},
+	}, nil)
+	groupExprs := []logicalplan.Expr{logicalplan.Col("name"), logicalplan.Duration(10 * time.Second)}
+
+	// The buckets of a series arrive across records in any order, with the
+	// timestamps of their first samples.
+	inputs := [][]any{
+		{"a", int64(41000), int64(4), 1.0, "b", int64(20000), int64(3), 3.0},
+		{"a", int64(11000), int64(1), 0.5},
+	}
+
+	for _, tc := range []struct {
+		fill     logicalplan.FillMode
+		expected []string
+	}{
+		{
+			fill: logicalplan.FillNull,
+			expected: []string{
+				"a 0 null null", "a 10000 1 0.5", "a 20000 null null", "a 30000 null null", "a 40000 4 1",
+				"b 0 null null", "b 10000 null null", "b 20000 3 3", "b 30000 null null", "b 40000 null null",
+			},
+		},
+		{
+			fill: logicalplan.FillPrevious,
+			expected: []string{
+				"a 0 null null", "a 10000 1 0.5", "a 20000 1 0.5", "a 30000 1 0.5", "a 40000 4 1",
+				"b 0 null null", "b 10000 null null", "b 20000 3 3", "b 30000 3 3", "b 40000 3 3",
+			},
+		},
+		{
+			fill: logicalplan.FillLinear,
+			expected: []string{
+				"a 0 null null", "a 10000 1 0.5", "a 20000 2 0.6666666667", "a 30000 3 0.8333333333", "a 40000 4 1",
+				"b 0 null null", "b 10000 null null", "b 20000 3 3", "b 30000 null null", "b 40000 null null",
+			},
+		},
+	} {
+		t.Run(tc.fill.String(), func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+			defer mem.AssertSize(t, 0)
+
+			g := GapFill(mem, trace.NewNoopTracerProvider().Tracer(""), &logicalplan.GapFill{
+				Start: 5000,
+				End:   50000,
+				Step:  10 * time.Second,
+				Fill:  tc.fill,
+			}, groupExprs)
+			var results []string
+			g.SetNext(&OutputPlan{
+				callback: func(_ context.Context, r arrow.Record) error {
+					require.True(t, r.Schema().Field(2).Nullable)
+					for i := 0; i < int(r.NumRows()); i++ {
+						row := fmt.Sprintf("%s %d", r.Column(0).(*array.Binary).Value(i), r.Column(1).(*array.Int64).Value(i))
+						for _, col := range r.Columns()[2:] {
+							switch {
+							case col.IsNull(i):
+								row += " null"
+							case col.DataType().ID() == arrow.FLOAT64:
+								row += fmt.Sprintf(" %.10g", col.(*array.Float64).Value(i))
+							default:
+								row += " " + col.ValueStr(i)
+							}
+						}
+						results = append(results, row)
+					}
+					return nil
+				},
+			})
+
+			ctx := context.Background()
+			for _, values := range inputs {
+				r := gapFillRecord(mem, schema, values)
+				require.NoError(t, g.Callback(ctx, r))
+				r.Release()
+			}
+			require.NoError(t, g.Finish(ctx))
+			require.Equal(t, tc.expected, results)
+		})
+	}
+}
+
+func gapFillRecord(mem memory.Allocator, schema *arrow.Schema, values []any) arrow.Record {
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	for i := 0; i < len(values); i += 4 {
+		b.Field(0).(*array.BinaryBuilder).AppendString(values[i].(string))
+		b.Field(1).(*array.Int64Builder).Append(values[i+1].(int64))
+		b.Field(2).(*array.Int64Builder).Append(values[i+2].(int64))
+		b.Field(3).(*array.Float64Builder).Append(values[i+3].(float64))
+	}
+	return b.NewRecord()
+}
@@ -0,0 +1,127 @@
+package physicalplan
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+)
+
+// bucketCounts are the partial state of histogram aggregations. They count
+// the values falling into each bucket, followed by the values greater than
+// the upper bound of the last bucket.
+type bucketCounts []int64
+
+func newBucketCounts(buckets []float64) bucketCounts {
+	return make(bucketCounts, len(buckets)+1)
+}
+
+// add counts the value into the first bucket whose upper bound is not less
+// than it. NaNs aren't counted.
+func (c bucketCounts) add(buckets []float64, v float64) {
+	if math.IsNaN(v) {
+		return
+	}
+	c[sort.SearchFloat64s(buckets, v)]++
+}
+
+func (c bucketCounts) merge(o bucketCounts) {
+	for i := range c {
+		c[i] += o[i]
+	}
+}
+
+func (c bucketCounts) encode() []byte {
+	b := make([]byte, 0, len(c)*8)
+	for _, count := range c {
+		b = binary.LittleEndian.AppendUint64(b, uint64(count))
+	}
+	return b
+}
+
+func (c bucketCounts) decode(b []byte) (bucketCounts, error) {
+	if len(b) != len(c)*8 {
+		return nil, fmt.Errorf("invalid counts of %d buckets in %d bytes", len(c), len(b))
+	}
+	o := make(bucketCounts, len(c))
+	for i := range o {
+		o[i] = int64(binary.LittleEndian.Uint64(b[i*8:]))
+	}
+	return o, nil
+}
+
+// histogramState is the partialState of histogram aggregations. The buckets
+// are given by their increasing upper bounds.
+type histogramState struct {
+	buckets []float64
+	counts  bucketCounts
+}
+
+func newHistogramState(buckets []float64) *histogramState {
+	return &histogramState{buckets: buckets, counts: newBucketCounts(buckets)}
+}
+
+// add counts the values of arr. Nulls are skipped.
+func (s *histogramState) add(arr, _ arrow.Array) error {
+	values, err := float64Values(arr)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) {
+			continue
+		}
+		s.counts.add(s.buckets, values(i))
+	}
+	return nil
+}
+
+func (s *histogramState) merge(b []byte) error {
+	o, err := s.counts.decode(b)
+	if err != nil {
+		return err
+	}
+	s.counts.merge(o)
+	return nil
+}
+
+func (s *histogramState) encode() []byte {
+	return s.counts.encode()
+}
+
+// final appends the histogram of the counts, whose last bucket has no upper
+// bound.
+func (s *histogramState) final(b array.Builder) error {
+	histogram := b.(*array.ListBuilder)
+	histogram.Append(true)
+	bucket := histogram.ValueBuilder().(*array.StructBuilder)
+	upperBounds := bucket.FieldBuilder(0).(*array.Float64Builder)
+	counts := bucket.FieldBuilder(1).(*array.Int64Builder)
+	for i, count := range s.counts {
+		bucket.Append(true)
+		if i < len(s.buckets) {
+			upperBounds.Append(s.buckets[i])
+		} else {
+			upperBounds.Append(math.Inf(1))
+		}
+		counts.Append(count)
+	}
+	return nil
+}
+
+// widthBucket returns the number of the bucket v falls into, out of n buckets
+// of equal width spanning [min, max). Values less than min fall into bucket 0
+// and values of at least max into bucket n+1.
+func widthBucket(v, min, max float64, n int64) int64 {
+	switch {
+	case v < min:
+		return 0
+	case v >= max:
+		return n + 1
+	}
+	// Rounding can place values right below max past the last bucket.
+	return 1 + int64(math.Min((v-min)/(max-min)*float64(n), float64(n-1)))
+}
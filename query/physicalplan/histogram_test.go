@@ -0,0 +1,119 @@
+package physicalplan
+
+import (
+	"context"
+	"hash/maphash"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+func TestWidthBucket(t *testing.T) {
+	for _, tc := range []struct {
+		v        float64
+		expected int64
+	}{
+		{v: -1, expected: 0},
+		{v: 0, expected: 1},
+		{v: 9.99, expected: 1},
+		{v: 10, expected: 2},
+		{v: 55, expected: 6},
+		{v: math.Nextafter(100, 0), expected: 10},
+		{v: 100, expected: 11},
+		{v: math.Inf(1), expected: 11},
+	} {
+		require.Equal(t, tc.expected, widthBucket(tc.v, 0, 100, 10), "width bucket of %v", tc.v)
+	}
+}
+
+func TestHistogramAggregation(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "name", Type: arrow.BinaryTypes.Binary},
+		{Name: "value", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}, nil)
+	agg := &logicalplan.Aggregation{
+		AggExprs: []logicalplan.Expr{
+			logicalplan.Histogram(logicalplan.Col("value"), logicalplan.ExponentialBuckets(1, 10, 3)),
+		},
+		GroupExprs: []logicalplan.Expr{logicalplan.Col("name")},
+	}
+	tracer := trace.NewNoopTracerProvider().Tracer("")
+	seed := maphash.MakeSeed()
+
+	// The values are counted by two partial aggregations, whose counts are
+	// merged by the final aggregation.
+	final, err := Aggregate(mem, tracer, agg, true, false, seed)
+	require.NoError(t, err)
+	results := map[string][]string{}
+	final.SetNext(&OutputPlan{
+		callback: func(_ context.Context, r arrow.Record) error {
+			require.True(t, arrow.TypeEqual(logicalplan.HistogramType, r.Column(1).DataType()))
+			histograms := r.Column(1).(*array.List)
+			buckets := histograms.ListValues().(*array.Struct)
+			upperBounds := buckets.Field(0).(*array.Float64)
+			counts := buckets.Field(1).(*array.Int64)
+			for i := 0; i < int(r.NumRows()); i++ {
+				name := string(r.Column(0).(*array.Binary).Value(i))
+				start, end := histograms.ValueOffsets(i)
+				for j := start; j < end; j++ {
+					results[name] = append(results[name], bucketString(upperBounds.Value(int(j)), counts.Value(int(j))))
+				}
+			}
+			return nil
+		},
+	})
+	sync := Synchronize(2)
+	sync.SetNext(final)
+
+	ctx := context.Background()
+	for _, values := range [][]any{
+		{"a", 0.5, "a", 5.0, "b", 1000.0, "a", nil},
+		{"a", 10.0, "a", 100.0, "a", math.NaN(), "b", 1.0},
+	} {
+		partial, err := Aggregate(mem, tracer, agg, false, false, seed)
+		require.NoError(t, err)
+		partial.SetNext(sync)
+
+		names := array.NewBinaryBuilder(mem, arrow.BinaryTypes.Binary)
+		floats := array.NewFloat64Builder(mem)
+		for i := 0; i < len(values); i += 2 {
+			names.AppendString(values[i].(string))
+			if values[i+1] == nil {
+				floats.AppendNull()
+				continue
+			}
+			floats.Append(values[i+1].(float64))
+		}
+		nameArr, valueArr := names.NewArray(), floats.NewArray()
+		r := array.NewRecord(schema, []arrow.Array{nameArr, valueArr}, int64(nameArr.Len()))
+		nameArr.Release()
+		valueArr.Release()
+		names.Release()
+		floats.Release()
+
+		require.NoError(t, partial.Callback(ctx, r))
+		require.NoError(t, partial.Finish(ctx))
+		r.Release()
+	}
+
+	// Nulls and NaNs aren't counted.
+	require.Equal(t, map[string][]string{
+		"a": {"1:1", "10:2", "100:1", "+Inf:0"},
+		"b": {"1:1", "10:0", "100:0", "+Inf:1"},
+	}, results)
+}
+
+func bucketString(upperBound float64, count int64) string {
+	return strconv.FormatFloat(upperBound, 'g', -1, 64) + ":" + strconv.FormatInt(count, 10)
+}
@@ -49,6 +49,10 @@ type OrderedAggregate struct {
 	aggregationFunction   logicalplan.AggFunc
 	next                  PhysicalPlan
 	columnToAggregate     logicalplan.Expr
+	// orderBy is the expression the values of first and last aggregations
+	// are ordered by. The order keys are carried along with the values to
+	// aggregate, so that the results of ordered sets can be merged by them.
+	orderBy logicalplan.Expr
 	// Indicate is this is the last aggregation or if this is an aggregation
 	// with another aggregation to follow after synchronizing.
 	finalStage bool
@@ -78,6 +82,11 @@ type OrderedAggregate struct {
 	// last group in a record since we cannot know whether that group continues
 	// in the next record.
 	arrayToAggCarry builder.ColumnBuilder
+	// valueType is the type of the values to aggregate.
+	valueType arrow.DataType
+	// orderKeyCarry carries over the order keys of the values in
+	// arrayToAggCarry.
+	orderKeyCarry builder.ColumnBuilder
 
 	// aggResultBuilder is a builder of the aggregation results for the current
 	// ordered set (i.e. each element in this builder is the aggregation result
@@ -91,6 +100,11 @@ type OrderedAggregate struct {
 	// belongs to ordered set i.
 	aggregationResults []arrow.Array
 
+	// orderKeyResultBuilder and orderKeyResults hold the order keys of the
+	// aggregation results of first and last aggregations.
+	orderKeyResultBuilder arrowutils.ArrayConcatenator
+	orderKeyResults       []arrow.Array
+
 	scratch struct {
 		// groupByMap is a scratch map that helps store a mapping from the
 		// field names of the group by columns found on each call to Callback to
@@ -115,6 +129,7 @@ func NewOrderedAggregate(
 		tracer:            tracer,
 		resultColumnName:  aggregation.resultName,
 		columnToAggregate: aggregation.expr,
+		orderBy:           aggregation.orderBy,
 		// TODO: Matchers can be optimized to be something like a radix tree or
 		// just a fast-lookup data structure for exact matches or prefix
 		// matches.
@@ -172,7 +187,7 @@ func (a *OrderedAggregate) Callback(_ context.Context, r arrow.Record) error {
 	// TODO(asubiotto): Explore a static schema in the execution engine, all
 	// this should be initialization code.
 
-	var columnToAggregate arrow.Array
+	var columnToAggregate, orderKeys arrow.Array
 	aggregateFieldFound := false
 	foundNewColumns := false
 	for i := 0; i < r.Schema().NumFields(); i++ {
@@ -200,14 +215,25 @@ func (a *OrderedAggregate) Callback(_ context.Context, r arrow.Record) error {
 			columnToAggregate = r.Column(i)
 			if a.arrayToAggCarry == nil {
 				a.arrayToAggCarry = builder.NewBuilder(a.pool, columnToAggregate.DataType())
+				a.valueType = columnToAggregate.DataType()
 			}
 			aggregateFieldFound = true
 		}
+
+		if a.orderBy != nil && a.orderBy.MatchColumn(field.Name) {
+			orderKeys = r.Column(i)
+			if a.orderKeyCarry == nil {
+				a.orderKeyCarry = builder.NewBuilder(a.pool, orderKeys.DataType())
+			}
+		}
 	}
 
 	if !aggregateFieldFound {
 		return errors.New("aggregate field not found, aggregations are not possible without it")
 	}
+	if a.orderBy != nil && orderKeys == nil {
+		return fmt.Errorf("order by field %s not found", a.orderBy.Name())
+	}
 
 	if foundNewColumns {
 		// Previous group results need to be updated with physical null columns
@@ -280,6 +306,7 @@ func (a *OrderedAggregate) Callback(_ context.Context, r arrow.Record) error {
 
 	// Aggregate the values for all groups found.
 	arraysToAggregate := make([]arrow.Array, 0, groupRanges.Len())
+	var orderKeysToAggregate []arrow.Array
 
 	// arraysToAggregateSetIdxs keeps track of the idxs in arraysToAggregate
 	// that represent new ordered sets. This is essentially a "conversion" of
@@ -312,6 +339,14 @@ func (a *OrderedAggregate) Callback(_ context.Context, r arrow.Record) error {
 			); err != nil {
 				return err
 			}
+			if orderKeys != nil {
+				if err := builder.AppendArray(
+					a.orderKeyCarry,
+					array.NewSlice(orderKeys, groupStart, int64(orderKeys.Len())),
+				); err != nil {
+					return err
+				}
+			}
 			break
 		}
 
@@ -320,17 +355,32 @@ func (a *OrderedAggregate) Callback(_ context.Context, r arrow.Record) error {
 		if groupEnd == 0 {
 			// End of the group found in the last record, the only data to
 			// aggregate was carried over.
-			toAgg = a.arrayToAggCarry.NewArray()
+			toAgg = a.flushCarry(&a.arrayToAggCarry)
 		} else {
 			toAgg = array.NewSlice(columnToAggregate, groupStart, groupEnd)
 			if a.arrayToAggCarry.Len() > 0 {
 				if err := builder.AppendArray(a.arrayToAggCarry, toAgg); err != nil {
 					return err
 				}
-				toAgg = a.arrayToAggCarry.NewArray()
+				toAgg = a.flushCarry(&a.arrayToAggCarry)
 			}
 		}
 		arraysToAggregate = append(arraysToAggregate, toAgg)
+		if orderKeys != nil {
+			var keys arrow.Array
+			if groupEnd == 0 {
+				keys = a.flushCarry(&a.orderKeyCarry)
+			} else {
+				keys = array.NewSlice(orderKeys, groupStart, groupEnd)
+				if a.orderKeyCarry.Len() > 0 {
+					if err := builder.AppendArray(a.orderKeyCarry, keys); err != nil {
+						return err
+					}
+					keys = a.flushCarry(&a.orderKeyCarry)
+				}
+			}
+			orderKeysToAggregate = append(orderKeysToAggregate, keys)
+		}
 
 		// Append the groups.
 		newOrderedSet := false
@@ -377,7 +427,7 @@ func (a *OrderedAggregate) Callback(_ context.Context, r arrow.Record) error {
 		return nil
 	}
 
-	results, err := runAggregation(a.finalStage, a.aggregationFunction, a.pool, arraysToAggregate)
+	results, resultKeys, err := a.aggregate(a.finalStage, arraysToAggregate, orderKeysToAggregate)
 	if err != nil {
 		return err
 	}
@@ -400,14 +450,52 @@ func (a *OrderedAggregate) Callback(_ context.Context, r arrow.Record) error {
 			}
 		}
 		a.aggregationResults = append(a.aggregationResults, set)
+		if resultKeys != nil {
+			keys := array.NewSlice(resultKeys, setStart, setEnd)
+			if a.orderKeyResultBuilder.Len() > 0 {
+				a.orderKeyResultBuilder.Add(keys)
+				var err error
+				keys, err = a.orderKeyResultBuilder.NewArray(a.pool)
+				if err != nil {
+					return err
+				}
+			}
+			a.orderKeyResults = append(a.orderKeyResults, keys)
+		}
 		setStart = setEnd
 	}
 	// The last ordered set cannot be determined to close within this
 	// record, so carry it over.
 	a.aggResultBuilder.Add(array.NewSlice(results, setStart, int64(results.Len())))
+	if resultKeys != nil {
+		a.orderKeyResultBuilder.Add(array.NewSlice(resultKeys, setStart, int64(resultKeys.Len())))
+	}
 	return nil
 }
 
+// flushCarry returns the carried over values and replaces the carry builder.
+// The builder can't be reused, since the values carried over next would
+// overwrite the returned array's buffers, which are still to be aggregated.
+func (a *OrderedAggregate) flushCarry(carry *builder.ColumnBuilder) arrow.Array {
+	arr := (*carry).NewArray()
+	(*carry).Release()
+	*carry = builder.NewBuilder(a.pool, arr.DataType())
+	return arr
+}
+
+// aggregate runs the aggregation function over the values of each group. For
+// first and last aggregations, the order keys of the picked values are
+// returned as well.
+func (a *OrderedAggregate) aggregate(
+	finalStage bool, values, orderKeys []arrow.Array,
+) (arrow.Array, arrow.Array, error) {
+	if a.orderBy == nil {
+		results, err := runAggregation(finalStage, a.aggregationFunction, a.pool, values)
+		return results, nil, err
+	}
+	return pickOrderedValues(a.aggregationFunction, a.pool, a.valueType, values, orderKeys)
+}
+
 func (a *OrderedAggregate) Finish(ctx context.Context) error {
 	ctx, span := a.tracer.Start(ctx, "OrderedAggregate/Finish")
 	defer span.End()
@@ -431,8 +519,12 @@ func (a *OrderedAggregate) Finish(ctx context.Context) error {
 			a.groupResults[n] = append(a.groupResults[n], b.NewArray())
 		}
 
-		results, err := runAggregation(
-			a.finalStage, a.aggregationFunction, a.pool, []arrow.Array{a.arrayToAggCarry.NewArray()},
+		var orderKeys []arrow.Array
+		if a.orderKeyCarry != nil {
+			orderKeys = []arrow.Array{a.orderKeyCarry.NewArray()}
+		}
+		results, resultKeys, err := a.aggregate(
+			a.finalStage, []arrow.Array{a.arrayToAggCarry.NewArray()}, orderKeys,
 		)
 		if err != nil {
 			return err
@@ -451,33 +543,61 @@ func (a *OrderedAggregate) Finish(ctx context.Context) error {
 			lastResults = results
 		}
 		a.aggregationResults = append(a.aggregationResults, lastResults)
+
+		if resultKeys != nil {
+			lastKeys := resultKeys
+			if a.orderKeyResultBuilder.Len() > 0 {
+				a.orderKeyResultBuilder.Add(resultKeys)
+				var err error
+				lastKeys, err = a.orderKeyResultBuilder.NewArray(a.pool)
+				if err != nil {
+					return err
+				}
+			}
+			a.orderKeyResults = append(a.orderKeyResults, lastKeys)
+		}
 	}
 
-	schema := arrow.NewSchema(
-		append(
-			a.groupColOrdering,
-			arrow.Field{Name: a.getResultColumnName(), Type: a.aggregationResults[0].DataType()},
-		),
-		nil,
+	fields := append(
+		a.groupColOrdering,
+		arrow.Field{Name: a.getResultColumnName(), Type: a.aggregationResults[0].DataType()},
 	)
+	// The order keys are needed to merge the ordered sets. They are passed
+	// on to later stages unless this is the final stage.
+	mergeFields := fields
+	if a.orderBy != nil {
+		mergeFields = append(mergeFields[:len(mergeFields):len(mergeFields)], arrow.Field{
+			Name: a.orderBy.Name(), Type: arrow.PrimitiveTypes.Int64,
+		})
+		if !a.finalStage {
+			fields = mergeFields
+		}
+	}
+	schema := arrow.NewSchema(fields, nil)
+	mergeSchema := arrow.NewSchema(mergeFields, nil)
 
 	records := make([]arrow.Record, 0, len(a.groupResults))
 	for i := range a.groupResults {
+		cols := append(a.groupResults[i], a.aggregationResults[i])
+		if a.orderBy != nil {
+			cols = append(cols, a.orderKeyResults[i])
+		}
 		records = append(
 			records,
 			array.NewRecord(
-				schema,
-				append(
-					a.groupResults[i],
-					a.aggregationResults[i],
-				),
+				mergeSchema,
+				cols,
 				int64(a.aggregationResults[i].Len()),
 			),
 		)
 	}
 
 	if len(records) == 1 {
-		if err := a.next.Callback(ctx, records[0]); err != nil {
+		r := records[0]
+		if a.orderBy != nil && a.finalStage {
+			r = array.NewRecord(schema, r.Columns()[:len(fields)], r.NumRows())
+		}
+		if err := a.next.Callback(ctx, r); err != nil {
 			return err
 		}
 	} else {
@@ -526,7 +646,17 @@ func (a *OrderedAggregate) Finish(ctx context.Context) error {
 			start = end
 		}
 
-		result, err := runAggregation(true, a.aggregationFunction, a.pool, toAggregate)
+		var orderKeys []arrow.Array
+		if a.orderBy != nil {
+			orderKeyVals := mergedRecord.Columns()[len(a.groupColOrdering)+1]
+			start := int64(0)
+			for _, end := range groupRanges {
+				orderKeys = append(orderKeys, array.NewSlice(orderKeyVals, start, end))
+				start = end
+			}
+		}
+
+		result, resultKeys, err := a.aggregate(true, toAggregate, orderKeys)
 		if err != nil {
 			return err
 		}
@@ -535,11 +665,15 @@ func (a *OrderedAggregate) Finish(ctx context.Context) error {
 		for _, field := range a.groupColOrdering {
 			groups = append(groups, a.groupBuilders[field.Name].NewArray())
 		}
+		cols := append(groups, result)
+		if resultKeys != nil && !a.finalStage {
+			cols = append(cols, resultKeys)
+		}
 		if err := a.next.Callback(
 			ctx,
 			array.NewRecord(
 				schema,
-				append(groups, result),
+				cols,
 				int64(result.Len()),
 			),
 		); err != nil {
@@ -0,0 +1,125 @@
+package physicalplan
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+// partialState is the state of an aggregation of a group whose partial
+// results are passed on to the final stage as encoded binary values.
+type partialState interface {
+	// add adds the values of the group, paired with the values of paired for
+	// aggregations of pairs. Paired is nil otherwise.
+	add(values, paired arrow.Array) error
+	// merge merges an encoded partial state into the state.
+	merge(b []byte) error
+	// encode returns the encoded partial state.
+	encode() []byte
+	// final appends the final result of the aggregation to the builder.
+	final(b array.Builder) error
+}
+
+// newPartialState returns the function creating the partial state of a group
+// of aggregations whose partial results are partial states, and the type of
+// their final results. The function is nil for other aggregations.
+func newPartialState(agg Aggregation) (func() partialState, arrow.DataType, error) {
+	switch fn := agg.function; {
+	case fn.Statistical():
+		return func() partialState {
+			return &statisticalState{fn: fn}
+		}, arrow.PrimitiveTypes.Float64, nil
+	case fn.Counter():
+		if agg.window.Milliseconds() <= 0 {
+			return nil, nil, fmt.Errorf("%s must be grouped by a duration of at least a millisecond", fn)
+		}
+		return func() partialState {
+			return &counterAggregationState{fn: fn, window: agg.window}
+		}, arrow.PrimitiveTypes.Float64, nil
+	case fn == logicalplan.AggFuncHistogram:
+		return func() partialState {
+			return newHistogramState(agg.buckets)
+		}, logicalplan.HistogramType, nil
+	default:
+		return nil, nil, nil
+	}
+}
+
+// runPartialStateAggregation computes the state created by newState of each
+// group and returns them encoded as binary partial states, or the final
+// results of the type for the final stage. If partialInputs is set, arrs hold
+// the partial states of each group, otherwise their values, paired with those
+// of paired for aggregations of pairs.
+func runPartialStateAggregation(
+	fn logicalplan.AggFunc,
+	pool memory.Allocator,
+	typ arrow.DataType,
+	newState func() partialState,
+	arrs, paired []arrow.Array,
+	partialInputs, finalStage bool,
+) (arrow.Array, error) {
+	var (
+		res   array.Builder
+		state *array.BinaryBuilder
+	)
+	if finalStage {
+		res = array.NewBuilder(pool, typ)
+	} else {
+		state = array.NewBinaryBuilder(pool, arrow.BinaryTypes.Binary)
+		res = state
+	}
+	defer res.Release()
+
+	for i, arr := range arrs {
+		s := newState()
+		var err error
+		if partialInputs {
+			err = mergePartialStates(s, arr)
+		} else {
+			var y arrow.Array
+			if paired != nil {
+				if i >= len(paired) {
+					return nil, fmt.Errorf("%s of %d groups with %d groups of paired values", fn, len(arrs), len(paired))
+				}
+				y = paired[i]
+			}
+			err = s.add(arr, y)
+		}
+		if err == nil {
+			if finalStage {
+				err = s.final(res)
+			} else {
+				state.Append(s.encode())
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fn, err)
+		}
+	}
+	return res.NewArray(), nil
+}
+
+// mergePartialStates merges the encoded partial states of arr into s. Empty
+// arrays of any type are groups without partial states.
+func mergePartialStates(s partialState, arr arrow.Array) error {
+	if arr.Len() == 0 {
+		return nil
+	}
+	states, ok := arr.(*array.Binary)
+	if !ok {
+		return fmt.Errorf("unsupported partial state of type: %s", arr.DataType())
+	}
+	for i := 0; i < states.Len(); i++ {
+		if states.IsNull(i) {
+			continue
+		}
+		if err := s.merge(states.Value(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
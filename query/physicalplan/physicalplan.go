@@ -2,17 +2,23 @@ package physicalplan
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"hash/maphash"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
 	"github.com/apache/arrow/go/v14/arrow/memory"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/polarsignals/frostdb/dynparquet"
+	"github.com/polarsignals/frostdb/pqarrow/arrowutils"
+	"github.com/polarsignals/frostdb/query/expr"
 	"github.com/polarsignals/frostdb/query/logicalplan"
 	"github.com/polarsignals/frostdb/recovery"
 )
@@ -28,6 +34,20 @@ type PhysicalPlan interface {
 	Close()
 }
 
+// OperatorAllocator is implemented by allocators that attribute the memory
+// allocated by a query to its operators.
+type OperatorAllocator interface {
+	memory.Allocator
+	ForOperator(operator string) memory.Allocator
+}
+
+func operatorPool(pool memory.Allocator, operator string) memory.Allocator {
+	if a, ok := pool.(OperatorAllocator); ok {
+		return a.ForOperator(operator)
+	}
+	return pool
+}
+
 type ScanPhysicalPlan interface {
 	Execute(ctx context.Context, pool memory.Allocator) error
 	Draw() *Diagram
@@ -56,6 +76,11 @@ func (f PostPlanVisitorFunc) PostVisit(plan *logicalplan.LogicalPlan) bool {
 type OutputPlan struct {
 	callback func(ctx context.Context, r arrow.Record) error
 	scan     ScanPhysicalPlan
+	// spill is the spiller of the query, if spilling is enabled.
+	spill *spiller
+	// sample is the sample the query is computed on, if any. The records
+	// passed on carry its fraction in their schema metadata.
+	sample *logicalplan.Sample
 }
 
 func (e *OutputPlan) Draw() *Diagram {
@@ -67,8 +92,21 @@ func (e *OutputPlan) DrawString() string {
 	return e.scan.Draw().String()
 }
 
+// AnalyzeString returns the plan including the runtime statistics of each
+// operator. The plan must have been built using WithAnalyze and executed.
+func (e *OutputPlan) AnalyzeString() string {
+	return e.scan.Draw().AnalyzeString()
+}
+
 func (e *OutputPlan) Callback(ctx context.Context, r arrow.Record) error {
-	return e.callback(ctx, r)
+	if e.sample == nil {
+		return e.callback(ctx, r)
+	}
+
+	md := logicalplan.SampleMetadata(e.sample.Fraction)
+	annotated := array.NewRecord(arrow.NewSchema(r.Schema().Fields(), &md), r.Columns(), r.NumRows())
+	defer annotated.Release()
+	return e.callback(ctx, annotated)
 }
 
 func (e *OutputPlan) SetNextCallback(next func(ctx context.Context, r arrow.Record) error) {
@@ -89,13 +127,20 @@ func (e *OutputPlan) SetNext(_ PhysicalPlan) {
 
 func (e *OutputPlan) Execute(ctx context.Context, pool memory.Allocator, callback func(ctx context.Context, r arrow.Record) error) error {
 	e.callback = callback
-	return e.scan.Execute(ctx, pool)
+	err := e.scan.Execute(ctx, pool)
+	if cleanupErr := e.spill.cleanup(); err == nil && cleanupErr != nil {
+		err = fmt.Errorf("cleanup spill directory: %w", cleanupErr)
+	}
+	return err
 }
 
 type TableScan struct {
 	tracer  trace.Tracer
 	options *logicalplan.TableScan
 	plans   []PhysicalPlan
+	stats   *OperatorStats
+	// watermarks, if set, is reported the progress of the scan.
+	watermarks *logicalplan.Watermarks
 }
 
 func (s *TableScan) Draw() *Diagram {
@@ -107,7 +152,7 @@ func (s *TableScan) Draw() *Diagram {
 			details += " [concurrent]"
 		}
 	}
-	return &Diagram{Details: details, Child: child}
+	return &Diagram{Details: details, Child: child, Stats: s.stats}
 }
 
 func (s *TableScan) Execute(ctx context.Context, pool memory.Allocator) error {
@@ -119,9 +164,17 @@ func (s *TableScan) Execute(ctx context.Context, pool memory.Allocator) error {
 		return fmt.Errorf("table not found: %w", err)
 	}
 
+	pool = operatorPool(pool, "TableScan")
 	callbacks := make([]logicalplan.Callback, 0, len(s.plans))
 	for _, plan := range s.plans {
-		callbacks = append(callbacks, plan.Callback)
+		callbacks = append(callbacks, flattenStructs(pool, s.options, plan.Callback))
+	}
+	if s.stats != nil {
+		start := time.Now()
+		defer func() { s.stats.observeActivity(start, time.Now()) }()
+		ctx = expr.ContextWithScanStats(ctx, s.stats.Scan)
+		pool = &analyzeAllocator{Allocator: pool, stats: s.stats}
+		callbacks = analyzeCallbacks(s.stats, callbacks)
 	}
 	defer func() { // Close all plans to ensure memory cleanup.
 		for _, plan := range s.plans {
@@ -139,6 +192,18 @@ func (s *TableScan) Execute(ctx context.Context, pool memory.Allocator) error {
 	if s.options.SkipSources {
 		opts = append(opts, logicalplan.WithInMemoryOnly())
 	}
+	if s.options.PersistedBefore != 0 {
+		opts = append(opts, logicalplan.WithPersistedOnly(s.options.PersistedBefore))
+	}
+	if len(s.options.MetadataAggregations) > 0 {
+		opts = append(opts, logicalplan.WithMetadataAggregations(s.options.MetadataAggregations...))
+	}
+	if s.options.Sample != nil {
+		opts = append(opts, logicalplan.WithSample(expr.SeededSample(s.options.Sample)))
+	}
+	if s.watermarks != nil {
+		opts = append(opts, logicalplan.WithWatermarks(s.watermarks.Column, s.watermarks.Advance))
+	}
 
 	errg, _ := errgroup.WithContext(ctx)
 	errg.Go(recovery.Do(func() error {
@@ -152,7 +217,9 @@ func (s *TableScan) Execute(ctx context.Context, pool memory.Allocator) error {
 			)
 		})
 	}))
-	if err := errg.Wait(); err != nil {
+	// Operators that reached their limit stop the scan, but the rows they
+	// produced are still passed on.
+	if err := errg.Wait(); err != nil && !errors.Is(err, ErrLimitReached) {
 		return err
 	}
 
@@ -171,10 +238,22 @@ func (s *TableScan) Execute(ctx context.Context, pool memory.Allocator) error {
 	return nil
 }
 
+// flattenStructs wraps the callback to replace the struct columns of nested
+// schemas whose fields the scan refers to by their fields, named by their
+// dotted path, so that operators treat nested fields like flat columns.
+func flattenStructs(pool memory.Allocator, scan *logicalplan.TableScan, callback logicalplan.Callback) logicalplan.Callback {
+	return func(ctx context.Context, r arrow.Record) error {
+		r = arrowutils.FlattenStructPaths(pool, r, scan.FlattensPath)
+		defer r.Release()
+		return callback(ctx, r)
+	}
+}
+
 type SchemaScan struct {
 	tracer  trace.Tracer
 	options *logicalplan.SchemaScan
 	plans   []PhysicalPlan
+	stats   *OperatorStats
 }
 
 func (s *SchemaScan) Draw() *Diagram {
@@ -182,7 +261,7 @@ func (s *SchemaScan) Draw() *Diagram {
 	// for _, plan := range s.plans {
 	//	children = append(children, plan.Draw())
 	// }
-	return &Diagram{Details: "SchemaScan"}
+	return &Diagram{Details: "SchemaScan", Stats: s.stats}
 }
 
 func (s *SchemaScan) Execute(ctx context.Context, pool memory.Allocator) error {
@@ -191,10 +270,18 @@ func (s *SchemaScan) Execute(ctx context.Context, pool memory.Allocator) error {
 		return fmt.Errorf("table not found: %w", err)
 	}
 
+	pool = operatorPool(pool, "SchemaScan")
 	callbacks := make([]logicalplan.Callback, 0, len(s.plans))
 	for _, plan := range s.plans {
 		callbacks = append(callbacks, plan.Callback)
 	}
+	if s.stats != nil {
+		start := time.Now()
+		defer func() { s.stats.observeActivity(start, time.Now()) }()
+		ctx = expr.ContextWithScanStats(ctx, s.stats.Scan)
+		pool = &analyzeAllocator{Allocator: pool, stats: s.stats}
+		callbacks = analyzeCallbacks(s.stats, callbacks)
+	}
 
 	opts := []logicalplan.Option{
 		logicalplan.WithPhysicalProjection(s.options.PhysicalProjection...),
@@ -219,7 +306,9 @@ func (s *SchemaScan) Execute(ctx context.Context, pool memory.Allocator) error {
 			)
 		})
 	}))
-	if err := errg.Wait(); err != nil {
+	// Operators that reached their limit stop the scan, but the rows they
+	// produced are still passed on.
+	if err := errg.Wait(); err != nil && !errors.Is(err, ErrLimitReached) {
 		return err
 	}
 
@@ -262,9 +351,14 @@ func (p *noopOperator) Draw() *Diagram {
 }
 
 type execOptions struct {
-	orderedAggregations bool
-	overrideInput       []PhysicalPlan
-	skipSources         bool
+	orderedAggregations   bool
+	streamingAggregations bool
+	overrideInput         []PhysicalPlan
+	skipSources           bool
+	persistedBefore       uint64
+	analyze               bool
+	spill                 *SpillConfig
+	partialAggregation    bool
 }
 
 type Option func(o *execOptions)
@@ -275,12 +369,50 @@ func WithInMemoryOnly() Option {
 	}
 }
 
+// WithPersistedOnly restricts table scans to the immutable blocks of the
+// table's sources that were created before the given block timestamp.
+func WithPersistedOnly(before uint64) Option {
+	return func(o *execOptions) {
+		o.persistedBefore = before
+	}
+}
+
 func WithOrderedAggregations() Option {
 	return func(o *execOptions) {
 		o.orderedAggregations = true
 	}
 }
 
+// WithStreamingAggregations plans aggregations grouped by a duration over
+// input ordered by timestamp to pass on the groups of each time window as soon
+// as the table scan moves past it, rather than once the whole input is
+// aggregated.
+func WithStreamingAggregations() Option {
+	return func(o *execOptions) {
+		o.streamingAggregations = true
+	}
+}
+
+// WithPartialAggregation plans the aggregation of the plan to pass on the
+// partial results of its groups rather than their final results, so that they
+// can be merged with the partial results of other instances holding different
+// parts of the data. The schema of the partial results is described by
+// PartialAggregationSchema.
+func WithPartialAggregation() Option {
+	return func(o *execOptions) {
+		o.partialAggregation = true
+	}
+}
+
+// WithAnalyze instruments the plan to collect runtime statistics of every
+// operator. Once executed, the statistics can be retrieved using
+// OutputPlan.AnalyzeString.
+func WithAnalyze() Option {
+	return func(o *execOptions) {
+		o.analyze = true
+	}
+}
+
 // WithOverrideInput can be used to provide an input stage on top of which the
 // Build function can build the physical plan.
 func WithOverrideInput(input []PhysicalPlan) Option {
@@ -300,6 +432,10 @@ func Build(
 	_, span := tracer.Start(ctx, "PhysicalPlan/Build")
 	defer span.End()
 
+	if params := plan.Params(); len(params) > 0 {
+		return nil, fmt.Errorf("plan has unbound parameters: %s", strings.Join(params, ", "))
+	}
+
 	execOpts := execOptions{}
 	for _, o := range options {
 		o(&execOpts)
@@ -307,6 +443,9 @@ func Build(
 	prev := execOpts.overrideInput
 
 	outputPlan := &OutputPlan{}
+	if execOpts.spill != nil {
+		outputPlan.spill = newSpiller(*execOpts.spill)
+	}
 	oInfo := &planOrderingInfo{
 		state: planOrderingInfoStateInit,
 	}
@@ -333,6 +472,7 @@ func Build(
 				tracer:  tracer,
 				options: plan.SchemaScan,
 				plans:   plans,
+				stats:   scanStats(execOpts.analyze),
 			}
 			prev = append(prev[:0], plans...)
 		case plan.TableScan != nil:
@@ -344,10 +484,12 @@ func Build(
 				plans[i] = &noopOperator{}
 			}
 			plan.TableScan.SkipSources = execOpts.skipSources
+			plan.TableScan.PersistedBefore = execOpts.persistedBefore
 			outputPlan.scan = &TableScan{
 				tracer:  tracer,
 				options: plan.TableScan,
 				plans:   plans,
+				stats:   scanStats(execOpts.analyze),
 			}
 			prev = append(prev[:0], plans...)
 			oInfo.nodeMaintainsOrdering()
@@ -358,23 +500,26 @@ func Build(
 				}
 			}
 			// For each previous physical plan create one Projection
+			stage := newStage(execOpts.analyze, pool, "Projection")
 			for i := range prev {
-				p, err := Project(pool, tracer, plan.Projection.Exprs)
+				p, err := Project(stage.pool, tracer, plan.Projection.Exprs)
 				if err != nil {
 					visitErr = err
 					return false
 				}
-				prev[i].SetNext(p)
-				prev[i] = p
+				wrapped := stage.instrument(p)
+				prev[i].SetNext(wrapped)
+				prev[i] = wrapped
 			}
 		case plan.Distinct != nil:
-			var sync *Synchronizer
+			var sync PhysicalPlan
 			if len(prev) > 1 {
 				// These distinct operators need to be synchronized.
-				sync = Synchronize(len(prev))
+				sync = newStage(execOpts.analyze, pool, "Synchronizer").instrument(Synchronize(len(prev)))
 			}
+			stage := newStage(execOpts.analyze, pool, "Distinct")
 			for i := 0; i < len(prev); i++ {
-				d := Distinct(pool, tracer, plan.Distinct.Exprs)
+				d := stage.instrument(Distinct(stage.pool, tracer, plan.Distinct.Exprs, plan.Distinct.Limit))
 				prev[i].SetNext(d)
 				prev[i] = d
 				if sync != nil {
@@ -384,7 +529,8 @@ func Build(
 			if sync != nil {
 				// Plan a distinct operator to run a distinct on all the
 				// synchronized distincts.
-				d := Distinct(pool, tracer, plan.Distinct.Exprs)
+				stage := newStage(execOpts.analyze, pool, "Distinct")
+				d := stage.instrument(Distinct(stage.pool, tracer, plan.Distinct.Exprs, plan.Distinct.Limit))
 				sync.SetNext(d)
 				prev = prev[0:1]
 				prev[0] = d
@@ -393,14 +539,16 @@ func Build(
 			// Create a filter for each previous plan.
 			// Can be multiple filters or just a single
 			// filter depending on the previous concurrency.
+			stage := newStage(execOpts.analyze, pool, "Filter")
 			for i := range prev {
-				f, err := Filter(pool, tracer, plan.Filter.Expr)
+				f, err := Filter(stage.pool, tracer, plan.Filter.Expr)
 				if err != nil {
 					visitErr = err
 					return false
 				}
-				prev[i].SetNext(f)
-				prev[i] = f
+				wrapped := stage.instrument(f)
+				prev[i].SetNext(wrapped)
+				prev[i] = wrapped
 			}
 			oInfo.applyFilter(plan.Filter.Expr)
 			oInfo.nodeMaintainsOrdering()
@@ -410,43 +558,145 @@ func Build(
 				// TODO(asubiotto): Log the error.
 				ordered = false
 			}
-			var sync PhysicalPlan
-			if len(prev) > 1 {
-				// These aggregate operators need to be synchronized.
-				if ordered && len(plan.Aggregation.GroupExprs) > 0 {
-					sync = NewOrderedSynchronizer(pool, len(prev), plan.Aggregation.GroupExprs)
-				} else {
-					sync = Synchronize(len(prev))
+			// A scan producing the partial results of the aggregation from
+			// metadata only needs the results to be merged.
+			metadata := plan.Input != nil && plan.Input.TableScan != nil &&
+				len(plan.Input.TableScan.MetadataAggregations) > 0
+			if execOpts.partialAggregation {
+				// Partial results are only passed on by hash aggregations.
+				ordered = false
+				prev, visitErr = planPartialAggregate(pool, tracer, execOpts, outputPlan.spill, plan.Aggregation, metadata, prev)
+				if visitErr != nil {
+					return false
 				}
-			}
-			seed := maphash.MakeSeed()
-			for i := 0; i < len(prev); i++ {
-				a, err := Aggregate(pool, tracer, plan.Aggregation, sync == nil, ordered, seed)
-				if err != nil {
-					visitErr = err
+			} else if scan, ok := outputPlan.scan.(*TableScan); ok && !metadata &&
+				shouldPlanWindowAggregate(execOpts, oInfo, plan.Aggregation) {
+				ordered = false
+				prev, visitErr = planWindowAggregate(pool, tracer, execOpts, outputPlan.spill, plan.Aggregation, scan, prev)
+				if visitErr != nil {
 					return false
 				}
-				prev[i].SetNext(a)
-				prev[i] = a
+			} else {
+				var sync PhysicalPlan
+				if len(prev) > 1 {
+					// These aggregate operators need to be synchronized.
+					stage := newStage(execOpts.analyze, pool, "Synchronizer")
+					if ordered && len(plan.Aggregation.GroupExprs) > 0 {
+						sync = stage.instrument(NewOrderedSynchronizer(stage.pool, len(prev), plan.Aggregation.GroupExprs))
+					} else {
+						sync = stage.instrument(Synchronize(len(prev)))
+					}
+				}
+				seed := maphash.MakeSeed()
+				stage := newStage(execOpts.analyze, pool, "Aggregate")
+				for i := 0; i < len(prev); i++ {
+					a, err := Aggregate(stage.pool, tracer, plan.Aggregation, sync == nil || metadata, ordered, seed)
+					if err != nil {
+						visitErr = err
+						return false
+					}
+					if h, ok := a.(*HashAggregate); ok && outputPlan.spill != nil {
+						h.setSpiller(outputPlan.spill)
+					}
+					wrapped := stage.instrument(a)
+					prev[i].SetNext(wrapped)
+					prev[i] = wrapped
+					if sync != nil {
+						wrapped.SetNext(sync)
+					}
+				}
 				if sync != nil {
-					a.SetNext(sync)
+					// Plan an aggregate operator to run an aggregation on all the
+					// aggregations.
+					stage := newStage(execOpts.analyze, pool, "Aggregate")
+					a, err := Aggregate(stage.pool, tracer, plan.Aggregation, true, ordered, seed)
+					if err != nil {
+						visitErr = err
+						return false
+					}
+					if h, ok := a.(*HashAggregate); ok && outputPlan.spill != nil {
+						h.setSpiller(outputPlan.spill)
+					}
+					wrapped := stage.instrument(a)
+					sync.SetNext(wrapped)
+					prev = prev[0:1]
+					prev[0] = wrapped
 				}
 			}
-			if sync != nil {
-				// Plan an aggregate operator to run an aggregation on all the
-				// aggregations.
-				a, err := Aggregate(pool, tracer, plan.Aggregation, true, ordered, seed)
-				if err != nil {
-					visitErr = err
-					return false
+			if outputPlan.sample != nil && outputPlan.sample.ScaleAggregations {
+				// The aggregations are computed on the sample, so sums and
+				// counts are scaled up to estimate those of all data.
+				stage := newStage(execOpts.analyze, pool, "AggregationScaler")
+				for i := range prev {
+					a := stage.instrument(ScaleAggregations(stage.pool, tracer, plan.Aggregation.AggExprs, outputPlan.sample.Fraction))
+					prev[i].SetNext(a)
+					prev[i] = a
 				}
-				sync.SetNext(a)
-				prev = prev[0:1]
-				prev[0] = a
 			}
 			if ordered {
 				oInfo.nodeMaintainsOrdering()
 			}
+		case plan.Sample != nil:
+			// Create a sampler for each previous plan.
+			stage := newStage(execOpts.analyze, pool, "Sample")
+			for i := range prev {
+				s := stage.instrument(Sample(tracer, plan.Sample))
+				prev[i].SetNext(s)
+				prev[i] = s
+			}
+			outputPlan.sample = plan.Sample
+			oInfo.nodeMaintainsOrdering()
+		case plan.Unnest != nil:
+			// Create an unnest operator for each previous plan.
+			stage := newStage(execOpts.analyze, pool, "Unnest")
+			for i := range prev {
+				u := stage.instrument(Unnest(stage.pool, tracer, plan.Unnest.Expr.Name()))
+				prev[i].SetNext(u)
+				prev[i] = u
+			}
+		case plan.GapFill != nil:
+			// The buckets of a series need to be gap filled together.
+			if len(prev) > 1 {
+				sync := newStage(execOpts.analyze, pool, "Synchronizer").instrument(Synchronize(len(prev)))
+				for i := range prev {
+					prev[i].SetNext(sync)
+				}
+				prev = append(prev[:0], sync)
+			}
+			agg := plan.GapFillAggregation()
+			if agg == nil {
+				visitErr = fmt.Errorf("gap fill input must be an aggregation")
+				return false
+			}
+			stage := newStage(execOpts.analyze, pool, "GapFill")
+			g := stage.instrument(GapFill(stage.pool, tracer, plan.GapFill, agg.GroupExprs))
+			prev[0].SetNext(g)
+			prev[0] = g
+		case plan.Pivot != nil:
+			// The rows of a group need to be pivoted together.
+			if len(prev) > 1 {
+				sync := newStage(execOpts.analyze, pool, "Synchronizer").instrument(Synchronize(len(prev)))
+				for i := range prev {
+					prev[i].SetNext(sync)
+				}
+				prev = append(prev[:0], sync)
+			}
+			stage := newStage(execOpts.analyze, pool, "Pivot")
+			p := stage.instrument(Pivot(stage.pool, tracer, plan.Pivot))
+			prev[0].SetNext(p)
+			prev[0] = p
+		case plan.Unpivot != nil:
+			// Create an unpivot operator for each previous plan.
+			stage := newStage(execOpts.analyze, pool, "Unpivot")
+			for i := range prev {
+				u := stage.instrument(Unpivot(stage.pool, tracer, plan.Unpivot))
+				prev[i].SetNext(u)
+				prev[i] = u
+			}
+		case plan.SemiJoin != nil:
+			// The inner plans of semi joins are executed while the query is
+			// planned, which turns them into filters.
+			visitErr = fmt.Errorf("semi join must be resolved into a filter: %s", plan.SemiJoin)
 		default:
 			panic("Unsupported plan")
 		}
@@ -461,9 +711,9 @@ func Build(
 	}
 
 	// Synchronize the last stage if necessary.
-	var sync *Synchronizer
+	var sync PhysicalPlan
 	if len(prev) > 1 {
-		sync = Synchronize(len(prev))
+		sync = newStage(execOpts.analyze, pool, "Synchronizer").instrument(Synchronize(len(prev)))
 		for i := range prev {
 			prev[i].SetNext(sync)
 		}
@@ -475,6 +725,155 @@ func Build(
 	return outputPlan, nil
 }
 
+// shouldPlanWindowAggregate returns whether the aggregation is grouped by a
+// duration over input ordered by timestamp, so that its time windows can be
+// passed on as the table scan moves past them. The scan reads the row groups
+// in the order of their timestamps, but it only moves past a window early if
+// the row groups hold their timestamps in narrow ranges.
+func shouldPlanWindowAggregate(
+	execOpts execOptions, oInfo *planOrderingInfo, agg *logicalplan.Aggregation,
+) bool {
+	if !execOpts.streamingAggregations || !oInfo.orderingMaintained() {
+		return false
+	}
+	var duration *logicalplan.DurationExpr
+	for _, expr := range agg.GroupExprs {
+		if d, ok := expr.(*logicalplan.DurationExpr); ok {
+			duration = d
+		}
+	}
+	if duration == nil {
+		return false
+	}
+	ordering := oInfo.getNonCoveringOrdering()
+	return len(ordering) > 0 && !ordering[0].Dynamic && duration.MatchColumn(ordering[0].Name)
+}
+
+// planWindowAggregate plans a WindowAggregate for each previous plan. Their
+// windows are merged by a final WindowAggregate that all of them push to. The
+// scan reports its progress through the timestamps to all of them, since the
+// rows of a window can be pushed to any of the previous plans.
+func planWindowAggregate(
+	pool memory.Allocator,
+	tracer trace.Tracer,
+	execOpts execOptions,
+	spill *spiller,
+	agg *logicalplan.Aggregation,
+	scan *TableScan,
+	prev []PhysicalPlan,
+) ([]PhysicalPlan, error) {
+	seed := maphash.MakeSeed()
+	var (
+		final   *WindowAggregate
+		partial []*WindowAggregate
+		inputs  []PhysicalPlan
+	)
+	if len(prev) > 1 {
+		stage := newStage(execOpts.analyze, pool, "WindowAggregate")
+		var err error
+		final, err = NewWindowAggregate(stage.pool, tracer, agg, true, seed, len(prev))
+		if err != nil {
+			return nil, err
+		}
+		if spill != nil {
+			final.setSpiller(spill)
+		}
+		for i := range prev {
+			inputs = append(inputs, stage.instrument(final.Input(i)))
+		}
+	}
+
+	stage := newStage(execOpts.analyze, pool, "WindowAggregate")
+	for i := range prev {
+		w, err := NewWindowAggregate(stage.pool, tracer, agg, inputs == nil, seed, 1)
+		if err != nil {
+			return nil, err
+		}
+		if spill != nil {
+			w.setSpiller(spill)
+		}
+		partial = append(partial, w)
+		wrapped := stage.instrument(w)
+		prev[i].SetNext(wrapped)
+		prev[i] = wrapped
+		if inputs != nil {
+			wrapped.SetNext(inputs[i])
+		}
+	}
+	if inputs != nil {
+		prev = prev[0:1]
+		prev[0] = inputs[0]
+	}
+
+	scan.watermarks = &logicalplan.Watermarks{
+		Column: partial[0].duration.ColumnsUsedExprs()[0].Name(),
+		Advance: func(ctx context.Context, watermark int64) error {
+			for _, w := range partial {
+				if err := w.Advance(ctx, watermark); err != nil {
+					return err
+				}
+			}
+			if final == nil {
+				return nil
+			}
+			return final.Advance(ctx, watermark)
+		},
+	}
+	return prev, nil
+}
+
+// planPartialAggregate plans a HashAggregate for each previous plan, whose
+// results are merged by a final HashAggregate passing on the partial results
+// of the groups. The final aggregate is planned even for a single previous
+// plan, since the hashes the partial aggregates pass on along with their
+// groups are only meaningful to aggregates of the same plan.
+func planPartialAggregate(
+	pool memory.Allocator,
+	tracer trace.Tracer,
+	execOpts execOptions,
+	spill *spiller,
+	agg *logicalplan.Aggregation,
+	metadata bool,
+	prev []PhysicalPlan,
+) ([]PhysicalPlan, error) {
+	seed := maphash.MakeSeed()
+	aggregate := func(stage stage, final bool) (PhysicalPlan, error) {
+		a, err := Aggregate(stage.pool, tracer, agg, final, false, seed)
+		if err != nil {
+			return nil, err
+		}
+		h := a.(*HashAggregate)
+		h.partialOutput = true
+		if spill != nil {
+			h.setSpiller(spill)
+		}
+		return stage.instrument(h), nil
+	}
+
+	final, err := aggregate(newStage(execOpts.analyze, pool, "Aggregate"), true)
+	if err != nil {
+		return nil, err
+	}
+	var sync PhysicalPlan = final
+	if len(prev) > 1 {
+		sync = newStage(execOpts.analyze, pool, "Synchronizer").instrument(Synchronize(len(prev)))
+		sync.SetNext(final)
+	}
+
+	stage := newStage(execOpts.analyze, pool, "Aggregate")
+	for i := range prev {
+		// A scan producing the partial results of the aggregation from
+		// metadata only needs the results to be merged.
+		a, err := aggregate(stage, metadata)
+		if err != nil {
+			return nil, err
+		}
+		prev[i].SetNext(a)
+		a.SetNext(sync)
+	}
+	return append(prev[:0], final), nil
+}
+
 func shouldPlanOrderedAggregate(
 	execOpts execOptions, oInfo *planOrderingInfo, agg *logicalplan.Aggregation,
 ) (bool, error) {
@@ -492,6 +891,11 @@ func shouldPlanOrderedAggregate(
 	groupExprs := agg.GroupExprs
 	ordering := oInfo.getNonCoveringOrdering()
 	for _, expr := range groupExprs {
+		if expr.Computed() {
+			// Computed values are not ordered like the columns they are
+			// computed from.
+			return false, nil
+		}
 		groupCols := expr.ColumnsUsedExprs()
 		if len(groupCols) > 1 {
 			return false, fmt.Errorf("expected only one group column but found %v", groupCols)
@@ -512,12 +916,42 @@ func shouldPlanOrderedAggregate(
 			return false, nil
 		}
 	}
+
+	var (
+		orderBy       logicalplan.Expr
+		partialStates bool
+	)
+	for _, expr := range agg.AggExprs {
+		expr.Accept(PreExprVisitorFunc(func(expr logicalplan.Expr) bool {
+			if f, ok := expr.(*logicalplan.AggregationFunction); ok {
+				orderBy = f.OrderBy
+				partialStates = f.Func.Statistical() || f.Func == logicalplan.AggFuncHistogram || f.Func.Counter()
+			}
+			return true
+		}))
+	}
+	if partialStates {
+		// The ordered sets are merged by aggregating their results, which
+		// are the final statistics, histograms, rates and increases rather
+		// than partial states.
+		return false, nil
+	}
+	if orderBy != nil {
+		// First and last values are only ordered within their groups if
+		// the column they are ordered by sorts right after the group
+		// columns.
+		if len(ordering) == 0 || ordering[0].Dynamic || !orderBy.MatchColumn(ordering[0].Name) {
+			return false, nil
+		}
+	}
 	return true, nil
 }
 
 type Diagram struct {
 	Details string
 	Child   *Diagram
+	// Stats are only set if the plan was built using WithAnalyze.
+	Stats *OperatorStats
 }
 
 func (d *Diagram) String() string {
@@ -530,3 +964,30 @@ func (d *Diagram) String() string {
 	}
 	return d.Details + " - " + child
 }
+
+// AnalyzeString returns the diagram with one operator per line, each followed
+// by its runtime statistics if available.
+func (d *Diagram) AnalyzeString() string {
+	var sb strings.Builder
+	indent := ""
+	for cur := d; cur != nil && cur.Details != ""; cur = cur.Child {
+		if indent != "" {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(indent)
+		sb.WriteString(cur.Details)
+		if cur.Stats != nil {
+			sb.WriteString(" ")
+			sb.WriteString(cur.Stats.String())
+		}
+		indent += "  "
+	}
+	return sb.String()
+}
+
+func scanStats(analyze bool) *OperatorStats {
+	if !analyze {
+		return nil
+	}
+	return &OperatorStats{Scan: &expr.ScanStats{}}
+}
@@ -0,0 +1,355 @@
+package physicalplan
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/polarsignals/frostdb/pqarrow/builder"
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+// Pivoter turns the values of a key column into columns holding the values of
+// a value column. The rows are grouped by the values of all other columns,
+// which are only known once all rows are seen, so all records are buffered
+// until the input finishes.
+type Pivoter struct {
+	pool    memory.Allocator
+	tracer  trace.Tracer
+	pivot   *logicalplan.Pivot
+	records []arrow.Record
+	next    PhysicalPlan
+}
+
+func Pivot(pool memory.Allocator, tracer trace.Tracer, pivot *logicalplan.Pivot) *Pivoter {
+	return &Pivoter{
+		pool:   pool,
+		tracer: tracer,
+		pivot:  pivot,
+	}
+}
+
+func (p *Pivoter) Callback(_ context.Context, r arrow.Record) error {
+	r.Retain()
+	p.records = append(p.records, r)
+	return nil
+}
+
+// pivotCell is the row of a record holding a value.
+type pivotCell struct {
+	record int
+	row    int
+}
+
+// pivotGroup is a row of the pivoted results. Its other columns are the ones
+// of the first row of the group, its pivoted columns are keyed by name.
+type pivotGroup struct {
+	first pivotCell
+	cells map[string]pivotCell
+}
+
+func (p *Pivoter) Finish(ctx context.Context) error {
+	ctx, span := p.tracer.Start(ctx, "Pivoter/Finish")
+	defer span.End()
+	defer p.release()
+
+	var (
+		fields    []arrow.Field
+		fieldIdx  = map[string]int{}
+		keyIdx    = make([]int, len(p.records))
+		valueIdx  = make([]int, len(p.records))
+		valueType arrow.DataType
+	)
+	for i, r := range p.records {
+		keyIdx[i], valueIdx[i] = -1, -1
+		for j, f := range r.Schema().Fields() {
+			switch {
+			case keyIdx[i] == -1 && p.pivot.Key.MatchColumn(f.Name):
+				keyIdx[i] = j
+			case valueIdx[i] == -1 && p.pivot.Value.MatchColumn(f.Name):
+				valueIdx[i] = j
+				if valueType == nil {
+					valueType = f.Type
+				} else if !arrow.TypeEqual(valueType, f.Type) {
+					return fmt.Errorf("pivot value column %s has different types: %s and %s", f.Name, valueType, f.Type)
+				}
+			default:
+				if _, ok := fieldIdx[f.Name]; !ok {
+					fieldIdx[f.Name] = len(fields)
+					fields = append(fields, f)
+				}
+			}
+		}
+		if keyIdx[i] == -1 || valueIdx[i] == -1 {
+			return fmt.Errorf("pivot key column %s and value column %s not found", p.pivot.Key.Name(), p.pivot.Value.Name())
+		}
+	}
+
+	// The other columns of each record, in the order of the fields. Records
+	// lacking a column have nulls in it.
+	columns := make([][]arrow.Array, len(p.records))
+	for i, r := range p.records {
+		columns[i] = make([]arrow.Array, len(fields))
+		for j, f := range r.Schema().Fields() {
+			if j == keyIdx[i] || j == valueIdx[i] {
+				continue
+			}
+			columns[i][fieldIdx[f.Name]] = r.Column(j)
+		}
+		for j, col := range columns[i] {
+			if col == nil {
+				fields[j].Nullable = true
+			}
+		}
+	}
+
+	var (
+		groups   []pivotGroup
+		groupIdx = map[string]int{}
+		names    = map[string]struct{}{}
+		key      []byte
+	)
+	for i, r := range p.records {
+		keys := r.Column(keyIdx[i])
+		for j := 0; j < int(r.NumRows()); j++ {
+			if keys.IsNull(j) {
+				continue
+			}
+			key = key[:0]
+			for _, col := range columns[i] {
+				if col == nil || col.IsNull(j) {
+					key = append(key, 0)
+					continue
+				}
+				v := col.ValueStr(j)
+				key = append(key, 1)
+				key = binary.AppendUvarint(key, uint64(len(v)))
+				key = append(key, v...)
+			}
+			idx, ok := groupIdx[string(key)]
+			if !ok {
+				idx = len(groups)
+				groupIdx[string(key)] = idx
+				groups = append(groups, pivotGroup{
+					first: pivotCell{record: i, row: j},
+					cells: map[string]pivotCell{},
+				})
+			}
+			name := stringValue(keys, j)
+			if p.pivot.Prefix != "" {
+				name = p.pivot.Prefix + "." + name
+			}
+			names[name] = struct{}{}
+			groups[idx].cells[name] = pivotCell{record: i, row: j}
+		}
+	}
+	if len(groups) == 0 {
+		return p.next.Finish(ctx)
+	}
+
+	// The pivoted columns are sorted by name, like the concrete columns of
+	// dynamic columns.
+	pivoted := make([]string, 0, len(names))
+	for name := range names {
+		pivoted = append(pivoted, name)
+	}
+	sort.Strings(pivoted)
+	numOther := len(fields)
+	for _, name := range pivoted {
+		fields = append(fields, arrow.Field{Name: name, Type: valueType, Nullable: true})
+	}
+
+	b := builder.NewRecordBuilder(p.pool, arrow.NewSchema(fields, nil))
+	defer b.Release()
+	b.Reserve(len(groups))
+	for _, g := range groups {
+		for k, col := range columns[g.first.record] {
+			if err := builder.AppendValue(b.Field(k), col, g.first.row); err != nil {
+				return fmt.Errorf("pivot column %s: %w", fields[k].Name, err)
+			}
+		}
+		for k, name := range pivoted {
+			cb := b.Field(numOther + k)
+			cell, ok := g.cells[name]
+			if !ok {
+				cb.AppendNull()
+				continue
+			}
+			values := p.records[cell.record].Column(valueIdx[cell.record])
+			if err := builder.AppendValue(cb, values, cell.row); err != nil {
+				return fmt.Errorf("pivot column %s: %w", name, err)
+			}
+		}
+	}
+
+	r := b.NewRecord()
+	defer r.Release()
+	if err := p.next.Callback(ctx, r); err != nil {
+		return err
+	}
+	return p.next.Finish(ctx)
+}
+
+func (p *Pivoter) SetNext(next PhysicalPlan) {
+	p.next = next
+}
+
+func (p *Pivoter) Draw() *Diagram {
+	var child *Diagram
+	if p.next != nil {
+		child = p.next.Draw()
+	}
+	details := fmt.Sprintf("Pivot (%s, %s)", p.pivot.Key.Name(), p.pivot.Value.Name())
+	return &Diagram{Details: details, Child: child}
+}
+
+func (p *Pivoter) Close() {
+	p.release()
+	p.next.Close()
+}
+
+func (p *Pivoter) release() {
+	for _, r := range p.records {
+		r.Release()
+	}
+	p.records = nil
+}
+
+// Unpivoter turns the columns an expression matches into rows of key and
+// value columns, repeating the values of the other columns. Null values don't
+// produce any rows.
+type Unpivoter struct {
+	pool    memory.Allocator
+	tracer  trace.Tracer
+	unpivot *logicalplan.Unpivot
+	next    PhysicalPlan
+}
+
+func Unpivot(pool memory.Allocator, tracer trace.Tracer, unpivot *logicalplan.Unpivot) *Unpivoter {
+	return &Unpivoter{
+		pool:    pool,
+		tracer:  tracer,
+		unpivot: unpivot,
+	}
+}
+
+func (u *Unpivoter) Callback(ctx context.Context, r arrow.Record) error {
+	// Generates high volume of spans. Comment out if needed during development.
+	// ctx, span := u.tracer.Start(ctx, "Unpivoter/Callback")
+	// defer span.End()
+
+	var (
+		fields  []arrow.Field
+		other   []int
+		matched []int
+		keys    []string
+	)
+	for i, f := range r.Schema().Fields() {
+		if !u.unpivot.Expr.MatchColumn(f.Name) {
+			other = append(other, i)
+			fields = append(fields, f)
+			continue
+		}
+		if len(matched) > 0 && !arrow.TypeEqual(r.Column(matched[0]).DataType(), f.Type) {
+			return fmt.Errorf("unpivot columns %s have different types: %s and %s",
+				u.unpivot.Expr.Name(), r.Column(matched[0]).DataType(), f.Type)
+		}
+		matched = append(matched, i)
+		keys = append(keys, u.key(f.Name))
+	}
+
+	numRows := 0
+	for _, i := range matched {
+		col := r.Column(i)
+		numRows += col.Len() - col.NullN()
+	}
+	if numRows == 0 {
+		return nil
+	}
+
+	value := r.Schema().Field(matched[0])
+	fields = append(fields,
+		arrow.Field{Name: u.unpivot.Key, Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: u.unpivot.Value, Type: value.Type, Nullable: value.Nullable},
+	)
+	b := builder.NewRecordBuilder(u.pool, arrow.NewSchema(fields, nil))
+	defer b.Release()
+	b.Reserve(numRows)
+
+	keyIdx, valueIdx := len(other), len(other)+1
+	for j := 0; j < int(r.NumRows()); j++ {
+		for m, i := range matched {
+			values := r.Column(i)
+			if values.IsNull(j) {
+				continue
+			}
+			for k, o := range other {
+				if err := builder.AppendValue(b.Field(k), r.Column(o), j); err != nil {
+					return fmt.Errorf("unpivot column %s: %w", fields[k].Name, err)
+				}
+			}
+			if err := builder.AppendGoValue(b.Field(keyIdx), keys[m]); err != nil {
+				return fmt.Errorf("unpivot key column %s: %w", u.unpivot.Key, err)
+			}
+			if err := builder.AppendValue(b.Field(valueIdx), values, j); err != nil {
+				return fmt.Errorf("unpivot value column %s: %w", u.unpivot.Value, err)
+			}
+		}
+	}
+
+	out := b.NewRecord()
+	defer out.Release()
+	return u.next.Callback(ctx, out)
+}
+
+// key returns the key of the unpivoted column. The concrete columns of a
+// dynamic column are keyed by their names without the dynamic column's name.
+func (u *Unpivoter) key(column string) string {
+	if dyn, ok := u.unpivot.Expr.(*logicalplan.DynamicColumn); ok {
+		return strings.TrimPrefix(column, dyn.ColumnName+".")
+	}
+	return column
+}
+
+func (u *Unpivoter) Finish(ctx context.Context) error {
+	return u.next.Finish(ctx)
+}
+
+func (u *Unpivoter) SetNext(next PhysicalPlan) {
+	u.next = next
+}
+
+func (u *Unpivoter) Draw() *Diagram {
+	var child *Diagram
+	if u.next != nil {
+		child = u.next.Draw()
+	}
+	details := fmt.Sprintf("Unpivot (%s)", u.unpivot.Expr.Name())
+	return &Diagram{Details: details, Child: child}
+}
+
+func (u *Unpivoter) Close() {
+	u.next.Close()
+}
+
+// stringValue returns the value of a row as a string. Binary values are taken
+// as they are rather than encoded.
+func stringValue(arr arrow.Array, i int) string {
+	switch a := arr.(type) {
+	case *array.Binary:
+		return string(a.Value(i))
+	case *array.String:
+		return a.Value(i)
+	case *array.Dictionary:
+		return stringValue(a.Dictionary(), a.GetValueIndex(i))
+	default:
+		return arr.ValueStr(i)
+	}
+}
@@ -3,6 +3,7 @@ package physicalplan
 import (
 	"context"
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/apache/arrow/go/v14/arrow"
@@ -44,6 +45,15 @@ func (a aliasProjection) Project(mem memory.Allocator, ar arrow.Record) ([]arrow
 			}
 		}
 		return fields, array, nil
+	case *logicalplan.WidthBucketExpr:
+		fields, arrays, err := widthBucketProjection{expr: e}.Project(mem, ar)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range fields {
+			fields[i].Name = a.name
+		}
+		return fields, arrays, nil
 	case *logicalplan.Column:
 		for i := 0; i < ar.Schema().NumFields(); i++ {
 			field := ar.Schema().Field(i)
@@ -126,6 +136,56 @@ func (b binaryExprProjection) Project(mem memory.Allocator, ar arrow.Record) ([]
 	}, []arrow.Array{builder.NewArray()}, nil
 }
 
+type widthBucketProjection struct {
+	expr *logicalplan.WidthBucketExpr
+}
+
+func (w widthBucketProjection) Name() string {
+	return w.expr.Name()
+}
+
+func (w widthBucketProjection) Project(mem memory.Allocator, ar arrow.Record) ([]arrow.Field, []arrow.Array, error) {
+	proj, err := projectionFromExpr(w.expr.Expr)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, arrs, err := proj.Project(mem, ar)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		for _, arr := range arrs {
+			arr.Release()
+		}
+	}()
+	if len(arrs) != 1 {
+		return nil, nil, fmt.Errorf("%s: expected a single column, got %d", w.expr.Name(), len(arrs))
+	}
+	values, err := float64Values(arrs[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", w.expr.Name(), err)
+	}
+
+	builder := array.NewInt64Builder(mem)
+	defer builder.Release()
+	builder.Reserve(arrs[0].Len())
+	for i := 0; i < arrs[0].Len(); i++ {
+		if arrs[0].IsNull(i) || math.IsNaN(values(i)) {
+			builder.AppendNull()
+			continue
+		}
+		builder.Append(widthBucket(values(i), w.expr.Min, w.expr.Max, w.expr.Buckets))
+	}
+
+	return []arrow.Field{
+		{
+			Name:     w.expr.Name(),
+			Type:     arrow.PrimitiveTypes.Int64,
+			Nullable: true,
+		},
+	}, []arrow.Array{builder.NewArray()}, nil
+}
+
 type plainProjection struct {
 	expr *logicalplan.Column
 }
@@ -196,6 +256,8 @@ func projectionFromExpr(expr logicalplan.Expr) (columnProjection, error) {
 		}, nil
 	case *logicalplan.AverageExpr:
 		return &averageProjection{expr: e}, nil
+	case *logicalplan.WidthBucketExpr:
+		return widthBucketProjection{expr: e}, nil
 	default:
 		return nil, fmt.Errorf("unsupported expression type for projection: %T", expr)
 	}
@@ -341,17 +403,40 @@ func (a *averageProjection) Project(mem memory.Allocator, r arrow.Record) ([]arr
 	}
 
 	// Add the field and column for the projected average aggregation.
+	var avgs arrow.Array
+	switch sums := sums.(type) {
+	case *array.Int64:
+		avgs = avgInt64arrays(mem, sums, counts)
+	case *array.Float64:
+		avgs = avgFloat64arrays(mem, sums, counts)
+	default:
+		for _, c := range columns {
+			c.Release()
+		}
+		return nil, nil, fmt.Errorf("unsupported sum type for average projection: %s", sums.DataType())
+	}
 	fields = append(fields, arrow.Field{
 		Name: resultName,
-		Type: &arrow.Int64Type{},
+		Type: avgs.DataType(),
 	})
-	columns = append(columns, avgInt64arrays(mem, sums, counts))
+	columns = append(columns, avgs)
 
 	return fields, columns, nil
 }
 
-func avgInt64arrays(pool memory.Allocator, sums, counts arrow.Array) arrow.Array {
-	sumsInts := sums.(*array.Int64)
+func avgFloat64arrays(pool memory.Allocator, sums *array.Float64, counts arrow.Array) arrow.Array {
+	countsInts := counts.(*array.Int64)
+
+	res := array.NewFloat64Builder(pool)
+	defer res.Release()
+	for i := 0; i < sums.Len(); i++ {
+		res.Append(sums.Value(i) / float64(countsInts.Value(i)))
+	}
+
+	return res.NewArray()
+}
+
+func avgInt64arrays(pool memory.Allocator, sumsInts *array.Int64, counts arrow.Array) arrow.Array {
 	countsInts := counts.(*array.Int64)
 
 	res := array.NewInt64Builder(pool)
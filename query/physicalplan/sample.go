@@ -0,0 +1,211 @@
+package physicalplan
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+// Sampler marks the rows of a sampled table scan. The rows are sampled by the
+// table scan the sample is pushed down to, which samples them by where they
+// are stored, so they are passed on as is.
+type Sampler struct {
+	tracer trace.Tracer
+	sample *logicalplan.Sample
+	next   PhysicalPlan
+}
+
+// Sample returns a Sampler for the sample.
+func Sample(tracer trace.Tracer, sample *logicalplan.Sample) *Sampler {
+	return &Sampler{
+		tracer: tracer,
+		sample: sample,
+	}
+}
+
+func (s *Sampler) Callback(ctx context.Context, r arrow.Record) error {
+	// Generates high volume of spans. Comment out if needed during development.
+	// ctx, span := s.tracer.Start(ctx, "Sampler/Callback")
+	// defer span.End()
+
+	return s.next.Callback(ctx, r)
+}
+
+func (s *Sampler) Finish(ctx context.Context) error {
+	return s.next.Finish(ctx)
+}
+
+func (s *Sampler) SetNext(next PhysicalPlan) {
+	s.next = next
+}
+
+func (s *Sampler) Draw() *Diagram {
+	var child *Diagram
+	if s.next != nil {
+		child = s.next.Draw()
+	}
+	details := fmt.Sprintf("Sample (%s %g)", s.sample.Method, s.sample.Fraction)
+	return &Diagram{Details: details, Child: child}
+}
+
+func (s *Sampler) Close() {
+	s.next.Close()
+}
+
+// AggregationScaler scales the results of the sum and count aggregations of a
+// sample up to the full data by dividing them by the sample's fraction.
+type AggregationScaler struct {
+	pool     memory.Allocator
+	tracer   trace.Tracer
+	fraction float64
+	aggs     []scaledAggregation
+	next     PhysicalPlan
+}
+
+// scaledAggregation matches the result columns of a sum or count aggregation.
+type scaledAggregation struct {
+	function logicalplan.AggFunc
+	expr     logicalplan.Expr
+	alias    string
+}
+
+func (a scaledAggregation) matches(name string) bool {
+	if a.alias != "" {
+		return name == a.alias
+	}
+	// The result columns are named after the concrete columns that are
+	// aggregated, e.g. sum(labels.foo) for a dynamic column.
+	prefix := strings.TrimSuffix(logicalplan.ResultNameWithConcreteColumn(a.function, ""), ")")
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ")") {
+		return false
+	}
+	return a.expr.MatchColumn(name[len(prefix) : len(name)-1])
+}
+
+// ScaleAggregations returns an AggregationScaler for the sum and count
+// aggregations among the given aggregations of a sample of the fraction.
+func ScaleAggregations(pool memory.Allocator, tracer trace.Tracer, aggExprs []logicalplan.Expr, fraction float64) *AggregationScaler {
+	s := &AggregationScaler{
+		pool:     pool,
+		tracer:   tracer,
+		fraction: fraction,
+	}
+	for _, expr := range aggExprs {
+		var alias string
+		if a, ok := expr.(*logicalplan.AliasExpr); ok {
+			alias, expr = a.Alias, a.Expr
+		}
+		f, ok := expr.(*logicalplan.AggregationFunction)
+		if !ok {
+			continue
+		}
+		switch f.Func {
+		case logicalplan.AggFuncSum, logicalplan.AggFuncCount:
+			s.aggs = append(s.aggs, scaledAggregation{function: f.Func, expr: f.Expr, alias: alias})
+		}
+	}
+	return s
+}
+
+func (s *AggregationScaler) Callback(ctx context.Context, r arrow.Record) error {
+	cols := make([]arrow.Array, r.NumCols())
+	defer func() {
+		for _, c := range cols {
+			if c != nil {
+				c.Release()
+			}
+		}
+	}()
+	for i, f := range r.Schema().Fields() {
+		for _, agg := range s.aggs {
+			if agg.matches(f.Name) {
+				scaled, err := s.scale(r.Column(i))
+				if err != nil {
+					return fmt.Errorf("scale %s: %w", f.Name, err)
+				}
+				cols[i] = scaled
+				break
+			}
+		}
+		if cols[i] == nil {
+			cols[i] = r.Column(i)
+			cols[i].Retain()
+		}
+	}
+
+	scaled := array.NewRecord(r.Schema(), cols, r.NumRows())
+	defer scaled.Release()
+	return s.next.Callback(ctx, scaled)
+}
+
+func (s *AggregationScaler) scale(arr arrow.Array) (arrow.Array, error) {
+	switch arr := arr.(type) {
+	case *array.Int64:
+		b := array.NewInt64Builder(s.pool)
+		defer b.Release()
+		b.Reserve(arr.Len())
+		for i := 0; i < arr.Len(); i++ {
+			if arr.IsNull(i) {
+				b.AppendNull()
+				continue
+			}
+			b.Append(int64(math.Round(float64(arr.Value(i)) / s.fraction)))
+		}
+		return b.NewArray(), nil
+	case *array.Uint64:
+		b := array.NewUint64Builder(s.pool)
+		defer b.Release()
+		b.Reserve(arr.Len())
+		for i := 0; i < arr.Len(); i++ {
+			if arr.IsNull(i) {
+				b.AppendNull()
+				continue
+			}
+			b.Append(uint64(math.Round(float64(arr.Value(i)) / s.fraction)))
+		}
+		return b.NewArray(), nil
+	case *array.Float64:
+		b := array.NewFloat64Builder(s.pool)
+		defer b.Release()
+		b.Reserve(arr.Len())
+		for i := 0; i < arr.Len(); i++ {
+			if arr.IsNull(i) {
+				b.AppendNull()
+				continue
+			}
+			b.Append(arr.Value(i) / s.fraction)
+		}
+		return b.NewArray(), nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", arr.DataType())
+	}
+}
+
+func (s *AggregationScaler) Finish(ctx context.Context) error {
+	return s.next.Finish(ctx)
+}
+
+func (s *AggregationScaler) SetNext(next PhysicalPlan) {
+	s.next = next
+}
+
+func (s *AggregationScaler) Draw() *Diagram {
+	var child *Diagram
+	if s.next != nil {
+		child = s.next.Draw()
+	}
+	details := fmt.Sprintf("AggregationScaler (%g)", s.fraction)
+	return &Diagram{Details: details, Child: child}
+}
+
+func (s *AggregationScaler) Close() {
+	s.next.Close()
+}
@@ -0,0 +1,53 @@
+package physicalplan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+func TestAggregationScaler(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	b := array.NewRecordBuilder(mem, arrow.NewSchema([]arrow.Field{
+		{Name: "labels.job", Type: arrow.BinaryTypes.String},
+		{Name: "sum(value)", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "rows", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "max(value)", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "avg", Type: arrow.PrimitiveTypes.Float64},
+	}, nil))
+	defer b.Release()
+	b.Field(0).(*array.StringBuilder).AppendValues([]string{"a", "b"}, nil)
+	b.Field(1).(*array.Int64Builder).AppendValues([]int64{10, 3}, nil)
+	b.Field(2).(*array.Int64Builder).AppendValues([]int64{2, 1}, nil)
+	b.Field(3).(*array.Int64Builder).AppendValues([]int64{7, 3}, nil)
+	b.Field(4).(*array.Float64Builder).AppendValues([]float64{5, 3}, nil)
+	r := b.NewRecord()
+	defer r.Release()
+
+	s := ScaleAggregations(mem, trace.NewNoopTracerProvider().Tracer(""), []logicalplan.Expr{
+		logicalplan.Sum(logicalplan.Col("value")),
+		logicalplan.Count(logicalplan.Col("value")).Alias("rows"),
+		logicalplan.Max(logicalplan.Col("value")),
+		logicalplan.Avg(logicalplan.Col("value")).Alias("avg"),
+	}, 0.25)
+	output := &OutputPlan{}
+	output.SetNextCallback(func(_ context.Context, r arrow.Record) error {
+		require.Equal(t, []int64{40, 12}, r.Column(1).(*array.Int64).Int64Values())
+		require.Equal(t, []int64{8, 4}, r.Column(2).(*array.Int64).Int64Values())
+		// Maxima and averages are not scaled.
+		require.Equal(t, []int64{7, 3}, r.Column(3).(*array.Int64).Int64Values())
+		require.Equal(t, []float64{5, 3}, r.Column(4).(*array.Float64).Float64Values())
+		return nil
+	})
+	s.SetNext(output)
+	require.NoError(t, s.Callback(context.Background(), r))
+}
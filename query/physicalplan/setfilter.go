@@ -0,0 +1,169 @@
+package physicalplan
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/scalar"
+
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+// SetFilter keeps the rows whose value is one of a set of values, or with
+// notIn, the rows whose value isn't. The values are held in hash sets by
+// their type, so that large sets, like the ones of semi joins, are cheap to
+// look up.
+type SetFilter struct {
+	left  *ArrayRef
+	notIn bool
+	size  int
+
+	null    bool
+	strings map[string]struct{}
+	ints    map[int64]struct{}
+	uints   map[uint64]struct{}
+	floats  map[float64]struct{}
+	bools   map[bool]struct{}
+}
+
+func setFilter(expr *logicalplan.InExpr) (*SetFilter, error) {
+	col, ok := expr.Expr.(*logicalplan.Column)
+	if !ok {
+		return nil, errors.New("set filters must be of a column")
+	}
+
+	f := &SetFilter{
+		left:    &ArrayRef{ColumnName: col.ColumnName},
+		notIn:   expr.Not,
+		size:    len(expr.Values),
+		strings: map[string]struct{}{},
+		ints:    map[int64]struct{}{},
+		uints:   map[uint64]struct{}{},
+		floats:  map[float64]struct{}{},
+		bools:   map[bool]struct{}{},
+	}
+	for _, v := range expr.Values {
+		if !v.IsValid() {
+			f.null = true
+			continue
+		}
+		switch s := v.(type) {
+		case *scalar.Binary:
+			f.strings[string(s.Data())] = struct{}{}
+		case *scalar.String:
+			f.strings[string(s.Data())] = struct{}{}
+		case *scalar.Int64:
+			f.ints[s.Value] = struct{}{}
+		case *scalar.Int32:
+			f.ints[int64(s.Value)] = struct{}{}
+		case *scalar.Uint64:
+			f.uints[s.Value] = struct{}{}
+		case *scalar.Float64:
+			f.floats[s.Value] = struct{}{}
+		case *scalar.Boolean:
+			f.bools[s.Value] = struct{}{}
+		default:
+			return nil, fmt.Errorf("unsupported set filter value of type: %s", v.DataType())
+		}
+	}
+	return f, nil
+}
+
+func (f *SetFilter) Eval(r arrow.Record) (*Bitmap, error) {
+	res := NewBitmap()
+	leftData, exists, err := f.left.ArrowArray(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		// Missing columns are null, which equals the empty string.
+		_, empty := f.strings[""]
+		if (f.null || empty) != f.notIn {
+			res.AddRange(0, uint64(r.NumRows()))
+		}
+		return res, nil
+	}
+
+	contains, err := f.contains(leftData)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < leftData.Len(); i++ {
+		in := f.null
+		if !leftData.IsNull(i) {
+			in = contains(i)
+		}
+		if in != f.notIn {
+			res.Add(uint32(i))
+		}
+	}
+	return res, nil
+}
+
+// contains returns a function reporting whether the non-null value of a row
+// of the array is in the set.
+func (f *SetFilter) contains(arr arrow.Array) (func(i int) bool, error) {
+	switch a := arr.(type) {
+	case *array.Binary:
+		return func(i int) bool {
+			_, ok := f.strings[string(a.Value(i))]
+			return ok
+		}, nil
+	case *array.String:
+		return func(i int) bool {
+			_, ok := f.strings[a.Value(i)]
+			return ok
+		}, nil
+	case *array.Int64:
+		return func(i int) bool {
+			_, ok := f.ints[a.Value(i)]
+			return ok
+		}, nil
+	case *array.Int32:
+		return func(i int) bool {
+			_, ok := f.ints[int64(a.Value(i))]
+			return ok
+		}, nil
+	case *array.Uint64:
+		return func(i int) bool {
+			_, ok := f.uints[a.Value(i)]
+			return ok
+		}, nil
+	case *array.Float64:
+		return func(i int) bool {
+			_, ok := f.floats[a.Value(i)]
+			return ok
+		}, nil
+	case *array.Boolean:
+		return func(i int) bool {
+			_, ok := f.bools[a.Value(i)]
+			return ok
+		}, nil
+	case *array.Dictionary:
+		// Every value of the dictionary is looked up once.
+		dict := a.Dictionary()
+		contains, err := f.contains(dict)
+		if err != nil {
+			return nil, err
+		}
+		in := make([]bool, dict.Len())
+		for i := range in {
+			in[i] = !dict.IsNull(i) && contains(i)
+		}
+		return func(i int) bool {
+			return in[a.GetValueIndex(i)]
+		}, nil
+	default:
+		return nil, fmt.Errorf("SetFilter: unsupported type: %T", arr)
+	}
+}
+
+func (f *SetFilter) String() string {
+	if f.notIn {
+		return fmt.Sprintf("%s NOT IN (%d values)", f.left.String(), f.size)
+	}
+	return fmt.Sprintf("%s IN (%d values)", f.left.String(), f.size)
+}
@@ -0,0 +1,66 @@
+package physicalplan
+
+import (
+	"testing"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/apache/arrow/go/v14/arrow/scalar"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+func TestSetFilter(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "name", Type: &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Uint32, ValueType: arrow.BinaryTypes.Binary}},
+	}, nil)
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	b.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 0, 4}, []bool{true, true, false, true})
+	names := b.Field(1).(*array.BinaryDictionaryBuilder)
+	for _, name := range []string{"a", "b", "a", "c"} {
+		require.NoError(t, names.AppendString(name))
+	}
+	r := b.NewRecord()
+	defer r.Release()
+
+	for _, tc := range []struct {
+		expr     *logicalplan.InExpr
+		expected []uint32
+	}{
+		{
+			expr:     logicalplan.In(logicalplan.Col("id"), scalar.NewInt64Scalar(2), scalar.NewInt64Scalar(4), scalar.NewInt64Scalar(5)),
+			expected: []uint32{1, 3},
+		},
+		{
+			expr:     logicalplan.NotIn(logicalplan.Col("id"), scalar.NewInt64Scalar(2), scalar.NewInt64Scalar(4)),
+			expected: []uint32{0, 2},
+		},
+		{
+			expr:     logicalplan.In(logicalplan.Col("name"), scalar.NewBinaryScalar(memory.NewBufferBytes([]byte("a")), arrow.BinaryTypes.Binary)),
+			expected: []uint32{0, 2},
+		},
+		{
+			expr:     logicalplan.NotIn(logicalplan.Col("name"), scalar.NewStringScalar("a"), scalar.NewStringScalar("c")),
+			expected: []uint32{1},
+		},
+		{
+			expr:     logicalplan.In(logicalplan.Col("missing"), scalar.NewStringScalar("")),
+			expected: []uint32{0, 1, 2, 3},
+		},
+	} {
+		t.Run(tc.expr.String(), func(t *testing.T) {
+			f, err := booleanExpr(tc.expr)
+			require.NoError(t, err)
+			res, err := f.Eval(r)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, res.ToArray())
+		})
+	}
+}
@@ -0,0 +1,543 @@
+package physicalplan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/ipc"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+
+	"github.com/polarsignals/frostdb/pqarrow/builder"
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+// ErrSpillDiskBudgetExceeded is returned when a query attempts to spill more
+// data to disk than its disk budget allows.
+var ErrSpillDiskBudgetExceeded = errors.New("spill disk budget exceeded")
+
+const defaultSpillPartitions = 16
+
+// SpillConfig configures how operators spill their state to disk once it
+// grows too large to be held in memory.
+type SpillConfig struct {
+	// Dir is the directory in which each query creates its own spill
+	// directory. It defaults to the system's temporary directory.
+	Dir string
+	// MemoryLimit is the number of bytes an aggregation may allocate for its
+	// state before spilling it to disk. Partitions whose merged state exceeds
+	// it again are spilled into smaller partitions.
+	MemoryLimit int64
+	// DiskBudget is the maximum number of bytes a query may spill to disk.
+	// Zero means unlimited.
+	DiskBudget int64
+	// Partitions is the number of hash partitions spilled state is split
+	// into. Each partition is merged separately at the end, so only a single
+	// partition needs to fit into memory at a time. Defaults to 16.
+	Partitions int
+}
+
+// WithSpill enables operators to spill their state to disk. Every execution
+// of the plan uses its own spill directory and disk budget.
+func WithSpill(cfg SpillConfig) Option {
+	return func(o *execOptions) {
+		o.spill = &cfg
+	}
+}
+
+// spiller manages the spill directory and disk budget of a single query.
+type spiller struct {
+	cfg SpillConfig
+
+	mtx sync.Mutex
+	// dir is created lazily on the first spill.
+	dir string
+
+	files   atomic.Int64
+	written atomic.Int64
+}
+
+func newSpiller(cfg SpillConfig) *spiller {
+	if cfg.Partitions <= 0 {
+		cfg.Partitions = defaultSpillPartitions
+	}
+	return &spiller{cfg: cfg}
+}
+
+func (s *spiller) createFile() (*os.File, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.dir == "" {
+		dir, err := os.MkdirTemp(s.cfg.Dir, "frostdb-spill-")
+		if err != nil {
+			return nil, fmt.Errorf("create spill directory: %w", err)
+		}
+		s.dir = dir
+	}
+
+	return os.Create(filepath.Join(s.dir, strconv.FormatInt(s.files.Add(1), 10)+".arrow"))
+}
+
+// write writes the record to a new spill file and returns its path.
+func (s *spiller) write(r arrow.Record) (string, error) {
+	f, err := s.createFile()
+	if err != nil {
+		return "", err
+	}
+
+	w := ipc.NewWriter(&budgetWriter{w: f, s: s}, ipc.WithSchema(r.Schema()))
+	err = w.Write(r)
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("spill record: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// read calls fn for every record of the spill file and removes the file
+// afterwards. The records are only valid for the duration of the call.
+func (s *spiller) read(path string, pool memory.Allocator, fn func(arrow.Record) error) error {
+	defer os.Remove(path)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r, err := ipc.NewReader(f, ipc.WithAllocator(pool))
+	if err != nil {
+		return fmt.Errorf("read spill file: %w", err)
+	}
+	defer r.Release()
+
+	for r.Next() {
+		if err := fn(r.Record()); err != nil {
+			return err
+		}
+	}
+	return r.Err()
+}
+
+// cleanup removes the query's spill directory. It is safe to call on a nil
+// spiller.
+func (s *spiller) cleanup() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.dir == "" {
+		return nil
+	}
+	err := os.RemoveAll(s.dir)
+	s.dir = ""
+	return err
+}
+
+// budgetWriter fails writes that exceed the spiller's disk budget.
+type budgetWriter struct {
+	w io.Writer
+	s *spiller
+}
+
+func (b *budgetWriter) Write(p []byte) (int, error) {
+	written := b.s.written.Add(int64(len(p)))
+	if b.s.cfg.DiskBudget > 0 && written > b.s.cfg.DiskBudget {
+		return 0, ErrSpillDiskBudgetExceeded
+	}
+	return b.w.Write(p)
+}
+
+// stateAllocator accounts the memory allocated through it, the way
+// LimitAllocator accounts the memory of a query, so that an aggregation
+// spills once the memory of its state exceeds the limit.
+type stateAllocator struct {
+	memory.Allocator
+	allocated atomic.Int64
+}
+
+func (a *stateAllocator) Allocate(size int) []byte {
+	a.allocated.Add(int64(size))
+	return a.Allocator.Allocate(size)
+}
+
+func (a *stateAllocator) Reallocate(size int, b []byte) []byte {
+	a.allocated.Add(int64(size - len(b)))
+	return a.Allocator.Reallocate(size, b)
+}
+
+func (a *stateAllocator) Free(b []byte) {
+	a.allocated.Add(-int64(len(b)))
+	a.Allocator.Free(b)
+}
+
+// spillFunctionKey is the metadata key that marks spilled fields holding
+// partial aggregates. Its value is the aggregation function.
+const spillFunctionKey = "frostdb.aggregate_function"
+
+// aggregateSpill is the spill state of a HashAggregate.
+type aggregateSpill struct {
+	*spiller
+
+	// state is the allocator of the aggregation.
+	state *stateAllocator
+	// baseline is the memory allocated through state when the aggregation's
+	// state was last reset. It is held by records passed on to the next
+	// operator rather than by the state.
+	baseline int64
+	// partitions holds the spill files of each hash partition.
+	partitions [][]string
+}
+
+// exceeded returns whether the memory allocated for the state of the
+// aggregation exceeds the memory limit.
+func (s *aggregateSpill) exceeded() bool {
+	return s.state.allocated.Load()-s.baseline > s.cfg.MemoryLimit
+}
+
+func (s *aggregateSpill) spilled() bool {
+	for _, paths := range s.partitions {
+		if len(paths) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// setSpiller enables the aggregation to spill its state using the spiller.
+// It must be set before any record is aggregated.
+func (a *HashAggregate) setSpiller(s *spiller) {
+	state := &stateAllocator{Allocator: a.pool}
+	a.pool = state
+	a.spill = &aggregateSpill{
+		spiller:    s,
+		state:      state,
+		partitions: make([][]string, s.cfg.Partitions),
+	}
+}
+
+// newBuilder returns a builder for the state of the aggregation. Aggregations
+// that spill build their state with Arrow's builders, which allocate through
+// the pool, so that the pool accounts the memory of their state.
+func (a *HashAggregate) newBuilder(t arrow.DataType) builder.ColumnBuilder {
+	if a.spill != nil {
+		switch t.(type) {
+		case *arrow.BinaryType, *arrow.Int64Type, *arrow.BooleanType:
+			return array.NewBuilder(a.pool, t)
+		}
+	}
+	return builder.NewBuilder(a.pool, t)
+}
+
+// spillState writes the partial aggregates of all groups to disk,
+// partitioned by the hash of the groups, and resets the in-memory state.
+func (a *HashAggregate) spillState() error {
+	return a.spillStateTo(a.spill.partitions, 1, false)
+}
+
+// spillStateTo writes the partial aggregates of all groups to the given
+// partitions and resets the in-memory state. A group's partition is picked by
+// its hash divided by divisor, so that partitions can be split further by the
+// higher digits of the hashes. merged indicates that the state holds merged
+// partial aggregates rather than raw values.
+func (a *HashAggregate) spillStateTo(partitions [][]string, divisor uint64, merged bool) error {
+	for i, aggregate := range a.aggregates {
+		if err := a.spillAggregate(i, aggregate, partitions, divisor, merged); err != nil {
+			return err
+		}
+	}
+	a.resetState()
+	return nil
+}
+
+// spillAggregate writes records consisting of the hash of each group, the
+// values grouped by and the partial aggregates.
+func (a *HashAggregate) spillAggregate(aggIdx int, aggregate *hashAggregate, partitions [][]string, divisor uint64, merged bool) error {
+	numRows := aggregate.rowCount
+	if numRows == 0 {
+		return nil
+	}
+
+	hashes := make([]int64, numRows)
+	for hash, tuple := range a.hashToAggregate {
+		if tuple.aggregate == aggIdx {
+			hashes[tuple.array] = int64(hash)
+		}
+	}
+
+	fields := make([]arrow.Field, 0, 1+len(aggregate.colOrdering)+len(aggregate.aggregations))
+	arrays := make([]arrow.Array, 0, cap(fields))
+	defer func() {
+		for _, arr := range arrays {
+			arr.Release()
+		}
+	}()
+
+	hashBuilder := array.NewInt64Builder(a.pool)
+	hashBuilder.AppendValues(hashes, nil)
+	arrays = append(arrays, hashBuilder.NewArray())
+	hashBuilder.Release()
+	fields = append(fields, arrow.Field{Name: "hash", Type: arrow.PrimitiveTypes.Int64})
+
+	for _, fieldName := range aggregate.colOrdering {
+		groupByCol := aggregate.groupByCols[fieldName]
+		for groupByCol.Len() < numRows {
+			groupByCol.AppendNull()
+		}
+		arr := groupByCol.NewArray()
+		arrays = append(arrays, arr)
+		fields = append(fields, arrow.Field{Name: fieldName, Type: arr.DataType(), Nullable: true})
+	}
+
+	partials, err := a.aggregateGroups(aggregate, a.finalStage || merged, false)
+	if err != nil {
+		return err
+	}
+	for i, aggregation := range aggregate.aggregations {
+		partial := partials[i]
+		if aggregation.rawInput {
+			continue
+		}
+		arrays = append(arrays, partial)
+		fields = append(fields, arrow.Field{
+			Name:     aggregation.resultName,
+			Type:     partial.DataType(),
+			Nullable: true,
+			Metadata: arrow.NewMetadata(
+				[]string{spillFunctionKey},
+				[]string{strconv.Itoa(int(aggregation.function))},
+			),
+		})
+	}
+
+	partitionRows := make([][]int, len(partitions))
+	for i, hash := range hashes {
+		p := uint64(hash) / divisor % uint64(len(partitions))
+		partitionRows[p] = append(partitionRows[p], i)
+	}
+
+	schema := arrow.NewSchema(fields, nil)
+	for p, rows := range partitionRows {
+		if len(rows) == 0 {
+			continue
+		}
+		r, err := takeRows(a.pool, schema, arrays, rows)
+		if err != nil {
+			return err
+		}
+		path, err := a.spill.write(r)
+		r.Release()
+		if err != nil {
+			return err
+		}
+		partitions[p] = append(partitions[p], path)
+	}
+	return nil
+}
+
+// takeRows returns a record of the given rows of the arrays. Rows beyond the
+// length of an array are null.
+func takeRows(pool memory.Allocator, schema *arrow.Schema, arrays []arrow.Array, rows []int) (arrow.Record, error) {
+	cols := make([]arrow.Array, 0, len(arrays))
+	defer func() {
+		for _, col := range cols {
+			col.Release()
+		}
+	}()
+
+	for _, arr := range arrays {
+		bldr := builder.NewBuilder(pool, arr.DataType())
+		for _, i := range rows {
+			if i >= arr.Len() {
+				bldr.AppendNull()
+				continue
+			}
+			if err := builder.AppendValue(bldr, arr, i); err != nil {
+				bldr.Release()
+				return nil, err
+			}
+		}
+		cols = append(cols, bldr.NewArray())
+		bldr.Release()
+	}
+	return array.NewRecord(schema, cols, int64(len(rows))), nil
+}
+
+// resetState releases all groups and resets the aggregation to its initial
+// state.
+func (a *HashAggregate) resetState() {
+	initial := a.aggregates[0]
+	static := make([]Aggregation, 0, initial.concreteAggregations)
+	for _, agg := range initial.aggregations[:initial.concreteAggregations] {
+		static = append(static, agg.withoutArrays())
+	}
+
+	a.releaseState()
+	a.hashToAggregate = map[uint64]hashtuple{}
+	a.aggregates = []*hashAggregate{newHashAggregate(initial.dynamicAggregations, static)}
+	a.spill.baseline = a.spill.state.allocated.Load()
+}
+
+// finishSpilled spills the remaining state and then merges the spilled
+// partial aggregates one partition at a time. It returns the number of groups
+// passed on.
+func (a *HashAggregate) finishSpilled(ctx context.Context) (int, error) {
+	if err := a.spillState(); err != nil {
+		return 0, err
+	}
+	return a.mergePartitions(ctx, a.spill.partitions, 1)
+}
+
+// mergePartitions merges the spilled partial aggregates of the partitions one
+// partition at a time and passes on the groups. If the merged state of a
+// partition exceeds the memory limit, it is spilled into partitions by the
+// next digit of the hashes, which are then merged recursively.
+func (a *HashAggregate) mergePartitions(ctx context.Context, partitions [][]string, divisor uint64) (int, error) {
+	// Partitions can't be split any further once all digits of the hashes
+	// are used.
+	n := uint64(len(partitions))
+	splittable := n > 1 && divisor <= math.MaxUint64/n
+
+	totalRows := 0
+	for p, paths := range partitions {
+		partitions[p] = nil
+
+		var split [][]string
+		for _, path := range paths {
+			if err := a.spill.read(path, a.pool, a.mergeSpilled); err != nil {
+				return 0, err
+			}
+			// A single group can't be split.
+			if splittable && a.spill.exceeded() && len(a.hashToAggregate) > 1 {
+				if split == nil {
+					split = make([][]string, n)
+				}
+				if err := a.spillStateTo(split, divisor*n, true); err != nil {
+					return 0, err
+				}
+			}
+		}
+		if split != nil {
+			if err := a.spillStateTo(split, divisor*n, true); err != nil {
+				return 0, err
+			}
+			rows, err := a.mergePartitions(ctx, split, divisor*n)
+			if err != nil {
+				return 0, err
+			}
+			totalRows += rows
+			continue
+		}
+
+		for i, aggregate := range a.aggregates {
+			if err := a.finishAggregate(ctx, i, aggregate, true); err != nil {
+				return 0, err
+			}
+			totalRows += aggregate.rowCount
+		}
+		a.resetState()
+	}
+	return totalRows, nil
+}
+
+// mergeSpilled adds the partial aggregates of a spilled record to the groups.
+func (a *HashAggregate) mergeSpilled(r arrow.Record) error {
+	aggregate := a.aggregates[len(a.aggregates)-1]
+	hashes := r.Column(0).(*array.Int64)
+
+	var (
+		groupByFields []arrow.Field
+		groupByArrays []arrow.Array
+	)
+	columnToAggregate := make([]arrow.Array, len(aggregate.aggregations))
+	for i, field := range r.Schema().Fields()[1:] {
+		col := r.Column(i + 1)
+		fn, ok := field.Metadata.GetValue(spillFunctionKey)
+		if !ok {
+			groupByFields = append(groupByFields, field)
+			groupByArrays = append(groupByArrays, col)
+			continue
+		}
+
+		j := slices.IndexFunc(aggregate.aggregations, func(agg Aggregation) bool {
+			return agg.resultName == field.Name
+		})
+		if j == -1 {
+			// The concrete columns of dynamic aggregations may differ between
+			// spilled records.
+			f, err := strconv.Atoi(fn)
+			if err != nil {
+				return fmt.Errorf("invalid spilled aggregation function %q: %w", fn, err)
+			}
+			a.addSpilledAggregation(Aggregation{
+				expr:       logicalplan.Col(field.Name),
+				dynamic:    true,
+				resultName: field.Name,
+				function:   logicalplan.AggFunc(f),
+				arrayType:  field.Type,
+			})
+			columnToAggregate = append(columnToAggregate, nil)
+			j = len(columnToAggregate) - 1
+		}
+		columnToAggregate[j] = col
+	}
+
+	for i := 0; i < int(r.NumRows()); i++ {
+		hash := uint64(hashes.Value(i))
+		tuple, ok := a.hashToAggregate[hash]
+		if !ok {
+			var err error
+			tuple, err = a.newGroup(hash, i, groupByArrays, groupByFields, columnToAggregate)
+			if err != nil {
+				return err
+			}
+		}
+
+		for j, col := range columnToAggregate {
+			aggregation := a.aggregates[tuple.aggregate].aggregations[j]
+			// The values and order keys of first and last aggregations
+			// must stay aligned, so their nulls are kept.
+			ordered := aggregation.orderKey != "" || aggregation.hidden
+			if col == nil || (col.IsNull(i) && !ordered) {
+				continue
+			}
+			bldr := aggregation.arrays[tuple.array]
+			if err := builder.AppendValue(bldr, col, i); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addSpilledAggregation adds an aggregation to all aggregates, with an empty
+// array for each existing group.
+func (a *HashAggregate) addSpilledAggregation(agg Aggregation) {
+	for _, aggregate := range a.aggregates {
+		agg := agg
+		agg.arrays = make([]builder.ColumnBuilder, 0, aggregate.rowCount)
+		for len(agg.arrays) < aggregate.rowCount {
+			agg.arrays = append(agg.arrays, a.newBuilder(agg.arrayType))
+		}
+		aggregate.aggregations = append(aggregate.aggregations, agg)
+	}
+}
@@ -0,0 +1,254 @@
+package physicalplan
+
+import (
+	"context"
+	"hash/maphash"
+	"os"
+	"testing"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+func Test_HashAggregate_Spill(t *testing.T) {
+	const (
+		numRecords = 10
+		numRows    = 100
+		numGroups  = 37
+	)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "name", Type: arrow.BinaryTypes.Binary},
+		{Name: "value", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "timestamp", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+
+	type result struct {
+		sum, count, min, first, last int64
+	}
+	expected := map[string]result{}
+	// The first and last values are picked by their timestamps, which are
+	// distinct but not ordered like the values.
+	firstTimestamps := map[string]int64{}
+	lastTimestamps := map[string]int64{}
+
+	records := make([]arrow.Record, 0, numRecords)
+	for i := 0; i < numRecords; i++ {
+		names := array.NewBinaryBuilder(memory.DefaultAllocator, arrow.BinaryTypes.Binary)
+		values := array.NewInt64Builder(memory.DefaultAllocator)
+		timestamps := array.NewInt64Builder(memory.DefaultAllocator)
+		for j := 0; j < numRows; j++ {
+			name := string(rune('a' + (i*numRows+j)%numGroups))
+			value := int64(i*numRows + j)
+			timestamp := value * 7919 % 1009
+			names.AppendString(name)
+			values.Append(value)
+			timestamps.Append(timestamp)
+
+			res, ok := expected[name]
+			if !ok {
+				res.min = value
+				firstTimestamps[name] = timestamp
+				lastTimestamps[name] = timestamp
+				res.first = value
+				res.last = value
+			}
+			res.sum += value
+			res.count++
+			res.min = min(res.min, value)
+			if timestamp < firstTimestamps[name] {
+				firstTimestamps[name] = timestamp
+				res.first = value
+			}
+			if timestamp > lastTimestamps[name] {
+				lastTimestamps[name] = timestamp
+				res.last = value
+			}
+			expected[name] = res
+		}
+		records = append(records, array.NewRecord(
+			schema, []arrow.Array{names.NewArray(), values.NewArray(), timestamps.NewArray()}, numRows,
+		))
+		names.Release()
+		values.Release()
+		timestamps.Release()
+	}
+	defer func() {
+		for _, r := range records {
+			r.Release()
+		}
+	}()
+
+	for _, tc := range []struct {
+		name        string
+		memoryLimit int64
+		budget      int64
+		err         error
+		// maxRows is the maximum number of groups merged at once.
+		maxRows int
+	}{
+		{name: "unlimited", memoryLimit: 256},
+		{name: "budget exceeded", memoryLimit: 256, budget: 1024, err: ErrSpillDiskBudgetExceeded},
+		// Partitions exceeding the limit are split until they hold a single
+		// group.
+		{name: "repartitioned", memoryLimit: 1, maxRows: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			s := newSpiller(SpillConfig{
+				Dir:         dir,
+				MemoryLimit: tc.memoryLimit,
+				DiskBudget:  tc.budget,
+				Partitions:  4,
+			})
+
+			agg := NewHashAggregate(
+				memory.DefaultAllocator,
+				trace.NewNoopTracerProvider().Tracer(""),
+				[]Aggregation{
+					{expr: logicalplan.Col("value"), resultName: "sum(value)", function: logicalplan.AggFuncSum},
+					{expr: logicalplan.Col("value"), resultName: "count(value)", function: logicalplan.AggFuncCount},
+					{expr: logicalplan.Col("value"), resultName: "min(value)", function: logicalplan.AggFuncMin},
+					{
+						expr: logicalplan.Col("value"), resultName: "first(value)", function: logicalplan.AggFuncFirst,
+						orderBy: logicalplan.Col("timestamp"),
+					},
+					{
+						expr: logicalplan.Col("value"), resultName: "last(value)", function: logicalplan.AggFuncLast,
+						orderBy: logicalplan.Col("timestamp"),
+					},
+				},
+				[]logicalplan.Expr{logicalplan.Col("name")},
+				maphash.MakeSeed(),
+				false,
+			)
+			agg.setSpiller(s)
+
+			results := map[string]result{}
+			maxRows := 0
+			agg.SetNext(&OutputPlan{
+				callback: func(_ context.Context, r arrow.Record) error {
+					maxRows = max(maxRows, int(r.NumRows()))
+					cols := map[string]arrow.Array{}
+					for i, f := range r.Schema().Fields() {
+						cols[f.Name] = r.Column(i)
+					}
+					for i := 0; i < int(r.NumRows()); i++ {
+						name := string(cols["name"].(*array.Binary).Value(i))
+						require.NotContains(t, results, name)
+						results[name] = result{
+							sum:   cols["sum(value)"].(*array.Int64).Value(i),
+							count: cols["count(value)"].(*array.Int64).Value(i),
+							min:   cols["min(value)"].(*array.Int64).Value(i),
+							first: cols["first(value)"].(*array.Int64).Value(i),
+							last:  cols["last(value)"].(*array.Int64).Value(i),
+						}
+					}
+					return nil
+				},
+			})
+
+			ctx := context.Background()
+			err := func() error {
+				for _, r := range records {
+					if err := agg.Callback(ctx, r); err != nil {
+						return err
+					}
+				}
+				return agg.Finish(ctx)
+			}()
+			require.NoError(t, s.cleanup())
+
+			entries, dirErr := os.ReadDir(dir)
+			require.NoError(t, dirErr)
+			require.Empty(t, entries)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Greater(t, s.files.Load(), int64(numRecords))
+			require.Equal(t, expected, results)
+			if tc.maxRows > 0 {
+				require.Equal(t, tc.maxRows, maxRows)
+			}
+		})
+	}
+}
+
+func Test_HashAggregate_SpillDynamicFloats(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	s := newSpiller(SpillConfig{
+		Dir:         t.TempDir(),
+		MemoryLimit: 1,
+		Partitions:  1,
+	})
+	agg := NewHashAggregate(
+		mem,
+		trace.NewNoopTracerProvider().Tracer(""),
+		[]Aggregation{{
+			expr:       logicalplan.DynCol("values"),
+			dynamic:    true,
+			resultName: "sum(values)",
+			function:   logicalplan.AggFuncSum,
+		}},
+		[]logicalplan.Expr{logicalplan.Col("name")},
+		maphash.MakeSeed(),
+		false,
+	)
+	agg.setSpiller(s)
+
+	results := map[string]float64{}
+	agg.SetNext(&OutputPlan{
+		callback: func(_ context.Context, r arrow.Record) error {
+			names := r.Column(r.Schema().FieldIndices("name")[0]).(*array.Binary)
+			for i, f := range r.Schema().Fields() {
+				if f.Name == "name" || f.Name == "hashed.name" {
+					continue
+				}
+				sums := r.Column(i).(*array.Float64)
+				for j := 0; j < int(r.NumRows()); j++ {
+					if sums.IsValid(j) {
+						results[string(names.Value(j))+" "+f.Name] += sums.Value(j)
+					}
+				}
+			}
+			return nil
+		},
+	})
+
+	// The float columns of the dynamic aggregation appear in different
+	// records, so each of them is missing from some of the spilled records.
+	ctx := context.Background()
+	for _, column := range []string{"values.a", "values.b"} {
+		schema := arrow.NewSchema([]arrow.Field{
+			{Name: "name", Type: arrow.BinaryTypes.Binary},
+			{Name: column, Type: arrow.PrimitiveTypes.Float64},
+		}, nil)
+		b := array.NewRecordBuilder(mem, schema)
+		b.Field(0).(*array.BinaryBuilder).AppendStringValues([]string{"x", "y", "x"}, nil)
+		b.Field(1).(*array.Float64Builder).AppendValues([]float64{0.5, 1.5, 2}, nil)
+		r := b.NewRecord()
+		b.Release()
+		require.NoError(t, agg.Callback(ctx, r))
+		r.Release()
+	}
+	require.NoError(t, agg.Finish(ctx))
+	agg.Close()
+	require.NoError(t, s.cleanup())
+
+	require.Equal(t, map[string]float64{
+		"x values.a": 2.5,
+		"y values.a": 1.5,
+		"x values.b": 2.5,
+		"y values.b": 1.5,
+	}, results)
+}
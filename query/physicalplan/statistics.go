@@ -0,0 +1,170 @@
+package physicalplan
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+// moments are the partial state of statistical aggregations. They are
+// updated using Welford's algorithm and merged using the parallel algorithm
+// of Chan et al., which are numerically stable and exact regardless of how
+// the values are split between partial aggregations.
+type moments struct {
+	count float64
+	meanX float64
+	meanY float64
+	// m2X and m2Y are the sums of squared differences from the means.
+	m2X float64
+	m2Y float64
+	// c is the sum of the products of the differences from the means.
+	c float64
+}
+
+// momentsSize is the size of encoded moments.
+const momentsSize = 6 * 8
+
+func (m *moments) add(x, y float64) {
+	m.count++
+	dx := x - m.meanX
+	dy := y - m.meanY
+	m.meanX += dx / m.count
+	m.meanY += dy / m.count
+	m.m2X += dx * (x - m.meanX)
+	m.m2Y += dy * (y - m.meanY)
+	m.c += dx * (y - m.meanY)
+}
+
+func (m *moments) merge(o moments) {
+	if o.count == 0 {
+		return
+	}
+	if m.count == 0 {
+		*m = o
+		return
+	}
+	count := m.count + o.count
+	dx := o.meanX - m.meanX
+	dy := o.meanY - m.meanY
+	f := m.count * o.count / count
+	m.meanX += dx * o.count / count
+	m.meanY += dy * o.count / count
+	m.m2X += o.m2X + dx*dx*f
+	m.m2Y += o.m2Y + dy*dy*f
+	m.c += o.c + dx*dy*f
+	m.count = count
+}
+
+func (m *moments) encode() []byte {
+	b := make([]byte, 0, momentsSize)
+	for _, v := range []float64{m.count, m.meanX, m.meanY, m.m2X, m.m2Y, m.c} {
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+	}
+	return b
+}
+
+func decodeMoments(b []byte) (moments, error) {
+	if len(b) != momentsSize {
+		return moments{}, fmt.Errorf("invalid moments of %d bytes", len(b))
+	}
+	v := func(i int) float64 {
+		return math.Float64frombits(binary.LittleEndian.Uint64(b[i*8:]))
+	}
+	return moments{count: v(0), meanX: v(1), meanY: v(2), m2X: v(3), m2Y: v(4), c: v(5)}, nil
+}
+
+// statistic returns the statistic of the moments computed by the aggregation
+// function. False is returned if it is undefined, e.g. the sample variance of
+// a single value.
+func (m *moments) statistic(fn logicalplan.AggFunc) (float64, bool) {
+	switch fn {
+	case logicalplan.AggFuncVarPop:
+		return m.m2X / m.count, m.count > 0
+	case logicalplan.AggFuncVarSamp:
+		return m.m2X / (m.count - 1), m.count > 1
+	case logicalplan.AggFuncStddevPop:
+		return math.Sqrt(m.m2X / m.count), m.count > 0
+	case logicalplan.AggFuncStddevSamp:
+		return math.Sqrt(m.m2X / (m.count - 1)), m.count > 1
+	case logicalplan.AggFuncCovarPop:
+		return m.c / m.count, m.count > 0
+	case logicalplan.AggFuncCovarSamp:
+		return m.c / (m.count - 1), m.count > 1
+	case logicalplan.AggFuncCorr:
+		return m.c / math.Sqrt(m.m2X*m.m2Y), m.m2X > 0 && m.m2Y > 0
+	default:
+		return 0, false
+	}
+}
+
+// statisticalState is the partialState of statistical aggregations.
+type statisticalState struct {
+	fn logicalplan.AggFunc
+	m  moments
+}
+
+// add adds the values of x, paired with those of y for aggregations of
+// pairs. Nulls are skipped.
+func (s *statisticalState) add(x, y arrow.Array) error {
+	if s.fn.Paired() && y == nil {
+		return fmt.Errorf("%d values without paired values", x.Len())
+	}
+	xs, err := float64Values(x)
+	if err != nil {
+		return err
+	}
+	ys := xs
+	if y != nil {
+		if ys, err = float64Values(y); err != nil {
+			return err
+		}
+		if y.Len() != x.Len() {
+			return fmt.Errorf("%d values paired with %d values", x.Len(), y.Len())
+		}
+	}
+	for i := 0; i < x.Len(); i++ {
+		if x.IsNull(i) || (y != nil && y.IsNull(i)) {
+			continue
+		}
+		s.m.add(xs(i), ys(i))
+	}
+	return nil
+}
+
+func (s *statisticalState) merge(b []byte) error {
+	o, err := decodeMoments(b)
+	if err != nil {
+		return err
+	}
+	s.m.merge(o)
+	return nil
+}
+
+func (s *statisticalState) encode() []byte {
+	return s.m.encode()
+}
+
+func (s *statisticalState) final(b array.Builder) error {
+	if v, ok := s.m.statistic(s.fn); ok {
+		b.(*array.Float64Builder).Append(v)
+	} else {
+		b.AppendNull()
+	}
+	return nil
+}
+
+func float64Values(arr arrow.Array) (func(int) float64, error) {
+	switch a := arr.(type) {
+	case *array.Int64:
+		return func(i int) float64 { return float64(a.Value(i)) }, nil
+	case *array.Float64:
+		return a.Value, nil
+	default:
+		return nil, fmt.Errorf("unsupported values of type: %s", arr.DataType())
+	}
+}
@@ -0,0 +1,179 @@
+package physicalplan
+
+import (
+	"context"
+	"hash/maphash"
+	"math"
+	"testing"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+func TestMomentsMerge(t *testing.T) {
+	xs := []float64{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16, 1e9 + 1, 1e9 + 3}
+	ys := []float64{3, -1, 2.5, 8, 0, 4}
+
+	var all moments
+	for i := range xs {
+		all.add(xs[i], ys[i])
+	}
+	for split := 0; split <= len(xs); split++ {
+		var lhs, rhs moments
+		for i := range xs {
+			if i < split {
+				lhs.add(xs[i], ys[i])
+			} else {
+				rhs.add(xs[i], ys[i])
+			}
+		}
+		decoded, err := decodeMoments(rhs.encode())
+		require.NoError(t, err)
+		lhs.merge(decoded)
+		require.Equal(t, all.count, lhs.count)
+		require.InDelta(t, all.meanX, lhs.meanX, 1e-6)
+		require.InDelta(t, all.m2X, lhs.m2X, 1e-6)
+		require.InDelta(t, all.m2Y, lhs.m2Y, 1e-9)
+		require.InDelta(t, all.c, lhs.c, 1e-6)
+	}
+
+	// The variance of large values with a small spread doesn't suffer from
+	// catastrophic cancellation.
+	v, ok := all.statistic(logicalplan.AggFuncVarPop)
+	require.True(t, ok)
+	require.InDelta(t, 532.0/18, v, 1e-6)
+}
+
+func TestStatisticalAggregation(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "name", Type: arrow.BinaryTypes.Binary},
+		{Name: "x", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "y", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	rows := []struct {
+		name string
+		x    float64
+		y    int64
+	}{
+		{"a", 1.5, 10},
+		{"a", 2.5, 20},
+		{"b", 4, 1},
+		{"a", 4.5, 25},
+		{"b", 6, 2},
+		{"a", 0.5, 5},
+		{"b", 5, 4},
+		{"c", 3, 3},
+	}
+
+	expected := map[string]map[string]float64{}
+	for _, name := range []string{"a", "b", "c"} {
+		var xs, ys []float64
+		for _, r := range rows {
+			if r.name == name {
+				xs = append(xs, r.x)
+				ys = append(ys, float64(r.y))
+			}
+		}
+		n := float64(len(xs))
+		var meanX, meanY float64
+		for i := range xs {
+			meanX += xs[i] / n
+			meanY += ys[i] / n
+		}
+		var m2X, m2Y, c float64
+		for i := range xs {
+			m2X += (xs[i] - meanX) * (xs[i] - meanX)
+			m2Y += (ys[i] - meanY) * (ys[i] - meanY)
+			c += (xs[i] - meanX) * (ys[i] - meanY)
+		}
+		expected[name] = map[string]float64{
+			"var_pop":     m2X / n,
+			"var_samp":    m2X / (n - 1),
+			"stddev_pop":  math.Sqrt(m2X / n),
+			"stddev_samp": math.Sqrt(m2X / (n - 1)),
+			"covar_pop":   c / n,
+			"covar_samp":  c / (n - 1),
+			"corr":        c / math.Sqrt(m2X*m2Y),
+		}
+	}
+
+	agg := &logicalplan.Aggregation{
+		AggExprs: []logicalplan.Expr{
+			logicalplan.VarPop(logicalplan.Col("x")).Alias("var_pop"),
+			logicalplan.VarSamp(logicalplan.Col("x")).Alias("var_samp"),
+			logicalplan.StddevPop(logicalplan.Col("x")).Alias("stddev_pop"),
+			logicalplan.StddevSamp(logicalplan.Col("x")).Alias("stddev_samp"),
+			logicalplan.CovarPop(logicalplan.Col("x"), logicalplan.Col("y")).Alias("covar_pop"),
+			logicalplan.CovarSamp(logicalplan.Col("x"), logicalplan.Col("y")).Alias("covar_samp"),
+			logicalplan.Corr(logicalplan.Col("x"), logicalplan.Col("y")).Alias("corr"),
+		},
+		GroupExprs: []logicalplan.Expr{logicalplan.Col("name")},
+	}
+	tracer := trace.NewNoopTracerProvider().Tracer("")
+	seed := maphash.MakeSeed()
+
+	// The rows are aggregated by two partial aggregations, whose partial
+	// states are merged by the final aggregation.
+	final, err := Aggregate(memory.DefaultAllocator, tracer, agg, true, false, seed)
+	require.NoError(t, err)
+	results := map[string]map[string]float64{}
+	nulls := map[string][]string{}
+	final.SetNext(&OutputPlan{
+		callback: func(_ context.Context, r arrow.Record) error {
+			for i := 0; i < int(r.NumRows()); i++ {
+				name := string(r.Column(0).(*array.Binary).Value(i))
+				results[name] = map[string]float64{}
+				for j := 1; j < int(r.NumCols()); j++ {
+					col := r.Column(j).(*array.Float64)
+					if col.IsNull(i) {
+						nulls[name] = append(nulls[name], r.Schema().Field(j).Name)
+						continue
+					}
+					results[name][r.Schema().Field(j).Name] = col.Value(i)
+				}
+			}
+			return nil
+		},
+	})
+	sync := Synchronize(2)
+	sync.SetNext(final)
+
+	ctx := context.Background()
+	for part := 0; part < 2; part++ {
+		partial, err := Aggregate(memory.DefaultAllocator, tracer, agg, false, false, seed)
+		require.NoError(t, err)
+		partial.SetNext(sync)
+
+		names := array.NewBinaryBuilder(memory.DefaultAllocator, arrow.BinaryTypes.Binary)
+		xs := array.NewFloat64Builder(memory.DefaultAllocator)
+		ys := array.NewInt64Builder(memory.DefaultAllocator)
+		for i, r := range rows {
+			if i%2 != part {
+				continue
+			}
+			names.AppendString(r.name)
+			xs.Append(r.x)
+			ys.Append(r.y)
+		}
+		r := array.NewRecord(schema, []arrow.Array{names.NewArray(), xs.NewArray(), ys.NewArray()}, int64(len(rows)/2))
+		require.NoError(t, partial.Callback(ctx, r))
+		require.NoError(t, partial.Finish(ctx))
+		r.Release()
+	}
+
+	for _, name := range []string{"a", "b"} {
+		require.Len(t, results[name], len(expected[name]))
+		for stat, v := range expected[name] {
+			require.InDelta(t, v, results[name][stat], 1e-9, "%s of %s", stat, name)
+		}
+	}
+	// The statistics of samples and the correlation of a single value are
+	// undefined.
+	require.Equal(t, []string{"var_samp", "stddev_samp", "covar_samp", "corr"}, nulls["c"])
+	require.Equal(t, 0.0, results["c"]["var_pop"])
+}
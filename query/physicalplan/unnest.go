@@ -0,0 +1,119 @@
+package physicalplan
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/polarsignals/frostdb/pqarrow/builder"
+)
+
+// Unnester flattens a list column, producing a row for every list element in
+// which the values of the other columns are repeated. Rows with null or empty
+// lists don't produce any rows.
+type Unnester struct {
+	pool   memory.Allocator
+	tracer trace.Tracer
+	column string
+	next   PhysicalPlan
+}
+
+func Unnest(pool memory.Allocator, tracer trace.Tracer, column string) *Unnester {
+	return &Unnester{
+		pool:   pool,
+		tracer: tracer,
+		column: column,
+	}
+}
+
+func (u *Unnester) Callback(ctx context.Context, r arrow.Record) error {
+	// Generates high volume of spans. Comment out if needed during development.
+	// ctx, span := u.tracer.Start(ctx, "Unnester/Callback")
+	// defer span.End()
+
+	indices := r.Schema().FieldIndices(u.column)
+	if len(indices) == 0 {
+		return fmt.Errorf("unnest column %s not found", u.column)
+	}
+	listIdx := indices[0]
+	list, ok := r.Column(listIdx).(*array.List)
+	if !ok {
+		return fmt.Errorf("unnest column %s is not a list: %s", u.column, r.Column(listIdx).DataType())
+	}
+
+	numElements := 0
+	for i := 0; i < list.Len(); i++ {
+		if list.IsValid(i) {
+			start, end := list.ValueOffsets(i)
+			numElements += int(end - start)
+		}
+	}
+	if numElements == 0 {
+		return nil
+	}
+
+	fields := make([]arrow.Field, len(r.Schema().Fields()))
+	copy(fields, r.Schema().Fields())
+	listType := list.DataType().(*arrow.ListType)
+	fields[listIdx] = arrow.Field{
+		Name:     fields[listIdx].Name,
+		Type:     listType.Elem(),
+		Nullable: listType.ElemField().Nullable,
+	}
+	metadata := r.Schema().Metadata()
+	schema := arrow.NewSchema(fields, &metadata)
+
+	b := builder.NewRecordBuilder(u.pool, schema)
+	defer b.Release()
+	b.Reserve(numElements)
+
+	values := list.ListValues()
+	for i := 0; i < list.Len(); i++ {
+		if list.IsNull(i) {
+			continue
+		}
+		start, end := list.ValueOffsets(i)
+		for j := start; j < end; j++ {
+			for k, col := range r.Columns() {
+				var err error
+				if k == listIdx {
+					err = builder.AppendValue(b.Field(k), values, int(j))
+				} else {
+					err = builder.AppendValue(b.Field(k), col, i)
+				}
+				if err != nil {
+					return fmt.Errorf("unnest column %s: %w", fields[k].Name, err)
+				}
+			}
+		}
+	}
+
+	out := b.NewRecord()
+	defer out.Release()
+	return u.next.Callback(ctx, out)
+}
+
+func (u *Unnester) Finish(ctx context.Context) error {
+	return u.next.Finish(ctx)
+}
+
+func (u *Unnester) SetNext(next PhysicalPlan) {
+	u.next = next
+}
+
+func (u *Unnester) Draw() *Diagram {
+	var child *Diagram
+	if u.next != nil {
+		child = u.next.Draw()
+	}
+	details := fmt.Sprintf("Unnest (%s)", u.column)
+	return &Diagram{Details: details, Child: child}
+}
+
+func (u *Unnester) Close() {
+	u.next.Close()
+}
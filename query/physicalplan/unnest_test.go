@@ -0,0 +1,67 @@
+package physicalplan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/polarsignals/frostdb/pqarrow/builder"
+)
+
+func TestUnnest(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	dictType := &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Uint32, ValueType: arrow.BinaryTypes.String}
+	lb := builder.NewListBuilder(mem, dictType)
+	defer lb.Release()
+	vb := lb.ValueBuilder().(*array.BinaryDictionaryBuilder)
+	ib := array.NewInt64Builder(mem)
+	defer ib.Release()
+
+	for i, list := range [][]string{{"a", "b"}, nil, {}, {"c"}} {
+		ib.Append(int64(i))
+		if list == nil {
+			lb.AppendNull()
+			continue
+		}
+		lb.Append(true)
+		for _, v := range list {
+			require.NoError(t, vb.AppendString(v))
+		}
+	}
+
+	locations := lb.NewArray()
+	defer locations.Release()
+	values := ib.NewArray()
+	defer values.Release()
+	metadata := arrow.NewMetadata([]string{"key"}, []string{"value"})
+	r := array.NewRecord(arrow.NewSchema([]arrow.Field{
+		{Name: "locations", Type: locations.DataType()},
+		{Name: "value", Type: arrow.PrimitiveTypes.Int64},
+	}, &metadata), []arrow.Array{locations, values}, 4)
+	defer r.Release()
+
+	var rows [][2]string
+	output := &OutputPlan{}
+	output.SetNextCallback(func(_ context.Context, r arrow.Record) error {
+		require.Equal(t, dictType, r.Schema().Field(0).Type)
+		require.Equal(t, metadata, r.Schema().Metadata())
+		for i := 0; i < int(r.NumRows()); i++ {
+			rows = append(rows, [2]string{r.Column(0).ValueStr(i), r.Column(1).ValueStr(i)})
+		}
+		return nil
+	})
+	u := Unnest(mem, trace.NewNoopTracerProvider().Tracer(""), "locations")
+	u.SetNext(output)
+	require.NoError(t, u.Callback(context.Background(), r))
+	require.NoError(t, u.Finish(context.Background()))
+
+	// Null and empty lists don't produce any rows.
+	require.Equal(t, [][2]string{{"a", "0"}, {"b", "0"}, {"c", "3"}}, rows)
+}
@@ -0,0 +1,373 @@
+package physicalplan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+// WindowAggregate is a hash aggregation grouped by a duration over input whose
+// progress through its timestamps is reported by the table scan. The rows of
+// each time window, the bucket of the duration their timestamps fall into,
+// are aggregated separately. The watermark is a lower bound of the timestamps
+// of the rows that are yet to arrive, so once it moves past a window no more
+// rows of it can arrive, and its groups are passed on and released right away
+// rather than once the whole input is aggregated. Rows of a window that was
+// already passed on mean that the watermark was wrong, which is an error.
+//
+// The watermark is advanced for the partial aggregations first, which pass on
+// the windows it moved past, and then for the final aggregation, so the final
+// aggregation has all partial results of the windows it passes on.
+type WindowAggregate struct {
+	pool         memory.Allocator
+	tracer       trace.Tracer
+	aggregations []Aggregation
+	groupExprs   []logicalplan.Expr
+	duration     *logicalplan.DurationExpr
+	seed         maphash.Seed
+	finalStage   bool
+	spill        *spiller
+	next         PhysicalPlan
+	open         atomic.Int32
+
+	mtx     sync.Mutex
+	windows map[int64]*HashAggregate
+	// unbucketed aggregates the rows without a timestamp, which are passed
+	// on once all inputs finish.
+	unbucketed *HashAggregate
+	watermark  int64
+	finished   []bool
+	running    int
+	// passed is the window before which all windows were passed on.
+	passed       int64
+	windowsTotal int
+}
+
+// NewWindowAggregate returns a WindowAggregate for the given number of
+// inputs. The aggregation must be grouped by a duration.
+func NewWindowAggregate(
+	pool memory.Allocator,
+	tracer trace.Tracer,
+	agg *logicalplan.Aggregation,
+	final bool,
+	seed maphash.Seed,
+	inputs int,
+) (*WindowAggregate, error) {
+	aggregations, err := aggregationsFromPlan(agg)
+	if err != nil {
+		return nil, err
+	}
+	w := &WindowAggregate{
+		pool:         pool,
+		tracer:       tracer,
+		aggregations: aggregations,
+		groupExprs:   agg.GroupExprs,
+		seed:         seed,
+		finalStage:   final,
+		windows:      map[int64]*HashAggregate{},
+		watermark:    math.MinInt64,
+		finished:     make([]bool, inputs),
+		running:      inputs,
+		passed:       math.MinInt64,
+	}
+	for _, expr := range agg.GroupExprs {
+		if d, ok := expr.(*logicalplan.DurationExpr); ok {
+			w.duration = d
+		}
+	}
+	if w.duration == nil {
+		return nil, errors.New("window aggregate: not grouped by a duration")
+	}
+	w.open.Store(int32(inputs))
+	return w, nil
+}
+
+// Input returns the plan the i-th input pushes its records to. Finish of the
+// WindowAggregate itself is that of its first input.
+func (w *WindowAggregate) Input(i int) PhysicalPlan {
+	return &windowInput{w: w, input: i}
+}
+
+func (w *WindowAggregate) setSpiller(s *spiller) {
+	w.spill = s
+}
+
+func (w *WindowAggregate) Callback(ctx context.Context, r arrow.Record) error {
+	// Generates high volume of spans. Comment out if needed during development.
+	// ctx, span := w.tracer.Start(ctx, "WindowAggregate/Callback")
+	// defer span.End()
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	timestamps, err := w.timestamps(r)
+	if err != nil {
+		return err
+	}
+	if timestamps == nil {
+		return w.unbucketedAggregate().Callback(ctx, r)
+	}
+
+	// Consecutive rows of the same window are aggregated together, which
+	// for input ordered by timestamp are all rows of a window in the record.
+	numRows := int(r.NumRows())
+	for start := 0; start < numRows; {
+		end := start + 1
+		for end < numRows && timestamps.IsNull(end) == timestamps.IsNull(start) &&
+			(timestamps.IsNull(start) || w.bucket(timestamps.Value(end)) == w.bucket(timestamps.Value(start))) {
+			end++
+		}
+		if err := w.aggregate(ctx, r, timestamps, start, end); err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
+
+// aggregate aggregates the rows from start to end of the record, which are
+// of the same window.
+func (w *WindowAggregate) aggregate(
+	ctx context.Context,
+	r arrow.Record,
+	timestamps *array.Int64,
+	start, end int,
+) error {
+	slice := r.NewSlice(int64(start), int64(end))
+	defer slice.Release()
+	if timestamps.IsNull(start) {
+		return w.unbucketedAggregate().Callback(ctx, slice)
+	}
+
+	bucket := w.bucket(timestamps.Value(start))
+	if bucket < w.passed {
+		return fmt.Errorf(
+			"window aggregate: window %d was already passed on, its rows arrived after the watermark", bucket,
+		)
+	}
+	h, ok := w.windows[bucket]
+	if !ok {
+		h = w.newAggregate()
+		w.windows[bucket] = h
+		w.windowsTotal++
+	}
+	return h.Callback(ctx, slice)
+}
+
+// Advance reports that no more rows with timestamps before the watermark will
+// arrive on any of the inputs, and passes on the windows it moved past.
+func (w *WindowAggregate) Advance(ctx context.Context, watermark int64) error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if watermark <= w.watermark {
+		return nil
+	}
+	w.watermark = watermark
+	return w.pass(ctx)
+}
+
+// pass passes on the windows the watermark moved past, or all windows once
+// all inputs finished.
+func (w *WindowAggregate) pass(ctx context.Context) error {
+	// The window of the watermark may still receive rows.
+	passed := w.bucket(w.watermark)
+	if w.running == 0 {
+		passed = math.MaxInt64
+	}
+	if passed <= w.passed {
+		return nil
+	}
+
+	buckets := make([]int64, 0, len(w.windows))
+	for bucket := range w.windows {
+		if w.running == 0 || bucket < passed {
+			buckets = append(buckets, bucket)
+		}
+	}
+	slices.Sort(buckets)
+	for _, bucket := range buckets {
+		h := w.windows[bucket]
+		delete(w.windows, bucket)
+		err := h.Finish(ctx)
+		h.Close()
+		if err != nil {
+			return err
+		}
+	}
+	w.passed = passed
+	return nil
+}
+
+// timestamps returns the timestamps of the record, or nil if it has none.
+func (w *WindowAggregate) timestamps(r arrow.Record) (*array.Int64, error) {
+	for i, f := range r.Schema().Fields() {
+		if !w.duration.MatchColumn(f.Name) {
+			continue
+		}
+		timestamps, ok := r.Column(i).(*array.Int64)
+		if !ok {
+			return nil, fmt.Errorf("window aggregate: unsupported timestamps of type: %s", f.Type)
+		}
+		return timestamps, nil
+	}
+	return nil, nil
+}
+
+// bucket returns the window of the timestamp.
+func (w *WindowAggregate) bucket(timestamp int64) int64 {
+	step := w.duration.Value().Milliseconds()
+	return timestamp / step * step
+}
+
+func (w *WindowAggregate) newAggregate() *HashAggregate {
+	h := NewHashAggregate(w.pool, w.tracer, w.aggregations, w.groupExprs, w.seed, w.finalStage)
+	h.SetNext(&windowOutput{w: w})
+	if w.spill != nil {
+		h.setSpiller(w.spill)
+	}
+	return h
+}
+
+func (w *WindowAggregate) unbucketedAggregate() *HashAggregate {
+	if w.unbucketed == nil {
+		w.unbucketed = w.newAggregate()
+	}
+	return w.unbucketed
+}
+
+func (w *WindowAggregate) Finish(ctx context.Context) error {
+	return w.finish(ctx, 0)
+}
+
+func (w *WindowAggregate) finish(ctx context.Context, input int) error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if w.finished[input] {
+		return errors.New("too many WindowAggregate Finish calls")
+	}
+	w.finished[input] = true
+	w.running--
+	if err := w.pass(ctx); err != nil {
+		return err
+	}
+	if w.running > 0 {
+		return nil
+	}
+
+	ctx, span := w.tracer.Start(ctx, "WindowAggregate/Finish")
+	span.SetAttributes(
+		attribute.Bool("finalStage", w.finalStage),
+		attribute.Int("windows", w.windowsTotal),
+	)
+	defer span.End()
+
+	if w.unbucketed != nil {
+		err := w.unbucketed.Finish(ctx)
+		w.unbucketed.Close()
+		w.unbucketed = nil
+		if err != nil {
+			return err
+		}
+	}
+	return w.next.Finish(ctx)
+}
+
+func (w *WindowAggregate) SetNext(next PhysicalPlan) {
+	w.next = next
+}
+
+func (w *WindowAggregate) Draw() *Diagram {
+	var child *Diagram
+	if w.next != nil {
+		child = w.next.Draw()
+	}
+
+	names := make([]string, 0, len(w.aggregations))
+	for _, agg := range w.aggregations {
+		names = append(names, agg.resultName)
+	}
+	var groupings []string
+	for _, grouping := range w.groupExprs {
+		groupings = append(groupings, grouping.Name())
+	}
+	details := fmt.Sprintf(
+		"WindowAggregate (%s by %s every %s)",
+		strings.Join(names, ","), strings.Join(groupings, ","), w.duration.Value(),
+	)
+	return &Diagram{Details: details, Child: child}
+}
+
+func (w *WindowAggregate) Close() {
+	if w.open.Add(-1) > 0 {
+		return
+	}
+	for bucket, h := range w.windows {
+		h.Close()
+		delete(w.windows, bucket)
+	}
+	if w.unbucketed != nil {
+		w.unbucketed.Close()
+		w.unbucketed = nil
+	}
+	w.next.Close()
+}
+
+// windowInput is an input of a WindowAggregate.
+type windowInput struct {
+	w     *WindowAggregate
+	input int
+}
+
+func (i *windowInput) Callback(ctx context.Context, r arrow.Record) error {
+	return i.w.Callback(ctx, r)
+}
+
+func (i *windowInput) Finish(ctx context.Context) error {
+	return i.w.finish(ctx, i.input)
+}
+
+func (i *windowInput) SetNext(next PhysicalPlan) {
+	i.w.SetNext(next)
+}
+
+func (i *windowInput) Draw() *Diagram {
+	return i.w.Draw()
+}
+
+func (i *windowInput) Close() {
+	i.w.Close()
+}
+
+// windowOutput passes on the groups of the aggregation of a window, which
+// finishes long before the WindowAggregate does.
+type windowOutput struct {
+	w *WindowAggregate
+}
+
+func (o *windowOutput) Callback(ctx context.Context, r arrow.Record) error {
+	return o.w.next.Callback(ctx, r)
+}
+
+func (o *windowOutput) Finish(context.Context) error { return nil }
+
+func (o *windowOutput) SetNext(PhysicalPlan) {}
+
+func (o *windowOutput) Draw() *Diagram { return nil }
+
+func (o *windowOutput) Close() {}
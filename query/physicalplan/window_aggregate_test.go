@@ -0,0 +1,145 @@
+package physicalplan
+
+import (
+	"context"
+	"fmt"
+	"hash/maphash"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+func TestWindowAggregate(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "name", Type: arrow.BinaryTypes.Binary},
+		{Name: "timestamp", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "value", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	agg := &logicalplan.Aggregation{
+		AggExprs:   []logicalplan.Expr{logicalplan.Sum(logicalplan.Col("value"))},
+		GroupExprs: []logicalplan.Expr{logicalplan.Col("name"), logicalplan.Duration(10 * time.Second)},
+	}
+	tracer := trace.NewNoopTracerProvider().Tracer("")
+	seed := maphash.MakeSeed()
+
+	final, err := NewWindowAggregate(mem, tracer, agg, true, seed, 2)
+	require.NoError(t, err)
+	var results []string
+	final.SetNext(&OutputPlan{
+		callback: func(_ context.Context, r arrow.Record) error {
+			names := r.Column(r.Schema().FieldIndices("name")[0]).(*array.Binary)
+			sums := r.Column(r.Schema().FieldIndices("sum(value)")[0]).(*array.Int64)
+			timestamps := r.Column(r.Schema().FieldIndices("timestamp")[0]).(*array.Int64)
+			for i := 0; i < int(r.NumRows()); i++ {
+				window := "null"
+				if !timestamps.IsNull(i) {
+					window = fmt.Sprint(timestamps.Value(i) / 10000 * 10000)
+				}
+				results = append(results, fmt.Sprintf("%s %s %d", names.Value(i), window, sums.Value(i)))
+			}
+			return nil
+		},
+	})
+
+	partials := make([]*WindowAggregate, 2)
+	for i := range partials {
+		partials[i], err = NewWindowAggregate(mem, tracer, agg, false, seed, 1)
+		require.NoError(t, err)
+		partials[i].SetNext(final.Input(i))
+	}
+
+	ctx := context.Background()
+	push := func(input int, values ...any) {
+		r := windowRecord(mem, schema, values)
+		defer r.Release()
+		require.NoError(t, partials[input].Callback(ctx, r))
+	}
+	push(0, "a", int64(1000), int64(1), "a", int64(2000), int64(2), "b", int64(11000), int64(4))
+	push(1, "b", int64(3000), int64(3), "a", int64(12000), int64(5))
+	push(0, "a", int64(21000), int64(6), "a", nil, int64(7))
+	require.Empty(t, results)
+
+	// The watermark moved past the first window, so it's complete. The
+	// partial aggregations pass it on before the final one does.
+	advance := func(watermark int64) {
+		for _, p := range partials {
+			require.NoError(t, p.Advance(ctx, watermark))
+		}
+		require.NoError(t, final.Advance(ctx, watermark))
+	}
+	advance(12000)
+	require.ElementsMatch(t, []string{"a 0 3", "b 0 3"}, results)
+
+	push(1, "a", int64(35000), int64(8))
+	require.ElementsMatch(t, []string{"a 0 3", "b 0 3"}, results)
+
+	require.NoError(t, partials[0].Finish(ctx))
+	require.NoError(t, partials[1].Finish(ctx))
+	require.ElementsMatch(t, []string{
+		"a 0 3", "b 0 3",
+		"b 10000 4", "a 10000 5",
+		"a 20000 6",
+		"a 30000 8",
+		"a null 7",
+	}, results)
+
+	for _, p := range partials {
+		p.Close()
+	}
+}
+
+func TestWindowAggregatePassedWindow(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "name", Type: arrow.BinaryTypes.Binary},
+		{Name: "timestamp", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "value", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	agg := &logicalplan.Aggregation{
+		AggExprs:   []logicalplan.Expr{logicalplan.Sum(logicalplan.Col("value"))},
+		GroupExprs: []logicalplan.Expr{logicalplan.Duration(10 * time.Second)},
+	}
+	w, err := NewWindowAggregate(mem, trace.NewNoopTracerProvider().Tracer(""), agg, false, maphash.MakeSeed(), 1)
+	require.NoError(t, err)
+	w.SetNext(&OutputPlan{callback: func(context.Context, arrow.Record) error { return nil }})
+	defer w.Close()
+
+	ctx := context.Background()
+	r := windowRecord(mem, schema, []any{"a", int64(1000), int64(1), "a", int64(21000), int64(2)})
+	require.NoError(t, w.Callback(ctx, r))
+	r.Release()
+	require.NoError(t, w.Advance(ctx, 21000))
+
+	// Rows arriving after the watermark moved past their window are an
+	// error, since the window was already passed on.
+	r = windowRecord(mem, schema, []any{"a", int64(5000), int64(3)})
+	require.ErrorContains(t, w.Callback(ctx, r), "window 0 was already passed on")
+	r.Release()
+}
+
+func windowRecord(mem memory.Allocator, schema *arrow.Schema, values []any) arrow.Record {
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	for i := 0; i < len(values); i += 3 {
+		b.Field(0).(*array.BinaryBuilder).AppendString(values[i].(string))
+		if values[i+1] == nil {
+			b.Field(1).AppendNull()
+		} else {
+			b.Field(1).(*array.Int64Builder).Append(values[i+1].(int64))
+		}
+		b.Field(2).(*array.Int64Builder).Append(values[i+2].(int64))
+	}
+	return b.NewRecord()
+}
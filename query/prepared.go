@@ -0,0 +1,80 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/apache/arrow/go/v14/arrow"
+
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+// PreparedQuery is a query that is built, validated and optimized once, and
+// executed any number of times with the values of its parameters bound. It is
+// safe for concurrent use.
+type PreparedQuery struct {
+	builder LocalQueryBuilder
+	plan    *logicalplan.LogicalPlan
+	schema  *arrow.Schema
+	params  []string
+}
+
+// Prepare builds, validates and optimizes the query, whose literals may be
+// placeholders of parameters created with logicalplan.Param. The values of the
+// parameters are bound when the prepared query is executed.
+func (e *LocalEngine) Prepare(query Builder) (*PreparedQuery, error) {
+	b, ok := query.(LocalQueryBuilder)
+	if !ok {
+		return nil, fmt.Errorf("unsupported query builder: %T", query)
+	}
+
+	plan, err := b.planBuilder.Build()
+	if err != nil {
+		return nil, err
+	}
+	// The output schema is computed before the plan is optimized, like it
+	// is when the query is executed directly.
+	schema, err := outputSchema(plan)
+	if err != nil {
+		return nil, err
+	}
+	for p := plan; p != nil; p = p.Input {
+		// The results of inner plans change with the data, so they can't be
+		// prepared.
+		if p.SemiJoin != nil {
+			return nil, errors.New("semi joins can't be prepared")
+		}
+	}
+	plan = optimize(plan)
+
+	return &PreparedQuery{
+		builder: b,
+		plan:    plan,
+		schema:  schema,
+		params:  plan.Params(),
+	}, nil
+}
+
+// Params returns the sorted names of the parameters of the query.
+func (q *PreparedQuery) Params() []string {
+	return q.params
+}
+
+// Execute binds the values of the query's parameters and executes it. Every
+// parameter must be given a value.
+func (q *PreparedQuery) Execute(
+	ctx context.Context,
+	params map[string]any,
+	callback func(ctx context.Context, r arrow.Record) error,
+) error {
+	ctx, span := q.builder.tracer.Start(ctx, "PreparedQuery/Execute")
+	defer span.End()
+
+	plan, err := q.plan.Bind(params)
+	if err != nil {
+		return err
+	}
+	ctx, pool, done := q.builder.queryAllocator(ctx)
+	return done(q.builder.executePlan(ctx, pool, plan, q.schema, callback))
+}
@@ -0,0 +1,160 @@
+package query
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+
+	"github.com/polarsignals/frostdb/query/physicalplan"
+)
+
+// recordReader is an array.RecordReader that pulls records out of a physical
+// plan that is executed in the background. The plan's output callback blocks
+// until the record is read, which applies backpressure all the way to the
+// scan.
+type recordReader struct {
+	refCount atomic.Int64
+
+	schema  *arrow.Schema
+	records chan arrow.Record
+	// pending is the first record of a dynamic output, which is read ahead
+	// to determine the schema.
+	pending arrow.Record
+	cur     arrow.Record
+
+	cancel context.CancelFunc
+	// done is closed once the plan finished executing, err is the error the
+	// execution returned.
+	done chan struct{}
+	err  error
+}
+
+var _ array.RecordReader = (*recordReader)(nil)
+
+// newRecordReader executes the plan in the background. The reader's schema is
+// the output schema of the plan, unless it's nil because the output contains
+// dynamic columns, in which case it is the schema of the first record, or the
+// concrete schema if there is none.
+func newRecordReader(
+	ctx context.Context,
+	plan *physicalplan.OutputPlan,
+	pool memory.Allocator,
+	schema *arrow.Schema,
+	concrete *arrow.Schema,
+	done func(error) error,
+) (*recordReader, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	r := &recordReader{
+		schema:  schema,
+		records: make(chan arrow.Record),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	r.refCount.Store(1)
+
+	go func() {
+		defer close(r.done)
+		r.err = done(executeWithSchema(ctx, plan, pool, schema, func(ctx context.Context, rec arrow.Record) error {
+			// The record is only valid for the duration of the callback, so
+			// retain it until the reader moves past it.
+			rec.Retain()
+			select {
+			case r.records <- rec:
+				return nil
+			case <-ctx.Done():
+				rec.Release()
+				return ctx.Err()
+			}
+		}))
+	}()
+	if schema != nil {
+		return r, nil
+	}
+
+	// Wait for the first record so that the schema of the dynamic output is
+	// known up front.
+	select {
+	case rec := <-r.records:
+		r.pending = rec
+		r.schema = rec.Schema()
+	case <-r.done:
+		if r.err != nil {
+			cancel()
+			return nil, r.err
+		}
+		r.schema = concrete
+	}
+
+	return r, nil
+}
+
+// Schema returns the output schema of the plan, or that of the first record
+// if the output contains dynamic columns. Records with dynamic columns may
+// have differing schemas, in which case the schema of the current record
+// should be used.
+func (r *recordReader) Schema() *arrow.Schema {
+	return r.schema
+}
+
+// Next advances the reader to the next record. The previous record is
+// released.
+func (r *recordReader) Next() bool {
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+
+	if r.pending != nil {
+		r.cur, r.pending = r.pending, nil
+		return true
+	}
+
+	select {
+	case rec := <-r.records:
+		r.cur = rec
+		return true
+	case <-r.done:
+		return false
+	}
+}
+
+func (r *recordReader) Record() arrow.Record {
+	return r.cur
+}
+
+// Err returns the error the execution failed with. It must only be called
+// once Next returned false.
+func (r *recordReader) Err() error {
+	select {
+	case <-r.done:
+		return r.err
+	default:
+		return nil
+	}
+}
+
+func (r *recordReader) Retain() {
+	r.refCount.Add(1)
+}
+
+// Release decreases the reference count of the reader. Once it reaches zero
+// the remaining execution is cancelled and all resources are released.
+func (r *recordReader) Release() {
+	if r.refCount.Add(-1) != 0 {
+		return
+	}
+
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+	if r.pending != nil {
+		r.pending.Release()
+		r.pending = nil
+	}
+	r.cancel()
+	<-r.done
+}
@@ -0,0 +1,181 @@
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/apache/arrow/go/v14/arrow/scalar"
+
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+// semiJoinValues returns the inner keys of a semi join.
+type semiJoinValues func(join *logicalplan.SemiJoin) ([]scalar.Scalar, error)
+
+// resolveSemiJoins replaces the plan's semi joins by filters of the sets of
+// their inner keys returned by values. The filters are then pushed down to the
+// table scan like any other, so that row groups are pruned by the sets. If
+// values is nil, the inner plans aren't executed and the filters are left
+// without keys, which is enough to inspect the plan. The nodes above the semi
+// joins are copied, since the plan of a query is built once for every
+// execution.
+func resolveSemiJoins(plan *logicalplan.LogicalPlan, values semiJoinValues) (*logicalplan.LogicalPlan, error) {
+	if plan == nil {
+		return nil, nil
+	}
+
+	input, err := resolveSemiJoins(plan.Input, values)
+	if err != nil {
+		return nil, err
+	}
+	if plan.SemiJoin == nil {
+		if input == plan.Input {
+			return plan, nil
+		}
+		resolved := *plan
+		resolved.Input = input
+		return &resolved, nil
+	}
+
+	var keys []scalar.Scalar
+	if values != nil {
+		if keys, err = values(plan.SemiJoin); err != nil {
+			return nil, err
+		}
+	}
+	return &logicalplan.LogicalPlan{
+		Input:  input,
+		Filter: plan.SemiJoin.Filter(keys),
+	}, nil
+}
+
+// resolvedPlan builds the query's plan, executes the inner plans of its semi
+// joins and optimizes it. The inner plans are executed with the query's
+// allocator, and the sets of their inner keys are allocated from it, so that
+// they count towards the query's memory limit. release must be called once the
+// plan is executed.
+func (b LocalQueryBuilder) resolvedPlan(
+	ctx context.Context,
+	pool memory.Allocator,
+) (plan *logicalplan.LogicalPlan, schema *arrow.Schema, release func(), err error) {
+	var keys []scalar.Scalar
+	release = func() {
+		releaseScalars(keys)
+	}
+
+	var values semiJoinValues
+	values = func(join *logicalplan.SemiJoin) ([]scalar.Scalar, error) {
+		res, err := b.executeSemiJoin(ctx, pool, join, values)
+		keys = append(keys, res...)
+		return res, err
+	}
+	plan, schema, err = b.optimizedPlan(values)
+	if err != nil {
+		release()
+		return nil, nil, nil, err
+	}
+	return plan, schema, release, nil
+}
+
+// executeSemiJoin executes the inner plan of the semi join, whose own semi
+// joins are resolved by values, and returns the distinct non-null values of
+// its inner key. The values are allocated from the pool and must be released.
+func (b LocalQueryBuilder) executeSemiJoin(
+	ctx context.Context,
+	pool memory.Allocator,
+	join *logicalplan.SemiJoin,
+	values semiJoinValues,
+) ([]scalar.Scalar, error) {
+	ctx, span := b.tracer.Start(ctx, "LocalQueryBuilder/executeSemiJoin")
+	defer span.End()
+
+	inner, err := resolveSemiJoins(join.Inner, values)
+	if err != nil {
+		return nil, err
+	}
+	phyPlan, err := b.buildPhysicalPlan(ctx, pool, optimize(inner))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		res  []scalar.Scalar
+		seen = map[string]struct{}{}
+	)
+	if err := phyPlan.Execute(ctx, pool, func(_ context.Context, r arrow.Record) error {
+		idx := -1
+		for i, f := range r.Schema().Fields() {
+			if join.InnerKey.MatchColumn(f.Name) {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			return fmt.Errorf("semi join inner key %s not found in the inner results", join.InnerKey.Name())
+		}
+
+		keys := r.Column(idx)
+		for i := 0; i < keys.Len(); i++ {
+			if keys.IsNull(i) {
+				continue
+			}
+			v, err := copyScalar(pool, keys, i)
+			if err != nil {
+				return fmt.Errorf("semi join inner key %s: %w", join.InnerKey.Name(), err)
+			}
+			if _, ok := seen[v.String()]; ok {
+				releaseScalars([]scalar.Scalar{v})
+				continue
+			}
+			seen[v.String()] = struct{}{}
+			res = append(res, v)
+		}
+		return nil
+	}); err != nil {
+		releaseScalars(res)
+		return nil, err
+	}
+	return res, nil
+}
+
+// copyScalar returns the value of the row as a scalar that doesn't refer to
+// the memory of the array, which is released once the record is passed on.
+// Binary and string values are copied into buffers allocated from the pool,
+// which the scalars retain.
+func copyScalar(pool memory.Allocator, arr arrow.Array, i int) (scalar.Scalar, error) {
+	newBuffer := func(size int) *memory.Buffer {
+		buf := memory.NewResizableBuffer(pool)
+		buf.Resize(size)
+		return buf
+	}
+	switch a := arr.(type) {
+	case *array.Binary:
+		v := a.Value(i)
+		buf := newBuffer(len(v))
+		copy(buf.Bytes(), v)
+		defer buf.Release()
+		return scalar.NewBinaryScalar(buf, arrow.BinaryTypes.Binary), nil
+	case *array.String:
+		v := a.Value(i)
+		buf := newBuffer(len(v))
+		copy(buf.Bytes(), v)
+		defer buf.Release()
+		return scalar.NewStringScalarFromBuffer(buf), nil
+	case *array.Dictionary:
+		return copyScalar(pool, a.Dictionary(), a.GetValueIndex(i))
+	default:
+		return scalar.GetScalar(arr, i)
+	}
+}
+
+// releaseScalars releases the scalars holding buffers.
+func releaseScalars(scalars []scalar.Scalar) {
+	for _, s := range scalars {
+		if r, ok := s.(scalar.Releasable); ok {
+			r.Release()
+		}
+	}
+}
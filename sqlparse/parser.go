@@ -18,6 +18,9 @@ func NewParser() *Parser {
 
 type ParseResult struct {
 	Explain bool
+	// Analyze is set for EXPLAIN ANALYZE statements, in which case the plan
+	// should be executed to collect runtime statistics.
+	Analyze bool
 	Plan    query.Builder
 }
 
@@ -43,5 +46,5 @@ func (p *Parser) ExperimentalParse(builder query.Builder, dynColNames []string,
 		return ParseResult{}, v.err
 	}
 
-	return ParseResult{Explain: v.explain, Plan: v.builder}, nil
+	return ParseResult{Explain: v.explain, Analyze: v.analyze, Plan: v.builder}, nil
 }
@@ -2,6 +2,7 @@ package sqlparse
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,9 +17,12 @@ import (
 
 type astVisitor struct {
 	explain     bool
+	analyze     bool
 	builder     query.Builder
 	dynColNames map[string]struct{}
 	err         error
+	// gapFill is the gap fill of the statement's aggregation, if any.
+	gapFill *logicalplan.GapFill
 
 	exprStack []logicalplan.Expr
 }
@@ -42,6 +46,30 @@ func (v *astVisitor) Enter(n ast.Node) (nRes ast.Node, skipChildren bool) {
 		// The SelectStmt is handled in during pre-visit given that it has many
 		// clauses we need to handle independently (e.g. a group by with a
 		// filter).
+		// The table is sampled before any other clause is applied.
+		sample, ok, err := tableSample(expr)
+		if err != nil {
+			v.err = err
+			return n, true
+		}
+		if ok {
+			b, err := v.extendedBuilder("TABLESAMPLE")
+			if err != nil {
+				v.err = err
+				return n, true
+			}
+			v.builder = b.Sample(sample)
+		}
+		// Lists are unnested before any other clause is applied, so that
+		// all of them refer to the list elements.
+		for _, col := range unnestColumns(expr) {
+			b, err := v.extendedBuilder("unnest")
+			if err != nil {
+				v.err = err
+				return n, true
+			}
+			v.builder = b.Unnest(col)
+		}
 		if expr.Where != nil {
 			expr.Where.Accept(v)
 			lastExpr, newExprs := pop(v.exprStack)
@@ -64,6 +92,14 @@ func (v *astVisitor) Enter(n ast.Node) (nRes ast.Node, skipChildren bool) {
 				}
 			}
 			v.builder = v.builder.Aggregate(agg, groups)
+			if v.gapFill != nil {
+				b, err := v.extendedBuilder("gap filling")
+				if err != nil {
+					v.err = err
+					return n, true
+				}
+				v.builder = b.GapFill(*v.gapFill)
+			}
 		case expr.Distinct:
 			v.builder = v.builder.Distinct(v.exprStack...)
 		default:
@@ -74,6 +110,16 @@ func (v *astVisitor) Enter(n ast.Node) (nRes ast.Node, skipChildren bool) {
 	return n, false
 }
 
+// extendedBuilder returns the builder if it supports the operations of
+// query.ExtendedBuilder needed by the given clause.
+func (v *astVisitor) extendedBuilder(clause string) (query.ExtendedBuilder, error) {
+	b, ok := v.builder.(query.ExtendedBuilder)
+	if !ok {
+		return nil, fmt.Errorf("%s is not supported by the query builder", clause)
+	}
+	return b, nil
+}
+
 func (v *astVisitor) Leave(n ast.Node) (nRes ast.Node, ok bool) {
 	if err := v.leaveImpl(n); err != nil {
 		v.err = err
@@ -89,6 +135,7 @@ func (v *astVisitor) leaveImpl(n ast.Node) error {
 		return nil
 	case *ast.ExplainStmt:
 		v.explain = true
+		v.analyze = expr.Analyze
 		return nil
 	case *ast.AggregateFuncExpr:
 		// At this point, the child node is the column name, so it has just been
@@ -105,6 +152,14 @@ func (v *astVisitor) leaveImpl(n ast.Node) error {
 			v.exprStack[lastExpr] = logicalplan.Max(v.exprStack[lastExpr])
 		case "avg":
 			v.exprStack[lastExpr] = logicalplan.Avg(v.exprStack[lastExpr])
+		case "stddev_pop":
+			v.exprStack[lastExpr] = logicalplan.StddevPop(v.exprStack[lastExpr])
+		case "stddev_samp":
+			v.exprStack[lastExpr] = logicalplan.StddevSamp(v.exprStack[lastExpr])
+		case "var_pop":
+			v.exprStack[lastExpr] = logicalplan.VarPop(v.exprStack[lastExpr])
+		case "var_samp":
+			v.exprStack[lastExpr] = logicalplan.VarSamp(v.exprStack[lastExpr])
 		default:
 			return fmt.Errorf("unhandled aggregate function %s", expr.F)
 		}
@@ -178,11 +233,36 @@ func (v *astVisitor) leaveImpl(n ast.Node) error {
 			e.Op = logicalplan.OpRegexNotMatch
 		}
 		v.exprStack = append(v.exprStack, e)
+	case *ast.PatternInExpr:
+		if expr.Sel != nil {
+			return fmt.Errorf("unhandled IN subquery")
+		}
+		// The list values were pushed after the column, so pop them first.
+		values := make([]logicalplan.Expr, len(expr.List))
+		for i := len(values) - 1; i >= 0; i-- {
+			values[i], v.exprStack = pop(v.exprStack)
+		}
+		leftExpr, newExprs := pop(v.exprStack)
+		v.exprStack = newExprs
+
+		op, combine := logicalplan.OpEq, logicalplan.Or
+		if expr.Not {
+			op, combine = logicalplan.OpNotEq, logicalplan.And
+		}
+		exprs := make([]logicalplan.Expr, 0, len(values))
+		for _, value := range values {
+			exprs = append(exprs, &logicalplan.BinaryExpr{
+				Left:  logicalplan.Col(leftExpr.Name()),
+				Op:    op,
+				Right: value,
+			})
+		}
+		v.exprStack = append(v.exprStack, combine(exprs...))
 	case *ast.FieldList, *ast.ColumnNameExpr, *ast.GroupByClause, *ast.ByItem, *ast.RowExpr,
 		*ast.ParenthesesExpr:
 		// Deliberate pass-through nodes.
 	case *ast.FuncCallExpr:
-		switch expr.FnName.String() {
+		switch expr.FnName.L {
 		case ast.Second:
 			// This is pretty hacky and only fine because it's in the test only.
 			left, right := pop(v.exprStack)
@@ -195,6 +275,95 @@ func (v *astVisitor) leaveImpl(n ast.Node) error {
 				exprStack = append(exprStack, logicalplan.Duration(duration))
 				v.exprStack = exprStack
 			}
+		case unnestFunc:
+			// The list column was unnested when entering the select
+			// statement, so the column now refers to the list elements.
+		case firstFunc, lastFunc:
+			if len(expr.Args) != 2 {
+				return fmt.Errorf("%s expects a value and a column to order by", expr.FnName.L)
+			}
+			orderBy, newExprs := pop(v.exprStack)
+			value, newExprs := pop(newExprs)
+			if expr.FnName.L == firstFunc {
+				v.exprStack = append(newExprs, logicalplan.First(value, orderBy))
+			} else {
+				v.exprStack = append(newExprs, logicalplan.Last(value, orderBy))
+			}
+		case covarPopFunc, covarSampFunc, corrFunc:
+			if len(expr.Args) != 2 {
+				return fmt.Errorf("%s expects two columns", expr.FnName.L)
+			}
+			y, newExprs := pop(v.exprStack)
+			x, newExprs := pop(newExprs)
+			switch expr.FnName.L {
+			case covarPopFunc:
+				v.exprStack = append(newExprs, logicalplan.CovarPop(x, y))
+			case covarSampFunc:
+				v.exprStack = append(newExprs, logicalplan.CovarSamp(x, y))
+			default:
+				v.exprStack = append(newExprs, logicalplan.Corr(x, y))
+			}
+		case rateFunc, increaseFunc:
+			if len(expr.Args) != 2 {
+				return fmt.Errorf("%s expects a counter and its timestamps", expr.FnName.L)
+			}
+			timestamp, newExprs := pop(v.exprStack)
+			value, newExprs := pop(newExprs)
+			if expr.FnName.L == rateFunc {
+				v.exprStack = append(newExprs, logicalplan.Rate(value, timestamp))
+			} else {
+				v.exprStack = append(newExprs, logicalplan.Increase(value, timestamp))
+			}
+		case histogramFunc:
+			if len(expr.Args) < 2 {
+				return fmt.Errorf("%s expects a column and the upper bounds of its buckets", expr.FnName.L)
+			}
+			// The bounds are read from the arguments rather than the
+			// literals they were pushed as.
+			buckets := make(logicalplan.HistogramBuckets, len(expr.Args)-1)
+			for i, arg := range expr.Args[1:] {
+				bound, err := numericValue(arg)
+				if err != nil {
+					return fmt.Errorf("%s bucket: %w", expr.FnName.L, err)
+				}
+				buckets[i] = bound
+			}
+			newExprs := v.exprStack[:len(v.exprStack)-len(buckets)]
+			value, newExprs := pop(newExprs)
+			v.exprStack = append(newExprs, logicalplan.Histogram(value, buckets))
+		case widthBucketFunc:
+			if len(expr.Args) != 4 {
+				return fmt.Errorf("%s expects a column, min, max and the number of buckets", expr.FnName.L)
+			}
+			var bounds [3]float64
+			for i, arg := range expr.Args[1:] {
+				bound, err := numericValue(arg)
+				if err != nil {
+					return fmt.Errorf("%s: %w", expr.FnName.L, err)
+				}
+				bounds[i] = bound
+			}
+			newExprs := v.exprStack[:len(v.exprStack)-len(bounds)]
+			value, newExprs := pop(newExprs)
+			v.exprStack = append(newExprs, logicalplan.WidthBucket(value, bounds[0], bounds[1], int64(bounds[2])))
+		case gapFillFunc:
+			if len(expr.Args) != 3 && len(expr.Args) != 4 {
+				return fmt.Errorf("%s expects a duration, start, end and optionally how to fill", expr.FnName.L)
+			}
+			gapFill, err := gapFillArgs(expr.Args)
+			if err != nil {
+				return fmt.Errorf("%s: %w", expr.FnName.L, err)
+			}
+			// Only the duration is grouped by, the other arguments were
+			// pushed as literals.
+			newExprs := v.exprStack[:len(v.exprStack)-len(expr.Args)+1]
+			duration, ok := newExprs[len(newExprs)-1].(*logicalplan.DurationExpr)
+			if !ok {
+				return fmt.Errorf("%s expects a duration as its first argument", expr.FnName.L)
+			}
+			gapFill.Step = duration.Value()
+			v.gapFill = &gapFill
+			v.exprStack = newExprs
 		default:
 			return fmt.Errorf("unhandled func call: %s", expr.FnName.String())
 		}
@@ -204,6 +373,183 @@ func (v *astVisitor) leaveImpl(n ast.Node) error {
 	return nil
 }
 
+// tableSample returns the sample of the statement's TABLESAMPLE clause, e.g.
+// TABLESAMPLE SYSTEM (10 PERCENT) REPEATABLE (42). False is returned if the
+// statement doesn't sample its table.
+func tableSample(stmt *ast.SelectStmt) (logicalplan.Sample, bool, error) {
+	if stmt.From == nil || stmt.From.TableRefs == nil {
+		return logicalplan.Sample{}, false, nil
+	}
+	source, ok := stmt.From.TableRefs.Left.(*ast.TableSource)
+	if !ok {
+		return logicalplan.Sample{}, false, nil
+	}
+	table, ok := source.Source.(*ast.TableName)
+	if !ok || table.TableSample == nil {
+		return logicalplan.Sample{}, false, nil
+	}
+
+	ts := table.TableSample
+	var sample logicalplan.Sample
+	switch ts.SampleMethod {
+	case ast.SampleMethodTypeBernoulli:
+		sample.Method = logicalplan.SampleBernoulli
+	case ast.SampleMethodTypeSystem, ast.SampleMethodTypeNone:
+		sample.Method = logicalplan.SampleSystem
+	default:
+		return logicalplan.Sample{}, false, fmt.Errorf("unhandled sample method")
+	}
+	if ts.SampleClauseUnit == ast.SampleClauseUnitTypeRow {
+		return logicalplan.Sample{}, false, fmt.Errorf("unhandled sample of a number of rows")
+	}
+	percent, err := numericValue(ts.Expr)
+	if err != nil {
+		return logicalplan.Sample{}, false, fmt.Errorf("sample size: %w", err)
+	}
+	sample.Fraction = percent / 100
+	if ts.RepeatableSeed != nil {
+		seed, err := numericValue(ts.RepeatableSeed)
+		if err != nil {
+			return logicalplan.Sample{}, false, fmt.Errorf("sample seed: %w", err)
+		}
+		sample.Seed = int64(seed)
+	}
+	return sample, true, nil
+}
+
+// gapFillArgs returns the gap fill of the arguments of gap_fill, apart from
+// its step, which is the duration it is given as the first argument.
+func gapFillArgs(args []ast.ExprNode) (logicalplan.GapFill, error) {
+	var gapFill logicalplan.GapFill
+	start, err := numericValue(args[1])
+	if err != nil {
+		return gapFill, fmt.Errorf("start: %w", err)
+	}
+	end, err := numericValue(args[2])
+	if err != nil {
+		return gapFill, fmt.Errorf("end: %w", err)
+	}
+	gapFill.Start, gapFill.End = int64(start), int64(end)
+	if len(args) < 4 {
+		return gapFill, nil
+	}
+
+	value, ok := args[3].(*test_driver.ValueExpr)
+	if !ok {
+		return gapFill, fmt.Errorf("expected a string literal of how to fill")
+	}
+	fill, ok := value.GetValue().(string)
+	if !ok {
+		return gapFill, fmt.Errorf("expected a string literal of how to fill")
+	}
+	switch strings.ToLower(fill) {
+	case "null":
+		gapFill.Fill = logicalplan.FillNull
+	case "previous", "locf":
+		gapFill.Fill = logicalplan.FillPrevious
+	case "linear":
+		gapFill.Fill = logicalplan.FillLinear
+	default:
+		return gapFill, fmt.Errorf("unknown fill %q", fill)
+	}
+	return gapFill, nil
+}
+
+// numericValue returns the value of a numeric literal.
+func numericValue(expr ast.ExprNode) (float64, error) {
+	value, ok := expr.(*test_driver.ValueExpr)
+	if !ok {
+		return 0, fmt.Errorf("expected a numeric literal")
+	}
+	switch v := value.GetValue().(type) {
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case *test_driver.MyDecimal:
+		return strconv.ParseFloat(v.String(), 64)
+	default:
+		return 0, fmt.Errorf("expected a numeric literal, got %T", v)
+	}
+}
+
+// unnestFunc is the name of the function flattening a list column, e.g.
+// SELECT unnest(stacktrace).
+const unnestFunc = "unnest"
+
+// firstFunc and lastFunc are the names of the functions returning the value
+// with the lowest and highest value of the column they are ordered by, e.g.
+// `last`(value, timestamp). They are keywords, so they must be quoted.
+const (
+	firstFunc = "first"
+	lastFunc  = "last"
+)
+
+// covarPopFunc, covarSampFunc and corrFunc are the names of the functions
+// aggregating pairs of values, e.g. corr(value, timestamp). Other statistical
+// aggregations are parsed as aggregate functions.
+const (
+	covarPopFunc  = "covar_pop"
+	covarSampFunc = "covar_samp"
+	corrFunc      = "corr"
+)
+
+// rateFunc and increaseFunc are the names of the functions returning the
+// per-second rate and the increase of a counter within the durations its
+// timestamps are grouped into, e.g. rate(value, timestamp).
+const (
+	rateFunc     = "rate"
+	increaseFunc = "increase"
+)
+
+// histogramFunc is the name of the function counting the values falling into
+// buckets with the given upper bounds, e.g. `histogram`(value, 1, 10, 100). It
+// is a keyword, so it must be quoted.
+// widthBucketFunc is the name of the function returning the bucket of a
+// value, e.g. width_bucket(value, 0, 100, 10) for 10 buckets spanning
+// [0, 100).
+const (
+	histogramFunc   = "histogram"
+	widthBucketFunc = "width_bucket"
+)
+
+// unnestColumns returns the list columns unnested anywhere in the statement.
+func unnestColumns(stmt *ast.SelectStmt) []logicalplan.Expr {
+	var cols []logicalplan.Expr
+	seen := map[string]struct{}{}
+	stmt.Accept(unnestFinder(func(call *ast.FuncCallExpr) {
+		if len(call.Args) != 1 {
+			return
+		}
+		col, ok := call.Args[0].(*ast.ColumnNameExpr)
+		if !ok {
+			return
+		}
+		name := columnNameToString(col.Name)
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			cols = append(cols, logicalplan.Col(name))
+		}
+	}))
+	return cols
+}
+
+// unnestFinder calls itself for every unnest function call it visits.
+type unnestFinder func(call *ast.FuncCallExpr)
+
+func (f unnestFinder) Enter(n ast.Node) (ast.Node, bool) {
+	if call, ok := n.(*ast.FuncCallExpr); ok && call.FnName.L == unnestFunc {
+		f(call)
+	}
+	return n, false
+}
+
+func (f unnestFinder) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
+
 func columnNameToString(c *ast.ColumnName) string {
 	// Note that in SQL labels.label2 is interpreted as referencing
 	// the label2 column of a table called labels. In our case,
@@ -221,3 +567,10 @@ func pop[T any](s []T) (T, []T) {
 	lastIdx := len(s) - 1
 	return s[lastIdx], s[:lastIdx]
 }
+
+// gapFillFunc is the name of the function filling in the buckets missing from
+// an aggregation grouped by a duration between a start and end timestamp in
+// milliseconds, e.g. GROUP BY gap_fill(second(60), 0, 600000, 'linear'). The
+// buckets are filled with 'null', the 'previous' value or a 'linear'
+// interpolation, and with nulls by default.
+const gapFillFunc = "gap_fill"
@@ -164,7 +164,7 @@ func (b *DefaultObjstoreBucket) Scan(ctx context.Context, prefix string, _ *dynp
 	return errg.Wait()
 }
 
-func (b *DefaultObjstoreBucket) openBlockFile(ctx context.Context, blockName string, size int64) (*parquet.File, error) {
+func (b *DefaultObjstoreBucket) openBlockFile(ctx context.Context, blockName string, size int64, skipBloomFilters bool) (*parquet.File, error) {
 	ctx, span := b.tracer.Start(ctx, "Source/IterateBucketBlocks/Iter/OpenFile")
 	defer span.End()
 	r, err := b.GetReaderAt(ctx, blockName)
@@ -176,7 +176,7 @@ func (b *DefaultObjstoreBucket) openBlockFile(ctx context.Context, blockName str
 		r,
 		size,
 		parquet.ReadBufferSize(5*MiB), // 5MB read buffers
-		parquet.SkipBloomFilters(true),
+		parquet.SkipBloomFilters(skipBloomFilters),
 		parquet.FileReadMode(parquet.ReadModeAsync),
 	)
 	if err != nil {
@@ -223,7 +223,8 @@ func (b *DefaultObjstoreBucket) ProcessFile(ctx context.Context, blockDir string
 		return nil
 	}
 
-	file, err := b.openBlockFile(ctx, blockName, attribs.Size)
+	// Bloom filters are only loaded if the filter is able to make use of them.
+	file, err := b.openBlockFile(ctx, blockName, attribs.Size, !expr.UsesBloomFilters(filter))
 	if err != nil {
 		return err
 	}
@@ -234,26 +235,36 @@ func (b *DefaultObjstoreBucket) ProcessFile(ctx context.Context, blockDir string
 		return err
 	}
 
-	return b.filterRowGroups(ctx, buf, filter, callback)
+	return b.filterRowGroups(ctx, blockUlid, buf, filter, callback)
 }
 
-func (b *DefaultObjstoreBucket) filterRowGroups(ctx context.Context, buf *dynparquet.SerializedBuffer, filter expr.TrueNegativeFilter, callback func(context.Context, any) error) error {
+func (b *DefaultObjstoreBucket) filterRowGroups(ctx context.Context, blockUlid ulid.ULID, buf *dynparquet.SerializedBuffer, filter expr.TrueNegativeFilter, callback func(context.Context, any) error) error {
 	_, span := b.tracer.Start(ctx, "Source/filterRowGroups")
 	defer span.End()
 	span.SetAttributes(attribute.Int("row_groups", buf.NumRowGroups()))
 
+	stats := expr.ScanStatsFromContext(ctx)
+	sample := expr.SampleFromContext(ctx)
+	pruned := true
 	for i := 0; i < buf.NumRowGroups(); i++ {
+		key := fmt.Sprintf("%s/%d", blockUlid, i)
+		if !expr.SampleRowGroup(sample, key) {
+			continue
+		}
 		rg := buf.DynamicRowGroup(i)
 		mayContainUsefulData, err := filter.Eval(rg)
 		if err != nil {
 			return err
 		}
+		stats.ObserveRowGroup(!mayContainUsefulData)
 		if mayContainUsefulData {
-			if err := callback(ctx, rg); err != nil {
+			pruned = false
+			if err := callback(ctx, expr.RowGroupOfSample(sample, key, rg)); err != nil {
 				return err
 			}
 		}
 	}
+	stats.ObservePart(pruned)
 
 	return nil
 }
@@ -38,6 +38,7 @@ import (
 	"github.com/polarsignals/frostdb/index"
 	"github.com/polarsignals/frostdb/parts"
 	"github.com/polarsignals/frostdb/pqarrow"
+	"github.com/polarsignals/frostdb/query/expr"
 	"github.com/polarsignals/frostdb/query/logicalplan"
 	"github.com/polarsignals/frostdb/query/physicalplan"
 	"github.com/polarsignals/frostdb/recovery"
@@ -100,6 +101,16 @@ func WithoutWAL() TableOption {
 	}
 }
 
+// WithMaterializedView declares the table as a materialized view that is
+// maintained as an aggregation of the records inserted into the view's source
+// table.
+func WithMaterializedView(view *tablepb.MaterializedView) TableOption {
+	return func(config *tablepb.TableConfig) error {
+		config.MaterializedView = view
+		return nil
+	}
+}
+
 // FromConfig sets the table configuration from the given config.
 // NOTE: that this does not override the schema even though that is included in the passed in config.
 func FromConfig(config *tablepb.TableConfig) TableOption {
@@ -109,6 +120,7 @@ func FromConfig(config *tablepb.TableConfig) TableOption {
 		}
 		cfg.DisableWal = config.DisableWal
 		cfg.RowGroupSize = config.RowGroupSize
+		cfg.MaterializedView = config.MaterializedView
 		return nil
 	}
 }
@@ -151,8 +163,10 @@ type completedBlock struct {
 }
 
 type Table struct {
-	db      *DB
-	name    string
+	db   *DB
+	name string
+	// id identifies the table among all tables, see ID.
+	id      ulid.ULID
 	metrics *tableMetrics
 	logger  log.Logger
 	tracer  trace.Tracer
@@ -169,6 +183,11 @@ type Table struct {
 
 	wal     WAL
 	closing bool
+
+	// pendingViewRecords are the records of a materialized view that failed
+	// to be inserted.
+	pendingViewMtx     sync.Mutex
+	pendingViewRecords []arrow.Record
 }
 
 type WAL interface {
@@ -225,6 +244,7 @@ type tableMetrics struct {
 	rowInsertSize        prometheus.Histogram
 	lastCompletedBlockTx prometheus.Gauge
 	numParts             prometheus.Gauge
+	pendingViewRecords   prometheus.Gauge
 
 	indexMetrics *index.LSMMetrics
 }
@@ -271,9 +291,16 @@ func newTable(
 		return nil, err
 	}
 
+	if view := tableConfig.GetMaterializedView(); view != nil {
+		if _, _, err := materializedViewExprs(view); err != nil {
+			return nil, err
+		}
+	}
+
 	t := &Table{
 		db:     db,
 		name:   name,
+		id:     generateULID(),
 		logger: logger,
 		tracer: tracer,
 		mtx:    &sync.RWMutex{},
@@ -313,6 +340,10 @@ func newTable(
 				Name: "frostdb_table_last_completed_block_tx",
 				Help: "Last completed block transaction.",
 			}),
+			pendingViewRecords: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+				Name: "frostdb_table_materialized_view_pending_records",
+				Help: "Number of records of a materialized view that failed to be inserted and are yet to be.",
+			}),
 			indexMetrics: index.NewLSMMetrics(reg),
 		},
 	}
@@ -478,13 +509,25 @@ func (t *Table) writeBlock(block *TableBlock, skipPersist, snapshotDB bool) {
 	}
 }
 
-func (t *Table) RotateBlock(_ context.Context, block *TableBlock, skipPersist bool) error {
+func (t *Table) RotateBlock(ctx context.Context, block *TableBlock, skipPersist bool) error {
+	rotated, err := t.rotateBlock(block, skipPersist)
+	if err != nil || !rotated {
+		return err
+	}
+
+	// Materialized views of the table that failed to insert deltas catch up
+	// as its block is rotated, rather than only with its next insert.
+	t.db.maintainViews(ctx, t.db.pendingViewDeltas(t))
+	return nil
+}
+
+func (t *Table) rotateBlock(block *TableBlock, skipPersist bool) (bool, error) {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
 
 	// Need to check that we haven't already rotated this block.
 	if t.active != block {
-		return nil
+		return false, nil
 	}
 
 	level.Debug(t.logger).Log("msg", "rotating block", "blockSize", block.Size(), "skipPersist", skipPersist)
@@ -501,7 +544,7 @@ func (t *Table) RotateBlock(_ context.Context, block *TableBlock, skipPersist bo
 		id = generateULID()
 	}
 	if err := t.newTableBlock(t.active.minTx, tx, id); err != nil {
-		return err
+		return false, err
 	}
 	t.metrics.blockRotated.Inc()
 	t.metrics.numParts.Set(float64(0))
@@ -512,7 +555,7 @@ func (t *Table) RotateBlock(_ context.Context, block *TableBlock, skipPersist bo
 	// will specify through skipPersist if they want the block to be persisted.
 	go t.writeBlock(block, skipPersist, true)
 
-	return nil
+	return true, nil
 }
 
 func (t *Table) ActiveBlock() *TableBlock {
@@ -566,6 +609,27 @@ func (t *Table) Write(ctx context.Context, vals ...any) (uint64, error) {
 }
 
 func (t *Table) InsertRecord(ctx context.Context, record arrow.Record) (uint64, error) {
+	deltas, err := t.db.viewDeltas(ctx, t, record)
+	if err != nil {
+		return 0, fmt.Errorf("maintain materialized views: %w", err)
+	}
+
+	tx, err := t.insertRecord(ctx, record)
+	if err != nil {
+		releaseViewDeltas(deltas)
+		return tx, err
+	}
+
+	// The record is inserted and committed at this point, so views that
+	// fail to insert their deltas don't fail the insert, which would then
+	// be retried and insert the record twice. They keep the deltas to catch
+	// up later.
+	t.db.maintainViews(ctx, deltas)
+
+	return tx, nil
+}
+
+func (t *Table) insertRecord(ctx context.Context, record arrow.Record) (uint64, error) {
 	block, finish, err := t.appender(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("get appender: %w", err)
@@ -678,31 +742,54 @@ func (t *Table) Iterator(
 	// buffered results are flushed to the next operator.
 	const bufferSize = 1024
 
+	// Row groups are passed on in the order of their watermark column to
+	// report the progress of the iteration through it.
+	var watermarks *rowGroupWatermarks
+	if iterOpts.Watermarks != nil && len(iterOpts.MetadataAggregations) == 0 {
+		watermarks = newRowGroupWatermarks(iterOpts.Watermarks)
+	}
+
 	errg, ctx := errgroup.WithContext(ctx)
 	for _, callback := range callbacks {
 		callback := callback
 		errg.Go(recovery.Do(func() error {
+			if len(iterOpts.MetadataAggregations) > 0 {
+				return aggregateMetadata(ctx, pool, iterOpts, rowGroups, callback)
+			}
+
 			converter := pqarrow.NewParquetConverter(pool, *iterOpts)
 			defer converter.Close()
 
+			flush := func() error {
+				r := converter.NewRecord()
+				if r == nil {
+					return nil
+				}
+				defer r.Release()
+				if r.NumRows() == 0 {
+					return nil
+				}
+				return callback(ctx, r)
+			}
+
 			for {
 				select {
 				case <-ctx.Done():
 					return ctx.Err()
 				case rg, ok := <-rowGroups:
 					if !ok {
-						r := converter.NewRecord()
-						if r == nil {
-							return nil
-						}
-						defer r.Release()
-						if r.NumRows() == 0 {
-							return nil
-						}
-						return callback(ctx, r)
+						return flush()
 					}
 
+					watermarked, isWatermarked := rg.(watermarkedRowGroup)
+					if isWatermarked {
+						rg = watermarked.rowGroup
+					}
 					switch t := rg.(type) {
+					case *expr.SampledRowGroup:
+						if err := sampleRows(ctx, pool, *iterOpts, t, callback); err != nil {
+							return err
+						}
 					case arrow.Record:
 						defer t.Release()
 						r := pqarrow.Project(t, iterOpts.PhysicalProjection)
@@ -717,35 +804,129 @@ func (t *Table) Iterator(
 						}
 						// This RowGroup had no relevant data. Ignore it.
 						if len(converter.Fields()) == 0 {
-							continue
+							break
 						}
 						if converter.NumRows() >= bufferSize {
-							err := func() error {
-								r := converter.NewRecord()
-								defer r.Release()
-								return callback(ctx, r)
-							}()
-							if err != nil {
+							if err := flush(); err != nil {
 								return err
 							}
 						}
 					default:
 						return fmt.Errorf("unknown row group type: %T", t)
 					}
+
+					if isWatermarked {
+						// The rows of the row group are passed on before
+						// it's done, so that the watermark never passes
+						// rows that are still buffered.
+						if err := flush(); err != nil {
+							return err
+						}
+						if err := watermarks.finish(ctx, watermarked.block, watermarked.index); err != nil {
+							return err
+						}
+					}
 				}
 			}
 		}))
 	}
 
 	errg.Go(func() error {
-		if err := t.collectRowGroups(ctx, tx, iterOpts.Filter, iterOpts.InMemoryOnly, rowGroups); err != nil {
+		var err error
+		if watermarks != nil {
+			err = t.collectWatermarkedRowGroups(ctx, tx, *iterOpts, watermarks, rowGroups)
+		} else {
+			err = t.collectRowGroups(ctx, tx, *iterOpts, rowGroups)
+		}
+		if err != nil {
 			return err
 		}
 		close(rowGroups)
 		return nil
 	})
 
-	return errg.Wait()
+	err := errg.Wait()
+	if watermarks != nil {
+		// Blocks whose row groups weren't all done as the iteration
+		// stopped early are only released once no worker reads them.
+		watermarks.release()
+	}
+	return err
+}
+
+// sampleRows passes the rows of the row group or record that are part of the
+// sample on to the callback. The row group is converted on its own, as the
+// rows are sampled by their offsets in it.
+func sampleRows(
+	ctx context.Context,
+	pool memory.Allocator,
+	iterOpts logicalplan.IterOptions,
+	rg *expr.SampledRowGroup,
+	callback logicalplan.Callback,
+) error {
+	var r arrow.Record
+	switch v := rg.RowGroup.(type) {
+	case arrow.Record:
+		defer v.Release()
+		r = pqarrow.Project(v, iterOpts.PhysicalProjection)
+	case dynparquet.DynamicRowGroup:
+		converter := pqarrow.NewParquetConverter(pool, iterOpts)
+		defer converter.Close()
+		if err := converter.Convert(ctx, v); err != nil {
+			return fmt.Errorf("failed to convert row group to arrow record: %v", err)
+		}
+		if r = converter.NewRecord(); r == nil {
+			return nil
+		}
+	default:
+		return fmt.Errorf("unknown row group type: %T", v)
+	}
+	defer r.Release()
+
+	// The sampled rows are copied in runs of consecutive rows.
+	var runs [][2]int64
+	var sampledRows int64
+	for i := int64(0); i < r.NumRows(); i++ {
+		if !expr.SampleRow(iterOpts.Sample, rg.Key, int(i)) {
+			continue
+		}
+		if n := len(runs); n > 0 && runs[n-1][1] == i {
+			runs[n-1][1]++
+		} else {
+			runs = append(runs, [2]int64{i, i + 1})
+		}
+		sampledRows++
+	}
+	if sampledRows == 0 {
+		return nil
+	}
+	if sampledRows == r.NumRows() {
+		return callback(ctx, r)
+	}
+
+	cols := make([]arrow.Array, 0, r.NumCols())
+	defer func() {
+		for _, c := range cols {
+			c.Release()
+		}
+	}()
+	for _, col := range r.Columns() {
+		slices := make([]arrow.Array, 0, len(runs))
+		for _, run := range runs {
+			slices = append(slices, array.NewSlice(col, run[0], run[1]))
+		}
+		sampled, err := array.Concatenate(slices, pool)
+		for _, s := range slices {
+			s.Release()
+		}
+		if err != nil {
+			return err
+		}
+		cols = append(cols, sampled)
+	}
+	sampled := array.NewRecord(r.Schema(), cols, sampledRows)
+	defer sampled.Release()
+	return callback(ctx, sampled)
 }
 
 // SchemaIterator iterates in order over all granules in the table and returns
@@ -793,6 +974,12 @@ func (t *Table) SchemaIterator(
 						return nil // we're done
 					}
 
+					if sampled, ok := rg.(*expr.SampledRowGroup); ok {
+						// Sampling the rows of a row group doesn't change
+						// the fields it has.
+						rg = sampled.RowGroup
+					}
+
 					b := array.NewRecordBuilder(pool, schema)
 
 					switch t := rg.(type) {
@@ -803,6 +990,7 @@ func (t *Table) SchemaIterator(
 						record := b.NewRecord()
 						err := callback(ctx, record)
 						record.Release()
+						b.Release()
 						t.Release()
 						if err != nil {
 							return err
@@ -834,7 +1022,7 @@ func (t *Table) SchemaIterator(
 	}
 
 	errg.Go(func() error {
-		if err := t.collectRowGroups(ctx, tx, iterOpts.Filter, iterOpts.InMemoryOnly, rowGroups); err != nil {
+		if err := t.collectRowGroups(ctx, tx, *iterOpts, rowGroups); err != nil {
 			return err
 		}
 		close(rowGroups)
@@ -1028,57 +1216,129 @@ func (t *Table) memoryBlocks() ([]*TableBlock, uint64) {
 	return memoryBlocks, lastReadBlockTimestamp
 }
 
+// ID returns the identifier of the table, which is unique among the tables of
+// all databases. A table that is dropped and created again has a new ID.
+func (t *Table) ID() string {
+	return t.id.String()
+}
+
+// LastBlockTimestamp returns the timestamp of the oldest block that is still
+// held in memory. Blocks of the table's sources that were created before it
+// are persisted and immutable. False is returned if the table has no sources
+// or no block in memory.
+func (t *Table) LastBlockTimestamp() (uint64, bool) {
+	if len(t.db.sources) == 0 {
+		return 0, false
+	}
+
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	if t.active == nil {
+		return 0, false
+	}
+
+	lastBlockTimestamp := t.active.ulid.Time()
+	for block := range t.pendingBlocks {
+		if block.ulid.Time() < lastBlockTimestamp {
+			lastBlockTimestamp = block.ulid.Time()
+		}
+	}
+	return lastBlockTimestamp, true
+}
+
 // collectRowGroups collects all the row groups from the table for the given filter.
 func (t *Table) collectRowGroups(
 	ctx context.Context,
 	tx uint64,
-	filterExpr logicalplan.Expr,
-	skipSources bool,
+	iterOpts logicalplan.IterOptions,
 	rowGroups chan<- any,
+) error {
+	return t.scanRowGroups(ctx, tx, iterOpts, func(ctx context.Context, v any) error {
+		select {
+		case rowGroups <- v:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}, nil)
+}
+
+// scanRowGroups calls fn with all the row groups from the table for the given
+// filter, one block after the other. If scanned is non-nil, it's called once
+// the row groups of each memory block and of each data source were passed to
+// fn, along with the function releasing the reference held on the memory block
+// while it's read, which is nil for data sources. The block can't be persisted
+// until the reference is released, so scanned must release it once the row
+// groups of the block are no longer used.
+func (t *Table) scanRowGroups(
+	ctx context.Context,
+	tx uint64,
+	iterOpts logicalplan.IterOptions,
+	fn func(ctx context.Context, v any) error,
+	scanned func(release func()) error,
 ) error {
 	ctx, span := t.tracer.Start(ctx, "Table/collectRowGroups")
 	defer span.End()
 
+	filterExpr := iterOpts.Filter
+	if iterOpts.Sample != nil {
+		ctx = expr.ContextWithSample(ctx, iterOpts.Sample)
+	}
+	if iterOpts.PersistedBefore != 0 {
+		return t.collectSourceRowGroups(ctx, filterExpr, iterOpts.PersistedBefore, fn, scanned)
+	}
+
 	// pending blocks could be uploaded to the bucket while we iterate on them.
 	// to avoid to iterate on them again while reading the block file
 	// we keep the last block timestamp to be read from the bucket and pass it to the IterateBucketBlocks() function
 	// so that every block with a timestamp >= lastReadBlockTimestamp is discarded while being read.
 	memoryBlocks, lastBlockTimestamp := t.memoryBlocks()
+	handedOff := 0
 	defer func() {
-		for _, block := range memoryBlocks {
+		for _, block := range memoryBlocks[handedOff:] {
 			block.pendingReadersWg.Done()
 		}
 	}()
 	for _, block := range memoryBlocks {
-		if err := block.index.Scan(ctx, "", t.schema, filterExpr, tx, func(ctx context.Context, v any) error {
-			select {
-			case rowGroups <- v:
-				return nil
-			case <-ctx.Done():
-				return ctx.Err()
-			}
-		}); err != nil {
+		if err := block.index.Scan(ctx, block.ulid.String(), t.schema, filterExpr, tx, fn); err != nil {
 			return err
 		}
+		if scanned != nil {
+			handedOff++
+			if err := scanned(block.pendingReadersWg.Done); err != nil {
+				return err
+			}
+		}
 	}
 
-	if skipSources {
+	if iterOpts.InMemoryOnly {
 		return nil
 	}
 
-	// Collect from all other data sources.
+	return t.collectSourceRowGroups(ctx, filterExpr, lastBlockTimestamp, fn, scanned)
+}
+
+// collectSourceRowGroups collects the row groups of the blocks of all data
+// sources created before lastBlockTimestamp.
+func (t *Table) collectSourceRowGroups(
+	ctx context.Context,
+	filterExpr logicalplan.Expr,
+	lastBlockTimestamp uint64,
+	fn func(ctx context.Context, v any) error,
+	scanned func(release func()) error,
+) error {
+	span := trace.SpanFromContext(ctx)
 	for _, source := range t.db.sources {
 		span.AddEvent(fmt.Sprintf("source/%s", source.String()))
-		if err := source.Scan(ctx, filepath.Join(t.db.name, t.name), t.schema, filterExpr, lastBlockTimestamp, func(ctx context.Context, v any) error {
-			select {
-			case rowGroups <- v:
-				return nil
-			case <-ctx.Done():
-				return ctx.Err()
-			}
-		}); err != nil {
+		if err := source.Scan(ctx, filepath.Join(t.db.name, t.name), t.schema, filterExpr, lastBlockTimestamp, fn); err != nil {
 			return err
 		}
+		if scanned != nil {
+			if err := scanned(nil); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -1346,7 +1606,7 @@ func (t *Table) distinctRecordsForCompaction(compact []parts.Part) ([]arrow.Reco
 		columnExprs = append(columnExprs, expr)
 	}
 
-	d := physicalplan.Distinct(memory.NewGoAllocator(), t.tracer, columnExprs)
+	d := physicalplan.Distinct(memory.NewGoAllocator(), t.tracer, columnExprs, 0)
 	output := physicalplan.OutputPlan{}
 	newRecords := make([]arrow.Record, 0)
 	output.SetNextCallback(func(ctx context.Context, r arrow.Record) error {
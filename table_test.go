@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
+	"os"
 	"runtime"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -21,6 +24,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/parquet-go/parquet-go"
 	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
 
 	"github.com/polarsignals/frostdb/dynparquet"
 	schemapb "github.com/polarsignals/frostdb/gen/proto/go/frostdb/schema/v1alpha1"
@@ -28,6 +32,7 @@ import (
 	"github.com/polarsignals/frostdb/pqarrow"
 	"github.com/polarsignals/frostdb/query"
 	"github.com/polarsignals/frostdb/query/logicalplan"
+	"github.com/polarsignals/frostdb/query/physicalplan"
 )
 
 type TestLogHelper interface {
@@ -1209,3 +1214,988 @@ func TestTableUniquePrimaryIndex(t *testing.T) {
 		}))
 	require.Equal(t, 1, rowsRead)
 }
+
+func Test_Table_ExecuteReader(t *testing.T) {
+	c, table := basicTable(t)
+	defer c.Close()
+
+	const numRecords = 10
+	samples := dynparquet.GenerateTestSamples(numRecords)
+	for i := range samples {
+		r, err := samples[i : i+1].ToRecord()
+		require.NoError(t, err)
+		_, err = table.InsertRecord(context.Background(), r)
+		require.NoError(t, err)
+		r.Release()
+	}
+
+	engine := query.NewEngine(memory.DefaultAllocator, table.db.TableProvider())
+
+	t.Run("ReadAll", func(t *testing.T) {
+		reader, err := engine.ScanTable("test").(query.ExtendedBuilder).ExecuteReader(context.Background())
+		require.NoError(t, err)
+		defer reader.Release()
+
+		require.True(t, reader.Schema().HasField("timestamp"))
+		rows := int64(0)
+		for reader.Next() {
+			rows += reader.Record().NumRows()
+		}
+		require.NoError(t, reader.Err())
+		require.Equal(t, int64(numRecords), rows)
+	})
+
+	t.Run("ReleaseEarly", func(t *testing.T) {
+		reader, err := engine.ScanTable("test").(query.ExtendedBuilder).ExecuteReader(context.Background())
+		require.NoError(t, err)
+		require.True(t, reader.Next())
+		// Releasing the reader before consuming all records must cancel the
+		// remaining work without blocking.
+		reader.Release()
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		reader, err := engine.ScanTable("test").
+			Filter(logicalplan.Col("timestamp").Lt(logicalplan.Literal(int64(-1)))).(query.ExtendedBuilder).
+			ExecuteReader(context.Background())
+		require.NoError(t, err)
+		defer reader.Release()
+
+		require.False(t, reader.Next())
+		require.NoError(t, reader.Err())
+		// The empty result of the dynamic output holds no concrete columns
+		// of the dynamic one, but all others.
+		require.True(t, reader.Schema().HasField("timestamp"))
+		require.False(t, reader.Schema().HasField("labels.label1"))
+	})
+
+	t.Run("OutputSchema", func(t *testing.T) {
+		// The output schema holds the types of the data read from parquet.
+		require.NoError(t, table.EnsureCompaction())
+		q := engine.ScanTable("test").
+			Aggregate(
+				[]logicalplan.Expr{logicalplan.Sum(logicalplan.Col("value"))},
+				[]logicalplan.Expr{logicalplan.Col("example_type")},
+			).(query.ExtendedBuilder)
+		schema, err := q.OutputSchema()
+		require.NoError(t, err)
+
+		reader, err := q.ExecuteReader(context.Background())
+		require.NoError(t, err)
+		defer reader.Release()
+		require.True(t, schema.Equal(reader.Schema()), "%s\n%s", schema, reader.Schema())
+		for reader.Next() {
+			require.True(t, schema.Equal(reader.Record().Schema()), "%s\n%s", schema, reader.Record().Schema())
+		}
+		require.NoError(t, reader.Err())
+	})
+}
+
+func Test_Table_OutputSchema(t *testing.T) {
+	c, table := basicTable(t)
+	defer c.Close()
+
+	samples := dynparquet.GenerateTestSamples(10)
+	r, err := samples.ToRecord()
+	require.NoError(t, err)
+	_, err = table.InsertRecord(context.Background(), r)
+	require.NoError(t, err)
+	r.Release()
+	// Compact the inserted record so that it is read from parquet, which
+	// determines the types of the output.
+	require.NoError(t, table.EnsureCompaction())
+
+	engine := query.NewEngine(memory.DefaultAllocator, table.db.TableProvider())
+	for _, tc := range []struct {
+		name  string
+		query query.Builder
+	}{
+		{
+			name: "Projection",
+			query: engine.ScanTable("test").
+				Project(logicalplan.Col("labels.node"), logicalplan.Col("timestamp"), logicalplan.Col("value").Gt(logicalplan.Literal(int64(5)))),
+		},
+		{
+			name: "Aggregation",
+			query: engine.ScanTable("test").
+				Aggregate(
+					[]logicalplan.Expr{
+						logicalplan.Sum(logicalplan.Col("value")).Alias("value_sum"),
+						logicalplan.Max(logicalplan.Col("value")),
+						logicalplan.Count(logicalplan.Col("value")),
+					},
+					[]logicalplan.Expr{logicalplan.Col("labels.node"), logicalplan.Col("example_type")},
+				),
+		},
+		{
+			name: "ComputedGrouping",
+			query: engine.ScanTable("test").
+				Aggregate(
+					[]logicalplan.Expr{logicalplan.Sum(logicalplan.Col("value"))},
+					[]logicalplan.Expr{
+						logicalplan.Col("value").Gt(logicalplan.Literal(int64(5))).Alias("large"),
+						logicalplan.Col("labels.node"),
+						logicalplan.Col("timestamp").Lt(logicalplan.Literal(int64(3))),
+					},
+				),
+		},
+		{
+			name: "Average",
+			query: engine.ScanTable("test").
+				Aggregate(
+					[]logicalplan.Expr{logicalplan.Avg(logicalplan.Col("value")), logicalplan.Min(logicalplan.Col("value"))},
+					[]logicalplan.Expr{logicalplan.Col("labels.node")},
+				),
+		},
+		{
+			name: "Distinct",
+			query: engine.ScanTable("test").
+				Distinct(logicalplan.Col("labels.node")),
+		},
+		{
+			name: "Filter",
+			query: engine.ScanTable("test").
+				Filter(logicalplan.Col("value").Gt(logicalplan.Literal(int64(3)))).
+				Project(logicalplan.Col("value"), logicalplan.Col("stacktrace")),
+		},
+		{
+			name:  "SchemaScan",
+			query: engine.ScanSchema("test"),
+		},
+		{
+			name: "Sample",
+			query: engine.ScanTable("test").(query.ExtendedBuilder).
+				Sample(logicalplan.Sample{Method: logicalplan.SampleBernoulli, Fraction: 1}).
+				Project(logicalplan.Col("timestamp"), logicalplan.Col("labels.node")),
+		},
+		{
+			name: "SemiJoin",
+			query: engine.ScanTable("test").(query.ExtendedBuilder).
+				SemiJoin(engine.ScanTable("test"), logicalplan.SemiJoin{
+					Key:      logicalplan.Col("example_type"),
+					InnerKey: logicalplan.Col("example_type"),
+				}).
+				Project(logicalplan.Col("example_type"), logicalplan.Col("value")),
+		},
+		{
+			name: "GapFill",
+			query: engine.ScanTable("test").
+				Aggregate(
+					[]logicalplan.Expr{logicalplan.Sum(logicalplan.Col("value"))},
+					[]logicalplan.Expr{logicalplan.Col("labels.node"), logicalplan.Duration(time.Millisecond)},
+				).(query.ExtendedBuilder).
+				GapFill(logicalplan.GapFill{Start: 0, End: 20, Step: time.Millisecond, Fill: logicalplan.FillLinear}),
+		},
+		{
+			name: "Unpivot",
+			query: engine.ScanTable("test").(query.ExtendedBuilder).
+				Unpivot(logicalplan.Unpivot{Expr: logicalplan.DynCol("labels"), Key: "label", Value: "label_value"}),
+		},
+		{
+			name: "Pivot",
+			query: engine.ScanTable("test").(query.ExtendedBuilder).
+				Unpivot(logicalplan.Unpivot{Expr: logicalplan.DynCol("labels"), Key: "label", Value: "label_value"}).
+				Pivot(logicalplan.Pivot{Key: logicalplan.Col("label"), Value: logicalplan.Col("label_value"), Prefix: "labels"}).
+				Project(logicalplan.Col("timestamp"), logicalplan.Col("labels.node")),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			schema, err := tc.query.(query.ExtendedBuilder).OutputSchema()
+			require.NoError(t, err)
+
+			var actual *arrow.Schema
+			require.NoError(t, tc.query.Execute(context.Background(), func(_ context.Context, r arrow.Record) error {
+				actual = r.Schema()
+				return nil
+			}))
+			require.NotNil(t, actual)
+			require.Equal(t, actual.NumFields(), schema.NumFields(), "expected %s, got %s", actual, schema)
+			for i, f := range actual.Fields() {
+				require.Equal(t, f.Name, schema.Field(i).Name)
+				require.True(t, arrow.TypeEqual(f.Type, schema.Field(i).Type), "field %s: expected %s, got %s", f.Name, f.Type, schema.Field(i).Type)
+			}
+		})
+	}
+
+	t.Run("Empty", func(t *testing.T) {
+		q := engine.ScanTable("test").
+			Filter(logicalplan.Col("value").Lt(logicalplan.Literal(int64(0)))).
+			Project(logicalplan.Col("value"), logicalplan.Col("labels.node"))
+		schema, err := q.(query.ExtendedBuilder).OutputSchema()
+		require.NoError(t, err)
+
+		records := 0
+		require.NoError(t, q.Execute(context.Background(), func(_ context.Context, r arrow.Record) error {
+			records++
+			require.Equal(t, int64(0), r.NumRows())
+			require.True(t, schema.Equal(r.Schema()))
+			return nil
+		}))
+		require.Equal(t, 1, records)
+	})
+
+	t.Run("Dynamic", func(t *testing.T) {
+		_, err := engine.ScanTable("test").Project(logicalplan.DynCol("labels")).(query.ExtendedBuilder).OutputSchema()
+		require.ErrorIs(t, err, logicalplan.ErrDynamicOutputSchema)
+	})
+
+	t.Run("Unnest", func(t *testing.T) {
+		repeated, err := table.db.Table("repeated", NewTableConfig(&schemapb.Schema{
+			Name: "repeated",
+			Columns: []*schemapb.Column{{
+				Name: "name",
+				StorageLayout: &schemapb.StorageLayout{
+					Type: schemapb.StorageLayout_TYPE_STRING,
+				},
+			}, {
+				Name: "values",
+				StorageLayout: &schemapb.StorageLayout{
+					Type:     schemapb.StorageLayout_TYPE_STRING,
+					Encoding: schemapb.StorageLayout_ENCODING_RLE_DICTIONARY,
+					Repeated: true,
+				},
+			}},
+			SortingColumns: []*schemapb.SortingColumn{{
+				Name:      "name",
+				Direction: schemapb.SortingColumn_DIRECTION_ASCENDING,
+			}},
+		}))
+		require.NoError(t, err)
+		buffer, err := repeated.Schema().GetBuffer(nil)
+		require.NoError(t, err)
+		_, err = buffer.WriteRows([]parquet.Row{{
+			parquet.ValueOf("a").Level(0, 0, 0),
+			parquet.ValueOf("x").Level(0, 1, 1),
+			parquet.ValueOf("y").Level(1, 1, 1),
+		}, {
+			parquet.ValueOf("b").Level(0, 0, 0),
+			parquet.ValueOf("z").Level(0, 1, 1),
+		}})
+		require.NoError(t, err)
+		converter := pqarrow.NewParquetConverter(memory.NewGoAllocator(), logicalplan.IterOptions{})
+		defer converter.Close()
+		require.NoError(t, converter.Convert(context.Background(), buffer))
+		record := converter.NewRecord()
+		defer record.Release()
+		_, err = repeated.InsertRecord(context.Background(), record)
+		require.NoError(t, err)
+		require.NoError(t, repeated.EnsureCompaction())
+
+		for _, tc := range []struct {
+			query query.Builder
+			rows  int64
+		}{
+			{query: engine.ScanTable("repeated"), rows: 2},
+			{query: engine.ScanTable("repeated").(query.ExtendedBuilder).Unnest(logicalplan.Col("values")), rows: 3},
+		} {
+			schema, err := tc.query.(query.ExtendedBuilder).OutputSchema()
+			require.NoError(t, err)
+			rows := int64(0)
+			require.NoError(t, tc.query.Execute(context.Background(), func(_ context.Context, r arrow.Record) error {
+				require.True(t, schema.Equal(r.Schema()), "expected %s, got %s", schema, r.Schema())
+				rows += r.NumRows()
+				return nil
+			}))
+			require.Equal(t, tc.rows, rows)
+		}
+	})
+}
+
+func Test_Table_AverageFloat(t *testing.T) {
+	c, err := New()
+	require.NoError(t, err)
+	defer c.Close()
+
+	db, err := c.DB(context.Background(), "test")
+	require.NoError(t, err)
+	table, err := db.Table("test", NewTableConfig(&schemapb.Schema{
+		Name: "average",
+		Columns: []*schemapb.Column{{
+			Name: "name",
+			StorageLayout: &schemapb.StorageLayout{
+				Type: schemapb.StorageLayout_TYPE_STRING,
+			},
+		}, {
+			Name: "value",
+			StorageLayout: &schemapb.StorageLayout{
+				Type: schemapb.StorageLayout_TYPE_DOUBLE,
+			},
+		}},
+		SortingColumns: []*schemapb.SortingColumn{{
+			Name:      "name",
+			Direction: schemapb.SortingColumn_DIRECTION_ASCENDING,
+		}},
+	}))
+	require.NoError(t, err)
+
+	type row struct {
+		Name  string
+		Value float64
+	}
+	ctx := context.Background()
+	_, err = table.Write(ctx, row{"a", 1}, row{"a", 2}, row{"b", 0.5})
+	require.NoError(t, err)
+
+	engine := query.NewEngine(memory.DefaultAllocator, db.TableProvider())
+	q := engine.ScanTable("test").Aggregate(
+		[]logicalplan.Expr{logicalplan.Avg(logicalplan.Col("value"))},
+		[]logicalplan.Expr{logicalplan.Col("name")},
+	)
+	schema, err := q.(query.ExtendedBuilder).OutputSchema()
+	require.NoError(t, err)
+	require.Equal(t, arrow.PrimitiveTypes.Float64, schema.Field(schema.FieldIndices("avg(value)")[0]).Type)
+
+	avgs := map[string]float64{}
+	require.NoError(t, q.Execute(ctx, func(_ context.Context, r arrow.Record) error {
+		names := r.Column(r.Schema().FieldIndices("name")[0]).(*array.Binary)
+		values := r.Column(r.Schema().FieldIndices("avg(value)")[0]).(*array.Float64)
+		for i := 0; i < int(r.NumRows()); i++ {
+			avgs[string(names.Value(i))] = values.Value(i)
+		}
+		return nil
+	}))
+	require.Equal(t, map[string]float64{"a": 1.5, "b": 0.5}, avgs)
+}
+
+func Test_Table_AggregateSpill(t *testing.T) {
+	c, table := basicTable(t)
+	defer c.Close()
+
+	samples := dynparquet.GenerateTestSamples(1000)
+	r, err := samples.ToRecord()
+	require.NoError(t, err)
+	_, err = table.InsertRecord(context.Background(), r)
+	require.NoError(t, err)
+	r.Release()
+
+	aggregate := func(engine *query.LocalEngine) map[int64][2]int64 {
+		results := map[int64][2]int64{}
+		err := engine.ScanTable("test").
+			Aggregate(
+				[]logicalplan.Expr{logicalplan.Sum(logicalplan.Col("value")), logicalplan.Count(logicalplan.Col("value"))},
+				[]logicalplan.Expr{logicalplan.Col("timestamp")},
+			).
+			Execute(context.Background(), func(_ context.Context, r arrow.Record) error {
+				for i := 0; i < int(r.NumRows()); i++ {
+					ts := r.Column(r.Schema().FieldIndices("timestamp")[0]).(*array.Int64).Value(i)
+					require.NotContains(t, results, ts)
+					results[ts] = [2]int64{
+						r.Column(r.Schema().FieldIndices("sum(value)")[0]).(*array.Int64).Value(i),
+						r.Column(r.Schema().FieldIndices("count(value)")[0]).(*array.Int64).Value(i),
+					}
+				}
+				return nil
+			})
+		require.NoError(t, err)
+		return results
+	}
+
+	dir := t.TempDir()
+	expected := aggregate(query.NewEngine(memory.DefaultAllocator, table.db.TableProvider()))
+	require.NotEmpty(t, expected)
+	actual := aggregate(query.NewEngine(
+		memory.DefaultAllocator,
+		table.db.TableProvider(),
+		query.WithPhysicalplanOptions(physicalplan.WithSpill(physicalplan.SpillConfig{
+			Dir:         dir,
+			MemoryLimit: 1024,
+		})),
+	))
+	require.Equal(t, expected, actual)
+
+	// The query's spill directory is removed once it finished.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func Test_Table_MetadataAggregations(t *testing.T) {
+	c, table := basicTable(t)
+	defer c.Close()
+
+	ctx := context.Background()
+	insert := func(from, to int64) {
+		samples := make(dynparquet.Samples, 0, to-from)
+		for _, s := range dynparquet.GenerateTestSamples(int(to)) {
+			if s.Timestamp >= from {
+				samples = append(samples, s)
+			}
+		}
+		r, err := samples.ToRecord()
+		require.NoError(t, err)
+		defer r.Release()
+		_, err = table.InsertRecord(ctx, r)
+		require.NoError(t, err)
+	}
+	// Compacted data is aggregated from its metadata, the remaining in-memory
+	// data row by row.
+	insert(10, 60)
+	insert(60, 100)
+	require.NoError(t, table.EnsureCompaction())
+	insert(100, 150)
+
+	aggExprs := []logicalplan.Expr{
+		logicalplan.Max(logicalplan.Col("timestamp")),
+		logicalplan.Min(logicalplan.Col("value")),
+		logicalplan.Count(logicalplan.Col("value")).Alias("rows"),
+	}
+	optimize := func(builder logicalplan.Builder) *logicalplan.TableScan {
+		plan, err := builder.Aggregate(aggExprs, nil).Build()
+		require.NoError(t, err)
+		for _, optimizer := range logicalplan.DefaultOptimizers() {
+			plan = optimizer.Optimize(plan)
+		}
+		for ; plan.TableScan == nil; plan = plan.Input {
+		}
+		return plan.TableScan
+	}
+	scan := func() logicalplan.Builder {
+		return (&logicalplan.Builder{}).Scan(table.db.TableProvider(), "test")
+	}
+	require.Equal(t, aggExprs, optimize(scan()).MetadataAggregations)
+	// The metadata doesn't tell which rows match the filter.
+	require.Nil(t, optimize(scan().Filter(logicalplan.Col("value").Gt(logicalplan.Literal(int64(10))))).MetadataAggregations)
+
+	pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer pool.AssertSize(t, 0)
+	engine := query.NewEngine(pool, table.db.TableProvider())
+	aggregate := func(builder query.Builder) []int64 {
+		var results []int64
+		require.NoError(t, builder.Aggregate(aggExprs, nil).Execute(ctx, func(_ context.Context, r arrow.Record) error {
+			require.Equal(t, int64(1), r.NumRows())
+			for _, name := range []string{"max(timestamp)", "min(value)", "rows"} {
+				results = append(results, r.Column(r.Schema().FieldIndices(name)[0]).(*array.Int64).Value(0))
+			}
+			return nil
+		}))
+		return results
+	}
+
+	require.Equal(t, []int64{149, 10, 140}, aggregate(engine.ScanTable("test")))
+	// Filtered scans are not pushed down and yield the same results.
+	require.Equal(t, []int64{149, 10, 140}, aggregate(
+		engine.ScanTable("test").Filter(logicalplan.Col("timestamp").GtEq(logicalplan.Literal(int64(0)))),
+	))
+}
+
+func Test_Table_Sample(t *testing.T) {
+	c, table := basicTable(t)
+	defer c.Close()
+
+	ctx := context.Background()
+	// Every insert is held in its own row group.
+	for i := 0; i < 20; i++ {
+		samples := dynparquet.GenerateTestSamples(10)
+		for j := range samples {
+			samples[j].Timestamp = int64(i*10 + j)
+		}
+		r, err := samples.ToRecord()
+		require.NoError(t, err)
+		_, err = table.InsertRecord(ctx, r)
+		r.Release()
+		require.NoError(t, err)
+	}
+
+	pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer pool.AssertSize(t, 0)
+	engine := query.NewEngine(pool, table.db.TableProvider())
+	count := func(sample logicalplan.Sample) int64 {
+		var rows int64
+		require.NoError(t, engine.ScanTable("test").(query.ExtendedBuilder).
+			Sample(sample).
+			Aggregate([]logicalplan.Expr{logicalplan.Count(logicalplan.Col("value")).Alias("rows")}, nil).
+			Execute(ctx, func(_ context.Context, r arrow.Record) error {
+				fraction, ok := logicalplan.SampleFraction(r.Schema())
+				require.True(t, ok)
+				require.Equal(t, sample.Fraction, fraction)
+				rows += r.Column(0).(*array.Int64).Value(0)
+				return nil
+			}))
+		return rows
+	}
+
+	// Whole row groups are sampled, the same ones for the same seed.
+	sample := logicalplan.Sample{Method: logicalplan.SampleSystem, Fraction: 0.5, Seed: 42}
+	rows := count(sample)
+	require.Less(t, rows, int64(200))
+	require.Zero(t, rows%10)
+
+	sample.ScaleAggregations = true
+	require.Equal(t, 2*rows, count(sample))
+
+	sample = logicalplan.Sample{Method: logicalplan.SampleSystem, Fraction: 1}
+	require.Equal(t, int64(200), count(sample))
+
+	timestamps := func(sample logicalplan.Sample, from int64) []int64 {
+		var res []int64
+		require.NoError(t, engine.ScanTable("test").(query.ExtendedBuilder).
+			Sample(sample).
+			Filter(logicalplan.Col("timestamp").GtEq(logicalplan.Literal(from))).
+			Execute(ctx, func(_ context.Context, r arrow.Record) error {
+				res = append(res, r.Column(r.Schema().FieldIndices("timestamp")[0]).(*array.Int64).Int64Values()...)
+				return nil
+			}))
+		sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+		return res
+	}
+
+	// Rows are sampled independently, the same ones for the same seed no
+	// matter how they are read and filtered.
+	sample = logicalplan.Sample{Method: logicalplan.SampleBernoulli, Fraction: 0.5, Seed: 42}
+	sampled := timestamps(sample, 0)
+	require.Greater(t, len(sampled), 50)
+	require.Less(t, len(sampled), 150)
+	perInsert := map[int64]int{}
+	for _, ts := range sampled {
+		perInsert[ts/10]++
+	}
+	partial := false
+	for _, n := range perInsert {
+		partial = partial || n < 10
+	}
+	require.True(t, partial, "rows of a row group are sampled independently")
+	for i := 0; i < 5; i++ {
+		require.Equal(t, sampled, timestamps(sample, 0))
+	}
+	filtered := timestamps(sample, 100)
+	require.Equal(t, sampled[sort.Search(len(sampled), func(i int) bool { return sampled[i] >= 100 }):], filtered)
+
+	// Unseeded samples are seeded once per query, so their rows are sampled
+	// the same way.
+	unseeded := timestamps(logicalplan.Sample{Method: logicalplan.SampleBernoulli, Fraction: 0.5}, 0)
+	require.Greater(t, len(unseeded), 50)
+	require.Less(t, len(unseeded), 150)
+
+	// The schema of row groups whose rows are sampled is passed on as is.
+	var names []string
+	require.NoError(t, table.SchemaIterator(ctx, table.db.highWatermark.Load(), pool, []logicalplan.Callback{
+		func(_ context.Context, r arrow.Record) error {
+			names = append(names, r.Column(0).(*array.String).Value(0))
+			return nil
+		},
+	}, logicalplan.WithSample(&sample)))
+	require.Len(t, names, 20)
+}
+
+func Test_Table_PivotUnpivot(t *testing.T) {
+	c, table := basicTable(t)
+	defer c.Close()
+
+	ctx := context.Background()
+	samples := dynparquet.Samples{{
+		ExampleType: "cpu",
+		Labels:      []dynparquet.Label{{Name: "namespace", Value: "x"}, {Name: "node", Value: "a"}},
+		Timestamp:   1,
+		Value:       1,
+	}, {
+		ExampleType: "cpu",
+		Labels:      []dynparquet.Label{{Name: "node", Value: "b"}},
+		Timestamp:   2,
+		Value:       2,
+	}, {
+		ExampleType: "cpu",
+		Labels:      []dynparquet.Label{{Name: "namespace", Value: "y"}, {Name: "pod", Value: "p"}},
+		Timestamp:   3,
+		Value:       3,
+	}}
+	r, err := samples.ToRecord()
+	require.NoError(t, err)
+	_, err = table.InsertRecord(ctx, r)
+	r.Release()
+	require.NoError(t, err)
+
+	pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer pool.AssertSize(t, 0)
+	engine := query.NewEngine(pool, table.db.TableProvider())
+	unpivot := logicalplan.Unpivot{Expr: logicalplan.DynCol("labels"), Key: "label", Value: "label_value"}
+
+	// The usage of each label is counted from the unpivoted labels.
+	usage := map[string]int64{}
+	require.NoError(t, engine.ScanTable("test").(query.ExtendedBuilder).
+		Unpivot(unpivot).
+		Aggregate(
+			[]logicalplan.Expr{logicalplan.Count(logicalplan.Col("label_value")).Alias("usage")},
+			[]logicalplan.Expr{logicalplan.Col("label")},
+		).
+		Execute(ctx, func(_ context.Context, r arrow.Record) error {
+			labels := r.Column(r.Schema().FieldIndices("label")[0]).(*array.String)
+			counts := r.Column(r.Schema().FieldIndices("usage")[0]).(*array.Int64)
+			for i := 0; i < int(r.NumRows()); i++ {
+				usage[labels.Value(i)] += counts.Value(i)
+			}
+			return nil
+		}))
+	require.Equal(t, map[string]int64{"namespace": 2, "node": 2, "pod": 1}, usage)
+
+	// Pivoting the unpivoted labels back restores the labels of each row.
+	rows := map[int64]string{}
+	require.NoError(t, engine.ScanTable("test").(query.ExtendedBuilder).
+		Unpivot(unpivot).
+		Pivot(logicalplan.Pivot{Key: logicalplan.Col("label"), Value: logicalplan.Col("label_value"), Prefix: "labels"}).
+		Execute(ctx, func(_ context.Context, r arrow.Record) error {
+			timestamps := r.Column(r.Schema().FieldIndices("timestamp")[0]).(*array.Int64)
+			for i := 0; i < int(r.NumRows()); i++ {
+				var labels []string
+				for j, f := range r.Schema().Fields() {
+					if !strings.HasPrefix(f.Name, "labels.") || r.Column(j).IsNull(i) {
+						continue
+					}
+					dict := r.Column(j).(*array.Dictionary)
+					value := dict.Dictionary().(*array.Binary).Value(dict.GetValueIndex(i))
+					labels = append(labels, f.Name+"="+string(value))
+				}
+				rows[timestamps.Value(i)] = strings.Join(labels, ",")
+			}
+			return nil
+		}))
+	require.Equal(t, map[int64]string{
+		1: "labels.namespace=x,labels.node=a",
+		2: "labels.node=b",
+		3: "labels.namespace=y,labels.pod=p",
+	}, rows)
+}
+
+func Test_Table_PreparedQuery(t *testing.T) {
+	c, table := basicTable(t)
+	defer c.Close()
+
+	ctx := context.Background()
+	samples := dynparquet.Samples{}
+	for i := 0; i < 10; i++ {
+		samples = append(samples, dynparquet.Sample{
+			ExampleType: "cpu",
+			Labels:      []dynparquet.Label{{Name: "node", Value: fmt.Sprintf("node%d", i%2)}},
+			Timestamp:   int64(i),
+			Value:       int64(i),
+		})
+	}
+	r, err := samples.ToRecord()
+	require.NoError(t, err)
+	_, err = table.InsertRecord(ctx, r)
+	r.Release()
+	require.NoError(t, err)
+
+	pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer pool.AssertSize(t, 0)
+	engine := query.NewEngine(pool, table.db.TableProvider())
+
+	prepared, err := engine.Prepare(engine.ScanTable("test").
+		Filter(logicalplan.And(
+			logicalplan.Col("labels.node").Eq(logicalplan.Param("node")),
+			logicalplan.Col("timestamp").GtEq(logicalplan.Param("start")),
+		)).
+		Aggregate(
+			[]logicalplan.Expr{logicalplan.Sum(logicalplan.Col("value"))},
+			nil,
+		))
+	require.NoError(t, err)
+	require.Equal(t, []string{"node", "start"}, prepared.Params())
+
+	sum := func(params map[string]any) int64 {
+		var sum int64
+		require.NoError(t, prepared.Execute(ctx, params, func(_ context.Context, r arrow.Record) error {
+			sums := r.Column(0).(*array.Int64)
+			for i := 0; i < sums.Len(); i++ {
+				sum += sums.Value(i)
+			}
+			return nil
+		}))
+		return sum
+	}
+	require.Equal(t, int64(0+2+4+6+8), sum(map[string]any{"node": "node0", "start": 0}))
+	require.Equal(t, int64(5+7+9), sum(map[string]any{"node": "node1", "start": 4}))
+	require.Equal(t, int64(8), sum(map[string]any{"node": "node0", "start": 7}))
+
+	err = prepared.Execute(ctx, map[string]any{"node": "node0"}, func(_ context.Context, _ arrow.Record) error {
+		return nil
+	})
+	require.EqualError(t, err, "missing value of parameter start")
+
+	// Queries with parameters can't be executed without binding them.
+	err = engine.ScanTable("test").
+		Filter(logicalplan.Col("timestamp").GtEq(logicalplan.Param("start"))).
+		Execute(ctx, func(_ context.Context, _ arrow.Record) error {
+			return nil
+		})
+	require.EqualError(t, err, "plan has unbound parameters: start")
+}
+
+func Test_Table_SemiJoin(t *testing.T) {
+	c, table := basicTable(t)
+	defer c.Close()
+
+	ctx := context.Background()
+	samples := dynparquet.Samples{}
+	for i, node := range []string{"a", "b", "c", "a", "b", "c"} {
+		samples = append(samples, dynparquet.Sample{
+			ExampleType: "cpu",
+			Labels:      []dynparquet.Label{{Name: "node", Value: node}},
+			Timestamp:   int64(i),
+			Value:       int64(i),
+		})
+	}
+	r, err := samples.ToRecord()
+	require.NoError(t, err)
+	_, err = table.InsertRecord(ctx, r)
+	r.Release()
+	require.NoError(t, err)
+
+	pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer pool.AssertSize(t, 0)
+	engine := query.NewEngine(pool, table.db.TableProvider())
+
+	// The inner query finds the nodes with values above 3, which are b and c.
+	inner := engine.ScanTable("test").
+		Filter(logicalplan.Col("value").Gt(logicalplan.Literal(int64(3)))).
+		Distinct(logicalplan.Col("labels.node"))
+	timestamps := func(anti bool) []int64 {
+		var timestamps []int64
+		require.NoError(t, engine.ScanTable("test").(query.ExtendedBuilder).
+			SemiJoin(inner, logicalplan.SemiJoin{
+				Key:      logicalplan.Col("labels.node"),
+				InnerKey: logicalplan.Col("labels.node"),
+				Anti:     anti,
+			}).
+			Project(logicalplan.Col("timestamp")).
+			Execute(ctx, func(_ context.Context, r arrow.Record) error {
+				col := r.Column(0).(*array.Int64)
+				for i := 0; i < col.Len(); i++ {
+					timestamps = append(timestamps, col.Value(i))
+				}
+				return nil
+			}))
+		sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+		return timestamps
+	}
+	require.Equal(t, []int64{1, 2, 4, 5}, timestamps(false))
+	require.Equal(t, []int64{0, 3}, timestamps(true))
+
+	_, err = engine.Prepare(engine.ScanTable("test").(query.ExtendedBuilder).SemiJoin(inner, logicalplan.SemiJoin{
+		Key:      logicalplan.Col("labels.node"),
+		InnerKey: logicalplan.Col("labels.node"),
+	}))
+	require.EqualError(t, err, "semi joins can't be prepared")
+
+	// Explaining the query draws the filter of the semi join without executing
+	// the inner query.
+	limiter := query.NewLimitAllocator(1024*1024*1024, pool)
+	explained, err := query.NewEngine(limiter, table.db.TableProvider()).ScanTable("test").(query.ExtendedBuilder).
+		SemiJoin(inner, logicalplan.SemiJoin{
+			Key:      logicalplan.Col("labels.node"),
+			InnerKey: logicalplan.Col("labels.node"),
+		}).
+		Explain(ctx)
+	require.NoError(t, err)
+	require.Contains(t, explained, "Filter")
+	require.Zero(t, limiter.Peak())
+}
+
+func Test_Table_StreamingAggregation(t *testing.T) {
+	c, err := New(WithReadWriteStorage(NewDefaultObjstoreBucket(objstore.NewInMemBucket())))
+	require.NoError(t, err)
+	defer c.Close()
+
+	db, err := c.DB(context.Background(), "test")
+	require.NoError(t, err)
+
+	type row struct {
+		Name      string
+		Timestamp int64
+		Value     int64
+	}
+	rows := make([]any, 0, 30)
+	for i := 0; i < 30; i++ {
+		rows = append(rows, row{Name: fmt.Sprintf("n%d", i%2), Timestamp: int64(i * 1000), Value: int64(i)})
+	}
+	// The rows of a write are ordered by timestamp, but the timestamps of
+	// separate writes overlap.
+	var even, odd []any
+	for i, r := range rows {
+		if i%2 == 0 {
+			even = append(even, r)
+		} else {
+			odd = append(odd, r)
+		}
+	}
+	// Single rows written out of order are scanned by fewer workers than
+	// there are writes, so some worker sees a timestamp of an earlier window
+	// after a later one.
+	shuffled := make([][]any, 0, len(rows))
+	for i := range rows {
+		shuffled = append(shuffled, []any{rows[i*7%len(rows)]})
+	}
+
+	for _, tc := range []struct {
+		name   string
+		writes [][]any
+		// rotate persists the block of each write but the last, so that
+		// row groups of later blocks hold timestamps of earlier windows.
+		rotate bool
+	}{
+		{name: "single write", writes: [][]any{rows}},
+		{name: "overlapping writes", writes: [][]any{even, odd}},
+		{name: "shuffled writes", writes: shuffled},
+		{name: "rotated overlapping writes", writes: [][]any{odd, even}, rotate: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			table, err := db.Table(strings.ReplaceAll(tc.name, " ", "_"), NewTableConfig(&schemapb.Schema{
+				Name: "streaming",
+				Columns: []*schemapb.Column{{
+					Name: "name",
+					StorageLayout: &schemapb.StorageLayout{
+						Type: schemapb.StorageLayout_TYPE_STRING,
+					},
+				}, {
+					Name: "timestamp",
+					StorageLayout: &schemapb.StorageLayout{
+						Type: schemapb.StorageLayout_TYPE_INT64,
+					},
+				}, {
+					Name: "value",
+					StorageLayout: &schemapb.StorageLayout{
+						Type: schemapb.StorageLayout_TYPE_INT64,
+					},
+				}},
+				SortingColumns: []*schemapb.SortingColumn{{
+					Name:      "timestamp",
+					Direction: schemapb.SortingColumn_DIRECTION_ASCENDING,
+				}},
+			}))
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			for i, rows := range tc.writes {
+				_, err = table.Write(ctx, rows...)
+				require.NoError(t, err)
+				if tc.rotate && i < len(tc.writes)-1 {
+					require.NoError(t, table.RotateBlock(ctx, table.ActiveBlock(), false))
+				}
+			}
+			require.Eventually(t, func() bool {
+				table.mtx.RLock()
+				defer table.mtx.RUnlock()
+				return len(table.pendingBlocks) == 0
+			}, time.Second, 10*time.Millisecond)
+
+			pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+			defer pool.AssertSize(t, 0)
+			engine := query.NewEngine(
+				pool,
+				db.TableProvider(),
+				query.WithPhysicalplanOptions(physicalplan.WithStreamingAggregations()),
+			)
+			q := engine.ScanTable(table.name).Aggregate(
+				[]logicalplan.Expr{logicalplan.Sum(logicalplan.Col("value"))},
+				[]logicalplan.Expr{logicalplan.Col("name"), logicalplan.Duration(10 * time.Second)},
+			)
+
+			explain, err := q.Explain(ctx)
+			require.NoError(t, err)
+			require.Contains(t, explain, "WindowAggregate")
+
+			sums := map[string]int64{}
+			require.NoError(t, q.Execute(ctx, func(_ context.Context, r arrow.Record) error {
+				names := r.Column(r.Schema().FieldIndices("name")[0]).(*array.Binary)
+				timestamps := r.Column(r.Schema().FieldIndices("timestamp")[0]).(*array.Int64)
+				values := r.Column(r.Schema().FieldIndices("sum(value)")[0]).(*array.Int64)
+				for i := 0; i < int(r.NumRows()); i++ {
+					key := fmt.Sprintf("%s %d", names.Value(i), timestamps.Value(i)/10000*10000)
+					sums[key] += values.Value(i)
+				}
+				return nil
+			}))
+			require.Equal(t, map[string]int64{
+				"n0 0":     0 + 2 + 4 + 6 + 8,
+				"n1 0":     1 + 3 + 5 + 7 + 9,
+				"n0 10000": 10 + 12 + 14 + 16 + 18,
+				"n1 10000": 11 + 13 + 15 + 17 + 19,
+				"n0 20000": 20 + 22 + 24 + 26 + 28,
+				"n1 20000": 21 + 23 + 25 + 27 + 29,
+			}, sums)
+		})
+	}
+}
+
+func Test_Table_DistinctLimit(t *testing.T) {
+	c, table := basicTable(t)
+	defer c.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		samples := dynparquet.Samples{}
+		for j := 0; j < 10; j++ {
+			samples = append(samples, dynparquet.Sample{
+				ExampleType: "cpu",
+				Labels:      []dynparquet.Label{{Name: "node", Value: fmt.Sprintf("node-%d", j%5+i%2*5)}},
+				Timestamp:   int64(i*10 + j),
+				Value:       1,
+			})
+		}
+		r, err := samples.ToRecord()
+		require.NoError(t, err)
+		_, err = table.InsertRecord(ctx, r)
+		r.Release()
+		require.NoError(t, err)
+	}
+
+	pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer pool.AssertSize(t, 0)
+	engine := query.NewEngine(pool, table.db.TableProvider())
+
+	nodes := func(filter logicalplan.Expr, limit uint64) []string {
+		b := engine.ScanTable("test")
+		if filter != nil {
+			b = b.Filter(filter)
+		}
+		if limit > 0 {
+			b = b.(query.ExtendedBuilder).DistinctLimit(limit, logicalplan.Col("labels.node"))
+		} else {
+			b = b.Distinct(logicalplan.Col("labels.node"))
+		}
+		var nodes []string
+		require.NoError(t, b.
+			Execute(ctx, func(_ context.Context, r arrow.Record) error {
+				switch col := r.Column(0).(type) {
+				case *array.Binary:
+					for i := 0; i < col.Len(); i++ {
+						nodes = append(nodes, string(col.Value(i)))
+					}
+				case *array.Dictionary:
+					dict := col.Dictionary().(*array.Binary)
+					for i := 0; i < col.Len(); i++ {
+						nodes = append(nodes, string(dict.Value(col.GetValueIndex(i))))
+					}
+				default:
+					return fmt.Errorf("unexpected column type: %s", col.DataType())
+				}
+				return nil
+			}))
+		return nodes
+	}
+
+	got := nodes(nil, 3)
+	require.Len(t, got, 3)
+	for _, node := range got {
+		require.True(t, strings.HasPrefix(node, "node-"))
+	}
+
+	got = nodes(logicalplan.Col("labels.node").RegexMatch("^node-[0-4]$"), 2)
+	require.Len(t, got, 2)
+	for _, node := range got {
+		require.Regexp(t, "^node-[0-4]$", node)
+	}
+
+	// Without a limit, a filter of the distinct column filters the distinct
+	// values read from the dictionaries.
+	got = nodes(logicalplan.Col("labels.node").RegexMatch("^node-[5-9]$"), 0)
+	sort.Strings(got)
+	require.Equal(t, []string{"node-5", "node-6", "node-7", "node-8", "node-9"}, got)
+
+	// A limit above the number of distinct values returns all of them.
+	got = nodes(nil, 100)
+	sort.Strings(got)
+	require.Equal(t, []string{
+		"node-0", "node-1", "node-2", "node-3", "node-4",
+		"node-5", "node-6", "node-7", "node-8", "node-9",
+	}, got)
+}
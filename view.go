@@ -0,0 +1,307 @@
+package frostdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/go-kit/log/level"
+
+	"github.com/polarsignals/frostdb/dynparquet"
+	tablepb "github.com/polarsignals/frostdb/gen/proto/go/frostdb/table/v1alpha1"
+	"github.com/polarsignals/frostdb/query"
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+// materializedViewExprs returns the aggregation and group by expressions of
+// the materialized view.
+func materializedViewExprs(view *tablepb.MaterializedView) ([]logicalplan.Expr, []logicalplan.Expr, error) {
+	if view.Source == "" {
+		return nil, nil, errors.New("materialized view source table must be set")
+	}
+	if len(view.Aggregations) == 0 {
+		return nil, nil, errors.New("materialized view must have at least one aggregation")
+	}
+
+	aggExprs := make([]logicalplan.Expr, 0, len(view.Aggregations))
+	for _, agg := range view.Aggregations {
+		var f *logicalplan.AggregationFunction
+		switch agg.Function {
+		case tablepb.Aggregation_FUNCTION_SUM:
+			f = logicalplan.Sum(logicalplan.Col(agg.Column))
+		case tablepb.Aggregation_FUNCTION_MIN:
+			f = logicalplan.Min(logicalplan.Col(agg.Column))
+		case tablepb.Aggregation_FUNCTION_MAX:
+			f = logicalplan.Max(logicalplan.Col(agg.Column))
+		case tablepb.Aggregation_FUNCTION_COUNT:
+			f = logicalplan.Count(logicalplan.Col(agg.Column))
+		default:
+			return nil, nil, fmt.Errorf("unsupported materialized view aggregation function: %s", agg.Function)
+		}
+		if agg.Alias == "" {
+			aggExprs = append(aggExprs, f)
+			continue
+		}
+		aggExprs = append(aggExprs, f.Alias(agg.Alias))
+	}
+
+	groupExprs := make([]logicalplan.Expr, 0, len(view.GroupBy))
+	for _, groupBy := range view.GroupBy {
+		switch expr := groupBy.Expr.(type) {
+		case *tablepb.GroupBy_Column:
+			groupExprs = append(groupExprs, logicalplan.Col(expr.Column))
+		case *tablepb.GroupBy_DynamicColumn:
+			groupExprs = append(groupExprs, logicalplan.DynCol(expr.DynamicColumn))
+		case *tablepb.GroupBy_DurationMillis:
+			if expr.DurationMillis <= 0 {
+				return nil, nil, fmt.Errorf("invalid materialized view duration: %dms", expr.DurationMillis)
+			}
+			groupExprs = append(groupExprs, logicalplan.Duration(time.Duration(expr.DurationMillis)*time.Millisecond))
+		default:
+			return nil, nil, fmt.Errorf("unsupported materialized view group by expression: %T", expr)
+		}
+	}
+
+	return aggExprs, groupExprs, nil
+}
+
+// validateMaterializedViewLocked returns an error if the table of the given
+// name would become a materialized view of itself, either directly or through
+// the sources of other views, as inserting into it would then never end. The
+// caller must hold the lock of the tables.
+func (db *DB) validateMaterializedViewLocked(name string, config *tablepb.TableConfig) error {
+	view := config.GetMaterializedView()
+	if view == nil {
+		return nil
+	}
+	if view.Source == name {
+		return fmt.Errorf("materialized view %s can't be its own source", name)
+	}
+
+	visited := map[string]struct{}{}
+	for view != nil {
+		if _, ok := visited[view.Source]; ok {
+			// The views of the other tables already form a cycle that
+			// doesn't go through this table.
+			return nil
+		}
+		visited[view.Source] = struct{}{}
+		source, ok := db.tables[view.Source]
+		if !ok {
+			return nil
+		}
+		view = source.config.Load().GetMaterializedView()
+		if view != nil && view.Source == name {
+			return fmt.Errorf("materialized view %s: its sources form a cycle through %s", name, source.name)
+		}
+	}
+	return nil
+}
+
+// viewDelta holds the records to insert into a materialized view for a record
+// inserted into its source table.
+type viewDelta struct {
+	view    *Table
+	records []arrow.Record
+}
+
+func releaseViewDeltas(deltas []viewDelta) {
+	for _, d := range deltas {
+		for _, r := range d.records {
+			r.Release()
+		}
+	}
+}
+
+// maxPendingViewRecords bounds the records a materialized view keeps after
+// failing to insert them. Inserts into its source fail while it keeps that
+// many and can't catch up.
+const maxPendingViewRecords = 1024
+
+// views returns the materialized views of the source table.
+func (db *DB) views(source *Table) []*Table {
+	db.mtx.RLock()
+	defer db.mtx.RUnlock()
+
+	var views []*Table
+	for _, table := range db.tables {
+		if view := table.config.Load().GetMaterializedView(); view != nil && view.Source == source.name {
+			views = append(views, table)
+		}
+	}
+	return views
+}
+
+// viewDeltas aggregates a record to be inserted into the source table for all
+// materialized views of the source table. Every insert adds the partial
+// aggregates of its record to a view, so queries of a view aggregate the
+// stored aggregates again, e.g. by summing the stored counts. The deltas are
+// computed before the record is inserted, so that a record the views can't be
+// maintained for isn't inserted either.
+func (db *DB) viewDeltas(ctx context.Context, source *Table, record arrow.Record) ([]viewDelta, error) {
+	views := db.views(source)
+	deltas := make([]viewDelta, 0, len(views))
+	for _, view := range views {
+		if err := view.catchUpView(ctx); err != nil {
+			releaseViewDeltas(deltas)
+			return nil, fmt.Errorf("materialized view %s: %w", view.name, err)
+		}
+		records, err := view.aggregateDelta(ctx, source.Schema(), record)
+		if err != nil {
+			releaseViewDeltas(deltas)
+			return nil, fmt.Errorf("materialized view %s: %w", view.name, err)
+		}
+		deltas = append(deltas, viewDelta{view: view, records: records})
+	}
+	return deltas, nil
+}
+
+// pendingViewDeltas returns empty deltas of all materialized views of the
+// source table, which insert the records the views failed to insert before.
+func (db *DB) pendingViewDeltas(source *Table) []viewDelta {
+	views := db.views(source)
+	deltas := make([]viewDelta, 0, len(views))
+	for _, view := range views {
+		deltas = append(deltas, viewDelta{view: view})
+	}
+	return deltas
+}
+
+// maintainViews inserts the deltas of a record inserted into the source
+// table into the materialized views, taking ownership of their records.
+//
+// Views are tables of their own, so they are updated right after their source
+// rather than atomically with it, and readers may briefly observe the source
+// ahead of its views. A view that fails to insert its delta keeps it and
+// inserts it again before its next delta or when the block of its source is
+// rotated, which closes the gap left by the failure. The failure is logged,
+// and the number of records the view keeps is reported by its metrics.
+func (db *DB) maintainViews(ctx context.Context, deltas []viewDelta) {
+	for _, d := range deltas {
+		if err := d.view.insertViewRecords(ctx, d.records); err != nil {
+			level.Warn(db.logger).Log(
+				"msg", "failed to maintain materialized view",
+				"view", d.view.name,
+				"err", err,
+			)
+		}
+	}
+}
+
+// aggregateDelta aggregates the record into the records to insert into the
+// table. Empty results are skipped.
+func (t *Table) aggregateDelta(ctx context.Context, schema *dynparquet.Schema, record arrow.Record) ([]arrow.Record, error) {
+	aggExprs, groupExprs, err := materializedViewExprs(t.config.Load().GetMaterializedView())
+	if err != nil {
+		return nil, err
+	}
+
+	engine := query.NewEngine(
+		t.db.columnStore.allocator,
+		recordTableProvider{table: &recordTable{schema: schema, record: record}},
+		query.WithTracer(t.tracer),
+	)
+	var records []arrow.Record
+	if err := engine.ScanTable("").
+		Aggregate(aggExprs, groupExprs).
+		Execute(ctx, func(_ context.Context, r arrow.Record) error {
+			if r.NumRows() == 0 {
+				return nil
+			}
+			r.Retain()
+			records = append(records, r)
+			return nil
+		}); err != nil {
+		for _, r := range records {
+			r.Release()
+		}
+		return nil, err
+	}
+	return records, nil
+}
+
+// insertViewRecords inserts the records of the materialized view, preceded by
+// the records that previously failed to be inserted. Records that fail to be
+// inserted are kept for the next call.
+func (t *Table) insertViewRecords(ctx context.Context, records []arrow.Record) error {
+	t.pendingViewMtx.Lock()
+	defer t.pendingViewMtx.Unlock()
+
+	records = append(t.pendingViewRecords, records...)
+	t.pendingViewRecords = nil
+	defer func() {
+		t.metrics.pendingViewRecords.Set(float64(len(t.pendingViewRecords)))
+	}()
+	for i, r := range records {
+		if _, err := t.InsertRecord(ctx, r); err != nil {
+			t.pendingViewRecords = records[i:]
+			return err
+		}
+		r.Release()
+	}
+	return nil
+}
+
+// catchUpView inserts the records the materialized view previously failed to
+// insert once it keeps maxPendingViewRecords of them, before more are added.
+// An error is returned if it still can't.
+func (t *Table) catchUpView(ctx context.Context) error {
+	t.pendingViewMtx.Lock()
+	pending := len(t.pendingViewRecords)
+	t.pendingViewMtx.Unlock()
+	if pending < maxPendingViewRecords {
+		return nil
+	}
+	if err := t.insertViewRecords(ctx, nil); err != nil {
+		return fmt.Errorf("%d records failed to be inserted: %w", pending, err)
+	}
+	return nil
+}
+
+// recordTableProvider provides a table that consists of a single record.
+type recordTableProvider struct {
+	table *recordTable
+}
+
+func (p recordTableProvider) GetTable(_ string) (logicalplan.TableReader, error) {
+	return p.table, nil
+}
+
+type recordTable struct {
+	schema *dynparquet.Schema
+	record arrow.Record
+}
+
+func (t *recordTable) View(ctx context.Context, fn func(ctx context.Context, tx uint64) error) error {
+	return fn(ctx, 0)
+}
+
+func (t *recordTable) Iterator(
+	ctx context.Context,
+	_ uint64,
+	_ memory.Allocator,
+	callbacks []logicalplan.Callback,
+	_ ...logicalplan.Option,
+) error {
+	if len(callbacks) == 0 {
+		return errors.New("no callbacks provided")
+	}
+	return callbacks[0](ctx, t.record)
+}
+
+func (t *recordTable) SchemaIterator(
+	_ context.Context,
+	_ uint64,
+	_ memory.Allocator,
+	_ []logicalplan.Callback,
+	_ ...logicalplan.Option,
+) error {
+	return errors.New("schema iteration of a record is not supported")
+}
+
+func (t *recordTable) Schema() *dynparquet.Schema {
+	return t.schema
+}
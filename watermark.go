@@ -0,0 +1,277 @@
+package frostdb
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/polarsignals/frostdb/dynparquet"
+	"github.com/polarsignals/frostdb/query/expr"
+	"github.com/polarsignals/frostdb/query/logicalplan"
+)
+
+// watermarkedRowGroup is a row group passed on in the order of the minimum
+// values of the watermark column among the row groups of its block, along with
+// the index of the block and its position in that order.
+type watermarkedRowGroup struct {
+	block    int
+	index    int
+	rowGroup any
+}
+
+// rowGroupWatermarks tracks the row groups of an iteration whose rows are yet
+// to be passed on. The row groups are collected block by block, and the ones
+// of each block are passed on in the order of their minimum values of the
+// watermark column, so the watermark is the lowest minimum value of the first
+// row groups of the blocks that aren't done. The row groups of the blocks left
+// to collect may hold any values, so the watermark only advances once all
+// blocks were collected.
+type rowGroupWatermarks struct {
+	*logicalplan.Watermarks
+
+	mtx       sync.Mutex
+	blocks    []*watermarkedBlock
+	collected bool
+	watermark int64
+}
+
+// watermarkedBlock holds the minimum values of the row groups of a block in
+// the order they are passed on.
+type watermarkedBlock struct {
+	mins []int64
+	done []bool
+	next int
+	// release releases the reference held on the block while its row groups
+	// are read. It's nil for blocks of data sources and once released.
+	release func()
+}
+
+func newRowGroupWatermarks(w *logicalplan.Watermarks) *rowGroupWatermarks {
+	return &rowGroupWatermarks{
+		Watermarks: w,
+		watermark:  math.MinInt64,
+	}
+}
+
+// addBlock adds a block whose row groups are passed on in the order of the
+// minimum values and returns its index. The reference on the block is released
+// once all of its row groups are done.
+func (w *rowGroupWatermarks) addBlock(mins []int64, release func()) int {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	b := &watermarkedBlock{
+		mins:    mins,
+		done:    make([]bool, len(mins)),
+		release: release,
+	}
+	if len(mins) == 0 {
+		b.releaseBlock()
+	}
+	w.blocks = append(w.blocks, b)
+	return len(w.blocks) - 1
+}
+
+// complete marks all blocks of the iteration as collected.
+func (w *rowGroupWatermarks) complete(ctx context.Context) error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	w.collected = true
+	return w.advance(ctx)
+}
+
+// finish marks the row group at the index of the block as done once all of
+// its rows were passed on.
+func (w *rowGroupWatermarks) finish(ctx context.Context, block, index int) error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	b := w.blocks[block]
+	b.done[index] = true
+	for b.next < len(b.done) && b.done[b.next] {
+		b.next++
+	}
+	if b.next == len(b.done) {
+		b.releaseBlock()
+	}
+	return w.advance(ctx)
+}
+
+// release releases the references held on the blocks whose row groups aren't
+// all done, once the iteration stopped.
+func (w *rowGroupWatermarks) release() {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	for _, b := range w.blocks {
+		b.releaseBlock()
+	}
+}
+
+func (w *rowGroupWatermarks) advance(ctx context.Context) error {
+	if !w.collected {
+		return nil
+	}
+	watermark := int64(math.MaxInt64)
+	for _, b := range w.blocks {
+		if b.next < len(b.mins) {
+			watermark = min(watermark, b.mins[b.next])
+		}
+	}
+	if watermark <= w.watermark {
+		return nil
+	}
+	w.watermark = watermark
+	return w.Advance(ctx, watermark)
+}
+
+func (b *watermarkedBlock) releaseBlock() {
+	if b.release != nil {
+		b.release()
+		b.release = nil
+	}
+}
+
+// collectWatermarkedRowGroups collects the row groups of the iteration block
+// by block and passes the ones of each block on ordered by the minimum values
+// of the watermark column as soon as the block is collected.
+func (t *Table) collectWatermarkedRowGroups(
+	ctx context.Context,
+	tx uint64,
+	iterOpts logicalplan.IterOptions,
+	watermarks *rowGroupWatermarks,
+	rowGroups chan<- any,
+) error {
+	var block []any
+	defer func() {
+		// Release the row groups of a block whose collection failed.
+		releaseRowGroups(block)
+	}()
+	if err := t.scanRowGroups(ctx, tx, iterOpts, func(_ context.Context, v any) error {
+		block = append(block, v)
+		return nil
+	}, func(release func()) error {
+		collected := block
+		block = nil
+		return passWatermarkedRowGroups(ctx, watermarks, collected, release, rowGroups)
+	}); err != nil {
+		return err
+	}
+	return watermarks.complete(ctx)
+}
+
+// passWatermarkedRowGroups passes the row groups of a block on ordered by the
+// minimum values of the watermark column.
+func passWatermarkedRowGroups(
+	ctx context.Context,
+	watermarks *rowGroupWatermarks,
+	block []any,
+	release func(),
+	rowGroups chan<- any,
+) error {
+	mins := make([]int64, len(block))
+	for i, rg := range block {
+		mins[i] = minValue(rg, watermarks.Column)
+	}
+	order := make([]int, len(block))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return mins[order[i]] < mins[order[j]] })
+
+	ordered := make([]int64, len(order))
+	for i, idx := range order {
+		ordered[i] = mins[idx]
+	}
+	blockIndex := watermarks.addBlock(ordered, release)
+
+	for i, idx := range order {
+		select {
+		case rowGroups <- watermarkedRowGroup{block: blockIndex, index: i, rowGroup: block[idx]}:
+		case <-ctx.Done():
+			for _, idx := range order[i:] {
+				releaseRowGroup(block[idx])
+			}
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// minValue returns a lower bound of the values of the int64 column in the row
+// group. Row groups without values of the column have no lower bound, so
+// math.MaxInt64 is returned, while row groups whose values can't be bounded
+// return math.MinInt64.
+func minValue(rg any, column string) int64 {
+	switch v := rg.(type) {
+	case *expr.SampledRowGroup:
+		return minValue(v.RowGroup, column)
+	case arrow.Record:
+		indices := v.Schema().FieldIndices(column)
+		if len(indices) == 0 {
+			return math.MaxInt64
+		}
+		arr, ok := v.Column(indices[0]).(*array.Int64)
+		if !ok {
+			return math.MinInt64
+		}
+		res := int64(math.MaxInt64)
+		for i := 0; i < arr.Len(); i++ {
+			if arr.IsValid(i) {
+				res = min(res, arr.Value(i))
+			}
+		}
+		return res
+	case dynparquet.DynamicRowGroup:
+		fields := v.Schema().Fields()
+		idx := -1
+		for i, field := range fields {
+			if field.Name() == column {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			return math.MaxInt64
+		}
+		if fields[idx].Type().Kind() != parquet.Int64 {
+			return math.MinInt64
+		}
+		index, err := v.ColumnChunks()[idx].ColumnIndex()
+		if err != nil {
+			return math.MinInt64
+		}
+		res := int64(math.MaxInt64)
+		for page := 0; page < index.NumPages(); page++ {
+			if !index.NullPage(page) {
+				res = min(res, index.MinValue(page).Int64())
+			}
+		}
+		return res
+	default:
+		return math.MinInt64
+	}
+}
+
+// releaseRowGroups releases the records among the row groups, which are not
+// passed on.
+func releaseRowGroups(rowGroups []any) {
+	for _, rg := range rowGroups {
+		releaseRowGroup(rg)
+	}
+}
+
+func releaseRowGroup(rg any) {
+	switch v := rg.(type) {
+	case *expr.SampledRowGroup:
+		releaseRowGroup(v.RowGroup)
+	case arrow.Record:
+		v.Release()
+	}
+}